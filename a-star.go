@@ -5,16 +5,64 @@ import (
 )
 
 // ShortestPathWithHeuristic returns the shortest path from the vertex with key startKey to the vertex with key endKey as a string slice, and if such a path exists at all, using a function to calculate an estimated distance from a vertex to the endVertex. The heuristic function is passed the keys of a vertex and the end vertex. This function uses the A* search algorithm.
+// The path is returned in end → start order; use ShortestPath for more options and a path in start → end order.
 func (g *Graph) ShortestPathWithHeuristic(startKey, endKey string, heuristic func(key, endKey string) int) (path []string, exists bool) {
+	defer g.track("ShortestPathWithHeuristic")()
+
 	g.RLock()
 	defer g.RUnlock()
 
+	path, err := g.shortestPath(startKey, endKey, &pathConfig{heuristic: heuristic})
+	if err != nil {
+		return nil, false
+	}
+
+	// reverse into end → start order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
+
+// shortestPath is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It answers the query from the path cache if possible, and otherwise runs the A* search configured by cfg. The path is returned in start → end order.
+func (g *Graph) shortestPath(startKey, endKey string, cfg *pathConfig) (path []string, err error) {
+	if cfg.recover {
+		defer recoverPanic(&err)
+	}
+
+	// answer from the cache if possible
+	cached, ok, version := g.pathCache.get(startKey, endKey)
+	if ok && !cfg.validates() {
+		return cached, nil
+	}
+
 	// start and end vertex
 	start := g.get(startKey)
 	end := g.get(endKey)
 
+	if start == nil || end == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if path, err = g.aStar(start, end, cfg); err != nil {
+		return
+	}
+
+	g.pathCache.put(path, version)
+
+	return
+}
+
+// aStar is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It runs the A* search algorithm from start to end and returns the path found in start → end order.
+func (g *Graph) aStar(start, end *Vertex, cfg *pathConfig) (path []string, err error) {
 	// priorityQueue for vertices that have not yet been visited (open vertices)
-	openQueue := &priorityQueue{}
+	var openQueue heap.Interface = &priorityQueue{}
+	if cfg.tieBreak != nil {
+		openQueue = newTieBreakQueue(cfg.tieBreak)
+	}
 
 	// priorityQueue for vertices that have not yet been visited (open vertices)
 	openList := map[*Vertex]*Item{}
@@ -37,15 +85,17 @@ func (g *Graph) ShortestPathWithHeuristic(startKey, endKey string, heuristic fun
 
 		// end vertex found?
 		if current == end {
-			// path exists
-			exists = true
-
 			// build path
 			for current != nil {
 				path = append(path, current.key)
 				current = closedList[current].prev
 			}
 
+			// reverse into start → end order
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+
 			return
 		}
 
@@ -57,7 +107,13 @@ func (g *Graph) ShortestPathWithHeuristic(startKey, endKey string, heuristic fun
 				continue
 			}
 
-			distanceToNeighbor := distance + weight
+			if cfg.nonNegative && weight < 0 {
+				return nil, &WeightError{current.key, neighbor.key, weight, ErrNegativeWeight}
+			}
+			distanceToNeighbor := distance + int64(weight)
+			if addOverflows(distance, int64(weight)) || (cfg.checkOverflow && !fitsInt(distanceToNeighbor)) {
+				return nil, &WeightError{current.key, neighbor.key, weight, ErrCostOverflow}
+			}
 
 			// skip neighbors that already have a better path leading to them
 			if md, ok := openList[neighbor]; ok {
@@ -68,11 +124,16 @@ func (g *Graph) ShortestPathWithHeuristic(startKey, endKey string, heuristic fun
 				}
 			}
 
+			estimate := int64(cfg.heuristic(neighbor.key, end.key))
+			if addOverflows(distanceToNeighbor, estimate) || (cfg.checkOverflow && !fitsInt(distanceToNeighbor+estimate)) {
+				return nil, &WeightError{current.key, neighbor.key, weight, ErrCostOverflow}
+			}
+
 			item := &Item{
 				neighbor,
 				current,
 				distanceToNeighbor,
-				distanceToNeighbor + heuristic(neighbor.key, endKey), // estimate (= priority)
+				distanceToNeighbor + estimate, // estimate (= priority)
 				0,
 			}
 
@@ -82,7 +143,12 @@ func (g *Graph) ShortestPathWithHeuristic(startKey, endKey string, heuristic fun
 			// push into priority queue
 			heap.Push(openQueue, item)
 		}
+
+		// bound memory usage
+		if cfg.maxOpen > 0 && len(openList) > cfg.maxOpen {
+			return nil, ErrOpenListLimit
+		}
 	}
 
-	return
+	return nil, ErrNoPath
 }
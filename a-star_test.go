@@ -89,7 +89,7 @@ func TestShortestPathWithHeuristic(t *testing.T) {
 	}
 }
 
-func ExampleShortestPathWithHeuristic() {
+func ExampleGraph_ShortestPathWithHeuristic() {
 	g := New()
 
 	// set key → value pairs
@@ -0,0 +1,56 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// AllShortestPaths returns every path of minimum cost from the vertex with key startKey to the vertex with key endKey, in start → end order and sorted by their keys, e.g. for tie-aware routing.
+// Weights must be positive; their number may grow exponentially with the size of the graph. Returns ErrInvalidKey if one of the keys is invalid and ErrNoPath if there is no path.
+func (g *Graph) AllShortestPaths(startKey, endKey string) ([][]string, error) {
+	defer g.track("AllShortestPaths")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(startKey)
+	end := g.get(endKey)
+
+	if start == nil || end == nil {
+		return nil, ErrInvalidKey
+	}
+
+	vertices, index := g.indexVertices()
+	_, preds, sigma := g.shortestPathCounts(vertices, index, index[start])
+
+	if sigma[index[end]] == 0 {
+		return nil, ErrNoPath
+	}
+
+	// walk back from the end along all predecessors
+	var paths [][]string
+	var walk func(v int, suffix []string)
+	walk = func(v int, suffix []string) {
+		suffix = append(suffix, vertices[v].key)
+
+		if v == index[start] {
+			path := make([]string, len(suffix))
+			for i, key := range suffix {
+				path[len(suffix)-1-i] = key
+			}
+			paths = append(paths, path)
+			return
+		}
+
+		for _, p := range preds[v] {
+			walk(p, suffix[:len(suffix):len(suffix)])
+		}
+	}
+	walk(index[end], nil)
+
+	sort.Slice(paths, func(i, j int) bool {
+		return strings.Join(paths[i], "\x00") < strings.Join(paths[j], "\x00")
+	})
+
+	return paths, nil
+}
@@ -0,0 +1,42 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAllShortestPaths(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.Set(key, nil)
+	}
+
+	// three paths of cost 4 from a to e, one of cost 5
+	g.Connect("a", "b", 1)
+	g.Connect("a", "c", 1)
+	g.Connect("b", "d", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 2)
+	g.Connect("c", "e", 3)
+	g.Connect("a", "e", 5)
+
+	paths, err := g.AllShortestPaths("a", "e")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := [][]string{{"a", "b", "d", "e"}, {"a", "c", "d", "e"}, {"a", "c", "e"}}
+	if !reflect.DeepEqual(paths, expected) {
+		t.Errorf("expected %v, got %v", expected, paths)
+	}
+
+	if paths, err = g.AllShortestPaths("a", "a"); err != nil || !reflect.DeepEqual(paths, [][]string{{"a"}}) {
+		t.Fail()
+	}
+	if _, err = g.AllShortestPaths("a", "f"); err != ErrNoPath {
+		t.Fail()
+	}
+	if _, err = g.AllShortestPaths("a", "x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
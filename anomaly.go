@@ -0,0 +1,198 @@
+package graph
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// AlertType identifies the kind of anomaly described by an Alert.
+type AlertType int
+
+const (
+	// AlertDegreeSpike means a vertex gained an unusual number of new edges within a short time.
+	AlertDegreeSpike AlertType = iota
+
+	// AlertBridge means a new edge connected two previously disconnected components, treating edges as undirected.
+	AlertBridge
+
+	// AlertWeightOutlier means an edge was created or updated with a weight far from the average weight of the edges seen so far.
+	AlertWeightOutlier
+)
+
+// Alert describes an anomaly found by an AnomalyDetector.
+type Alert struct {
+	Type   AlertType
+	Key    string    // key of the vertex with a degree spike, or of the vertex the edge starts at
+	ToKey  string    // key of the vertex the edge ends at; not set for AlertDegreeSpike
+	Weight int       // weight of the edge; not set for AlertDegreeSpike
+	Edges  int       // number of new edges within the spike window; only set for AlertDegreeSpike
+	Time   time.Time // time the anomaly was detected
+}
+
+// AnomalyConfig configures the detectors of an AnomalyDetector. Detectors with zero thresholds are disabled.
+type AnomalyConfig struct {
+	// SpikeEdges is the number of new edges (incoming or outgoing) a vertex has to gain within SpikeWindow to raise an AlertDegreeSpike.
+	SpikeEdges  int
+	SpikeWindow time.Duration
+
+	// Bridges enables AlertBridge.
+	Bridges bool
+
+	// OutlierDeviations is the number of standard deviations a weight has to differ from the mean weight to raise an AlertWeightOutlier. No alerts are raised before OutlierMinSamples weights were seen.
+	OutlierDeviations float64
+	OutlierMinSamples int
+
+	// Now returns the current time. If it is nil, time.Now is used.
+	Now func() time.Time
+}
+
+// AnomalyDetector watches the mutations of a graph for structural anomalies, e.g. for security monitoring.
+type AnomalyDetector struct {
+	g      *Graph
+	config AnomalyConfig
+	alert  func(Alert)
+	cancel func()
+
+	edges      map[[2]string]struct{} // all edges of the graph
+	components *disjointSet           // weakly connected components, outdated if dirty
+	dirty      bool                   // set when edges are removed, since components can't be split
+
+	recent map[string][]time.Time // times new edges were added to a vertex within the spike window
+
+	samples  int     // number of weights seen
+	mean, m2 float64 // running mean and sum of squared differences from the mean of the weights (Welford's algorithm)
+
+	sync.Mutex
+}
+
+// NewAnomalyDetector starts watching g, calling alert for every anomaly found until Close is called.
+// alert is called synchronously while the graph is locked, so it must not call any of the graph's methods.
+// Bridge detection keeps track of the weakly connected components; removing edges or vertices makes the next new edge recompute them.
+func NewAnomalyDetector(g *Graph, config AnomalyConfig, alert func(Alert)) *AnomalyDetector {
+	if config.Now == nil {
+		config.Now = time.Now
+	}
+
+	d := &AnomalyDetector{
+		g:      g,
+		config: config,
+		alert:  alert,
+		edges:  map[[2]string]struct{}{},
+		recent: map[string][]time.Time{},
+	}
+
+	// take in the current edges and subscribe atomically, so no mutation is missed
+	g.Lock()
+	for key, v := range g.vertices {
+		for neighbor := range v.GetOutgoing() {
+			d.edges[[2]string{key, neighbor.key}] = struct{}{}
+		}
+	}
+	d.dirty = true
+	d.cancel = g.subscribe(d.observe)
+	g.Unlock()
+
+	return d
+}
+
+// Close stops watching the graph.
+func (d *AnomalyDetector) Close() {
+	d.cancel()
+}
+
+// observe is called for every event of the watched graph, while the graph is locked.
+func (d *AnomalyDetector) observe(e Event) {
+	d.Lock()
+	defer d.Unlock()
+
+	switch e.Type {
+	case EventDelete:
+		for edge := range d.edges {
+			if edge[0] == e.Key || edge[1] == e.Key {
+				delete(d.edges, edge)
+			}
+		}
+		delete(d.recent, e.Key)
+		d.dirty = true
+
+	case EventDisconnect:
+		delete(d.edges, [2]string{e.Key, e.ToKey})
+		d.dirty = true
+
+	case EventConnect:
+		now := d.config.Now()
+		edge := [2]string{e.Key, e.ToKey}
+
+		if _, ok := d.edges[edge]; !ok {
+			d.edges[edge] = struct{}{}
+
+			if d.config.Bridges && d.bridges(e.Key, e.ToKey) {
+				d.alert(Alert{Type: AlertBridge, Key: e.Key, ToKey: e.ToKey, Weight: e.Weight, Time: now})
+			}
+
+			if d.config.SpikeEdges > 0 {
+				d.spike(e.Key, now)
+				d.spike(e.ToKey, now)
+			}
+		}
+
+		if d.config.OutlierDeviations > 0 {
+			d.outlier(e, now)
+		}
+	}
+}
+
+// bridges merges the components of the vertices with the given keys and returns true if they were different. Does NOT lock the detector.
+func (d *AnomalyDetector) bridges(fromKey, toKey string) bool {
+	// the graph is locked by the mutation being observed
+	from, to := d.g.get(fromKey), d.g.get(toKey)
+
+	if d.dirty {
+		d.components = newDisjointSet()
+		for edge := range d.edges {
+			if edge == [2]string{fromKey, toKey} {
+				continue
+			}
+			if a, b := d.g.get(edge[0]), d.g.get(edge[1]); a != nil && b != nil {
+				d.components.union(a, b)
+			}
+		}
+		d.dirty = false
+	}
+
+	return d.components.union(from, to)
+}
+
+// spike records a new edge at the vertex with the given key and raises an alert if it reaches the threshold. Does NOT lock the detector.
+func (d *AnomalyDetector) spike(key string, now time.Time) {
+	// forget edges outside of the window
+	times := d.recent[key]
+	for len(times) > 0 && now.Sub(times[0]) > d.config.SpikeWindow {
+		times = times[1:]
+	}
+	times = append(times, now)
+	d.recent[key] = times
+
+	// alert once when the threshold is reached, not for every further edge
+	if len(times) == d.config.SpikeEdges {
+		d.alert(Alert{Type: AlertDegreeSpike, Key: key, Edges: len(times), Time: now})
+	}
+}
+
+// outlier checks the weight of a created or updated edge against the weights seen so far and adds it to them. Does NOT lock the detector.
+func (d *AnomalyDetector) outlier(e Event, now time.Time) {
+	w := float64(e.Weight)
+
+	if d.samples >= d.config.OutlierMinSamples && d.samples > 1 {
+		deviation := math.Sqrt(d.m2 / float64(d.samples-1))
+		if math.Abs(w-d.mean) > d.config.OutlierDeviations*deviation {
+			d.alert(Alert{Type: AlertWeightOutlier, Key: e.Key, ToKey: e.ToKey, Weight: e.Weight, Time: now})
+		}
+	}
+
+	d.samples++
+	delta := w - d.mean
+	d.mean += delta / float64(d.samples)
+	d.m2 += delta * (w - d.mean)
+}
@@ -0,0 +1,115 @@
+package graph
+
+import (
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestAnomalyDetectorBridges(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("c", "d", 1)
+
+	var alerts []Alert
+	d := NewAnomalyDetector(g, AnomalyConfig{Bridges: true}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+	defer d.Close()
+
+	// within a component
+	g.Connect("b", "a", 1)
+	if len(alerts) != 0 {
+		t.Fatal("unexpected alert")
+	}
+
+	g.Connect("b", "c", 1)
+	if len(alerts) != 1 || alerts[0].Type != AlertBridge || alerts[0].Key != "b" || alerts[0].ToKey != "c" {
+		t.Fatalf("expected bridge alert, got %v", alerts)
+	}
+
+	// updating the weight is not a new edge
+	g.Connect("b", "c", 2)
+	if len(alerts) != 1 {
+		t.Fail()
+	}
+
+	// splitting the components and connecting them again
+	g.Disconnect("b", "c")
+	g.Connect("d", "a", 1)
+	if len(alerts) != 2 || alerts[1].Key != "d" {
+		t.Fatalf("expected second bridge alert, got %v", alerts)
+	}
+}
+
+func TestAnomalyDetectorDegreeSpike(t *testing.T) {
+	g := New()
+	g.Set("hub", nil)
+	for i := 0; i < 10; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	var alerts []Alert
+	d := NewAnomalyDetector(g, AnomalyConfig{
+		SpikeEdges:  3,
+		SpikeWindow: time.Minute,
+		Now:         func() time.Time { return now },
+	}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+	defer d.Close()
+
+	// slow growth
+	for i := 0; i < 4; i++ {
+		g.Connect("hub", strconv.Itoa(i), 1)
+		now = now.Add(time.Hour)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("unexpected alerts %v", alerts)
+	}
+
+	// burst
+	for i := 4; i < 10; i++ {
+		g.Connect(strconv.Itoa(i), "hub", 1)
+		now = now.Add(time.Second)
+	}
+	if len(alerts) != 1 || alerts[0].Type != AlertDegreeSpike || alerts[0].Key != "hub" || alerts[0].Edges != 3 {
+		t.Fatalf("expected one spike alert, got %v", alerts)
+	}
+}
+
+func TestAnomalyDetectorWeightOutlier(t *testing.T) {
+	g := New()
+	for i := 0; i < 12; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+
+	var alerts []Alert
+	d := NewAnomalyDetector(g, AnomalyConfig{OutlierDeviations: 3, OutlierMinSamples: 5}, func(a Alert) {
+		alerts = append(alerts, a)
+	})
+
+	for i := 0; i < 10; i++ {
+		g.Connect(strconv.Itoa(i), strconv.Itoa(i+1), 10+i%3)
+	}
+	if len(alerts) != 0 {
+		t.Fatalf("unexpected alerts %v", alerts)
+	}
+
+	g.Connect("10", "11", 1000)
+	if len(alerts) != 1 || alerts[0].Type != AlertWeightOutlier || alerts[0].Weight != 1000 {
+		t.Fatalf("expected outlier alert, got %v", alerts)
+	}
+
+	// no more alerts after closing
+	d.Close()
+	g.Connect("11", "10", 5000)
+	if len(alerts) != 1 {
+		t.Fail()
+	}
+}
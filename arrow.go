@@ -0,0 +1,284 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// arrowBatchSize is the maximum number of rows of the record batches written by the Arrow exporters.
+const arrowBatchSize = 1 << 16
+
+// Constants of the Arrow IPC format, see Message.fbs and Schema.fbs in the Arrow repository.
+const (
+	arrowContinuation = 0xFFFFFFFF
+	arrowVersionV5    = 4
+
+	arrowHeaderSchema      = 1
+	arrowHeaderRecordBatch = 3
+
+	arrowTypeInt  = 2
+	arrowTypeUtf8 = 5
+)
+
+// WriteArrowVertices writes the vertex table of the graph to w as an Arrow IPC stream, e.g. for pyarrow.ipc.open_stream or DuckDB: a key column of strings and a nullable value column of the values encoded as JSON strings, with nil values as nulls, in key order and in record batches of up to 65536 rows.
+// Values are encoded with encoding/json, so they must be marshalable. The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) WriteArrowVertices(w io.Writer) error {
+	defer g.track("WriteArrowVertices")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	aw := &arrowWriter{w: bufio.NewWriter(w)}
+	aw.schema(vertexColumns())
+	if err := g.columnarVertices(arrowBatchSize, aw.batch); err != nil {
+		return fmt.Errorf("graph: encoding Arrow: %v", err)
+	}
+
+	return aw.end()
+}
+
+// WriteArrowEdges writes the edge table of the graph to w as an Arrow IPC stream: from and to columns of strings and a weight column of 64 bit integers, sorted by keys and in record batches of up to 65536 rows.
+// The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) WriteArrowEdges(w io.Writer) error {
+	defer g.track("WriteArrowEdges")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	aw := &arrowWriter{w: bufio.NewWriter(w)}
+	aw.schema(edgeColumns())
+	if err := g.columnarEdges(arrowBatchSize, aw.batch); err != nil {
+		return fmt.Errorf("graph: encoding Arrow: %v", err)
+	}
+
+	return aw.end()
+}
+
+// arrowWriter writes the messages of an Arrow IPC stream.
+type arrowWriter struct {
+	w *bufio.Writer
+}
+
+// message writes an encapsulated message: the continuation marker, the length of the metadata, the Message FlatBuffer padded to 8 bytes, and the body.
+func (aw *arrowWriter) message(headerType uint64, header func(fb *flatBuilder) int, body []byte) {
+	fb := &flatBuilder{}
+	fb.uint32(0) // offset of the root table, set below
+
+	root := fb.table(
+		flatField{size: 2, value: arrowVersionV5},
+		flatField{size: 1, value: headerType},
+		flatField{child: func() int { return header(fb) }},
+		flatField{size: 8, value: uint64(len(body))},
+	)
+	fb.setUint32(0, uint32(root))
+	fb.align(8, 0)
+
+	prefix := &flatBuilder{}
+	prefix.uint32(arrowContinuation, uint32(len(fb.buf)))
+
+	aw.w.Write(prefix.buf)
+	aw.w.Write(fb.buf)
+	aw.w.Write(body)
+}
+
+// schema writes the Schema message for columns.
+func (aw *arrowWriter) schema(columns []*columnarColumn) {
+	aw.message(arrowHeaderSchema, func(fb *flatBuilder) int {
+		return fb.table(
+			flatField{size: 2, value: 0}, // little endian
+			flatField{child: func() int {
+				return fb.tableVector(len(columns), func(i int) int {
+					c := columns[i]
+
+					typeType := uint64(arrowTypeUtf8)
+					typ := func() int { return fb.table() }
+					if c.integer {
+						typeType = arrowTypeInt
+						typ = func() int {
+							return fb.table(flatField{size: 4, value: 64}, flatField{size: 1, value: 1})
+						}
+					}
+
+					nullable := uint64(0)
+					if c.nullable {
+						nullable = 1
+					}
+
+					return fb.table(
+						flatField{child: func() int { return fb.flatString(c.name) }},
+						flatField{size: 1, value: nullable},
+						flatField{size: 1, value: typeType},
+						flatField{child: typ},
+						flatField{}, // dictionary
+						flatField{child: func() int { return fb.tableVector(0, nil) }},
+					)
+				})
+			}},
+		)
+	}, nil)
+}
+
+// batch writes a RecordBatch message with the rows of columns.
+func (aw *arrowWriter) batch(rows int, columns []*columnarColumn) error {
+	var body []byte
+	var nodes, buffers [][2]int64
+
+	// buffer appends b to the body as the next buffer, padded to 8 bytes
+	buffer := func(b []byte) {
+		buffers = append(buffers, [2]int64{int64(len(body)), int64(len(b))})
+		body = append(body, b...)
+		for len(body)%8 != 0 {
+			body = append(body, 0)
+		}
+	}
+
+	for _, c := range columns {
+		nulls := c.nulls()
+		nodes = append(nodes, [2]int64{int64(rows), int64(nulls)})
+
+		// the validity bitmap can be left out if all rows have values
+		var validity []byte
+		if nulls > 0 {
+			validity = make([]byte, (rows+7)/8)
+			for i, valid := range c.valid {
+				if valid {
+					validity[i/8] |= 1 << uint(i%8)
+				}
+			}
+		}
+		buffer(validity)
+
+		if c.integer {
+			data := make([]byte, 0, 8*rows)
+			for _, i := range c.ints {
+				data = appendLittleEndian(data, uint64(i), 8)
+			}
+			buffer(data)
+			continue
+		}
+
+		offsets := make([]byte, 0, 4*(rows+1))
+		var data []byte
+		offsets = appendLittleEndian(offsets, 0, 4)
+		for i, s := range c.strings {
+			if c.valid == nil || c.valid[i] {
+				data = append(data, s...)
+			}
+			if len(data) > math.MaxInt32 {
+				return fmt.Errorf("column %q exceeds 2 GiB in a batch", c.name)
+			}
+			offsets = appendLittleEndian(offsets, uint64(len(data)), 4)
+		}
+		buffer(offsets)
+		buffer(data)
+	}
+
+	aw.message(arrowHeaderRecordBatch, func(fb *flatBuilder) int {
+		return fb.table(
+			flatField{size: 8, value: uint64(rows)},
+			flatField{child: func() int { return fb.structVector(nodes) }},
+			flatField{child: func() int { return fb.structVector(buffers) }},
+		)
+	}, body)
+
+	return nil
+}
+
+// end writes the end-of-stream marker and flushes the stream.
+func (aw *arrowWriter) end() error {
+	eos := &flatBuilder{}
+	eos.uint32(arrowContinuation, 0)
+	aw.w.Write(eos.buf)
+
+	return aw.w.Flush()
+}
+
+// flatField is a field of a table written by flatBuilder.table: a scalar value of size bytes, an offset to the object written by child, which returns its position, or absent if both are zero.
+type flatField struct {
+	size  int
+	value uint64
+	child func() int
+}
+
+// table writes a table with fields in slot order, preceded by its own vtable, followed by the objects its offset fields refer to, and returns its position. Scalars are laid out by decreasing size, so they are aligned.
+func (fb *flatBuilder) table(fields ...flatField) int {
+	offsets := make([]int, len(fields))
+	size, aligned8 := 4, false
+	for _, n := range []int{8, 4, 2, 1} {
+		for i, f := range fields {
+			if f.size == n || (n == 4 && f.child != nil) {
+				offsets[i] = size
+				size += n
+				aligned8 = aligned8 || n == 8
+			}
+		}
+	}
+
+	fb.align(2, 0)
+	vtable := len(fb.buf)
+	fb.uint16(uint16(4+2*len(fields)), uint16(size))
+	for _, offset := range offsets {
+		fb.uint16(uint16(offset))
+	}
+
+	// 8 byte scalars start right after the vtable offset
+	if aligned8 {
+		fb.align(8, 4)
+	} else {
+		fb.align(4, 0)
+	}
+	table := len(fb.buf)
+	fb.uint32(uint32(table - vtable))
+	fb.buf = append(fb.buf, make([]byte, size-4)...)
+
+	for i, f := range fields {
+		if f.child == nil && f.size > 0 {
+			appendLittleEndian(fb.buf[table+offsets[i]:table+offsets[i]], f.value, f.size)
+		}
+	}
+	for i, f := range fields {
+		if f.child != nil {
+			fb.setOffset(table+offsets[i], f.child())
+		}
+	}
+
+	return table
+}
+
+// flatString writes a string and returns its position.
+func (fb *flatBuilder) flatString(s string) int {
+	fb.align(4, 0)
+	pos := len(fb.buf)
+	fb.uint32(uint32(len(s)))
+	fb.buf = append(fb.buf, s...)
+	fb.buf = append(fb.buf, 0)
+	return pos
+}
+
+// tableVector writes a vector of n tables written by element, which returns their positions, and returns its position.
+func (fb *flatBuilder) tableVector(n int, element func(i int) int) int {
+	fb.align(4, 0)
+	pos := len(fb.buf)
+	fb.uint32(uint32(n))
+	for i := 0; i < n; i++ {
+		fb.uint32(0)
+	}
+	for i := 0; i < n; i++ {
+		fb.setOffset(pos+4+4*i, element(i))
+	}
+	return pos
+}
+
+// structVector writes a vector of structs of two 64 bit integers and returns its position.
+func (fb *flatBuilder) structVector(structs [][2]int64) int {
+	fb.align(8, 4)
+	pos := len(fb.buf)
+	fb.uint32(uint32(len(structs)))
+	for _, s := range structs {
+		fb.buf = appendLittleEndian(fb.buf, uint64(s[0]), 8)
+		fb.buf = appendLittleEndian(fb.buf, uint64(s[1]), 8)
+	}
+	return pos
+}
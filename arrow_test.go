@@ -0,0 +1,202 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// readArrowStream decodes an Arrow IPC stream of string and 64 bit integer columns, checking the framing and alignment, and returns the field names, the type of each field and all rows.
+func readArrowStream(t *testing.T, b []byte) (names []string, types []string, rows [][]interface{}) {
+	t.Helper()
+
+	for pos := 0; ; {
+		if pos%8 != 0 || pos+8 > len(b) {
+			t.Fatalf("message at %d: misaligned or truncated", pos)
+		}
+		prefix := &FlatGraph{buf: b[pos : pos+8]}
+		if prefix.uint32(0) != arrowContinuation {
+			t.Fatalf("message at %d: missing continuation marker", pos)
+		}
+		n := int(prefix.uint32(4))
+		if n == 0 {
+			if pos+8 != len(b) {
+				t.Fatalf("data after the end of stream marker")
+			}
+			return
+		}
+		if n%8 != 0 || pos+8+n > len(b) {
+			t.Fatalf("message at %d: invalid metadata length %d", pos, n)
+		}
+
+		f := &FlatGraph{buf: b[pos+8 : pos+8+n]}
+		msg := int(f.uint32(0))
+		if version := f.uint16(f.field(msg, 0)); version != arrowVersionV5 {
+			t.Fatalf("message at %d: version %d", pos, version)
+		}
+		if f.field(msg, 3)%8 != 0 {
+			t.Fatalf("message at %d: misaligned body length", pos)
+		}
+		bodyLength := int(f.uint64(f.field(msg, 3)))
+		header := f.deref(f.field(msg, 2))
+		body := b[pos+8+n : pos+8+n+bodyLength]
+
+		switch headerType := f.buf[f.field(msg, 1)]; headerType {
+		case arrowHeaderSchema:
+			fields := f.deref(f.field(header, 1))
+			for i := 0; i < int(f.uint32(fields)); i++ {
+				field := f.deref(fields + 4 + 4*i)
+				names = append(names, string(f.bytes(f.deref(f.field(field, 0)))))
+
+				if f.field(field, 5) == 0 {
+					t.Errorf("field %d: missing children", i)
+				}
+
+				nullable := f.buf[f.field(field, 1)] == 1
+				typ := f.deref(f.field(field, 3))
+				switch f.buf[f.field(field, 2)] {
+				case arrowTypeUtf8:
+					types = append(types, fmt.Sprintf("utf8 nullable=%v", nullable))
+				case arrowTypeInt:
+					types = append(types, fmt.Sprintf("int%d signed=%v nullable=%v", f.uint32(f.field(typ, 0)), f.buf[f.field(typ, 1)] == 1, nullable))
+				default:
+					t.Fatalf("field %d: unexpected type", i)
+				}
+			}
+
+		case arrowHeaderRecordBatch:
+			if f.field(header, 0)%8 != 0 {
+				t.Fatalf("message at %d: misaligned length", pos)
+			}
+			length := int(f.uint64(f.field(header, 0)))
+			nodes := f.deref(f.field(header, 1))
+			buffers := f.deref(f.field(header, 2))
+			if (nodes+4)%8 != 0 || (buffers+4)%8 != 0 {
+				t.Fatalf("message at %d: misaligned structs", pos)
+			}
+
+			buffer := func(i int) []byte {
+				offset, size := int(f.uint64(buffers+4+16*i)), int(f.uint64(buffers+12+16*i))
+				if offset%8 != 0 {
+					t.Fatalf("buffer %d: misaligned", i)
+				}
+				return body[offset : offset+size]
+			}
+
+			batch := make([][]interface{}, length)
+			next := 0
+			for column := range types {
+				if nodeLength := int(f.uint64(nodes + 4 + 16*column)); nodeLength != length {
+					t.Fatalf("column %d: length %d, expected %d", column, nodeLength, length)
+				}
+				nulls := int(f.uint64(nodes + 12 + 16*column))
+
+				validity := buffer(next)
+				if (nulls == 0) != (len(validity) == 0) {
+					t.Fatalf("column %d: %d nulls, but validity bitmap of %d bytes", column, nulls, len(validity))
+				}
+
+				if types[column][0] == 'i' {
+					data := &FlatGraph{buf: buffer(next + 1)}
+					for row := range batch {
+						batch[row] = append(batch[row], int64(data.uint64(8*row)))
+					}
+					next += 2
+					continue
+				}
+
+				offsets := &FlatGraph{buf: buffer(next + 1)}
+				data := buffer(next + 2)
+				for row := range batch {
+					if len(validity) > 0 && validity[row/8]&(1<<uint(row%8)) == 0 {
+						batch[row] = append(batch[row], nil)
+						continue
+					}
+					batch[row] = append(batch[row], string(data[offsets.uint32(4*row):offsets.uint32(4*row+4)]))
+				}
+				next += 3
+			}
+			rows = append(rows, batch...)
+
+		default:
+			t.Fatalf("message at %d: unexpected header type %d", pos, headerType)
+		}
+
+		pos += 8 + n + bodyLength
+	}
+}
+
+func TestWriteArrow(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", nil)
+	g.Set("c", map[string]interface{}{"x": "y"})
+
+	g.Connect("a", "b", 5)
+	g.Connect("a", "c", -1)
+	g.Connect("c", "a", 1<<40)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteArrowVertices(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	names, types, rows := readArrowStream(t, buf.Bytes())
+	if !reflect.DeepEqual(names, []string{"key", "value"}) || !reflect.DeepEqual(types, []string{"utf8 nullable=false", "utf8 nullable=true"}) {
+		t.Errorf("unexpected schema %v %v", names, types)
+	}
+	expected := [][]interface{}{{"a", "1"}, {"b", nil}, {"c", `{"x":"y"}`}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+
+	buf.Reset()
+	if err := g.WriteArrowEdges(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	names, types, rows = readArrowStream(t, buf.Bytes())
+	if !reflect.DeepEqual(names, []string{"from", "to", "weight"}) || !reflect.DeepEqual(types, []string{"utf8 nullable=false", "utf8 nullable=false", "int64 signed=true nullable=false"}) {
+		t.Errorf("unexpected schema %v %v", names, types)
+	}
+	expected = [][]interface{}{{"a", "b", int64(5)}, {"a", "c", int64(-1)}, {"c", "a", int64(1 << 40)}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+
+	// empty graphs have a schema and no batches
+	buf.Reset()
+	if err := New().WriteArrowEdges(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if names, _, rows := readArrowStream(t, buf.Bytes()); len(names) != 3 || len(rows) != 0 {
+		t.Errorf("expected schema only, got %v %v", names, rows)
+	}
+
+	// unmarshalable values fail
+	g.Set("b", func() {})
+	if err := g.WriteArrowVertices(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unmarshalable value")
+	}
+}
+
+func TestWriteArrowBatches(t *testing.T) {
+	g := New()
+	for i := 0; i < arrowBatchSize+10; i++ {
+		g.Set(fmt.Sprintf("%06d", i), nil)
+	}
+	for i := 1; i < arrowBatchSize+10; i++ {
+		g.Connect(fmt.Sprintf("%06d", i), "000000", i)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteArrowEdges(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, _, rows := readArrowStream(t, buf.Bytes())
+	if len(rows) != arrowBatchSize+9 || rows[arrowBatchSize][2] != int64(arrowBatchSize+1) {
+		t.Errorf("expected %d rows across batches, got %d", arrowBatchSize+9, len(rows))
+	}
+}
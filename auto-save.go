@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// SnapshotSink persists the snapshots taken by AutoSave.
+type SnapshotSink interface {
+	// SaveSnapshot persists snapshot, a copy of the graph which isn't changed while it is saved.
+	SaveSnapshot(snapshot *Graph) error
+}
+
+// SnapshotSinkFunc adapts a function to a SnapshotSink.
+type SnapshotSinkFunc func(snapshot *Graph) error
+
+// SaveSnapshot calls f(snapshot).
+func (f SnapshotSinkFunc) SaveSnapshot(snapshot *Graph) error {
+	return f(snapshot)
+}
+
+// SnapshotFile returns a sink writing snapshots to the file at path with Snapshot, replacing the previous one atomically.
+func SnapshotFile(path string, opts ...SnapshotOption) SnapshotSink {
+	return SnapshotSinkFunc(func(snapshot *Graph) error {
+		return snapshot.Snapshot(path, opts...)
+	})
+}
+
+// AutoSaveOption configures AutoSave.
+type AutoSaveOption func(*autoSaveConfig)
+
+// autoSaveConfig holds the settings of AutoSave.
+type autoSaveConfig struct {
+	hook func(d time.Duration, err error)
+}
+
+// AutoSaveHook makes AutoSave call fn after every save with its duration and the error returned by the sink, e.g. to collect metrics or log failures.
+func AutoSaveHook(fn func(d time.Duration, err error)) AutoSaveOption {
+	return func(cfg *autoSaveConfig) {
+		cfg.hook = fn
+	}
+}
+
+// AutoSave saves the graph to sink every interval in the background, if it changed since the last save. Each save copies the graph while it is locked for reading, which is quick, and passes the copy to sink afterwards, so writers aren't blocked while it is serialized. A failed save is retried after the next interval.
+// The returned function stops saving and saves a last time if the graph changed, returning the error of that save.
+func (g *Graph) AutoSave(interval time.Duration, sink SnapshotSink, opts ...AutoSaveOption) (stop func() error) {
+	defer g.track("AutoSave")()
+
+	cfg := &autoSaveConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// the first interval saves the graph as it is, later ones only if it changed
+	changed := int32(1)
+
+	g.Lock()
+	cancel := g.subscribe(func(Event) {
+		atomic.StoreInt32(&changed, 1)
+	})
+	g.Unlock()
+
+	save := func() error {
+		if atomic.SwapInt32(&changed, 0) == 0 {
+			return nil
+		}
+
+		start := time.Now()
+
+		g.RLock()
+		c := g.clone()
+		g.RUnlock()
+
+		err := sink.SaveSnapshot(c)
+		if err != nil {
+			atomic.StoreInt32(&changed, 1)
+		}
+
+		if cfg.hook != nil {
+			cfg.hook(time.Since(start), err)
+		}
+
+		return err
+	}
+
+	ticker := time.NewTicker(interval)
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+
+	go func() {
+		defer close(stopped)
+
+		for {
+			select {
+			case <-ticker.C:
+				save()
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() error {
+		var err error
+		once.Do(func() {
+			ticker.Stop()
+			close(done)
+			<-stopped
+
+			cancel()
+			err = save()
+		})
+		return err
+	}
+}
@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAutoSave(t *testing.T) {
+	var m sync.Mutex
+	var saved []int
+	var hooked []error
+
+	g := New()
+	g.Set("a", 1)
+
+	sink := SnapshotSinkFunc(func(snapshot *Graph) error {
+		m.Lock()
+		defer m.Unlock()
+		saved = append(saved, snapshot.Len())
+		if len(saved) == 2 {
+			return errors.New("disk full")
+		}
+		return nil
+	})
+	hook := AutoSaveHook(func(d time.Duration, err error) {
+		m.Lock()
+		hooked = append(hooked, err)
+		m.Unlock()
+	})
+
+	stop := g.AutoSave(5*time.Millisecond, sink, hook)
+
+	// wait for a number of saves
+	wait := func(n int) {
+		deadline := time.Now().Add(5 * time.Second)
+		for time.Now().Before(deadline) {
+			m.Lock()
+			done := len(saved) >= n
+			m.Unlock()
+			if done {
+				return
+			}
+			time.Sleep(time.Millisecond)
+		}
+		t.Fatalf("timed out waiting for save %d", n)
+	}
+
+	wait(1)
+	g.Set("b", 2)
+	wait(2)
+
+	// the failed save is retried, even without changes
+	wait(3)
+
+	// unchanged graphs aren't saved again
+	time.Sleep(20 * time.Millisecond)
+	m.Lock()
+	if len(saved) != 3 {
+		t.Errorf("expected 3 saves of the unchanged graph, got %d", len(saved))
+	}
+	m.Unlock()
+
+	// stopping saves the last changes
+	g.Set("c", 3)
+	if err := stop(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := stop(); err != nil {
+		t.Fatalf("expected stopping twice to succeed, got %v", err)
+	}
+	g.Set("d", 4)
+	time.Sleep(20 * time.Millisecond)
+
+	m.Lock()
+	defer m.Unlock()
+	if len(saved) != 4 || saved[0] != 1 || saved[3] != 3 {
+		t.Errorf("unexpected saves %v", saved)
+	}
+	if len(hooked) != 4 || hooked[1] == nil || hooked[2] != nil {
+		t.Errorf("unexpected hook calls %v", hooked)
+	}
+}
+
+func TestSnapshotFileSink(t *testing.T) {
+	dir, err := ioutil.TempDir("", "autosave")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.snapshot")
+
+	g := New()
+	g.Set("a", 1)
+
+	stop := g.AutoSave(time.Hour, SnapshotFile(path, SnapshotCompress(SnapshotGzip)))
+	if err := stop(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	h, err := LoadSnapshot(path)
+	if err != nil || h.Len() != 1 {
+		t.Errorf("expected the saved graph, got %v", err)
+	}
+}
@@ -0,0 +1,204 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrSelfLoop is returned when an edge would connect a vertex to itself.
+var ErrSelfLoop = errors.New("graph: edge from a vertex to itself")
+
+// Batch is a list of mutations, each described by the Event that would be emitted for it.
+type Batch []Event
+
+// Set adds the creation or update of a vertex to the batch.
+func (b *Batch) Set(key string, value interface{}) {
+	*b = append(*b, Event{Type: EventSet, Key: key, Value: value})
+}
+
+// Delete adds the deletion of a vertex to the batch.
+func (b *Batch) Delete(key string) {
+	*b = append(*b, Event{Type: EventDelete, Key: key})
+}
+
+// Connect adds the creation of an edge to the batch.
+func (b *Batch) Connect(fromKey, toKey string, weight int) {
+	*b = append(*b, Event{Type: EventConnect, Key: fromKey, ToKey: toKey, Weight: weight})
+}
+
+// Disconnect adds the removal of an edge to the batch.
+func (b *Batch) Disconnect(fromKey, toKey string) {
+	*b = append(*b, Event{Type: EventDisconnect, Key: fromKey, ToKey: toKey})
+}
+
+// SetEdgeAttr adds setting an attribute of an edge to the batch.
+func (b *Batch) SetEdgeAttr(fromKey, toKey, name string, value interface{}) {
+	*b = append(*b, Event{Type: EventSetEdgeAttr, Key: fromKey, ToKey: toKey, Attr: name, Value: value})
+}
+
+// DeleteEdgeAttr adds the removal of an attribute of an edge to the batch.
+func (b *Batch) DeleteEdgeAttr(fromKey, toKey, name string) {
+	*b = append(*b, Event{Type: EventDeleteEdgeAttr, Key: fromKey, ToKey: toKey, Attr: name})
+}
+
+// BatchError describes why a mutation of a batch is invalid.
+type BatchError struct {
+	Index    int   // position of the mutation in the batch
+	Mutation Event // the invalid mutation
+	Err      error // the reason, e.g. ErrInvalidKey
+}
+
+func (e *BatchError) Error() string {
+	return fmt.Sprintf("graph: batch mutation %d: %v", e.Index, e.Err)
+}
+
+// Validate checks if all mutations in the batch could be applied to the graph in order, without applying anything.
+// Mutations referring to vertices that neither exist in the graph nor are created by earlier mutations of the batch (or that were deleted by earlier mutations) are invalid, as are edges from a vertex to itself unless self-loops are enabled, see EnableSelfLoops. Likewise, edge attributes can only be set or deleted on edges which exist or are connected by earlier mutations.
+// It returns a *BatchError for every invalid mutation; the result is empty if the batch is valid.
+func (g *Graph) Validate(batch Batch) (errs []error) {
+	defer g.track("Validate")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	// simulate the changes to the unique index, if any
+	unique := g.unique.copy()
+
+	// existence of the vertices touched by the batch so far
+	exists := map[string]bool{}
+	valid := func(key string) bool {
+		if e, ok := exists[key]; ok {
+			return e
+		}
+		return g.get(key) != nil
+	}
+
+	// existence of the edges touched by the batch so far; edges of deleted vertices don't exist anymore, even if the vertex is created again
+	edges := map[[2]string]bool{}
+	deleted := map[string]bool{}
+	connected := func(fromKey, toKey string) bool {
+		if e, ok := edges[[2]string{fromKey, toKey}]; ok {
+			return e
+		}
+		if deleted[fromKey] || deleted[toKey] {
+			return false
+		}
+
+		fromV, toV := g.get(fromKey), g.get(toKey)
+		if fromV == nil || toV == nil {
+			return false
+		}
+
+		fromV.RLock()
+		defer fromV.RUnlock()
+
+		_, ok := fromV.outgoingEdges[toV]
+		return ok
+	}
+
+	for i, m := range batch {
+		var err error
+
+		switch m.Type {
+		case EventSet:
+			if !unique.set(m.Key, m.Value) {
+				err = ErrDuplicateValue
+				break
+			}
+			exists[m.Key] = true
+
+		case EventDelete:
+			if !valid(m.Key) {
+				err = ErrInvalidKey
+			} else if g.isProtected(g.get(m.Key)) {
+				err = ErrProtected
+			}
+			exists[m.Key] = false
+			unique.remove(m.Key)
+
+			deleted[m.Key] = true
+			for edge := range edges {
+				if edge[0] == m.Key || edge[1] == m.Key {
+					delete(edges, edge)
+				}
+			}
+
+		case EventConnect, EventDisconnect:
+			if m.Key == m.ToKey && !g.selfLoops {
+				err = ErrSelfLoop
+			} else if !valid(m.Key) || !valid(m.ToKey) {
+				err = ErrInvalidKey
+			} else {
+				edges[[2]string{m.Key, m.ToKey}] = m.Type == EventConnect
+			}
+
+		case EventSetEdgeAttr, EventDeleteEdgeAttr:
+			if !valid(m.Key) || !valid(m.ToKey) {
+				err = ErrInvalidKey
+			} else if !connected(m.Key, m.ToKey) {
+				err = ErrNoEdge
+			}
+
+		default:
+			err = fmt.Errorf("unknown mutation type %d", m.Type)
+		}
+
+		if err != nil {
+			errs = append(errs, &BatchError{i, m, err})
+		}
+	}
+
+	return
+}
+
+// Apply validates the batch and, if it is valid, applies all its mutations in order. Otherwise, nothing is applied and the validation errors are returned.
+// Mutations are applied one by one, so other goroutines may observe or interleave with a partially applied batch; mutations made invalid by them are skipped and reported.
+func (g *Graph) Apply(batch Batch) (errs []error) {
+	defer g.track("Apply")()
+
+	if errs = g.Validate(batch); len(errs) > 0 {
+		return
+	}
+
+	for i, m := range batch {
+		if !g.apply(m) {
+			err := ErrInvalidKey
+			switch {
+			case m.Type == EventSet:
+				err = ErrDuplicateValue
+			case m.Type == EventDelete && g.IsProtected(m.Key):
+				err = ErrProtected
+			case m.Type == EventSetEdgeAttr || m.Type == EventDeleteEdgeAttr:
+				_, err = g.GetEdge(m.Key, m.ToKey)
+				if err == nil {
+					err = ErrNoEdge
+				}
+			}
+
+			errs = append(errs, &BatchError{i, m, err})
+		}
+	}
+
+	return
+}
+
+// apply performs the mutation described by e. Returns false if it failed.
+func (g *Graph) apply(e Event) bool {
+	switch e.Type {
+	case EventSet:
+		_, err := g.TrySet(e.Key, e.Value)
+		return err == nil
+	case EventDelete:
+		return g.Delete(e.Key)
+	case EventConnect:
+		return g.Connect(e.Key, e.ToKey, e.Weight)
+	case EventDisconnect:
+		return g.Disconnect(e.Key, e.ToKey)
+	case EventSetEdgeAttr:
+		return g.SetEdgeAttr(e.Key, e.ToKey, e.Attr, e.Value) == nil
+	case EventDeleteEdgeAttr:
+		return g.DeleteEdgeAttr(e.Key, e.ToKey, e.Attr) == nil
+	}
+
+	return false
+}
@@ -0,0 +1,59 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestValidate(t *testing.T) {
+	g := New()
+	g.Set("1", nil)
+	g.Set("2", nil)
+
+	b := Batch{}
+	b.Set("3", nil)
+	b.Connect("1", "3", 1) // valid, "3" is created by the batch
+	b.Delete("2")
+	b.Connect("1", "2", 1) // invalid, "2" was deleted by the batch
+	b.Connect("1", "1", 1) // invalid, self loop
+	b.Disconnect("4", "1") // invalid, "4" doesn't exist
+	b.Delete("5")          // invalid, "5" doesn't exist
+
+	errs := g.Validate(b)
+	if len(errs) != 4 {
+		t.Fatalf("expected 4 errors, got %v", errs)
+	}
+
+	expected := []struct {
+		index int
+		err   error
+	}{{3, ErrInvalidKey}, {4, ErrSelfLoop}, {5, ErrInvalidKey}, {6, ErrInvalidKey}}
+
+	for i, e := range expected {
+		batchErr := errs[i].(*BatchError)
+		if batchErr.Index != e.index || batchErr.Err != e.err || batchErr.Mutation != b[e.index] {
+			t.Errorf("unexpected error %v", errs[i])
+		}
+	}
+
+	// nothing was applied
+	if g.Len() != 2 {
+		t.Fail()
+	}
+
+	// invalid batches are not applied
+	if errs = g.Apply(b); len(errs) != 4 || g.Len() != 2 {
+		t.Fail()
+	}
+
+	b = b[:3]
+	if errs = g.Apply(b); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	if g.Len() != 2 {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("1", "3"); !ok {
+		t.Fail()
+	}
+}
@@ -0,0 +1,175 @@
+package graph
+
+import (
+	"container/heap"
+)
+
+// ShortestPathBidirectional returns the shortest path from the vertex with key startKey to the vertex with key endKey as a string slice in start → end order, and if such a path exists at all.
+// It runs two A* searches simultaneously, one forward from the start vertex along outgoing edges and one backward from the end vertex along incoming edges, until they meet in the middle.
+// The heuristic function is passed the keys of two vertices and has to estimate the distance from the first to the second one: the forward search calls it as heuristic(key, endKey), the backward search as heuristic(startKey, key).
+// For the path to be the shortest one, the heuristic must never overestimate the distance and must be consistent.
+func (g *Graph) ShortestPathBidirectional(startKey, endKey string, heuristic func(key, endKey string) int) (path []string, exists bool) {
+	defer g.track("ShortestPathBidirectional")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	// answer from the cache if possible
+	cached, ok, version := g.pathCache.get(startKey, endKey)
+	if ok {
+		return cached, true
+	}
+
+	// start and end vertex
+	start := g.get(startKey)
+	end := g.get(endKey)
+
+	if start == nil || end == nil {
+		return
+	}
+
+	forward := newFrontier(start, (*Vertex).GetOutgoing, func(v *Vertex) int { return heuristic(v.key, endKey) })
+	backward := newFrontier(end, (*Vertex).GetIncoming, func(v *Vertex) int { return heuristic(startKey, v.key) })
+
+	// best known path length and the vertex where the two searches met on it
+	var best int64
+	var meet *Vertex
+
+	if start == end {
+		meet = start
+	}
+
+	for forward.queue.Len() > 0 && backward.queue.Len() > 0 {
+		// no open vertex on either side can lead to a shorter path anymore
+		if meet != nil && (forward.minPriority() >= best || backward.minPriority() >= best) {
+			break
+		}
+
+		// expand the smaller frontier
+		side, other := forward, backward
+		if backward.queue.Len() < forward.queue.Len() {
+			side, other = backward, forward
+		}
+
+		side.expand(func(v *Vertex, distance int64) {
+			// vertex was reached by the other search, too
+			if otherDistance, ok := other.distance(v); ok {
+				if addOverflows(distance, otherDistance) {
+					return
+				}
+				if meet == nil || distance+otherDistance < best {
+					best = distance + otherDistance
+					meet = v
+				}
+			}
+		})
+	}
+
+	if meet == nil {
+		return
+	}
+
+	exists = true
+
+	// path from the start vertex to the meeting point
+	for v := meet; v != nil; v = forward.prev(v) {
+		path = append(path, v.key)
+	}
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	// path from the meeting point to the end vertex
+	for v := backward.prev(meet); v != nil; v = backward.prev(v) {
+		path = append(path, v.key)
+	}
+
+	g.pathCache.put(path, version)
+
+	return
+}
+
+// frontier is one direction of a bidirectional search.
+type frontier struct {
+	queue    *priorityQueue                  // open vertices by priority
+	open     map[*Vertex]*Item               // vertices that have not yet been visited
+	closed   map[*Vertex]*Item               // vertices that have been visited already
+	edges    func(v *Vertex) map[*Vertex]int // edges to follow from a vertex
+	estimate func(v *Vertex) int             // estimated distance to the other end of the search
+}
+
+// newFrontier initializes a frontier starting at v.
+func newFrontier(v *Vertex, edges func(v *Vertex) map[*Vertex]int, estimate func(v *Vertex) int) *frontier {
+	f := &frontier{&priorityQueue{}, map[*Vertex]*Item{}, map[*Vertex]*Item{}, edges, estimate}
+
+	item := &Item{v, nil, 0, int64(estimate(v)), 0}
+	f.open[v] = item
+
+	heap.Push(f.queue, item)
+
+	return f
+}
+
+// minPriority returns the lowest priority of all open vertices. The queue must not be empty.
+func (f *frontier) minPriority() int64 {
+	return (*f.queue)[0].priority
+}
+
+// distance returns the shortest distance from the frontier's origin to v known so far, and if v was reached at all.
+func (f *frontier) distance(v *Vertex) (int64, bool) {
+	if item, ok := f.open[v]; ok {
+		return item.distanceFromStart, true
+	}
+	if item, ok := f.closed[v]; ok {
+		return item.distanceFromStart, true
+	}
+	return 0, false
+}
+
+// prev returns the predecessor of v on the shortest known path from the frontier's origin.
+func (f *frontier) prev(v *Vertex) *Vertex {
+	if item, ok := f.open[v]; ok {
+		return item.prev
+	}
+	if item, ok := f.closed[v]; ok {
+		return item.prev
+	}
+	return nil
+}
+
+// expand visits the open vertex with the highest priority and calls reached for every neighbor to which a shorter path was found.
+func (f *frontier) expand(reached func(v *Vertex, distance int64)) {
+	current := heap.Pop(f.queue).(*Item)
+
+	// current vertex was now visited; add to closed list
+	delete(f.open, current.v)
+	f.closed[current.v] = current
+
+	for neighbor, weight := range f.edges(current.v) {
+		if _, ok := f.closed[neighbor]; ok {
+			continue
+		}
+
+		// paths whose cost overflows are ignored
+		if addOverflows(current.distanceFromStart, int64(weight)) {
+			continue
+		}
+
+		distanceToNeighbor := current.distanceFromStart + int64(weight)
+
+		// skip neighbors that already have a better path leading to them
+		if md, ok := f.open[neighbor]; ok {
+			if md.distanceFromStart <= distanceToNeighbor {
+				continue
+			}
+			heap.Remove(f.queue, md.index)
+		}
+
+		item := &Item{neighbor, current.v, distanceToNeighbor, distanceToNeighbor + int64(f.estimate(neighbor)), 0}
+		f.open[neighbor] = item
+
+		heap.Push(f.queue, item)
+
+		reached(neighbor, distanceToNeighbor)
+	}
+}
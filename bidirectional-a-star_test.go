@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func noHeuristic(key, endKey string) int {
+	return 0
+}
+
+// pathCost sums the edge weights along path, or returns -1 if the path is broken.
+func pathCost(g *Graph, path []string) int {
+	cost := 0
+	for i := 1; i < len(path); i++ {
+		ok, weight := g.IsConnected(path[i-1], path[i])
+		if !ok {
+			return -1
+		}
+		cost += weight
+	}
+	return cost
+}
+
+func TestShortestPathBidirectional(t *testing.T) {
+	g := New()
+
+	// set key → value pairs
+	for i := 1; i <= 9; i++ {
+		g.Set(strconv.Itoa(i), i)
+	}
+
+	// connect vertices/nodes
+	g.Connect("1", "2", 1)
+	g.Connect("1", "3", 2) // these two lines make it cheaper to go 1→3
+	g.Connect("2", "3", 2) // than 1→2→3
+	g.Connect("3", "4", 1)
+	g.Connect("4", "5", 1)
+	g.Connect("5", "6", 1)
+	g.Connect("6", "7", 1)
+	g.Connect("6", "8", 2) // these two lines make it cheaper to go 6→8
+	g.Connect("7", "8", 2) // than 6→7→8
+	g.Connect("8", "9", 1)
+
+	path, ok := g.ShortestPathBidirectional("1", "9", noHeuristic)
+	if !ok {
+		t.Fatal("no path found")
+	}
+
+	expected := []string{"1", "3", "4", "5", "6", "8", "9"}
+	if len(path) != len(expected) {
+		t.Fatalf("expected %v, got %v", expected, path)
+	}
+	for i := range expected {
+		if path[i] != expected[i] {
+			t.Fatalf("expected %v, got %v", expected, path)
+		}
+	}
+
+	// path to itself
+	path, ok = g.ShortestPathBidirectional("4", "4", noHeuristic)
+	if !ok || len(path) != 1 || path[0] != "4" {
+		t.Fail()
+	}
+
+	// impossible paths
+	if _, ok = g.ShortestPathBidirectional("9", "1", noHeuristic); ok {
+		t.Fail()
+	}
+
+	if _, ok = g.ShortestPathBidirectional("1", "10", noHeuristic); ok {
+		t.Fail()
+	}
+}
+
+func TestShortestPathBidirectionalRandom(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	for round := 0; round < 20; round++ {
+		g := New()
+
+		for i := 0; i < 50; i++ {
+			g.Set(strconv.Itoa(i), i)
+		}
+		for i := 0; i < 150; i++ {
+			g.Connect(strconv.Itoa(r.Intn(50)), strconv.Itoa(r.Intn(50)), 1+r.Intn(10))
+		}
+
+		for i := 0; i < 10; i++ {
+			startKey, endKey := strconv.Itoa(r.Intn(50)), strconv.Itoa(r.Intn(50))
+
+			expected, expectedOk := g.ShortestPathWithHeuristic(startKey, endKey, noHeuristic)
+
+			// ShortestPathWithHeuristic returns the path in end → start order
+			for i, j := 0, len(expected)-1; i < j; i, j = i+1, j-1 {
+				expected[i], expected[j] = expected[j], expected[i]
+			}
+
+			path, ok := g.ShortestPathBidirectional(startKey, endKey, noHeuristic)
+
+			if ok != expectedOk {
+				t.Fatalf("%s → %s: expected path to exist: %v", startKey, endKey, expectedOk)
+			}
+			if !ok {
+				continue
+			}
+
+			if path[0] != startKey || path[len(path)-1] != endKey {
+				t.Fatalf("%s → %s: wrong endpoints in %v", startKey, endKey, path)
+			}
+			if pathCost(g, path) != pathCost(g, expected) {
+				t.Fatalf("%s → %s: expected cost %d, got %d", startKey, endKey, pathCost(g, expected), pathCost(g, path))
+			}
+		}
+	}
+}
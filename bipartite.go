@@ -0,0 +1,196 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNotDisjoint is returned by MaxBipartiteMatching if a key is part of both sides.
+var ErrNotDisjoint = errors.New("graph: key on both sides of bipartition")
+
+// IsBipartite returns true and a partition of the vertices into two sets if every edge (ignoring its direction) connects a vertex of one set with a vertex of the other.
+// In each weakly connected component, the vertex with the smallest key is put into the left set. Both sets are sorted.
+func (g *Graph) IsBipartite() (left, right []string, ok bool) {
+	defer g.track("IsBipartite")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := make([]string, 0, len(g.vertices))
+	for key := range g.vertices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	side := map[*Vertex]bool{} // true means left
+
+	for _, key := range keys {
+		start := g.get(key)
+		if _, ok := side[start]; ok {
+			continue
+		}
+
+		// 2-color the component breadth-first
+		side[start] = true
+		queue := []*Vertex{start}
+
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+
+			for _, edges := range []map[*Vertex]int{current.GetOutgoing(), current.GetIncoming()} {
+				for neighbor := range edges {
+					s, colored := side[neighbor]
+					if !colored {
+						side[neighbor] = !side[current]
+						queue = append(queue, neighbor)
+					} else if s == side[current] {
+						return nil, nil, false
+					}
+				}
+			}
+		}
+	}
+
+	for _, key := range keys {
+		if side[g.get(key)] {
+			left = append(left, key)
+		} else {
+			right = append(right, key)
+		}
+	}
+
+	return left, right, true
+}
+
+// MaxBipartiteMatching computes a maximum matching between the vertices with the keys in left and the vertices with the keys in right using the Hopcroft–Karp algorithm.
+// Edges are considered in either direction; edges within one side are ignored. It returns the matched pairs as a map from left key to right key.
+// Returns ErrInvalidKey if a key is invalid, and ErrNotDisjoint if a key is part of both sides.
+func (g *Graph) MaxBipartiteMatching(left, right []string) (matching map[string]string, err error) {
+	defer g.track("MaxBipartiteMatching")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	// number the vertices on both sides
+	leftIndex := map[*Vertex]int{}
+	rightIndex := map[*Vertex]int{}
+
+	for i, key := range left {
+		v := g.get(key)
+		if v == nil {
+			return nil, ErrInvalidKey
+		}
+		leftIndex[v] = i
+	}
+
+	rightVertices := make([]*Vertex, len(right))
+	for i, key := range right {
+		v := g.get(key)
+		if v == nil {
+			return nil, ErrInvalidKey
+		}
+		if _, ok := leftIndex[v]; ok {
+			return nil, ErrNotDisjoint
+		}
+		rightIndex[v] = i
+		rightVertices[i] = v
+	}
+
+	adjacency := make([][]int, len(left))
+	for i, key := range left {
+		v := g.get(key)
+		seen := map[int]bool{}
+
+		for _, edges := range []map[*Vertex]int{v.GetOutgoing(), v.GetIncoming()} {
+			for neighbor := range edges {
+				if j, ok := rightIndex[neighbor]; ok && !seen[j] {
+					seen[j] = true
+					adjacency[i] = append(adjacency[i], j)
+				}
+			}
+		}
+
+		// deterministic results
+		sort.Ints(adjacency[i])
+	}
+
+	const unmatched = -1
+
+	matchLeft := make([]int, len(left))
+	matchRight := make([]int, len(right))
+	for i := range matchLeft {
+		matchLeft[i] = unmatched
+	}
+	for j := range matchRight {
+		matchRight[j] = unmatched
+	}
+
+	dist := make([]int, len(left))
+	const infinity = int(^uint(0) >> 1)
+
+	// bfs layers the free left vertices and the vertices reachable from them via alternating paths; returns true if an augmenting path exists
+	bfs := func() bool {
+		var queue []int
+		for i := range left {
+			if matchLeft[i] == unmatched {
+				dist[i] = 0
+				queue = append(queue, i)
+			} else {
+				dist[i] = infinity
+			}
+		}
+
+		found := false
+		for len(queue) > 0 {
+			i := queue[0]
+			queue = queue[1:]
+
+			for _, j := range adjacency[i] {
+				next := matchRight[j]
+				if next == unmatched {
+					found = true
+				} else if dist[next] == infinity {
+					dist[next] = dist[i] + 1
+					queue = append(queue, next)
+				}
+			}
+		}
+
+		return found
+	}
+
+	// dfs augments along a shortest alternating path starting at left vertex i
+	var dfs func(i int) bool
+	dfs = func(i int) bool {
+		for _, j := range adjacency[i] {
+			next := matchRight[j]
+			if next == unmatched || (dist[next] == dist[i]+1 && dfs(next)) {
+				matchLeft[i] = j
+				matchRight[j] = i
+				return true
+			}
+		}
+
+		// no augmenting path through i in this phase
+		dist[i] = infinity
+		return false
+	}
+
+	for bfs() {
+		for i := range left {
+			if matchLeft[i] == unmatched {
+				dfs(i)
+			}
+		}
+	}
+
+	matching = map[string]string{}
+	for i, j := range matchLeft {
+		if j != unmatched {
+			matching[left[i]] = right[j]
+		}
+	}
+
+	return
+}
@@ -0,0 +1,88 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestIsBipartite(t *testing.T) {
+	g := New()
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Set("3", nil)
+	g.Set("4", nil)
+	g.Set("5", nil)
+
+	// even cycle 1 → 2 → 3 → 4 → 1, plus isolated 5
+	g.Connect("1", "2", 1)
+	g.Connect("2", "3", 1)
+	g.Connect("3", "4", 1)
+	g.Connect("4", "1", 1)
+
+	left, right, ok := g.IsBipartite()
+	if !ok {
+		t.Fatal("even cycle not bipartite")
+	}
+
+	if len(left) != 3 || left[0] != "1" || left[1] != "3" || left[2] != "5" {
+		t.Errorf("unexpected left side %v", left)
+	}
+	if len(right) != 2 || right[0] != "2" || right[1] != "4" {
+		t.Errorf("unexpected right side %v", right)
+	}
+
+	// odd cycle, regardless of edge direction
+	g.Connect("3", "1", 1)
+	if _, _, ok = g.IsBipartite(); ok {
+		t.Fail()
+	}
+}
+
+func TestMaxBipartiteMatching(t *testing.T) {
+	g := New()
+
+	workers := []string{"w1", "w2", "w3", "w4"}
+	jobs := []string{"j1", "j2", "j3", "j4"}
+
+	for _, key := range append(append([]string{}, workers...), jobs...) {
+		g.Set(key, nil)
+	}
+
+	// w4 can only do j1, which the greedy choice of w1 would take
+	g.Connect("w1", "j1", 1)
+	g.Connect("w1", "j2", 1)
+	g.Connect("w2", "j2", 1)
+	g.Connect("j3", "w2", 1) // direction doesn't matter
+	g.Connect("w3", "j3", 1)
+	g.Connect("w4", "j1", 1)
+	g.Connect("w1", "w2", 1) // ignored, both on the same side
+
+	matching, err := g.MaxBipartiteMatching(workers, jobs)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(matching) != 3 {
+		t.Fatalf("expected 3 pairs, got %v", matching)
+	}
+
+	used := map[string]bool{}
+	for worker, job := range matching {
+		if ok, _ := g.IsConnected(worker, job); !ok {
+			if ok, _ = g.IsConnected(job, worker); !ok {
+				t.Errorf("%s and %s are not connected", worker, job)
+			}
+		}
+		if used[job] {
+			t.Errorf("%s matched twice", job)
+		}
+		used[job] = true
+	}
+
+	if _, err = g.MaxBipartiteMatching(workers, []string{"w1"}); err != ErrNotDisjoint {
+		t.Fail()
+	}
+	if _, err = g.MaxBipartiteMatching(workers, []string{"x"}); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
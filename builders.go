@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrDimensions is returned when a matrix doesn't match the number of keys it describes.
+var ErrDimensions = errors.New("graph: matrix dimensions don't match keys")
+
+// KNearestNeighbors builds a k-nearest-neighbor graph: it contains a vertex for every key in values (holding the corresponding value), with edges from every vertex to the k other vertices closest to it as determined by distance.
+// The edge weights are the distances. Ties are broken by key. Distances are computed for all pairs, so this is meant for small to medium-sized data.
+func KNearestNeighbors(values map[string]interface{}, k int, distance func(a, b interface{}) int) *Graph {
+	g := New()
+
+	keys := make([]string, 0, len(values))
+	for key, value := range values {
+		keys = append(keys, key)
+		g.vertices[key] = newVertex(key, value)
+	}
+	sort.Strings(keys)
+
+	type candidate struct {
+		key      string
+		distance int
+	}
+
+	for _, key := range keys {
+		candidates := make([]candidate, 0, len(keys)-1)
+		for _, otherKey := range keys {
+			if otherKey != key {
+				candidates = append(candidates, candidate{otherKey, distance(values[key], values[otherKey])})
+			}
+		}
+
+		sort.SliceStable(candidates, func(i, j int) bool {
+			return candidates[i].distance < candidates[j].distance
+		})
+
+		for i := 0; i < k && i < len(candidates); i++ {
+			from, to := g.vertices[key], g.vertices[candidates[i].key]
+			from.outgoingEdges[to] = candidates[i].distance
+			to.incomingEdges[from] = candidates[i].distance
+		}
+	}
+
+	return g
+}
+
+// ThresholdGraph builds a graph from a similarity matrix: it contains a vertex with a nil value for every key, and an edge from keys[i] to keys[j] whenever similarity[i][j] is at least threshold.
+// weight converts a similarity into the edge weight; if it is nil, all edges have a weight of 1. The diagonal of the matrix is ignored. Returns ErrDimensions if the matrix is not len(keys) × len(keys).
+func ThresholdGraph(keys []string, similarity [][]float64, threshold float64, weight func(similarity float64) int) (*Graph, error) {
+	if len(similarity) != len(keys) {
+		return nil, ErrDimensions
+	}
+	for _, row := range similarity {
+		if len(row) != len(keys) {
+			return nil, ErrDimensions
+		}
+	}
+
+	g := New()
+	for _, key := range keys {
+		g.vertices[key] = newVertex(key, nil)
+	}
+
+	for i, row := range similarity {
+		for j, s := range row {
+			if i == j || s < threshold {
+				continue
+			}
+
+			w := 1
+			if weight != nil {
+				w = weight(s)
+			}
+
+			from, to := g.vertices[keys[i]], g.vertices[keys[j]]
+			from.outgoingEdges[to] = w
+			to.incomingEdges[from] = w
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestKNearestNeighbors(t *testing.T) {
+	values := map[string]interface{}{"a": 0, "b": 1, "c": 3, "d": 10}
+
+	g := KNearestNeighbors(values, 2, func(a, b interface{}) int {
+		d := a.(int) - b.(int)
+		if d < 0 {
+			d = -d
+		}
+		return d
+	})
+
+	if g.Len() != 4 {
+		t.Fail()
+	}
+
+	expected := map[string][]string{
+		"a": {"b", "c"},
+		"b": {"a", "c"},
+		"c": {"a", "b"},
+		"d": {"b", "c"},
+	}
+
+	for key, neighbors := range expected {
+		v, _ := g.Get(key)
+		if len(v.GetOutgoing()) != 2 {
+			t.Errorf("%s: expected 2 neighbors", key)
+		}
+		for _, neighbor := range neighbors {
+			if ok, _ := g.IsConnected(key, neighbor); !ok {
+				t.Errorf("%s: expected edge to %s", key, neighbor)
+			}
+		}
+	}
+
+	if _, weight := g.IsConnected("d", "c"); weight != 7 {
+		t.Fail()
+	}
+	if v, _ := g.Get("d"); v.Value() != 10 {
+		t.Fail()
+	}
+}
+
+func TestThresholdGraph(t *testing.T) {
+	keys := []string{"a", "b", "c"}
+	similarity := [][]float64{
+		{1, 0.9, 0.1},
+		{0.9, 1, 0.5},
+		{0.1, 0.6, 1},
+	}
+
+	g, err := ThresholdGraph(keys, similarity, 0.55, func(s float64) int { return int(s * 10) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 9 {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("b", "a"); !ok {
+		t.Fail()
+	}
+	if ok, weight := g.IsConnected("c", "b"); !ok || weight != 6 {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("b", "c"); ok {
+		t.Fail()
+	}
+
+	if _, err = ThresholdGraph(keys, similarity[:2], 0.5, nil); err != ErrDimensions {
+		t.Fail()
+	}
+}
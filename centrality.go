@@ -0,0 +1,162 @@
+package graph
+
+import (
+	"container/heap"
+)
+
+// BetweennessCentrality computes the betweenness centrality of every vertex using Brandes' algorithm: the sum over all pairs of other vertices s, t of the fraction of shortest paths from s to t passing through the vertex.
+// Paths are weighted by the edge weights, which must be positive. The scores are not normalized.
+func (g *Graph) BetweennessCentrality() map[string]float64 {
+	defer g.track("BetweennessCentrality")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	scores, _ := g.betweennessCentrality(nil)
+
+	return scores
+}
+
+// BetweennessCentralityWithProgress computes the betweenness centrality like BetweennessCentrality, reporting the progress to fn after processing the paths from each vertex. It fails with ErrCanceled if fn returns false.
+func (g *Graph) BetweennessCentralityWithProgress(fn ProgressFunc) (map[string]float64, error) {
+	defer g.track("BetweennessCentralityWithProgress")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.betweennessCentrality(fn)
+}
+
+// betweennessCentrality is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+func (g *Graph) betweennessCentrality(fn ProgressFunc) (map[string]float64, error) {
+	vertices, index := g.indexVertices()
+	progress := newProgressTracker(fn, len(vertices))
+	centrality := make([]float64, len(vertices))
+
+	for s := range vertices {
+		order, preds, sigma := g.shortestPathCounts(vertices, index, s)
+
+		// accumulate dependencies in order of non-increasing distance from s
+		delta := make([]float64, len(vertices))
+		for i := len(order) - 1; i >= 0; i-- {
+			w := order[i]
+			for _, v := range preds[w] {
+				delta[v] += sigma[v] / sigma[w] * (1 + delta[w])
+			}
+			if w != s {
+				centrality[w] += delta[w]
+			}
+		}
+
+		if err := progress.step(); err != nil {
+			return nil, err
+		}
+	}
+
+	scores := make(map[string]float64, len(vertices))
+	for i, v := range vertices {
+		scores[v.key] = centrality[i]
+	}
+
+	return scores, nil
+}
+
+// indexVertices is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It numbers the vertices of the graph, returning them as a slice and a map from vertex to index.
+func (g *Graph) indexVertices() (vertices []*Vertex, index map[*Vertex]int) {
+	vertices = make([]*Vertex, 0, len(g.vertices))
+	index = make(map[*Vertex]int, len(g.vertices))
+
+	for _, v := range g.vertices {
+		index[v] = len(vertices)
+		vertices = append(vertices, v)
+	}
+
+	return
+}
+
+// shortestPathCounts is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// Starting at vertex s, it returns the reachable vertices in the order they were settled by Dijkstra's algorithm, the predecessors of each vertex on all of its shortest paths from s, and the number of shortest paths from s to each vertex.
+func (g *Graph) shortestPathCounts(vertices []*Vertex, index map[*Vertex]int, s int) (order []int, preds [][]int, sigma []float64) {
+	preds = make([][]int, len(vertices))
+	sigma = make([]float64, len(vertices))
+	dist := make([]int64, len(vertices))
+	reached := make([]bool, len(vertices))
+	settled := make([]bool, len(vertices))
+
+	sigma[s] = 1
+	reached[s] = true
+
+	queue := &priorityQueue{}
+	heap.Push(queue, &Item{vertices[s], nil, 0, 0, 0})
+
+	for queue.Len() > 0 {
+		item := heap.Pop(queue).(*Item)
+		v := index[item.v]
+
+		// outdated queue entry
+		if settled[v] || item.distanceFromStart > dist[v] {
+			continue
+		}
+
+		settled[v] = true
+		order = append(order, v)
+
+		for neighbor, weight := range item.v.GetOutgoing() {
+			w := index[neighbor]
+
+			// paths whose cost overflows are ignored
+			if addOverflows(dist[v], int64(weight)) {
+				continue
+			}
+			d := dist[v] + int64(weight)
+
+			switch {
+			case !reached[w] || d < dist[w]:
+				reached[w] = true
+				dist[w] = d
+				sigma[w] = sigma[v]
+				preds[w] = append(preds[w][:0], v)
+
+				heap.Push(queue, &Item{neighbor, nil, d, d, 0})
+
+			case d == dist[w] && !settled[w]:
+				sigma[w] += sigma[v]
+				preds[w] = append(preds[w], v)
+			}
+		}
+	}
+
+	return
+}
+
+// ClosenessCentrality computes the closeness centrality of every vertex: the number of other vertices divided by the sum of the weighted distances from the vertex to them, following outgoing edges.
+// If reachableOnly is false, vertices that can't reach all other vertices get a score of 0. If it is true, only the vertices reachable from a vertex are taken into account for its score, so vertices in disconnected graphs still get meaningful scores.
+func (g *Graph) ClosenessCentrality(reachableOnly bool) map[string]float64 {
+	defer g.track("ClosenessCentrality")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	scores := make(map[string]float64, len(g.vertices))
+
+	for key, v := range g.vertices {
+		dist, _ := g.dijkstra(v, nil, nil)
+
+		// the vertex itself is always reached at distance 0
+		reached := len(dist) - 1
+		var sum int64
+		for _, d := range dist {
+			sum += d
+		}
+
+		if reached == 0 || sum == 0 || (!reachableOnly && reached < len(g.vertices)-1) {
+			scores[key] = 0
+			continue
+		}
+
+		scores[key] = float64(reached) / float64(sum)
+	}
+
+	return scores
+}
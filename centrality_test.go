@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestBetweennessCentrality(t *testing.T) {
+	g := New()
+
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	// two equally short paths a → b → d and a → c → d, then d → e
+	g.Connect("a", "b", 1)
+	g.Connect("a", "c", 1)
+	g.Connect("b", "d", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 2)
+
+	scores := g.BetweennessCentrality()
+
+	expected := map[string]float64{
+		"a": 0,
+		"b": 1, // half of a → d and a → e
+		"c": 1,
+		"d": 3, // a → e, b → e, c → e
+		"e": 0,
+	}
+
+	for key, score := range expected {
+		if math.Abs(scores[key]-score) > 1e-9 {
+			t.Errorf("%s: expected %f, got %f", key, score, scores[key])
+		}
+	}
+
+	// a longer path is not counted
+	g.Connect("a", "d", 3)
+	if scores = g.BetweennessCentrality(); math.Abs(scores["b"]-1) > 1e-9 {
+		t.Fail()
+	}
+
+	// a shorter path takes over
+	g.Connect("a", "d", 1)
+	if scores = g.BetweennessCentrality(); scores["b"] != 0 || math.Abs(scores["d"]-3) > 1e-9 {
+		t.Fail()
+	}
+}
+
+func TestClosenessCentrality(t *testing.T) {
+	g := New()
+
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c, a → c; d is isolated
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("a", "c", 4)
+
+	scores := g.ClosenessCentrality(false)
+	for key, score := range scores {
+		if score != 0 {
+			t.Errorf("%s: expected 0 in disconnected graph, got %f", key, score)
+		}
+	}
+
+	scores = g.ClosenessCentrality(true)
+
+	// a reaches b at 1 and c at 3
+	if math.Abs(scores["a"]-2.0/4.0) > 1e-9 {
+		t.Errorf("a: expected 0.5, got %f", scores["a"])
+	}
+	if math.Abs(scores["b"]-1.0/2.0) > 1e-9 {
+		t.Errorf("b: expected 0.5, got %f", scores["b"])
+	}
+	if scores["c"] != 0 || scores["d"] != 0 {
+		t.Fail()
+	}
+
+	// strongly connected without d
+	g.Delete("d")
+	g.Connect("c", "a", 1)
+
+	scores = g.ClosenessCentrality(false)
+
+	// c reaches a at 1 and b at 2
+	if math.Abs(scores["c"]-2.0/3.0) > 1e-9 {
+		t.Errorf("c: expected 0.667, got %f", scores["c"])
+	}
+}
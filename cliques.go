@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"sort"
+)
+
+// MaximalCliques enumerates all maximal cliques of the graph using the Bron–Kerbosch algorithm with pivoting, treating every edge as undirected: a clique is a set of vertices each pair of which is connected in at least one direction.
+// Each clique is passed to fn as a sorted slice of keys as soon as it is found, so cliques don't have to be kept in memory. Isolated vertices form cliques of their own. The enumeration stops when fn returns false.
+func (g *Graph) MaximalCliques(fn func(clique []string) bool) {
+	defer g.track("MaximalCliques")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	neighbors := make(map[*Vertex]map[*Vertex]struct{}, len(g.vertices))
+	candidates := make(map[*Vertex]struct{}, len(g.vertices))
+	for _, v := range g.vertices {
+		neighbors[v] = undirectedNeighbors(v)
+		candidates[v] = struct{}{}
+	}
+
+	c := &cliqueSearch{neighbors, fn}
+	c.bronKerbosch(nil, candidates, map[*Vertex]struct{}{})
+}
+
+// cliqueSearch holds the state shared by the recursive calls of the Bron–Kerbosch algorithm.
+type cliqueSearch struct {
+	neighbors map[*Vertex]map[*Vertex]struct{}
+	fn        func(clique []string) bool
+}
+
+// bronKerbosch reports all maximal cliques containing all vertices of clique, some of candidates and none of excluded. Returns false if the enumeration was stopped.
+func (c *cliqueSearch) bronKerbosch(clique []*Vertex, candidates, excluded map[*Vertex]struct{}) bool {
+	if len(candidates) == 0 {
+		if len(excluded) > 0 {
+			return true
+		}
+
+		keys := make([]string, len(clique))
+		for i, v := range clique {
+			keys[i] = v.key
+		}
+		sort.Strings(keys)
+
+		return c.fn(keys)
+	}
+
+	// choose the pivot with the most neighbors among the candidates, since its neighbors don't need to be tried
+	var pivot *Vertex
+	most := -1
+	for _, set := range []map[*Vertex]struct{}{candidates, excluded} {
+		for u := range set {
+			n := 0
+			for v := range c.neighbors[u] {
+				if _, ok := candidates[v]; ok {
+					n++
+				}
+			}
+			if n > most {
+				pivot, most = u, n
+			}
+		}
+	}
+
+	var tries []*Vertex
+	for v := range candidates {
+		if _, ok := c.neighbors[pivot][v]; !ok {
+			tries = append(tries, v)
+		}
+	}
+
+	for _, v := range tries {
+		nextCandidates := map[*Vertex]struct{}{}
+		nextExcluded := map[*Vertex]struct{}{}
+		for u := range c.neighbors[v] {
+			if _, ok := candidates[u]; ok {
+				nextCandidates[u] = struct{}{}
+			}
+			if _, ok := excluded[u]; ok {
+				nextExcluded[u] = struct{}{}
+			}
+		}
+
+		if !c.bronKerbosch(append(clique[:len(clique):len(clique)], v), nextCandidates, nextExcluded) {
+			return false
+		}
+
+		delete(candidates, v)
+		excluded[v] = struct{}{}
+	}
+
+	return true
+}
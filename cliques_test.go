@@ -0,0 +1,46 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+)
+
+func TestMaximalCliques(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.Set(key, nil)
+	}
+
+	// clique a, b, c, d (with mixed directions), triangle-free tail d - e, isolated f
+	g.Connect("a", "b", 1)
+	g.Connect("a", "c", 1)
+	g.Connect("d", "a", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "b", 1)
+	g.Connect("b", "d", 1)
+	g.Connect("d", "c", 1)
+	g.Connect("d", "e", 1)
+
+	var cliques []string
+	g.MaximalCliques(func(clique []string) bool {
+		cliques = append(cliques, strings.Join(clique, ""))
+		return true
+	})
+	sort.Strings(cliques)
+
+	if expected := []string{"abcd", "de", "f"}; !reflect.DeepEqual(cliques, expected) {
+		t.Fatalf("expected %v, got %v", expected, cliques)
+	}
+
+	// stopping early
+	n := 0
+	g.MaximalCliques(func(clique []string) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,108 @@
+package graph
+
+// TriangleCount returns the number of triangles in the graph, treating every edge as undirected: three vertices form a triangle if each pair of them is connected in at least one direction.
+func (g *Graph) TriangleCount() int {
+	defer g.track("TriangleCount")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	vertices, index := g.indexVertices()
+
+	neighbors := make([]map[*Vertex]struct{}, len(vertices))
+	for i, v := range vertices {
+		neighbors[i] = undirectedNeighbors(v)
+	}
+
+	// count every triangle once, from its vertex with the lowest index
+	triangles := 0
+	for i := range vertices {
+		for u := range neighbors[i] {
+			if index[u] <= i {
+				continue
+			}
+
+			for w := range neighbors[index[u]] {
+				if index[w] <= index[u] {
+					continue
+				}
+
+				if _, ok := neighbors[i][w]; ok {
+					triangles++
+				}
+			}
+		}
+	}
+
+	return triangles
+}
+
+// ClusteringCoefficient returns the local clustering coefficient of the vertex with the specified key, treating every edge as undirected: the fraction of pairs of its neighbors which are connected themselves.
+// The coefficient of vertices with less than two neighbors is 0. Returns false if the key is invalid.
+func (g *Graph) ClusteringCoefficient(key string) (coefficient float64, ok bool) {
+	defer g.track("ClusteringCoefficient")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	v := g.get(key)
+	if v == nil {
+		return 0, false
+	}
+
+	return clusteringCoefficient(v), true
+}
+
+// AverageClusteringCoefficient returns the average of the local clustering coefficients of all vertices, see ClusteringCoefficient. Returns 0 for an empty graph.
+func (g *Graph) AverageClusteringCoefficient() float64 {
+	defer g.track("AverageClusteringCoefficient")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if len(g.vertices) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range g.vertices {
+		sum += clusteringCoefficient(v)
+	}
+
+	return sum / float64(len(g.vertices))
+}
+
+// clusteringCoefficient computes the local clustering coefficient of v. Does NOT lock the graph.
+func clusteringCoefficient(v *Vertex) float64 {
+	neighbors := undirectedNeighbors(v)
+	if len(neighbors) < 2 {
+		return 0
+	}
+
+	// every connected pair of neighbors is counted once from each side
+	links := 0
+	for u := range neighbors {
+		for w := range undirectedNeighbors(u) {
+			if _, ok := neighbors[w]; ok {
+				links++
+			}
+		}
+	}
+
+	return float64(links) / float64(len(neighbors)*(len(neighbors)-1))
+}
+
+// undirectedNeighbors returns the set of vertices connected to v in either direction. v itself is no neighbor, even if it has a self-loop.
+func undirectedNeighbors(v *Vertex) map[*Vertex]struct{} {
+	neighbors := map[*Vertex]struct{}{}
+
+	for neighbor := range v.GetOutgoing() {
+		neighbors[neighbor] = struct{}{}
+	}
+	for neighbor := range v.GetIncoming() {
+		neighbors[neighbor] = struct{}{}
+	}
+	delete(neighbors, v)
+
+	return neighbors
+}
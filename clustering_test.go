@@ -0,0 +1,62 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTriangleCount(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+
+	// triangle a, b, c with mixed directions and a double edge, d hanging off c
+	g.Connect("a", "b", 1)
+	g.Connect("b", "a", 1)
+	g.Connect("c", "b", 1)
+	g.Connect("a", "c", 1)
+	g.Connect("c", "d", 1)
+
+	if n := g.TriangleCount(); n != 1 {
+		t.Fatalf("expected 1 triangle, got %d", n)
+	}
+
+	g.Connect("d", "a", 1)
+	if n := g.TriangleCount(); n != 2 {
+		t.Fatalf("expected 2 triangles, got %d", n)
+	}
+}
+
+func TestClusteringCoefficient(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("c", "d", 1)
+
+	// c has 3 neighbors, one pair of which is connected
+	if c, ok := g.ClusteringCoefficient("c"); !ok || c != 1.0/3 {
+		t.Errorf("unexpected coefficient %f", c)
+	}
+	if c, _ := g.ClusteringCoefficient("a"); c != 1 {
+		t.Fail()
+	}
+	if c, _ := g.ClusteringCoefficient("d"); c != 0 {
+		t.Fail()
+	}
+	if _, ok := g.ClusteringCoefficient("x"); ok {
+		t.Fail()
+	}
+
+	if avg := g.AverageClusteringCoefficient(); math.Abs(avg-(1+1+1.0/3)/4) > 1e-9 {
+		t.Errorf("unexpected average %f", avg)
+	}
+	if New().AverageClusteringCoefficient() != 0 {
+		t.Fail()
+	}
+}
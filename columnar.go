@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// columnarColumn is a column of a batch of rows of the vertex or edge table written by the Arrow and Parquet exporters.
+type columnarColumn struct {
+	name     string
+	nullable bool
+	integer  bool // int64 values in ints, otherwise UTF-8 strings in strings
+
+	strings []string
+	valid   []bool // for nullable columns, whether each row has a value
+	ints    []int64
+}
+
+// nulls returns the number of rows without a value.
+func (c *columnarColumn) nulls() int {
+	n := 0
+	for _, valid := range c.valid {
+		if !valid {
+			n++
+		}
+	}
+	return n
+}
+
+// reset empties the column for the next batch.
+func (c *columnarColumn) reset() {
+	c.strings, c.valid, c.ints = c.strings[:0], c.valid[:0], c.ints[:0]
+}
+
+// vertexColumns returns the empty columns of the vertex table: key and value, encoded as JSON.
+func vertexColumns() []*columnarColumn {
+	return []*columnarColumn{{name: "key"}, {name: "value", nullable: true}}
+}
+
+// edgeColumns returns the empty columns of the edge table: from, to and weight.
+func edgeColumns() []*columnarColumn {
+	return []*columnarColumn{{name: "from"}, {name: "to"}, {name: "weight", integer: true}}
+}
+
+// columnarVertices is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It calls fn with batches of up to size rows of the vertex table in key order, in the columns returned by vertexColumns. Values are encoded as JSON, nil values as nulls.
+func (g *Graph) columnarVertices(size int, fn func(rows int, columns []*columnarColumn) error) error {
+	columns := vertexColumns()
+	key, value := columns[0], columns[1]
+
+	keys := g.sortedKeys()
+
+	for start := 0; start < len(keys); start += size {
+		end := start + size
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		key.reset()
+		value.reset()
+
+		for _, k := range keys[start:end] {
+			key.strings = append(key.strings, k)
+
+			v := g.vertices[k].Value()
+			if v == nil {
+				value.strings = append(value.strings, "")
+				value.valid = append(value.valid, false)
+				continue
+			}
+
+			b, err := json.Marshal(v)
+			if err != nil {
+				return fmt.Errorf("value of %q: %v", k, err)
+			}
+			value.strings = append(value.strings, string(b))
+			value.valid = append(value.valid, true)
+		}
+
+		if err := fn(end-start, columns); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// columnarEdges is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It calls fn with batches of up to size rows of the edge table sorted by keys, in the columns returned by edgeColumns.
+func (g *Graph) columnarEdges(size int, fn func(rows int, columns []*columnarColumn) error) error {
+	columns := edgeColumns()
+	from, to, weight := columns[0], columns[1], columns[2]
+
+	flush := func() error {
+		if len(from.strings) == 0 {
+			return nil
+		}
+		err := fn(len(from.strings), columns)
+		from.reset()
+		to.reset()
+		weight.reset()
+		return err
+	}
+
+	for _, key := range g.sortedKeys() {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			from.strings = append(from.strings, key)
+			to.strings = append(to.strings, neighbor.key)
+			weight.ints = append(weight.ints, int64(outgoing[neighbor]))
+
+			if len(from.strings) == size {
+				if err := flush(); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return flush()
+}
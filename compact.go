@@ -0,0 +1,246 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// compactMagic starts the format written by WriteCompact, followed by a version byte.
+const compactMagic = "\x80gsc"
+
+// compactVersion is the version of the format written by WriteCompact.
+const compactVersion = 1
+
+// WriteCompact writes the graph to w in a compact binary format meant for snapshots of huge graphs, where the overhead of gob is prohibitive:
+//
+//	magic "\x80gsc", version byte, uvarint vertex count
+//	for every vertex in key order:
+//	  uvarint length of the prefix shared with the previous key, uvarint length of the rest, the rest of the key
+//	  uvarint length of the MessagePack encoded value (0 for nil), the encoded value
+//	for every vertex in key order:
+//	  uvarint number of outgoing edges
+//	  for every neighbor in key order: uvarint difference to the previous neighbor's index (the index itself for the first one), zigzag varint weight
+//
+// Keys are stored once and edges refer to them by index, so an edge between nearby vertices with a small weight takes two bytes. Values are subject to the same restrictions as with MarshalMsgpack. The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) WriteCompact(w io.Writer) error {
+	defer g.track("WriteCompact")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	var scratch [binary.MaxVarintLen64]byte
+
+	writeUvarint := func(u uint64) {
+		bw.Write(scratch[:binary.PutUvarint(scratch[:], u)])
+	}
+
+	bw.WriteString(compactMagic)
+	bw.WriteByte(compactVersion)
+
+	keys := g.sortedKeys()
+	writeUvarint(uint64(len(keys)))
+
+	index := make(map[*Vertex]int, len(keys))
+	previous := ""
+	enc := &msgpackEncoder{}
+
+	for i, key := range keys {
+		v := g.vertices[key]
+		index[v] = i
+
+		shared := 0
+		for shared < len(key) && shared < len(previous) && key[shared] == previous[shared] {
+			shared++
+		}
+		writeUvarint(uint64(shared))
+		writeUvarint(uint64(len(key) - shared))
+		bw.WriteString(key[shared:])
+		previous = key
+
+		value := v.Value()
+		if value == nil {
+			writeUvarint(0)
+			continue
+		}
+
+		enc.buf = enc.buf[:0]
+		if err := enc.encode(value); err != nil {
+			return fmt.Errorf("graph: encoding compact: value of %q: %v", key, err)
+		}
+		writeUvarint(uint64(len(enc.buf)))
+		bw.Write(enc.buf)
+	}
+
+	type neighborWeight struct {
+		index, weight int
+	}
+	var neighbors []neighborWeight
+
+	for _, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+		writeUvarint(uint64(len(outgoing)))
+
+		neighbors = neighbors[:0]
+		for neighbor, weight := range outgoing {
+			neighbors = append(neighbors, neighborWeight{index[neighbor], weight})
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].index < neighbors[j].index })
+
+		last := 0
+		for _, n := range neighbors {
+			writeUvarint(uint64(n.index - last))
+			bw.Write(scratch[:binary.PutVarint(scratch[:], int64(n.weight))])
+			last = n.index
+		}
+	}
+
+	return bw.Flush()
+}
+
+// ReadCompact reads a graph written by WriteCompact from r. Returns an error wrapping ErrUnsupportedVersion if it was written by a newer version of this package. Unless r is an io.ByteReader, it may be read beyond the end of the graph.
+// Values are decoded as with UnmarshalMsgpack. If the graph contains self-loops, they are enabled on the returned graph, see EnableSelfLoops.
+func ReadCompact(r io.Reader) (*Graph, error) {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		buffered := bufio.NewReader(r)
+		r, br = buffered, buffered
+	}
+
+	fail := func(format string, args ...interface{}) (*Graph, error) {
+		return nil, fmt.Errorf("graph: decoding compact: "+format, args...)
+	}
+
+	header := make([]byte, len(compactMagic)+1)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return fail("%v", err)
+	}
+	if string(header[:len(compactMagic)]) != compactMagic {
+		return fail("not in compact format")
+	}
+	if header[len(compactMagic)] > compactVersion {
+		return nil, fmt.Errorf("graph: decoding compact: %w %d", ErrUnsupportedVersion, header[len(compactMagic)])
+	}
+
+	// readLength reads a length or count. Buffers are filled in chunks and slices grow as they are read, so corrupt lengths fail at the end of the input instead of allocating huge buffers up front.
+	readLength := func() (int, error) {
+		u, err := binary.ReadUvarint(br)
+		if err == nil && u > 1<<31 {
+			err = errors.New("invalid length")
+		}
+		return int(u), err
+	}
+
+	readBytes := func(n int) ([]byte, error) {
+		var b []byte
+		for len(b) < n {
+			chunk := n - len(b)
+			if chunk > 1<<16 {
+				chunk = 1 << 16
+			}
+			start := len(b)
+			b = append(b, make([]byte, chunk)...)
+			if _, err := io.ReadFull(r, b[start:]); err != nil {
+				return nil, err
+			}
+		}
+		return b, nil
+	}
+
+	n, err := readLength()
+	if err != nil {
+		return fail("vertex count: %v", err)
+	}
+
+	g := New()
+	var keys []string
+
+	for i := 0; i < n; i++ {
+		shared, err := readLength()
+		if err != nil {
+			return fail("vertex %d: %v", i+1, err)
+		}
+		length, err := readLength()
+		if err != nil {
+			return fail("vertex %d: %v", i+1, err)
+		}
+		if i == 0 && shared > 0 || i > 0 && shared > len(keys[i-1]) {
+			return fail("vertex %d: invalid key prefix", i+1)
+		}
+
+		suffix, err := readBytes(length)
+		if err != nil {
+			return fail("vertex %d: %v", i+1, err)
+		}
+		key := string(suffix)
+		if i > 0 {
+			key = keys[i-1][:shared] + key
+			if key <= keys[i-1] {
+				return fail("vertex %d: keys out of order", i+1)
+			}
+		}
+		keys = append(keys, key)
+
+		length, err = readLength()
+		if err != nil {
+			return fail("vertex %d: %v", i+1, err)
+		}
+
+		var value interface{}
+		if length > 0 {
+			b, err := readBytes(length)
+			if err != nil {
+				return fail("vertex %d: %v", i+1, err)
+			}
+
+			dec := &msgpackDecoder{buf: b}
+			value, err = dec.decode()
+			if err == nil && dec.pos < len(b) {
+				err = errors.New("trailing data")
+			}
+			if err != nil {
+				return fail("value of %q: %v", key, err)
+			}
+		}
+
+		g.Set(key, value)
+	}
+
+	for i, key := range keys {
+		degree, err := readLength()
+		if err != nil {
+			return fail("edges of %q: %v", key, err)
+		}
+
+		neighbor := 0
+		for j := 0; j < degree; j++ {
+			delta, err := binary.ReadUvarint(br)
+			if err != nil {
+				return fail("edges of %q: %v", key, err)
+			}
+			weight, err := binary.ReadVarint(br)
+			if err != nil {
+				return fail("edges of %q: %v", key, err)
+			}
+
+			if delta >= uint64(len(keys)) || (j > 0 && delta == 0) || neighbor+int(delta) >= len(keys) {
+				return fail("edges of %q: invalid neighbor", key)
+			}
+			neighbor += int(delta)
+
+			if int64(int(weight)) != weight {
+				return fail("edges of %q: weight %d out of range", key, weight)
+			}
+			if neighbor == i {
+				g.selfLoops = true
+			}
+			g.Connect(key, keys[neighbor], int(weight))
+		}
+	}
+
+	return g, nil
+}
@@ -0,0 +1,131 @@
+package graph
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestWriteReadCompact(t *testing.T) {
+	g := New()
+	g.Set("apple", int64(1))
+	g.Set("application", "abc")
+	g.Set("apply", nil)
+	g.Set("banana", map[string]interface{}{"x": []interface{}{true, 1.5}})
+	g.Set("", nil)
+
+	g.Connect("apple", "banana", 5)
+	g.Connect("apple", "apply", -300)
+	g.Connect("banana", "", 1<<40)
+	g.Connect("", "apple", 0)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteCompact(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	read, err := ReadCompact(bytes.NewReader(buf.Bytes()))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if read.Len() != g.Len() {
+		t.Errorf("expected %d vertices, got %d", g.Len(), read.Len())
+	}
+	for key, v := range g.vertices {
+		readV, err := read.Get(key)
+		if err != nil {
+			t.Errorf("%q: unexpected error %v", key, err)
+			continue
+		}
+		if !reflect.DeepEqual(readV.Value(), v.Value()) {
+			t.Errorf("%q: expected value %v, got %v", key, v.Value(), readV.Value())
+		}
+	}
+	for _, e := range []Edge{{From: "apple", To: "banana", Weight: 5}, {From: "apple", To: "apply", Weight: -300}, {From: "banana", To: "", Weight: 1 << 40}, {From: "", To: "apple", Weight: 0}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%q → %q: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+
+	// unsupported values fail
+	g.Set("apple", struct{}{})
+	if err := g.WriteCompact(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
+
+func TestCompactSize(t *testing.T) {
+	g := New()
+	for i := 0; i < 1000; i++ {
+		g.Set(fmt.Sprintf("vertex-%04d", i), nil)
+	}
+	for i := 0; i < 1000; i++ {
+		for j := 1; j <= 10; j++ {
+			g.Connect(fmt.Sprintf("vertex-%04d", i), fmt.Sprintf("vertex-%04d", (i+j)%1000), j)
+		}
+	}
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteCompact(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	gob, err := g.GobEncode()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// front-coded keys and two bytes per edge
+	if buf.Len() > 30000 || buf.Len() >= len(gob)/3 {
+		t.Errorf("expected a compact encoding, got %d bytes (gob: %d)", buf.Len(), len(gob))
+	}
+
+	read, err := ReadCompact(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := read.IsConnected("vertex-0999", "vertex-0009"); !ok || weight != 10 {
+		t.Errorf("expected edge of weight 10, got %v %d", ok, weight)
+	}
+}
+
+func TestReadCompactErrors(t *testing.T) {
+	g := New()
+	g.Set("a", "value")
+	g.Set("b", 2)
+	g.Connect("a", "b", 1)
+	g.Connect("b", "a", 2)
+
+	buf := &bytes.Buffer{}
+	g.WriteCompact(buf)
+	b := buf.Bytes()
+
+	// every truncation fails
+	for i := 0; i < len(b); i++ {
+		if _, err := ReadCompact(bytes.NewReader(b[:i])); err == nil {
+			t.Errorf("expected an error when truncated to %d bytes", i)
+		}
+	}
+
+	if _, err := ReadCompact(bytes.NewReader([]byte("not compact"))); err == nil {
+		t.Error("expected an error for a missing header")
+	}
+
+	newer := append([]byte(compactMagic), compactVersion+1)
+	if _, err := ReadCompact(bytes.NewReader(newer)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+
+	// a neighbor out of range, a repeated neighbor and keys out of order
+	for _, corrupt := range [][]byte{
+		append([]byte(compactMagic+"\x01\x01\x00\x01a\x00"), 1, 1, 0),
+		append([]byte(compactMagic+"\x01\x02\x00\x01a\x00\x00\x01b\x00"), 2, 1, 0, 0, 0, 0),
+		[]byte(compactMagic + "\x01\x02\x00\x01b\x00\x00\x01a\x00\x00\x00"),
+	} {
+		if _, err := ReadCompact(bytes.NewReader(corrupt)); err == nil {
+			t.Errorf("%q: expected an error", corrupt)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"sort"
+)
+
+// WeaklyConnectedComponents returns the keys of the vertices in each weakly connected component of the graph, i.e. the components the graph falls apart into when edge directions are ignored.
+// Keys are sorted within each component, and components are sorted by their first key.
+func (g *Graph) WeaklyConnectedComponents() (components [][]string) {
+	defer g.track("WeaklyConnectedComponents")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	for _, component := range g.weakComponents() {
+		keys := make([]string, len(component))
+		for i, v := range component {
+			keys[i] = v.key
+		}
+		sort.Strings(keys)
+
+		components = append(components, keys)
+	}
+
+	sort.Slice(components, func(i, j int) bool {
+		return components[i][0] < components[j][0]
+	})
+
+	return
+}
+
+// IsWeaklyConnected returns true if every vertex can be reached from every other vertex when edge directions are ignored. An empty graph is considered connected.
+func (g *Graph) IsWeaklyConnected() bool {
+	defer g.track("IsWeaklyConnected")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return len(g.weakComponents()) <= 1
+}
+
+// weakComponents is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the vertices of each weakly connected component.
+func (g *Graph) weakComponents() (components [][]*Vertex) {
+	visited := map[*Vertex]bool{}
+
+	for _, v := range g.vertices {
+		if visited[v] {
+			continue
+		}
+
+		// breadth-first search in both edge directions
+		visited[v] = true
+		component := []*Vertex{v}
+
+		for i := 0; i < len(component); i++ {
+			current := component[i]
+
+			for neighbor := range current.GetOutgoing() {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					component = append(component, neighbor)
+				}
+			}
+
+			for neighbor := range current.GetIncoming() {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					component = append(component, neighbor)
+				}
+			}
+		}
+
+		components = append(components, component)
+	}
+
+	return
+}
@@ -0,0 +1,51 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestWeaklyConnectedComponents(t *testing.T) {
+	g := New()
+
+	if !g.IsWeaklyConnected() || len(g.WeaklyConnectedComponents()) != 0 {
+		t.Fail()
+	}
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Set("3", nil)
+	g.Set("4", nil)
+	g.Set("5", nil)
+
+	// 1 → 2 ← 3 is connected when ignoring directions
+	g.Connect("1", "2", 1)
+	g.Connect("3", "2", 1)
+	g.Connect("4", "5", 1)
+
+	components := g.WeaklyConnectedComponents()
+	if len(components) != 2 {
+		t.Fatalf("expected 2 components, got %v", components)
+	}
+
+	if len(components[0]) != 3 || components[0][0] != "1" || components[0][1] != "2" || components[0][2] != "3" {
+		t.Fail()
+	}
+	if len(components[1]) != 2 || components[1][0] != "4" || components[1][1] != "5" {
+		t.Fail()
+	}
+
+	if g.IsWeaklyConnected() {
+		t.Fail()
+	}
+
+	g.Connect("5", "3", 1)
+	if !g.IsWeaklyConnected() {
+		t.Fail()
+	}
+
+	// an isolated vertex is a component of its own
+	g.Set("6", nil)
+	if len(g.WeaklyConnectedComponents()) != 2 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,386 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+	"sync/atomic"
+)
+
+// ErrNoHierarchy is returned by CHShortestPath if no contraction hierarchy was built, or if it is outdated because edges or vertices changed since.
+var ErrNoHierarchy = errors.New("graph: no up-to-date contraction hierarchy")
+
+// witnessLimit bounds the number of vertices settled by each witness search while building a contraction hierarchy. Aborted searches add shortcuts which might not be needed, which only costs query time.
+const witnessLimit = 500
+
+// contractionHierarchy is the auxiliary structure built by BuildContractionHierarchy. Vertices are numbered in the order of the graph's sorted keys.
+type contractionHierarchy struct {
+	keys   []string
+	index  map[string]int
+	up     [][]chArc      // arcs from each vertex to vertices contracted later
+	down   [][]chArc      // reversed arcs to each vertex from vertices contracted later
+	middle map[[2]int]int // maps shortcut arcs to the vertex they bypass
+	stale  int32          // set to 1 when the graph changes, accessed atomically
+	cancel func()         // cancels the subscription to the graph's events
+}
+
+// chArc is an edge or shortcut of a contraction hierarchy.
+type chArc struct {
+	to     int
+	weight int64
+}
+
+// BuildContractionHierarchy preprocesses the graph for fast point-to-point queries with CHShortestPath, e.g. for routing in road networks: it contracts the vertices one by one, least important first, adding shortcut edges which preserve the shortest paths between the remaining vertices.
+// The hierarchy is kept until it is rebuilt, but becomes outdated as soon as an edge or vertex is deleted, or an edge is created or changes its weight. Setting values doesn't affect it.
+// Returns a *WeightError wrapping ErrNegativeWeight if the graph has negative edge weights.
+func (g *Graph) BuildContractionHierarchy() error {
+	defer g.track("BuildContractionHierarchy")()
+
+	ch := &contractionHierarchy{}
+
+	// subscribe before building, so changes made in between mark the hierarchy as outdated
+	g.Lock()
+	ch.cancel = g.subscribe(func(e Event) {
+		switch e.Type {
+		case EventDelete, EventConnect, EventDisconnect:
+			atomic.StoreInt32(&ch.stale, 1)
+		}
+	})
+	g.Unlock()
+
+	g.RLock()
+	err := g.contract(ch)
+	g.RUnlock()
+
+	if err != nil {
+		ch.cancel()
+		return err
+	}
+
+	g.Lock()
+	old := g.hierarchy
+	g.hierarchy = ch
+	g.Unlock()
+
+	// cancelling locks the graph
+	if old != nil {
+		old.cancel()
+	}
+
+	return nil
+}
+
+// contract is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It builds the hierarchy ch of the graph.
+func (g *Graph) contract(ch *contractionHierarchy) error {
+	ch.keys = append([]string(nil), g.sortedKeys()...)
+	ch.index = make(map[string]int, len(ch.keys))
+	for i, key := range ch.keys {
+		ch.index[key] = i
+	}
+	ch.middle = map[[2]int]int{}
+
+	// adjacency of the vertices which are not contracted yet, including shortcuts
+	n := len(ch.keys)
+	out := make([]map[int]int64, n)
+	in := make([]map[int]int64, n)
+	for i := range ch.keys {
+		out[i] = map[int]int64{}
+		in[i] = map[int]int64{}
+	}
+
+	for i, key := range ch.keys {
+		for neighbor, weight := range g.vertices[key].GetOutgoing() {
+			if weight < 0 {
+				return &WeightError{key, neighbor.key, weight, ErrNegativeWeight}
+			}
+
+			j := ch.index[neighbor.key]
+			out[i][j] = int64(weight)
+			in[j][i] = int64(weight)
+		}
+	}
+
+	c := &contractor{out: out, in: in, middle: ch.middle, contractedNeighbors: make([]int, n)}
+
+	order := &chQueue{}
+	for i := 0; i < n; i++ {
+		heap.Push(order, chItem{i, int64(c.priority(i))})
+	}
+
+	rank := make([]int, n)
+	var arcs [][3]int64 // from, to, weight of all edges and shortcuts
+
+	for next := 0; order.Len() > 0; {
+		item := heap.Pop(order).(chItem)
+
+		// priorities change as neighbors are contracted, so they are updated lazily
+		if p := int64(c.priority(item.v)); order.Len() > 0 && p > (*order)[0].priority {
+			heap.Push(order, chItem{item.v, p})
+			continue
+		}
+
+		v := item.v
+		rank[v] = next
+		next++
+
+		c.contract(v, true)
+
+		// the arcs of v are final now
+		for to, weight := range out[v] {
+			arcs = append(arcs, [3]int64{int64(v), int64(to), weight})
+			delete(in[to], v)
+			c.contractedNeighbors[to]++
+		}
+		for from, weight := range in[v] {
+			arcs = append(arcs, [3]int64{int64(from), int64(v), weight})
+			delete(out[from], v)
+			c.contractedNeighbors[from]++
+		}
+		out[v], in[v] = nil, nil
+	}
+
+	ch.up = make([][]chArc, n)
+	ch.down = make([][]chArc, n)
+	for _, a := range arcs {
+		from, to := int(a[0]), int(a[1])
+		if rank[from] < rank[to] {
+			ch.up[from] = append(ch.up[from], chArc{to, a[2]})
+		} else {
+			ch.down[to] = append(ch.down[to], chArc{from, a[2]})
+		}
+	}
+
+	return nil
+}
+
+// contractor holds the state of the contraction of a graph.
+type contractor struct {
+	out, in             []map[int]int64 // adjacency of the vertices not contracted yet
+	middle              map[[2]int]int  // maps shortcuts to the vertex they bypass
+	contractedNeighbors []int           // number of contracted neighbors of each vertex, to contract evenly
+}
+
+// priority returns how important v is: contracting vertices which add few shortcuts compared to the edges they remove first keeps the hierarchy small.
+func (c *contractor) priority(v int) int {
+	return c.contract(v, false) - len(c.in[v]) - len(c.out[v]) + c.contractedNeighbors[v]
+}
+
+// contract adds the shortcuts needed to remove v, if apply is true, and returns how many are needed.
+func (c *contractor) contract(v int, apply bool) int {
+	var maxOut int64
+	for _, weight := range c.out[v] {
+		if weight > maxOut {
+			maxOut = weight
+		}
+	}
+
+	shortcuts := 0
+	for from, w1 := range c.in[v] {
+		dist := c.witnessSearch(from, v, w1+maxOut)
+
+		for to, w2 := range c.out[v] {
+			if to == from {
+				continue
+			}
+
+			// a witness path avoiding v is at least as short, no shortcut needed
+			if d, ok := dist[to]; ok && d <= w1+w2 {
+				continue
+			}
+			if weight, ok := c.out[from][to]; ok && weight <= w1+w2 {
+				continue
+			}
+
+			shortcuts++
+			if apply {
+				c.out[from][to] = w1 + w2
+				c.in[to][from] = w1 + w2
+				c.middle[[2]int{from, to}] = v
+			}
+		}
+	}
+
+	return shortcuts
+}
+
+// witnessSearch returns the distances from source to the vertices up to limit away, avoiding the vertex skip, computed by Dijkstra's algorithm. It gives up after settling witnessLimit vertices.
+func (c *contractor) witnessSearch(source, skip int, limit int64) map[int]int64 {
+	dist := map[int]int64{source: 0}
+	settled := map[int]bool{}
+
+	queue := &chQueue{}
+	heap.Push(queue, chItem{source, 0})
+
+	for queue.Len() > 0 && len(settled) < witnessLimit {
+		item := heap.Pop(queue).(chItem)
+		if settled[item.v] {
+			continue
+		}
+		settled[item.v] = true
+
+		d := dist[item.v]
+		if d > limit {
+			break
+		}
+
+		for to, weight := range c.out[item.v] {
+			if to == skip {
+				continue
+			}
+
+			if known, ok := dist[to]; !ok || d+weight < known {
+				dist[to] = d + weight
+				heap.Push(queue, chItem{to, d + weight})
+			}
+		}
+	}
+
+	return dist
+}
+
+// CHShortestPath returns the shortest path from the vertex with key startKey to the vertex with key endKey in start → end order, using the hierarchy built by BuildContractionHierarchy: a bidirectional Dijkstra search which only follows edges to more important vertices, settling far fewer vertices than A* on large graphs.
+// Returns ErrNoHierarchy if there is no up-to-date hierarchy, ErrInvalidKey if one of the keys is invalid and ErrNoPath if there is no path.
+func (g *Graph) CHShortestPath(startKey, endKey string) ([]string, error) {
+	defer g.track("CHShortestPath")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	ch := g.hierarchy
+	if ch == nil || atomic.LoadInt32(&ch.stale) != 0 {
+		return nil, ErrNoHierarchy
+	}
+
+	if g.get(startKey) == nil || g.get(endKey) == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if startKey == endKey {
+		return []string{startKey}, nil
+	}
+
+	// vertices created after the hierarchy was built have no edges yet
+	start, ok := ch.index[startKey]
+	if !ok {
+		return nil, ErrNoPath
+	}
+	end, ok := ch.index[endKey]
+	if !ok {
+		return nil, ErrNoPath
+	}
+
+	arcs := ch.query(start, end)
+	if arcs == nil {
+		return nil, ErrNoPath
+	}
+
+	path := []string{startKey}
+	for i := 1; i < len(arcs); i++ {
+		for _, v := range ch.unpack(arcs[i-1], arcs[i]) {
+			path = append(path, ch.keys[v])
+		}
+	}
+
+	return path, nil
+}
+
+// query returns the vertices along the shortest path from start to end in the hierarchy, connected by edges or shortcuts, or nil if there is no path.
+func (ch *contractionHierarchy) query(start, end int) []int {
+	type search struct {
+		arcs  [][]chArc
+		dist  map[int]int64
+		prev  map[int]int
+		queue *chQueue
+	}
+
+	forward := &search{ch.up, map[int]int64{start: 0}, map[int]int{}, &chQueue{{start, 0}}}
+	backward := &search{ch.down, map[int]int64{end: 0}, map[int]int{}, &chQueue{{end, 0}}}
+
+	best, meeting := int64(-1), -1
+
+	for forward.queue.Len() > 0 || backward.queue.Len() > 0 {
+		for _, s := range []*search{forward, backward} {
+			if s.queue.Len() == 0 {
+				continue
+			}
+
+			item := heap.Pop(s.queue).(chItem)
+			d := s.dist[item.v]
+			if item.priority > d {
+				continue // outdated queue entry
+			}
+
+			// no shorter path can be found from here
+			if best >= 0 && d >= best {
+				s.queue = &chQueue{}
+				continue
+			}
+
+			other := backward
+			if s == backward {
+				other = forward
+			}
+			if od, ok := other.dist[item.v]; ok && (best < 0 || d+od < best) {
+				best, meeting = d+od, item.v
+			}
+
+			for _, a := range s.arcs[item.v] {
+				if known, ok := s.dist[a.to]; !ok || d+a.weight < known {
+					s.dist[a.to] = d + a.weight
+					s.prev[a.to] = item.v
+					heap.Push(s.queue, chItem{a.to, d + a.weight})
+				}
+			}
+		}
+	}
+
+	if meeting < 0 {
+		return nil
+	}
+
+	var path []int
+	for v := meeting; v != start; v = forward.prev[v] {
+		path = append(path, v)
+	}
+	path = append(path, start)
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	for v := meeting; v != end; {
+		v = backward.prev[v]
+		path = append(path, v)
+	}
+
+	return path
+}
+
+// unpack returns the vertices along the edge or shortcut from one vertex to another, excluding from.
+func (ch *contractionHierarchy) unpack(from, to int) []int {
+	v, ok := ch.middle[[2]int{from, to}]
+	if !ok {
+		return []int{to}
+	}
+
+	return append(ch.unpack(from, v), ch.unpack(v, to)...)
+}
+
+// chItem is an entry of a chQueue.
+type chItem struct {
+	v        int
+	priority int64
+}
+
+// chQueue is a min-heap of vertices by priority, implementing heap.Interface.
+type chQueue []chItem
+
+func (q chQueue) Len() int            { return len(q) }
+func (q chQueue) Less(i, j int) bool  { return q[i].priority < q[j].priority }
+func (q chQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *chQueue) Push(x interface{}) { *q = append(*q, x.(chItem)) }
+
+func (q *chQueue) Pop() interface{} {
+	item := (*q)[len(*q)-1]
+	*q = (*q)[:len(*q)-1]
+	return item
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestContractionHierarchy(t *testing.T) {
+	g := New()
+
+	if _, err := g.CHShortestPath("a", "b"); err != ErrNoHierarchy {
+		t.Fail()
+	}
+
+	// random sparse graph with some unreachable pairs
+	r := rand.New(rand.NewSource(1))
+	n := 60
+	for i := 0; i < n; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+	for i := 0; i < 3*n; i++ {
+		g.Connect(strconv.Itoa(r.Intn(n)), strconv.Itoa(r.Intn(n)), r.Intn(20))
+	}
+
+	if err := g.BuildContractionHierarchy(); err != nil {
+		t.Fatal(err)
+	}
+
+	cost := func(path []string) int {
+		sum := 0
+		for i := 1; i < len(path); i++ {
+			ok, weight := g.IsConnected(path[i-1], path[i])
+			if !ok {
+				t.Fatalf("path %v uses missing edge %s → %s", path, path[i-1], path[i])
+			}
+			sum += weight
+		}
+		return sum
+	}
+
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			start, end := strconv.Itoa(i), strconv.Itoa(j)
+
+			expected, err := g.ShortestPath(start, end)
+			path, chErr := g.CHShortestPath(start, end)
+
+			if err != chErr {
+				t.Fatalf("%s → %s: expected error %v, got %v", start, end, err, chErr)
+			}
+			if err != nil {
+				continue
+			}
+
+			if path[0] != start || path[len(path)-1] != end || cost(path) != cost(expected) {
+				t.Fatalf("%s → %s: expected a path of cost %d like %v, got %v", start, end, cost(expected), expected, path)
+			}
+		}
+	}
+
+	if _, err := g.CHShortestPath("0", "x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+
+	// new vertices without edges don't outdate the hierarchy, new edges do
+	g.Set("new", nil)
+	if _, err := g.CHShortestPath("0", "new"); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+	g.Connect("0", "new", 1)
+	if _, err := g.CHShortestPath("0", "new"); err != ErrNoHierarchy {
+		t.Errorf("expected ErrNoHierarchy, got %v", err)
+	}
+
+	if err := g.BuildContractionHierarchy(); err != nil {
+		t.Fatal(err)
+	}
+	if path, err := g.CHShortestPath("0", "new"); err != nil || len(path) != 2 {
+		t.Fail()
+	}
+	if len(g.subscribers) != 1 {
+		t.Errorf("expected the old hierarchy to unsubscribe, %d subscribers left", len(g.subscribers))
+	}
+
+	g.Connect("1", "2", -1)
+	if err := g.BuildContractionHierarchy(); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
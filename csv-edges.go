@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CSVOption configures ReadCSVEdges and WriteCSVEdges.
+type CSVOption func(*csvConfig)
+
+// csvConfig holds the settings of an edge list in CSV format.
+type csvConfig struct {
+	delimiter     rune
+	header        bool
+	createMissing bool
+	missingValue  interface{}
+}
+
+// CSVDelimiter sets the field delimiter, which is a comma by default.
+func CSVDelimiter(delimiter rune) CSVOption {
+	return func(cfg *csvConfig) {
+		cfg.delimiter = delimiter
+	}
+}
+
+// CSVHeader makes WriteCSVEdges start with a "from,to,weight" header row, and ReadCSVEdges expect one: its column names (case-insensitive) tell which columns hold the endpoints and weights, so they may come in any order and other columns are ignored.
+func CSVHeader() CSVOption {
+	return func(cfg *csvConfig) {
+		cfg.header = true
+	}
+}
+
+// CSVCreateMissing makes ReadCSVEdges create the vertices edges refer to which don't exist, with the given value.
+func CSVCreateMissing(value interface{}) CSVOption {
+	return func(cfg *csvConfig) {
+		cfg.createMissing = true
+		cfg.missingValue = value
+	}
+}
+
+// newCSVConfig applies opts to the default settings.
+func newCSVConfig(opts []CSVOption) *csvConfig {
+	cfg := &csvConfig{delimiter: ','}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return cfg
+}
+
+// ReadCSVEdges connects the edges read from r, an edge list in CSV format with from,to,weight rows. The weight column is optional, missing or empty weights are 1.
+// Edges may only refer to existing vertices unless CSVCreateMissing is given; all others are reported by an *ImportError after connecting the rest.
+func (g *Graph) ReadCSVEdges(r io.Reader, opts ...CSVOption) error {
+	defer g.track("ReadCSVEdges")()
+
+	cfg := newCSVConfig(opts)
+
+	reader := csv.NewReader(r)
+	reader.Comma = cfg.delimiter
+	reader.FieldsPerRecord = -1
+
+	from, to, weight := 0, 1, 2
+	row := 0
+
+	if cfg.header {
+		header, err := reader.Read()
+		if err != nil {
+			return fmt.Errorf("graph: reading CSV header: %v", err)
+		}
+		row++
+
+		from, to, weight = -1, -1, -1
+		for i, name := range header {
+			switch strings.ToLower(strings.TrimSpace(name)) {
+			case "from":
+				from = i
+			case "to":
+				to = i
+			case "weight":
+				weight = i
+			}
+		}
+		if from < 0 || to < 0 {
+			return fmt.Errorf("graph: reading CSV header: missing from or to column")
+		}
+	}
+
+	im := g.NewImporter()
+	im.CreateMissing = cfg.createMissing
+	im.Placeholder = func(string) interface{} { return cfg.missingValue }
+
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("graph: reading CSV: %v", err)
+		}
+		row++
+
+		if from >= len(record) || to >= len(record) {
+			return fmt.Errorf("graph: row %d: missing from or to field", row)
+		}
+
+		w := 1
+		if weight >= 0 && weight < len(record) && record[weight] != "" {
+			if w, err = strconv.Atoi(strings.TrimSpace(record[weight])); err != nil {
+				return fmt.Errorf("graph: row %d: invalid weight %q", row, record[weight])
+			}
+		}
+
+		im.connect(fmt.Sprintf("row %d", row), record[from], record[to], w)
+	}
+
+	return im.Finalize()
+}
+
+// WriteCSVEdges writes the edges of the graph to w as from,to,weight rows, sorted by key. Vertices without edges are not written.
+func (g *Graph) WriteCSVEdges(w io.Writer, opts ...CSVOption) error {
+	defer g.track("WriteCSVEdges")()
+
+	cfg := newCSVConfig(opts)
+
+	writer := csv.NewWriter(w)
+	writer.Comma = cfg.delimiter
+
+	if cfg.header {
+		writer.Write([]string{"from", "to", "weight"})
+	}
+
+	g.RLock()
+
+	for _, key := range g.sortedKeys() {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			writer.Write([]string{key, neighbor.key, strconv.Itoa(outgoing[neighbor])})
+		}
+	}
+
+	g.RUnlock()
+
+	writer.Flush()
+	return writer.Error()
+}
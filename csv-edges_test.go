@@ -0,0 +1,78 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestCSVEdgesRoundTrip(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c;d"} {
+		g.Set(key, nil)
+	}
+	g.Connect("b", "c;d", -2)
+	g.Connect("a", "b", 5)
+	g.Connect("a", "c;d", 1)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteCSVEdges(buf, CSVDelimiter(';'), CSVHeader()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := "from;to;weight\na;b;5\na;\"c;d\";1\nb;\"c;d\";-2\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	read := New()
+	if err := read.ReadCSVEdges(buf, CSVDelimiter(';'), CSVHeader(), CSVCreateMissing("new")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 5}, {From: "a", To: "c;d", Weight: 1}, {From: "b", To: "c;d", Weight: -2}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+
+	if v, _ := read.Get("a"); v == nil || v.Value() != "new" {
+		t.Error("expected a to be created with the default value")
+	}
+}
+
+func TestReadCSVEdges(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+
+	// columns in any order, extra columns ignored, missing weights default to 1
+	src := "Weight,note,To,From\n3,x,b,a\n,y,a,b\n"
+	if err := g.ReadCSVEdges(strings.NewReader(src), CSVHeader()); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 3 {
+		t.Errorf("expected a → b with weight 3, got %v %d", ok, weight)
+	}
+	if ok, weight := g.IsConnected("b", "a"); !ok || weight != 1 {
+		t.Errorf("expected b → a with weight 1, got %v %d", ok, weight)
+	}
+
+	// without CSVCreateMissing, edges to unknown vertices are reported
+	err := g.ReadCSVEdges(strings.NewReader("a,x,1\nb,a\n"))
+	importErr, ok := err.(*ImportError)
+	if !ok || len(importErr.References) != 1 || importErr.References[0].Source != "row 1" {
+		t.Errorf("expected an import error for row 1, got %v", err)
+	}
+
+	if err := g.ReadCSVEdges(strings.NewReader("a,b,heavy\n")); err == nil {
+		t.Error("expected an error for an invalid weight")
+	}
+	if err := g.ReadCSVEdges(strings.NewReader("a\n")); err == nil {
+		t.Error("expected an error for a missing field")
+	}
+	if err := g.ReadCSVEdges(strings.NewReader("source,target\n"), CSVHeader()); err == nil {
+		t.Error("expected an error for a header without from and to")
+	}
+}
@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// CypherOption configures the output of WriteCypher.
+type CypherOption func(*cypherConfig)
+
+// cypherConfig holds the settings of WriteCypher.
+type cypherConfig struct {
+	label, relationship string
+}
+
+// CypherLabel sets the label of the created nodes, which is Vertex by default.
+func CypherLabel(label string) CypherOption {
+	return func(cfg *cypherConfig) {
+		cfg.label = label
+	}
+}
+
+// CypherRelationship sets the type of the created relationships, which is EDGE by default.
+func CypherRelationship(relationship string) CypherOption {
+	return func(cfg *cypherConfig) {
+		cfg.relationship = relationship
+	}
+}
+
+// WriteCypher writes Cypher statements to w which create the graph in Neo4j, e.g. when piped into cypher-shell: an index on the key property, a node with key and value properties for every vertex, and a relationship with a weight property for every edge. There is one statement per line, in key order.
+// Booleans, integers, floats and strings are written as literals, nil values are left out, and all other values are written as JSON strings, or formatted with fmt.Sprint if they can't be marshaled, since Neo4j doesn't support nested properties.
+func (g *Graph) WriteCypher(w io.Writer, opts ...CypherOption) error {
+	defer g.track("WriteCypher")()
+
+	cfg := &cypherConfig{label: "Vertex", relationship: "EDGE"}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	label, relationship := cypherIdentifier(cfg.label), cypherIdentifier(cfg.relationship)
+
+	g.RLock()
+	defer g.RUnlock()
+
+	bw := bufio.NewWriter(w)
+
+	// edges find their endpoints by key
+	fmt.Fprintf(bw, "CREATE INDEX IF NOT EXISTS FOR (n:%s) ON (n.key);\n", label)
+
+	keys := g.sortedKeys()
+
+	for _, key := range keys {
+		fmt.Fprintf(bw, "CREATE (:%s {key: %s", label, cypherString(key))
+		if value := g.vertices[key].Value(); value != nil {
+			fmt.Fprintf(bw, ", value: %s", cypherValue(value))
+		}
+		bw.WriteString("});\n")
+	}
+
+	for _, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			fmt.Fprintf(bw, "MATCH (a:%s {key: %s}), (b:%s {key: %s}) CREATE (a)-[:%s {weight: %d}]->(b);\n",
+				label, cypherString(key), label, cypherString(neighbor.key), relationship, outgoing[neighbor])
+		}
+	}
+
+	return bw.Flush()
+}
+
+// cypherValue formats value as a Cypher literal.
+func cypherValue(value interface{}) string {
+	switch v := value.(type) {
+	case bool:
+		return strconv.FormatBool(v)
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		return fmt.Sprint(v)
+	case float32:
+		return cypherFloat(float64(v))
+	case float64:
+		return cypherFloat(v)
+	case string:
+		return cypherString(v)
+	}
+
+	if b, err := json.Marshal(value); err == nil {
+		return cypherString(string(b))
+	}
+	return cypherString(fmt.Sprint(value))
+}
+
+// cypherFloat formats f as a Cypher float literal. Infinite and NaN values have no literal and are written as strings.
+func cypherFloat(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return cypherString(fmt.Sprint(f))
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+// cypherQuoter escapes the characters which can't appear literally in single-quoted Cypher strings.
+var cypherQuoter = strings.NewReplacer(`\`, `\\`, `'`, `\'`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+
+// cypherString returns s as a single-quoted Cypher string literal.
+func cypherString(s string) string {
+	return "'" + cypherQuoter.Replace(s) + "'"
+}
+
+// cypherIdentifier returns name as a backtick-quoted Cypher identifier, so it may contain any characters.
+func cypherIdentifier(name string) string {
+	return "`" + strings.Replace(name, "`", "``", -1) + "`"
+}
@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCypher(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", "it's\n")
+	g.Set("c", nil)
+	g.Set("d", map[string]interface{}{"x": []int{1, 2}})
+	g.Set("e", 2.0)
+
+	g.Connect("a", "b", 5)
+	g.Connect("c", "a", -1)
+	g.Connect("a", "c", 0)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteCypher(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := "CREATE INDEX IF NOT EXISTS FOR (n:`Vertex`) ON (n.key);\n" +
+		"CREATE (:`Vertex` {key: 'a', value: 1});\n" +
+		"CREATE (:`Vertex` {key: 'b', value: 'it\\'s\\n'});\n" +
+		"CREATE (:`Vertex` {key: 'c'});\n" +
+		"CREATE (:`Vertex` {key: 'd', value: '{\"x\":[1,2]}'});\n" +
+		"CREATE (:`Vertex` {key: 'e', value: 2.0});\n" +
+		"MATCH (a:`Vertex` {key: 'a'}), (b:`Vertex` {key: 'b'}) CREATE (a)-[:`EDGE` {weight: 5}]->(b);\n" +
+		"MATCH (a:`Vertex` {key: 'a'}), (b:`Vertex` {key: 'c'}) CREATE (a)-[:`EDGE` {weight: 0}]->(b);\n" +
+		"MATCH (a:`Vertex` {key: 'c'}), (b:`Vertex` {key: 'a'}) CREATE (a)-[:`EDGE` {weight: -1}]->(b);\n"
+
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	h := New()
+	h.Set("x", nil)
+	h.Set("y", nil)
+	h.Connect("x", "y", 1)
+
+	buf.Reset()
+	if err := h.WriteCypher(buf, CypherLabel("Package"), CypherRelationship("DEPENDS`ON")); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected = "CREATE INDEX IF NOT EXISTS FOR (n:`Package`) ON (n.key);\n" +
+		"CREATE (:`Package` {key: 'x'});\n" +
+		"CREATE (:`Package` {key: 'y'});\n" +
+		"MATCH (a:`Package` {key: 'x'}), (b:`Package` {key: 'y'}) CREATE (a)-[:`DEPENDS``ON` {weight: 1}]->(b);\n"
+
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
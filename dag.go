@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"container/heap"
+	"errors"
+)
+
+// ErrCycle is returned by operations that require the graph to be a directed acyclic graph (DAG) if it contains a cycle.
+var ErrCycle = errors.New("graph: graph contains a cycle")
+
+// TopologicalSort returns the keys of all vertices in an order in which every edge leads from an earlier to a later vertex, e.g. an order in which dependencies can be processed.
+// Among the vertices which could come next, the one with the smallest key is chosen, so the order is deterministic. Returns ErrCycle if the graph contains a cycle.
+func (g *Graph) TopologicalSort() (keys []string, err error) {
+	defer g.track("TopologicalSort")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	order, err := g.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	keys = make([]string, len(order))
+	for i, v := range order {
+		keys[i] = v.key
+	}
+
+	return
+}
+
+// topologicalOrder is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It sorts the vertices topologically using Kahn's algorithm, see TopologicalSort.
+func (g *Graph) topologicalOrder() (order []*Vertex, err error) {
+	// number of incoming edges from vertices not in the order yet
+	remaining := make(map[*Vertex]int, len(g.vertices))
+	ready := &keyHeap{}
+
+	for _, v := range g.vertices {
+		remaining[v] = len(v.GetIncoming())
+		if remaining[v] == 0 {
+			heap.Push(ready, v)
+		}
+	}
+
+	for ready.Len() > 0 {
+		v := heap.Pop(ready).(*Vertex)
+		order = append(order, v)
+
+		for neighbor := range v.GetOutgoing() {
+			remaining[neighbor]--
+			if remaining[neighbor] == 0 {
+				heap.Push(ready, neighbor)
+			}
+		}
+	}
+
+	// vertices on or behind cycles never become ready
+	if len(order) < len(g.vertices) {
+		return nil, ErrCycle
+	}
+
+	return
+}
+
+// keyHeap implements heap.Interface and holds vertices ordered by key.
+type keyHeap []*Vertex
+
+func (h keyHeap) Len() int            { return len(h) }
+func (h keyHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h keyHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *keyHeap) Push(x interface{}) { *h = append(*h, x.(*Vertex)) }
+
+func (h *keyHeap) Pop() interface{} {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return v
+}
+
+// TransitiveReduction returns a new graph with the same vertices (values are copied shallowly) and reachability as this one, but with the minimal set of edges: every edge from u to v for which there is another path from u to v is left out.
+// The remaining edges keep their weights. The graph must be a DAG; returns ErrCycle otherwise.
+func (g *Graph) TransitiveReduction() (*Graph, error) {
+	defer g.track("TransitiveReduction")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	redundant, err := g.redundantEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	reduction := New()
+	for key, v := range g.vertices {
+		reduction.vertices[key] = newVertex(key, v.Value())
+	}
+
+	for key, v := range g.vertices {
+		from := reduction.vertices[key]
+
+		for neighbor, weight := range v.GetOutgoing() {
+			if redundant[[2]*Vertex{v, neighbor}] {
+				continue
+			}
+
+			to := reduction.vertices[neighbor.key]
+			from.outgoingEdges[to] = weight
+			to.incomingEdges[from] = weight
+		}
+	}
+
+	return reduction, nil
+}
+
+// RemoveTransitiveEdges turns the graph into its transitive reduction in place, see TransitiveReduction, and returns the number of edges removed.
+// The graph must be a DAG; returns ErrCycle otherwise, without changing the graph.
+func (g *Graph) RemoveTransitiveEdges() (removed int, err error) {
+	defer g.track("RemoveTransitiveEdges")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	redundant, err := g.redundantEdges()
+	if err != nil {
+		return 0, err
+	}
+
+	for edge := range redundant {
+		g.disconnect(edge[0], edge[1])
+	}
+
+	return len(redundant), nil
+}
+
+// redundantEdges is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the edges of the DAG which are implied by other paths, or ErrCycle if the graph isn't a DAG.
+func (g *Graph) redundantEdges() (map[[2]*Vertex]bool, error) {
+	if _, err := g.topologicalOrder(); err != nil {
+		return nil, err
+	}
+
+	redundant := map[[2]*Vertex]bool{}
+
+	for _, v := range g.vertices {
+		children := v.GetOutgoing()
+
+		// vertices reachable from v via at least two edges
+		var grandchildren []*Vertex
+		for child := range children {
+			for grandchild := range child.GetOutgoing() {
+				grandchildren = append(grandchildren, grandchild)
+			}
+		}
+
+		// in a DAG, a child can't be reached via itself, so reaching it means there is a longer path
+		for reached := range g.reachable(grandchildren) {
+			if _, ok := children[reached]; ok {
+				redundant[[2]*Vertex{v, reached}] = true
+			}
+		}
+	}
+
+	return redundant, nil
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTopologicalSort(t *testing.T) {
+	g := New()
+	for _, key := range []string{"shirt", "tie", "jacket", "belt", "pants", "shoes", "socks"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("shirt", "tie", 1)
+	g.Connect("tie", "jacket", 1)
+	g.Connect("shirt", "belt", 1)
+	g.Connect("belt", "jacket", 1)
+	g.Connect("pants", "belt", 1)
+	g.Connect("pants", "shoes", 1)
+	g.Connect("socks", "shoes", 1)
+
+	keys, err := g.TopologicalSort()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := []string{"pants", "shirt", "belt", "socks", "shoes", "tie", "jacket"}
+	if !reflect.DeepEqual(keys, expected) {
+		t.Fatalf("expected %v, got %v", expected, keys)
+	}
+
+	g.Connect("jacket", "shirt", 1)
+	if _, err = g.TopologicalSort(); err != ErrCycle {
+		t.Fail()
+	}
+}
+
+func TestTransitiveReduction(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, key)
+	}
+
+	// a → b → c → d with shortcuts a → c, a → d, b → d, and a → e
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "d", 3)
+	g.Connect("a", "c", 4)
+	g.Connect("a", "d", 5)
+	g.Connect("b", "d", 6)
+	g.Connect("a", "e", 7)
+
+	reduction, err := g.TransitiveReduction()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	kept := map[[2]string]int{{"a", "b"}: 1, {"b", "c"}: 2, {"c", "d"}: 3, {"a", "e"}: 7}
+	if reduction.Stats().Edges != len(kept) {
+		t.Fatalf("expected %d edges, got %d", len(kept), reduction.Stats().Edges)
+	}
+	for edge, weight := range kept {
+		if ok, w := reduction.IsConnected(edge[0], edge[1]); !ok || w != weight {
+			t.Errorf("expected edge %v", edge)
+		}
+	}
+	if v, _ := reduction.Get("e"); v.Value() != "e" {
+		t.Fail()
+	}
+
+	// in place
+	if removed, err := g.RemoveTransitiveEdges(); err != nil || removed != 3 {
+		t.Fatalf("expected 3 removed edges, got %d, %v", removed, err)
+	}
+	if ok, _ := g.IsConnected("a", "d"); ok {
+		t.Fail()
+	}
+
+	g.Connect("d", "a", 1)
+	if _, err = g.TransitiveReduction(); err != ErrCycle {
+		t.Fail()
+	}
+	if _, err = g.RemoveTransitiveEdges(); err != ErrCycle {
+		t.Fail()
+	}
+}
@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"sort"
+)
+
+// EdgeClass is the class of an edge with respect to a depth-first search, see OnEdge.
+type EdgeClass int
+
+const (
+	// TreeEdge leads to a vertex discovered through it.
+	TreeEdge EdgeClass = iota
+
+	// BackEdge leads to an ancestor of its start in the search tree, i.e. closes a cycle.
+	BackEdge
+
+	// ForwardEdge leads to a descendant of its start in the search tree which was discovered through another path.
+	ForwardEdge
+
+	// CrossEdge leads to a vertex which is neither an ancestor nor a descendant of its start, in the same or an earlier search tree.
+	CrossEdge
+)
+
+func (c EdgeClass) String() string {
+	switch c {
+	case TreeEdge:
+		return "tree"
+	case BackEdge:
+		return "back"
+	case ForwardEdge:
+		return "forward"
+	case CrossEdge:
+		return "cross"
+	}
+	return "unknown"
+}
+
+// DFSOption configures a search started with DFS.
+type DFSOption func(*dfsConfig)
+
+// dfsConfig holds the settings of a depth-first search.
+type dfsConfig struct {
+	pre, post func(v *Vertex) bool
+	edge      func(e Edge, class EdgeClass) bool
+	all       bool // restart from unvisited vertices until all are visited
+}
+
+// PreOrder makes DFS call fn when a vertex is discovered, before its descendants. The search stops when fn returns false.
+func PreOrder(fn func(v *Vertex) bool) DFSOption {
+	return func(cfg *dfsConfig) {
+		cfg.pre = fn
+	}
+}
+
+// PostOrder makes DFS call fn when a vertex is finished, after its descendants. The search stops when fn returns false.
+func PostOrder(fn func(v *Vertex) bool) DFSOption {
+	return func(cfg *dfsConfig) {
+		cfg.post = fn
+	}
+}
+
+// OnEdge makes DFS call fn with every edge it examines and its class, before following tree edges. The search stops when fn returns false.
+func OnEdge(fn func(e Edge, class EdgeClass) bool) DFSOption {
+	return func(cfg *dfsConfig) {
+		cfg.edge = fn
+	}
+}
+
+// VisitAll makes DFS continue with the unvisited vertices in key order after finishing the vertices reachable from the start vertex, until all vertices are visited.
+func VisitAll() DFSOption {
+	return func(cfg *dfsConfig) {
+		cfg.all = true
+	}
+}
+
+// DFS runs a depth-first search from the vertex with key startKey, following outgoing edges in key order, and reports the vertices and edges it encounters to the callbacks configured by opts.
+// The search is iterative, so deep graphs can't overflow the stack. Returns ErrInvalidKey if startKey is invalid.
+func (g *Graph) DFS(startKey string, opts ...DFSOption) error {
+	defer g.track("DFS")()
+
+	cfg := &dfsConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(startKey)
+	if start == nil {
+		return ErrInvalidKey
+	}
+
+	s := &dfsSearch{cfg: cfg, discovered: map[*Vertex]int{}, finished: map[*Vertex]bool{}}
+	if !s.run(start) || !cfg.all {
+		return nil
+	}
+
+	for _, key := range g.sortedKeys() {
+		if _, ok := s.discovered[g.vertices[key]]; ok {
+			continue
+		}
+		if !s.run(g.vertices[key]) {
+			break
+		}
+	}
+
+	return nil
+}
+
+// dfsSearch holds the state of a depth-first search.
+type dfsSearch struct {
+	cfg        *dfsConfig
+	discovered map[*Vertex]int // discovery time of each vertex
+	finished   map[*Vertex]bool
+}
+
+// dfsFrame is a vertex on the stack of a depth-first search, with its neighbors and the index of the next one to examine.
+type dfsFrame struct {
+	v         *Vertex
+	neighbors []*Vertex
+	next      int
+}
+
+// run searches from start. Returns false if the search was stopped.
+func (s *dfsSearch) run(start *Vertex) bool {
+	if !s.discover(start) {
+		return false
+	}
+	stack := []*dfsFrame{s.frame(start)}
+
+	for len(stack) > 0 {
+		top := stack[len(stack)-1]
+
+		if top.next == len(top.neighbors) {
+			stack = stack[:len(stack)-1]
+			s.finished[top.v] = true
+
+			if s.cfg.post != nil && !s.cfg.post(top.v) {
+				return false
+			}
+			continue
+		}
+
+		w := top.neighbors[top.next]
+		top.next++
+
+		class := s.classify(top.v, w)
+		if s.cfg.edge != nil && !s.cfg.edge(Edge{From: top.v.key, To: w.key, Weight: top.v.GetOutgoing()[w]}, class) {
+			return false
+		}
+
+		if class == TreeEdge {
+			if !s.discover(w) {
+				return false
+			}
+			stack = append(stack, s.frame(w))
+		}
+	}
+
+	return true
+}
+
+// discover records the discovery of v. Returns false if the search was stopped.
+func (s *dfsSearch) discover(v *Vertex) bool {
+	s.discovered[v] = len(s.discovered)
+	return s.cfg.pre == nil || s.cfg.pre(v)
+}
+
+// frame returns a stack frame for v with its neighbors sorted by key.
+func (s *dfsSearch) frame(v *Vertex) *dfsFrame {
+	f := &dfsFrame{v: v}
+	for neighbor := range v.GetOutgoing() {
+		f.neighbors = append(f.neighbors, neighbor)
+	}
+	sort.Slice(f.neighbors, func(i, j int) bool { return f.neighbors[i].key < f.neighbors[j].key })
+
+	return f
+}
+
+// classify returns the class of the edge from u to w, which is being examined.
+func (s *dfsSearch) classify(u, w *Vertex) EdgeClass {
+	discovered, ok := s.discovered[w]
+
+	switch {
+	case !ok:
+		return TreeEdge
+	case !s.finished[w]:
+		return BackEdge
+	case s.discovered[u] < discovered:
+		return ForwardEdge
+	default:
+		return CrossEdge
+	}
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDFS(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c → a (back), a → c (forward), d → c (cross, from a later tree), e → f isolated from a
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "a", 3)
+	g.Connect("a", "c", 4)
+	g.Connect("d", "c", 5)
+	g.Connect("d", "b", 6)
+	g.Connect("e", "f", 7)
+
+	var pre, post, edges []string
+	err := g.DFS("a",
+		PreOrder(func(v *Vertex) bool { pre = append(pre, v.Key()); return true }),
+		PostOrder(func(v *Vertex) bool { post = append(post, v.Key()); return true }),
+		OnEdge(func(e Edge, class EdgeClass) bool {
+			edges = append(edges, e.From+e.To+" "+class.String()+" "+strconv.Itoa(e.Weight))
+			return true
+		}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := []string{"a", "b", "c"}; !reflect.DeepEqual(pre, expected) {
+		t.Errorf("expected pre-order %v, got %v", expected, pre)
+	}
+	if expected := []string{"c", "b", "a"}; !reflect.DeepEqual(post, expected) {
+		t.Errorf("expected post-order %v, got %v", expected, post)
+	}
+	if expected := []string{"ab tree 1", "bc tree 2", "ca back 3", "ac forward 4"}; !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected edges %v, got %v", expected, edges)
+	}
+
+	// all vertices
+	pre, edges = nil, nil
+	g.DFS("a", VisitAll(),
+		PreOrder(func(v *Vertex) bool { pre = append(pre, v.Key()); return true }),
+		OnEdge(func(e Edge, class EdgeClass) bool {
+			edges = append(edges, e.From+e.To+" "+class.String())
+			return true
+		}),
+	)
+	if expected := []string{"a", "b", "c", "d", "e", "f"}; !reflect.DeepEqual(pre, expected) {
+		t.Errorf("expected pre-order %v, got %v", expected, pre)
+	}
+	if expected := []string{"ab tree", "bc tree", "ca back", "ac forward", "db cross", "dc cross", "ef tree"}; !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected edges %v, got %v", expected, edges)
+	}
+
+	// stopping early
+	n := 0
+	g.DFS("a", VisitAll(), PreOrder(func(v *Vertex) bool { n++; return n < 2 }))
+	if n != 2 {
+		t.Fail()
+	}
+
+	if g.DFS("x") != ErrInvalidKey {
+		t.Fail()
+	}
+}
+
+func TestDFSDeep(t *testing.T) {
+	g := New()
+	n := 100000
+	for i := 0; i < n; i++ {
+		g.Set(strconv.Itoa(i), nil)
+		if i > 0 {
+			g.Connect(strconv.Itoa(i-1), strconv.Itoa(i), 1)
+		}
+	}
+
+	visited := 0
+	g.DFS("0", PostOrder(func(v *Vertex) bool { visited++; return true }))
+	if visited != n {
+		t.Fail()
+	}
+}
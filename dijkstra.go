@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"container/heap"
+)
+
+// edgeCost returns the cost of traversing the edge from → to with the given weight, and false if the edge must not be used at all.
+type edgeCost func(from, to *Vertex, weight int) (cost int, ok bool)
+
+// dijkstra is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It runs Dijkstra's algorithm from start and returns the distance and the predecessor on the shortest path of every settled vertex (the start vertex has no predecessor).
+// If end is not nil, the search stops as soon as end is settled. If cost is nil, the plain edge weights are used. Paths whose cost overflows an int64 are ignored.
+func (g *Graph) dijkstra(start, end *Vertex, cost edgeCost) (dist map[*Vertex]int64, prev map[*Vertex]*Vertex) {
+	return g.directedDijkstra(start, end, cost, false)
+}
+
+// directedDijkstra is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It works like dijkstra, but follows incoming instead of outgoing edges if reverse is true, computing the distances to start. Predecessors are the vertices closer to start then.
+func (g *Graph) directedDijkstra(start, end *Vertex, cost edgeCost, reverse bool) (dist map[*Vertex]int64, prev map[*Vertex]*Vertex) {
+	var ends map[*Vertex]bool
+	if end != nil {
+		ends = map[*Vertex]bool{end: true}
+	}
+
+	dist, prev, _ = g.multiDijkstra([]*Vertex{start}, ends, cost, reverse)
+
+	return
+}
+
+// multiDijkstra is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It works like directedDijkstra, but starts from all vertices in starts at once, as if there was a virtual source with edges of weight 0 to them, and stops as soon as one of the vertices in ends is settled, which it returns.
+func (g *Graph) multiDijkstra(starts []*Vertex, ends map[*Vertex]bool, cost edgeCost, reverse bool) (dist map[*Vertex]int64, prev map[*Vertex]*Vertex, end *Vertex) {
+	dist = map[*Vertex]int64{}
+	prev = map[*Vertex]*Vertex{}
+
+	// priorityQueue for vertices that have not yet been settled
+	openQueue := &priorityQueue{}
+
+	// maps open vertices to their item in the queue
+	openList := map[*Vertex]*Item{}
+
+	for _, start := range starts {
+		if _, ok := openList[start]; ok {
+			continue
+		}
+
+		item := &Item{start, nil, 0, 0, 0}
+		openList[start] = item
+
+		heap.Push(openQueue, item)
+	}
+
+	for openQueue.Len() > 0 {
+		item := heap.Pop(openQueue).(*Item)
+		current := item.v
+
+		// current vertex is now settled
+		delete(openList, current)
+		dist[current] = item.distanceFromStart
+		prev[current] = item.prev
+
+		if ends[current] {
+			return dist, prev, current
+		}
+
+		edges := current.GetOutgoing()
+		if reverse {
+			edges = current.GetIncoming()
+		}
+
+		for neighbor, weight := range edges {
+			if _, ok := dist[neighbor]; ok {
+				continue
+			}
+
+			if cost != nil {
+				from, to := current, neighbor
+				if reverse {
+					from, to = neighbor, current
+				}
+
+				var ok bool
+				if weight, ok = cost(from, to, weight); !ok {
+					continue
+				}
+			}
+
+			if addOverflows(item.distanceFromStart, int64(weight)) {
+				continue
+			}
+
+			distanceToNeighbor := item.distanceFromStart + int64(weight)
+
+			// skip neighbors that already have a better path leading to them
+			if md, ok := openList[neighbor]; ok {
+				if md.distanceFromStart <= distanceToNeighbor {
+					continue
+				}
+				heap.Remove(openQueue, md.index)
+			}
+
+			next := &Item{neighbor, current, distanceToNeighbor, distanceToNeighbor, 0}
+			openList[neighbor] = next
+
+			heap.Push(openQueue, next)
+		}
+	}
+
+	return
+}
+
+// pathTo builds the path from the start of a search to end by following the predecessors in prev. The keys are in start → end order.
+func pathTo(prev map[*Vertex]*Vertex, end *Vertex) (path []string) {
+	for v := end; v != nil; v = prev[v] {
+		path = append(path, v.key)
+	}
+
+	// reverse into start → end order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return
+}
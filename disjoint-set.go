@@ -0,0 +1,48 @@
+package graph
+
+// disjointSet is a union-find structure over vertices, using path compression and union by size.
+type disjointSet struct {
+	parent map[*Vertex]*Vertex
+	size   map[*Vertex]int
+}
+
+func newDisjointSet() *disjointSet {
+	return &disjointSet{map[*Vertex]*Vertex{}, map[*Vertex]int{}}
+}
+
+// find returns the representative of v's set. Vertices not seen before form a set of their own.
+func (s *disjointSet) find(v *Vertex) *Vertex {
+	root, ok := s.parent[v]
+	if !ok {
+		s.parent[v] = v
+		s.size[v] = 1
+		return v
+	}
+
+	if root == v {
+		return v
+	}
+
+	root = s.find(root)
+	s.parent[v] = root
+
+	return root
+}
+
+// union merges the sets of a and b. Returns false if they were in the same set already.
+func (s *disjointSet) union(a, b *Vertex) bool {
+	a, b = s.find(a), s.find(b)
+	if a == b {
+		return false
+	}
+
+	if s.size[a] < s.size[b] {
+		a, b = b, a
+	}
+
+	s.parent[b] = a
+	s.size[a] += s.size[b]
+	delete(s.size, b)
+
+	return true
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"sort"
+)
+
+// DominatingSetApprox returns the sorted keys of a small dominating set, treating every edge as undirected: every vertex is either in the set or connected to a vertex in the set.
+// It uses the greedy algorithm, which repeatedly picks the vertex dominating the most vertices not dominated yet (ties are broken by key), and is at most a factor of ln(n)+1 larger than a minimum dominating set.
+func (g *Graph) DominatingSetApprox() (keys []string) {
+	defer g.track("DominatingSetApprox")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	vertices := make([]*Vertex, 0, len(g.vertices))
+	neighbors := make(map[*Vertex]map[*Vertex]struct{}, len(g.vertices))
+	for _, v := range g.vertices {
+		vertices = append(vertices, v)
+		neighbors[v] = undirectedNeighbors(v)
+	}
+	sort.Slice(vertices, func(i, j int) bool {
+		return vertices[i].key < vertices[j].key
+	})
+
+	dominated := make(map[*Vertex]bool, len(vertices))
+
+	// number of vertices not dominated yet that picking a vertex would dominate
+	gain := func(v *Vertex) int {
+		n := 0
+		if !dominated[v] {
+			n++
+		}
+		for neighbor := range neighbors[v] {
+			if !dominated[neighbor] {
+				n++
+			}
+		}
+		return n
+	}
+
+	for len(dominated) < len(vertices) {
+		var best *Vertex
+		bestGain := 0
+		for _, v := range vertices {
+			if n := gain(v); n > bestGain {
+				best, bestGain = v, n
+			}
+		}
+
+		keys = append(keys, best.key)
+		dominated[best] = true
+		for neighbor := range neighbors[best] {
+			dominated[neighbor] = true
+		}
+	}
+
+	sort.Strings(keys)
+
+	return
+}
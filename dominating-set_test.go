@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestDominatingSetApprox(t *testing.T) {
+	g := New()
+
+	// two stars with centers "a" and "b" (edges in both directions), and an isolated vertex
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("x", nil)
+	for i := 0; i < 4; i++ {
+		g.Set("a"+strconv.Itoa(i), nil)
+		g.Set("b"+strconv.Itoa(i), nil)
+		g.Connect("a", "a"+strconv.Itoa(i), 1)
+		g.Connect("b"+strconv.Itoa(i), "b", 1)
+	}
+	g.Connect("a0", "b0", 1)
+
+	set := g.DominatingSetApprox()
+	if !reflect.DeepEqual(set, []string{"a", "b", "x"}) {
+		t.Fatalf("unexpected set %v", set)
+	}
+
+	// every vertex is dominated
+	in := map[string]bool{}
+	for _, key := range set {
+		in[key] = true
+	}
+	for _, v := range g.GetAll() {
+		dominated := in[v.Key()]
+		for neighbor := range undirectedNeighbors(v) {
+			dominated = dominated || in[neighbor.Key()]
+		}
+		if !dominated {
+			t.Errorf("%s is not dominated", v.Key())
+		}
+	}
+
+	if len(New().DominatingSetApprox()) != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,525 @@
+package graph
+
+import (
+	"fmt"
+	"io"
+	"io/ioutil"
+	"strconv"
+	"strings"
+)
+
+// ReadDOT parses a graph in the DOT language of Graphviz, e.g. one written by WriteDOT. Nodes become vertices keyed by their names, with their "label" attribute as value (a string), or nil if they have none. Edges get their "weight" attribute as weight, or their "label" if it is an integer (as written by WriteDOT), or 1 otherwise.
+// The parser supports the common subset of DOT: strict, directed and undirected graphs, node, edge and attribute statements, edge chains like a -> b -> c, node and edge defaults scoped by subgraphs, quoted, HTML and concatenated strings, ports (which are ignored) and comments.
+// Edges of undirected graphs are connected in both directions. Subgraphs as edge endpoints, e.g. a -> {b c}, are not supported; repeated edges replace earlier ones. If there are self-loops, they are enabled on the returned graph, see EnableSelfLoops.
+func ReadDOT(r io.Reader) (*Graph, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graph: reading DOT: %v", err)
+	}
+
+	tokens, err := lexDOT(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &dotParser{tokens: tokens, values: map[string]interface{}{}}
+	if err := p.parseGraph(); err != nil {
+		return nil, err
+	}
+
+	g := New()
+	for _, key := range p.keys {
+		g.Set(key, p.values[key])
+	}
+	for _, e := range p.edges {
+		if e.From == e.To {
+			g.selfLoops = true
+		}
+		g.Connect(e.From, e.To, e.Weight)
+	}
+
+	return g, nil
+}
+
+// dotKind is the kind of a dotToken.
+type dotKind int
+
+const (
+	dotEOF    dotKind = iota
+	dotID             // identifier, numeral, quoted or HTML string
+	dotPunct          // one of { } [ ] ; , = :
+	dotEdgeOp         // -> or --
+)
+
+// dotToken is a token of the DOT language.
+type dotToken struct {
+	kind   dotKind
+	text   string
+	quoted bool // whether an ID was quoted, so it can't be a keyword
+	line   int
+}
+
+// lexDOT splits src into tokens, dropping whitespace and comments.
+func lexDOT(src string) ([]dotToken, error) {
+	var tokens []dotToken
+	line := 1
+
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("graph: reading DOT: line %d: %s", line, fmt.Sprintf(format, args...))
+	}
+
+	// skip skips whitespace and comments starting at i and returns the next position
+	skip := func(i int) (int, error) {
+		for i < len(src) {
+			switch {
+			case src[i] == '\n':
+				line++
+				i++
+			case src[i] == ' ' || src[i] == '\t' || src[i] == '\r':
+				i++
+			case strings.HasPrefix(src[i:], "//"), src[i] == '#' && (i == 0 || src[i-1] == '\n'):
+				for i < len(src) && src[i] != '\n' {
+					i++
+				}
+			case strings.HasPrefix(src[i:], "/*"):
+				end := strings.Index(src[i+2:], "*/")
+				if end < 0 {
+					return i, errorf("unterminated comment")
+				}
+				line += strings.Count(src[i:i+2+end], "\n")
+				i += end + 4
+			default:
+				return i, nil
+			}
+		}
+		return i, nil
+	}
+
+	// quoted reads the quoted string starting at i and returns its contents and the position after it
+	quoted := func(i int) (string, int, error) {
+		var sb strings.Builder
+		for i++; i < len(src); i++ {
+			switch c := src[i]; {
+			case c == '"':
+				return sb.String(), i + 1, nil
+			case c == '\\' && i+1 < len(src):
+				i++
+				switch src[i] {
+				case '"', '\\':
+					sb.WriteByte(src[i])
+				case 'n':
+					sb.WriteByte('\n')
+				case 'r':
+					sb.WriteByte('\r')
+				case '\n':
+					line++ // line continuation
+				default:
+					sb.WriteByte('\\')
+					sb.WriteByte(src[i])
+				}
+			default:
+				if c == '\n' {
+					line++
+				}
+				sb.WriteByte(c)
+			}
+		}
+		return "", i, errorf("unterminated string")
+	}
+
+	i, err := skip(0)
+	for ; err == nil && i < len(src); i, err = skip(i) {
+		c := src[i]
+		start := i
+
+		switch {
+		case strings.ContainsRune("{}[];,=:", rune(c)):
+			tokens = append(tokens, dotToken{dotPunct, string(c), false, line})
+			i++
+
+		case strings.HasPrefix(src[i:], "->"), strings.HasPrefix(src[i:], "--"):
+			tokens = append(tokens, dotToken{dotEdgeOp, src[i : i+2], false, line})
+			i += 2
+
+		case c == '"':
+			var s string
+			if s, i, err = quoted(i); err != nil {
+				return nil, err
+			}
+
+			// "a" + "b" concatenates strings
+			for {
+				j, err := skip(i)
+				if err != nil {
+					return nil, err
+				}
+				if j >= len(src) || src[j] != '+' {
+					break
+				}
+				if j, err = skip(j + 1); err != nil {
+					return nil, err
+				}
+				if j >= len(src) || src[j] != '"' {
+					return nil, errorf("expected string after +")
+				}
+
+				var next string
+				if next, i, err = quoted(j); err != nil {
+					return nil, err
+				}
+				s += next
+			}
+
+			tokens = append(tokens, dotToken{dotID, s, true, line})
+
+		case c == '<':
+			depth := 0
+			for ; i < len(src); i++ {
+				if src[i] == '<' {
+					depth++
+				} else if src[i] == '>' {
+					depth--
+					if depth == 0 {
+						break
+					}
+				} else if src[i] == '\n' {
+					line++
+				}
+			}
+			if i >= len(src) {
+				return nil, errorf("unterminated HTML string")
+			}
+			tokens = append(tokens, dotToken{dotID, src[start+1 : i], true, line})
+			i++
+
+		case c == '-' || c == '.' || (c >= '0' && c <= '9'):
+			for i++; i < len(src) && (src[i] == '.' || (src[i] >= '0' && src[i] <= '9')); i++ {
+			}
+			if src[start:i] == "-" || src[start:i] == "." {
+				return nil, errorf("unexpected %q", src[start:i])
+			}
+			tokens = append(tokens, dotToken{dotID, src[start:i], false, line})
+
+		case c == '_' || (c|0x20 >= 'a' && c|0x20 <= 'z') || c >= 0x80:
+			for ; i < len(src); i++ {
+				c := src[i]
+				if c != '_' && !(c|0x20 >= 'a' && c|0x20 <= 'z') && !(c >= '0' && c <= '9') && c < 0x80 {
+					break
+				}
+			}
+			tokens = append(tokens, dotToken{dotID, src[start:i], false, line})
+
+		default:
+			return nil, errorf("unexpected %q", c)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return append(tokens, dotToken{dotEOF, "", false, line}), nil
+}
+
+// dotParser builds the vertices and edges of a graph from DOT tokens.
+type dotParser struct {
+	tokens   []dotToken
+	pos      int
+	directed bool
+
+	keys   []string // vertex keys in order of appearance
+	values map[string]interface{}
+	edges  []Edge
+}
+
+// dotScope holds the default attributes of nodes and edges within a graph or subgraph.
+type dotScope struct {
+	node, edge map[string]string
+}
+
+// peek returns the current token.
+func (p *dotParser) peek() dotToken {
+	return p.tokens[p.pos]
+}
+
+// next returns the current token and advances to the next one.
+func (p *dotParser) next() dotToken {
+	t := p.tokens[p.pos]
+	if t.kind != dotEOF {
+		p.pos++
+	}
+	return t
+}
+
+// is returns true if t is the punctuation or edge operator s.
+func (t dotToken) is(s string) bool {
+	return (t.kind == dotPunct || t.kind == dotEdgeOp) && t.text == s
+}
+
+// keyword returns true if t is the unquoted keyword kw, which is case-insensitive.
+func (t dotToken) keyword(kw string) bool {
+	return t.kind == dotID && !t.quoted && strings.EqualFold(t.text, kw)
+}
+
+// errorf returns a parse error at the current token.
+func (p *dotParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: reading DOT: line %d: %s", p.peek().line, fmt.Sprintf(format, args...))
+}
+
+// expect consumes the punctuation s or fails.
+func (p *dotParser) expect(s string) error {
+	if t := p.peek(); !t.is(s) {
+		return p.errorf("expected %q, found %q", s, t.text)
+	}
+	p.next()
+	return nil
+}
+
+// parseGraph parses: [strict] (graph | digraph) [ID] '{' stmt_list '}'
+func (p *dotParser) parseGraph() error {
+	if p.peek().keyword("strict") {
+		p.next()
+	}
+
+	switch t := p.next(); {
+	case t.keyword("digraph"):
+		p.directed = true
+	case t.keyword("graph"):
+	default:
+		return fmt.Errorf("graph: reading DOT: line %d: expected graph or digraph, found %q", t.line, t.text)
+	}
+
+	if p.peek().kind == dotID {
+		p.next()
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+	if err := p.parseStatements(dotScope{map[string]string{}, map[string]string{}}); err != nil {
+		return err
+	}
+
+	if t := p.peek(); t.kind != dotEOF {
+		return p.errorf("unexpected %q after graph", t.text)
+	}
+
+	return nil
+}
+
+// parseStatements parses statements up to and including the closing brace of a graph or subgraph.
+func (p *dotParser) parseStatements(scope dotScope) error {
+	for {
+		t := p.peek()
+
+		switch {
+		case t.kind == dotEOF:
+			return p.errorf("expected \"}\"")
+
+		case t.is("}"):
+			p.next()
+			return nil
+
+		case t.is(";"), t.is(","):
+			p.next()
+
+		case t.keyword("graph"), t.keyword("node"), t.keyword("edge"):
+			p.next()
+			attrs, err := p.parseAttributes()
+			if err != nil {
+				return err
+			}
+
+			defaults := scope.node
+			if t.keyword("edge") {
+				defaults = scope.edge
+			}
+			if !t.keyword("graph") {
+				for name, value := range attrs {
+					defaults[name] = value
+				}
+			}
+
+		case t.keyword("subgraph"), t.is("{"):
+			if err := p.parseSubgraph(scope); err != nil {
+				return err
+			}
+			if p.peek().kind == dotEdgeOp {
+				return p.errorf("subgraphs as edge endpoints are not supported")
+			}
+
+		case t.kind == dotID:
+			if err := p.parseNodeOrEdge(scope); err != nil {
+				return err
+			}
+
+		default:
+			return p.errorf("unexpected %q", t.text)
+		}
+	}
+}
+
+// parseSubgraph parses: [subgraph [ID]] '{' stmt_list '}'. Changes to the defaults within the subgraph don't affect the enclosing scope.
+func (p *dotParser) parseSubgraph(scope dotScope) error {
+	if p.peek().keyword("subgraph") {
+		p.next()
+		if p.peek().kind == dotID {
+			p.next()
+		}
+	}
+
+	if err := p.expect("{"); err != nil {
+		return err
+	}
+
+	inner := dotScope{map[string]string{}, map[string]string{}}
+	for name, value := range scope.node {
+		inner.node[name] = value
+	}
+	for name, value := range scope.edge {
+		inner.edge[name] = value
+	}
+
+	return p.parseStatements(inner)
+}
+
+// parseNodeOrEdge parses a graph attribute (ID '=' ID), a node statement or an edge statement.
+func (p *dotParser) parseNodeOrEdge(scope dotScope) error {
+	key := p.next().text
+
+	if p.peek().is("=") {
+		p.next()
+		if p.next().kind != dotID {
+			return p.errorf("expected attribute value")
+		}
+		return nil
+	}
+
+	if err := p.skipPort(); err != nil {
+		return err
+	}
+
+	chain := []string{key}
+	for p.peek().kind == dotEdgeOp {
+		if op := p.next(); (op.text == "->") != p.directed {
+			return fmt.Errorf("graph: reading DOT: line %d: edge operator %s doesn't match the graph type", op.line, op.text)
+		}
+
+		t := p.peek()
+		if t.keyword("subgraph") || t.is("{") {
+			return p.errorf("subgraphs as edge endpoints are not supported")
+		}
+		if t.kind != dotID || t.keyword("node") || t.keyword("edge") || t.keyword("graph") {
+			return p.errorf("expected node, found %q", t.text)
+		}
+
+		chain = append(chain, p.next().text)
+		if err := p.skipPort(); err != nil {
+			return err
+		}
+	}
+
+	attrs := map[string]string{}
+	if p.peek().is("[") {
+		var err error
+		if attrs, err = p.parseAttributes(); err != nil {
+			return err
+		}
+	}
+
+	if len(chain) == 1 {
+		p.addNode(key, scope)
+		if label, ok := attrs["label"]; ok {
+			p.values[key] = label
+		}
+		return nil
+	}
+
+	for name, value := range scope.edge {
+		if _, ok := attrs[name]; !ok {
+			attrs[name] = value
+		}
+	}
+
+	weight := 1
+	if s, ok := attrs["weight"]; ok {
+		w, err := strconv.Atoi(s)
+		if err != nil {
+			return p.errorf("invalid weight %q", s)
+		}
+		weight = w
+	} else if w, err := strconv.Atoi(attrs["label"]); err == nil {
+		weight = w
+	}
+
+	for i := 1; i < len(chain); i++ {
+		from, to := chain[i-1], chain[i]
+
+		p.addNode(from, scope)
+		p.addNode(to, scope)
+
+		p.edges = append(p.edges, Edge{From: from, To: to, Weight: weight})
+		if !p.directed {
+			p.edges = append(p.edges, Edge{From: to, To: from, Weight: weight})
+		}
+	}
+
+	return nil
+}
+
+// addNode creates the vertex with key, labeled by the default label of the scope, unless it already exists.
+func (p *dotParser) addNode(key string, scope dotScope) {
+	if _, ok := p.values[key]; ok {
+		return
+	}
+
+	p.keys = append(p.keys, key)
+	p.values[key] = nil
+	if label, ok := scope.node["label"]; ok {
+		p.values[key] = label
+	}
+}
+
+// skipPort skips a port like :port or :port:compass following a node name.
+func (p *dotParser) skipPort() error {
+	for i := 0; i < 2 && p.peek().is(":"); i++ {
+		p.next()
+		if p.next().kind != dotID {
+			return p.errorf("expected port")
+		}
+	}
+	return nil
+}
+
+// parseAttributes parses one or more attribute lists: '[' [ID ['=' ID] [';' | ',']]... ']'. Attributes without value are set to "true".
+func (p *dotParser) parseAttributes() (map[string]string, error) {
+	attrs := map[string]string{}
+
+	for p.peek().is("[") {
+		p.next()
+
+		for !p.peek().is("]") {
+			t := p.next()
+			if t.kind != dotID {
+				return nil, fmt.Errorf("graph: reading DOT: line %d: expected attribute, found %q", t.line, t.text)
+			}
+
+			value := "true"
+			if p.peek().is("=") {
+				p.next()
+				v := p.next()
+				if v.kind != dotID {
+					return nil, fmt.Errorf("graph: reading DOT: line %d: expected attribute value, found %q", v.line, v.text)
+				}
+				value = v.text
+			}
+			attrs[t.text] = value
+
+			if p.peek().is(";") || p.peek().is(",") {
+				p.next()
+			}
+		}
+		p.next()
+	}
+
+	return attrs, nil
+}
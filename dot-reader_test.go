@@ -0,0 +1,118 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestReadDOTRoundTrip(t *testing.T) {
+	g := New()
+	g.Set("a", "first")
+	g.Set("b", "say \"hi\"\\")
+	g.Set("c d", nil)
+
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c d", -2)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteDOT(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	read, err := ReadDOT(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, key := range []string{"a", "b", "c d"} {
+		v, err := read.Get(key)
+		if err != nil {
+			t.Fatalf("missing vertex %q", key)
+		}
+		if expected, _ := g.Get(key); v.Value() != expected.Value() {
+			t.Errorf("%q: expected value %v, got %v", key, expected.Value(), v.Value())
+		}
+	}
+
+	if ok, weight := read.IsConnected("a", "b"); !ok || weight != 5 {
+		t.Errorf("expected edge a → b with weight 5, got %v %d", ok, weight)
+	}
+	if ok, weight := read.IsConnected("b", "c d"); !ok || weight != -2 {
+		t.Errorf("expected edge b → c d with weight -2, got %v %d", ok, weight)
+	}
+	if ok, _ := read.IsConnected("b", "a"); ok {
+		t.Error("unexpected edge b → a")
+	}
+}
+
+func TestReadDOT(t *testing.T) {
+	src := `
+# preprocessor line
+strict graph G {
+	rankdir = LR; // graph attribute
+	node [label="default", shape=box]
+	edge [weight=3]
+
+	a [label=<<b>A</b>>]
+	a -- b -- c:port:n
+	/* a subgraph with its own defaults */
+	subgraph cluster_x {
+		edge [weight=7]
+		node [label="inner"]
+		c -- d
+	}
+	d -- e [weight=-4, color=red]
+	e -- f [label="x"]
+	"long" + " name" -- a
+}
+`
+
+	g, err := ReadDOT(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for key, expected := range map[string]interface{}{
+		"a":         "<b>A</b>",
+		"b":         "default",
+		"c":         "default",
+		"d":         "inner",
+		"e":         "default",
+		"long name": "default",
+	} {
+		v, err := g.Get(key)
+		if err != nil {
+			t.Errorf("missing vertex %q", key)
+			continue
+		}
+		if v.Value() != expected {
+			t.Errorf("%q: expected value %v, got %v", key, expected, v.Value())
+		}
+	}
+
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 3}, {From: "b", To: "c", Weight: 3}, {From: "c", To: "d", Weight: 7}, {From: "d", To: "e", Weight: -4}, {From: "e", To: "f", Weight: 3}, {From: "long name", To: "a", Weight: 3}} {
+		for _, pair := range [][2]string{{e.From, e.To}, {e.To, e.From}} {
+			if ok, weight := g.IsConnected(pair[0], pair[1]); !ok || weight != e.Weight {
+				t.Errorf("%s → %s: expected weight %d, got %v %d", pair[0], pair[1], e.Weight, ok, weight)
+			}
+		}
+	}
+}
+
+func TestReadDOTErrors(t *testing.T) {
+	for _, src := range []string{
+		`digraph { a -- b }`,
+		`graph { a -> b }`,
+		`digraph { a -> {b c} }`,
+		`digraph { a -> b [weight=1.5] }`,
+		`digraph { a [label="unterminated] }`,
+		`digraph { a -> b `,
+		`tree { }`,
+		`digraph { } extra`,
+	} {
+		if _, err := ReadDOT(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: expected an error", src)
+		}
+	}
+}
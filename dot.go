@@ -0,0 +1,111 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DOTOption configures the output of WriteDOT.
+type DOTOption func(*dotConfig)
+
+// dotConfig holds the settings of WriteDOT.
+type dotConfig struct {
+	name    string
+	label   func(key string, value interface{}) string
+	weights bool
+}
+
+// DOTName sets the name of the graph in the DOT output, which is unnamed by default.
+func DOTName(name string) DOTOption {
+	return func(cfg *dotConfig) {
+		cfg.name = name
+	}
+}
+
+// DOTLabel makes WriteDOT label the vertices with the strings returned by fn instead of their values formatted with fmt.Sprint. Vertices for which fn returns an empty string get no label, so dot shows their keys.
+func DOTLabel(fn func(key string, value interface{}) string) DOTOption {
+	return func(cfg *dotConfig) {
+		cfg.label = fn
+	}
+}
+
+// DOTOmitWeights makes WriteDOT leave out the edge labels showing the weights.
+func DOTOmitWeights() DOTOption {
+	return func(cfg *dotConfig) {
+		cfg.weights = false
+	}
+}
+
+// WriteDOT writes the graph to w in the DOT language of Graphviz, so it can be rendered with dot: vertices are nodes named by their keys and labeled with their values, edges are labeled with their weights. Vertices with nil values get no label.
+// Vertices and edges are written in key order, so the output is deterministic.
+func (g *Graph) WriteDOT(w io.Writer, opts ...DOTOption) error {
+	defer g.track("WriteDOT")()
+
+	cfg := &dotConfig{
+		label: func(_ string, value interface{}) string {
+			if value == nil {
+				return ""
+			}
+			return fmt.Sprint(value)
+		},
+		weights: true,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g.RLock()
+	defer g.RUnlock()
+
+	bw := bufio.NewWriter(w)
+
+	if cfg.name != "" {
+		fmt.Fprintf(bw, "digraph %s {\n", dotQuote(cfg.name))
+	} else {
+		bw.WriteString("digraph {\n")
+	}
+
+	keys := g.sortedKeys()
+
+	for _, key := range keys {
+		if label := cfg.label(key, g.vertices[key].Value()); label != "" {
+			fmt.Fprintf(bw, "\t%s [label=%s];\n", dotQuote(key), dotQuote(label))
+		} else {
+			fmt.Fprintf(bw, "\t%s;\n", dotQuote(key))
+		}
+	}
+
+	for _, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			fmt.Fprintf(bw, "\t%s -> %s", dotQuote(key), dotQuote(neighbor.key))
+			if cfg.weights {
+				fmt.Fprintf(bw, " [label=%s]", dotQuote(strconv.Itoa(outgoing[neighbor])))
+			}
+			bw.WriteString(";\n")
+		}
+	}
+
+	bw.WriteString("}\n")
+
+	return bw.Flush()
+}
+
+// dotQuoter escapes the characters which can't appear literally in quoted DOT strings.
+var dotQuoter = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`)
+
+// dotQuote returns s as a quoted DOT string.
+func dotQuote(s string) string {
+	return `"` + dotQuoter.Replace(s) + `"`
+}
@@ -0,0 +1,60 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"testing"
+)
+
+func TestWriteDOT(t *testing.T) {
+	g := New()
+	g.Set("b", `say "hi"`)
+	g.Set("a", 1)
+	g.Set("c", nil)
+
+	g.Connect("a", "c", 2)
+	g.Connect("a", "b", 5)
+	g.Connect("c", "b", -1)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteDOT(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `digraph {
+	"a" [label="1"];
+	"b" [label="say \"hi\""];
+	"c";
+	"a" -> "b" [label="5"];
+	"a" -> "c" [label="2"];
+	"c" -> "b" [label="-1"];
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	err := g.WriteDOT(buf, DOTName("deps"), DOTOmitWeights(), DOTLabel(func(key string, value interface{}) string {
+		if key == "a" {
+			return fmt.Sprintf("%s=%v", key, value)
+		}
+		return ""
+	}))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected = `digraph "deps" {
+	"a" [label="a=1"];
+	"b";
+	"c";
+	"a" -> "b";
+	"a" -> "c";
+	"c" -> "b";
+}
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
@@ -0,0 +1,160 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// MetricOption configures the computation of Diameter and Radius.
+type MetricOption func(*metricConfig)
+
+// metricConfig holds the settings of a Diameter or Radius computation.
+type metricConfig struct {
+	samples  int // number of vertices to compute eccentricities for, 0 means all
+	seed     int64
+	progress ProgressFunc // nil if progress isn't reported
+}
+
+// Sample makes Diameter and Radius approximate the result from the eccentricities of n randomly chosen vertices instead of all, for graphs too large for all-pairs shortest paths.
+// The diameter found is a lower bound and the radius an upper bound of the exact value. The vertices are chosen with a random source seeded with seed.
+func Sample(n int, seed int64) MetricOption {
+	return func(cfg *metricConfig) {
+		cfg.samples = n
+		cfg.seed = seed
+	}
+}
+
+// WithProgress makes Diameter and Radius report their progress to fn after computing the eccentricity of each vertex. They fail with ErrCanceled if fn returns false.
+func WithProgress(fn ProgressFunc) MetricOption {
+	return func(cfg *metricConfig) {
+		cfg.progress = fn
+	}
+}
+
+// Eccentricity returns the largest weighted distance from the vertex with the specified key to any other vertex, following outgoing edges.
+// Returns ErrInvalidKey if the key is invalid and ErrNoPath if some vertex can't be reached, i.e. the eccentricity is infinite.
+func (g *Graph) Eccentricity(key string) (int, error) {
+	defer g.track("Eccentricity")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	v := g.get(key)
+	if v == nil {
+		return 0, ErrInvalidKey
+	}
+
+	return g.eccentricity(v)
+}
+
+// Diameter returns the largest eccentricity of all vertices, i.e. the longest shortest path in the graph, configured by opts. Returns 0 for an empty graph, and ErrNoPath if some vertex can't be reached from another one.
+func (g *Graph) Diameter(opts ...MetricOption) (int, error) {
+	defer g.track("Diameter")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	vertices, progress := g.metricVertices(opts)
+
+	diameter := 0
+	for _, v := range vertices {
+		e, err := g.eccentricity(v)
+		if err != nil {
+			return 0, err
+		}
+
+		if e > diameter {
+			diameter = e
+		}
+
+		if err = progress.step(); err != nil {
+			return 0, err
+		}
+	}
+
+	return diameter, nil
+}
+
+// Radius returns the smallest eccentricity of all vertices, configured by opts. Vertices that can't reach all others are ignored. Returns 0 for an empty graph, and ErrNoPath if no vertex reaches all others.
+func (g *Graph) Radius(opts ...MetricOption) (int, error) {
+	defer g.track("Radius")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	vertices, progress := g.metricVertices(opts)
+	if len(vertices) == 0 {
+		return 0, nil
+	}
+
+	radius, found := 0, false
+	for _, v := range vertices {
+		e, err := g.eccentricity(v)
+		if err != nil && err != ErrNoPath {
+			return 0, err
+		}
+
+		if err == nil && (!found || e < radius) {
+			radius, found = e, true
+		}
+
+		if err = progress.step(); err != nil {
+			return 0, err
+		}
+	}
+
+	if !found {
+		return 0, ErrNoPath
+	}
+
+	return radius, nil
+}
+
+// metricVertices is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the vertices whose eccentricities have to be computed as configured by opts, and the tracker to report the progress of computing them to.
+func (g *Graph) metricVertices(opts []MetricOption) ([]*Vertex, *progressTracker) {
+	cfg := &metricConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// sort the vertices, so sampling is reproducible
+	vertices := make([]*Vertex, 0, len(g.vertices))
+	for _, v := range g.vertices {
+		vertices = append(vertices, v)
+	}
+	sort.Slice(vertices, func(i, j int) bool {
+		return vertices[i].key < vertices[j].key
+	})
+
+	if cfg.samples > 0 && cfg.samples < len(vertices) {
+		r := rand.New(rand.NewSource(cfg.seed))
+		r.Shuffle(len(vertices), func(i, j int) {
+			vertices[i], vertices[j] = vertices[j], vertices[i]
+		})
+		vertices = vertices[:cfg.samples]
+	}
+
+	return vertices, newProgressTracker(cfg.progress, len(vertices))
+}
+
+// eccentricity is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+func (g *Graph) eccentricity(v *Vertex) (int, error) {
+	dist, _ := g.dijkstra(v, nil, nil)
+	if len(dist) < len(g.vertices) {
+		return 0, ErrNoPath
+	}
+
+	var e int64
+	for _, d := range dist {
+		if d > e {
+			e = d
+		}
+	}
+
+	if !fitsInt(e) {
+		return 0, ErrCostOverflow
+	}
+
+	return int(e), nil
+}
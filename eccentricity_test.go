@@ -0,0 +1,69 @@
+package graph
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestEccentricity(t *testing.T) {
+	g := New()
+
+	// path 0 - 1 - 2 - 3 - 4 in both directions
+	for i := 0; i < 5; i++ {
+		g.Set(strconv.Itoa(i), nil)
+		if i > 0 {
+			g.Connect(strconv.Itoa(i-1), strconv.Itoa(i), 2)
+			g.Connect(strconv.Itoa(i), strconv.Itoa(i-1), 2)
+		}
+	}
+
+	if e, err := g.Eccentricity("0"); err != nil || e != 8 {
+		t.Fail()
+	}
+	if e, err := g.Eccentricity("2"); err != nil || e != 4 {
+		t.Fail()
+	}
+	if _, err := g.Eccentricity("x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+
+	if d, err := g.Diameter(); err != nil || d != 8 {
+		t.Fail()
+	}
+	if r, err := g.Radius(); err != nil || r != 4 {
+		t.Fail()
+	}
+
+	// sampled values are bounds of the exact ones
+	for seed := int64(0); seed < 5; seed++ {
+		if d, err := g.Diameter(Sample(2, seed)); err != nil || d > 8 || d < 4 {
+			t.Fail()
+		}
+		if r, err := g.Radius(Sample(2, seed)); err != nil || r < 4 || r > 8 {
+			t.Fail()
+		}
+	}
+
+	// a vertex that can't be reached makes the diameter infinite; it is the only possible center now
+	g.Set("5", nil)
+	g.Connect("5", "4", 1)
+
+	if _, err := g.Diameter(); err != ErrNoPath {
+		t.Fail()
+	}
+	if _, err := g.Eccentricity("0"); err != ErrNoPath {
+		t.Fail()
+	}
+	if r, err := g.Radius(); err != nil || r != 9 {
+		t.Fail()
+	}
+
+	g.Disconnect("5", "4")
+	if _, err := g.Radius(); err != ErrNoPath {
+		t.Fail()
+	}
+
+	if d, err := New().Diameter(); err != nil || d != 0 {
+		t.Fail()
+	}
+}
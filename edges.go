@@ -0,0 +1,158 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrNoEdge is returned when there is no edge between the requested vertices.
+var ErrNoEdge = errors.New("graph: no such edge")
+
+// Edge describes a directed edge by the keys of its vertices, its weight and its attributes.
+type Edge struct {
+	From, To string
+	Weight   int
+	Attrs    map[string]interface{} // attributes set by SetEdgeAttr, filled in by GetEdge; nil if there are none
+}
+
+// GetEdge returns the edge from fromKey to toKey with a copy of its attributes. Returns ErrInvalidKey if one or both of the keys are invalid and ErrNoEdge if they aren't connected.
+func (g *Graph) GetEdge(fromKey, toKey string) (Edge, error) {
+	defer g.track("GetEdge")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	fromV, toV := g.get(fromKey), g.get(toKey)
+	if fromV == nil || toV == nil {
+		return Edge{}, ErrInvalidKey
+	}
+
+	fromV.RLock()
+	defer fromV.RUnlock()
+
+	weight, ok := fromV.outgoingEdges[toV]
+	if !ok {
+		return Edge{}, ErrNoEdge
+	}
+
+	return Edge{From: fromKey, To: toKey, Weight: weight, Attrs: fromV.getEdgeAttrs(toV)}, nil
+}
+
+// SetEdgeAttr sets the attribute name of the edge from fromKey to toKey to value, e.g. a label, a capacity or a timestamp which doesn't fit into the weight. Attributes are kept when the weight is changed by Connect, and removed with the edge.
+// Returns ErrInvalidKey if one or both of the keys are invalid and ErrNoEdge if they aren't connected.
+// Subscribers receive an EventSetEdgeAttr, so attributes are kept by replicas, mutation logs and stores as well.
+func (g *Graph) SetEdgeAttr(fromKey, toKey, name string, value interface{}) error {
+	defer g.track("SetEdgeAttr")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	fromV, toV := g.get(fromKey), g.get(toKey)
+	if fromV == nil || toV == nil {
+		return ErrInvalidKey
+	}
+
+	// concurrent edge mutations must emit their events in the order they are applied
+	g.edgeMutations.Lock()
+	defer g.edgeMutations.Unlock()
+
+	fromV.Lock()
+	if _, ok := fromV.outgoingEdges[toV]; !ok {
+		fromV.Unlock()
+		return ErrNoEdge
+	}
+	fromV.setEdgeAttr(toV, name, value)
+	fromV.Unlock()
+
+	g.emit(Event{Type: EventSetEdgeAttr, Key: fromKey, ToKey: toKey, Attr: name, Value: value})
+
+	return nil
+}
+
+// DeleteEdgeAttr removes the attribute name from the edge from fromKey to toKey, see SetEdgeAttr. Subscribers receive an EventDeleteEdgeAttr if the edge had the attribute.
+// Returns ErrInvalidKey if one or both of the keys are invalid and ErrNoEdge if they aren't connected.
+func (g *Graph) DeleteEdgeAttr(fromKey, toKey, name string) error {
+	defer g.track("DeleteEdgeAttr")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	fromV, toV := g.get(fromKey), g.get(toKey)
+	if fromV == nil || toV == nil {
+		return ErrInvalidKey
+	}
+
+	g.edgeMutations.Lock()
+	defer g.edgeMutations.Unlock()
+
+	fromV.Lock()
+	if _, ok := fromV.outgoingEdges[toV]; !ok {
+		fromV.Unlock()
+		return ErrNoEdge
+	}
+	_, existed := fromV.edgeAttrs[toV][name]
+	delete(fromV.edgeAttrs[toV], name)
+	if len(fromV.edgeAttrs[toV]) == 0 {
+		delete(fromV.edgeAttrs, toV)
+	}
+	fromV.Unlock()
+
+	if existed {
+		g.emit(Event{Type: EventDeleteEdgeAttr, Key: fromKey, ToKey: toKey, Attr: name})
+	}
+
+	return nil
+}
+
+// setEdgeAttrs is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It sets the attributes attrs of the existing edge from fromV to toV and emits an event for each, e.g. when the edge is moved to another vertex.
+func (g *Graph) setEdgeAttrs(fromV, toV *Vertex, attrs map[string]interface{}) {
+	if len(attrs) == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(attrs))
+	for name := range attrs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	g.edgeMutations.Lock()
+	defer g.edgeMutations.Unlock()
+
+	fromV.Lock()
+	for _, name := range names {
+		fromV.setEdgeAttr(toV, name, attrs[name])
+	}
+	fromV.Unlock()
+
+	for _, name := range names {
+		g.emit(Event{Type: EventSetEdgeAttr, Key: fromV.key, ToKey: toV.key, Attr: name, Value: attrs[name]})
+	}
+}
+
+// getEdgeAttrs returns a copy of the attributes of the edge from v to neighbor, nil if it has none. v must be locked.
+func (v *Vertex) getEdgeAttrs(neighbor *Vertex) map[string]interface{} {
+	attrs := v.edgeAttrs[neighbor]
+	if len(attrs) == 0 {
+		return nil
+	}
+
+	c := make(map[string]interface{}, len(attrs))
+	for name, value := range attrs {
+		c[name] = value
+	}
+
+	return c
+}
+
+// setEdgeAttr sets an attribute of the edge from v to neighbor. v must be locked.
+func (v *Vertex) setEdgeAttr(neighbor *Vertex, name string, value interface{}) {
+	if v.edgeAttrs == nil {
+		v.edgeAttrs = map[*Vertex]map[string]interface{}{}
+	}
+	if v.edgeAttrs[neighbor] == nil {
+		v.edgeAttrs[neighbor] = map[string]interface{}{}
+	}
+	v.edgeAttrs[neighbor][name] = value
+}
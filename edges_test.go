@@ -0,0 +1,332 @@
+package graph
+
+import (
+	"bytes"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestEdgeAttrs(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Set("c", 3)
+	g.Connect("a", "b", 5)
+	g.Connect("c", "a", 1)
+
+	if err := g.SetEdgeAttr("a", "b", "label", "knows"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.SetEdgeAttr("a", "b", "since", 2010)
+
+	e, err := g.GetEdge("a", "b")
+	expected := Edge{From: "a", To: "b", Weight: 5, Attrs: map[string]interface{}{"label": "knows", "since": 2010}}
+	if err != nil || !reflect.DeepEqual(e, expected) {
+		t.Errorf("expected %+v, got %+v %v", expected, e, err)
+	}
+
+	// the returned attributes are a copy
+	e.Attrs["label"] = "changed"
+	if e, _ := g.GetEdge("a", "b"); e.Attrs["label"] != "knows" {
+		t.Errorf("expected the attribute to be unchanged, got %v", e.Attrs["label"])
+	}
+
+	// changing the weight keeps the attributes
+	g.Connect("a", "b", 7)
+	if e, _ := g.GetEdge("a", "b"); e.Weight != 7 || len(e.Attrs) != 2 {
+		t.Errorf("expected the new weight and the attributes, got %+v", e)
+	}
+
+	if err := g.DeleteEdgeAttr("a", "b", "since"); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e, _ := g.GetEdge("a", "b"); !reflect.DeepEqual(e.Attrs, map[string]interface{}{"label": "knows"}) {
+		t.Errorf("expected the remaining attribute, got %v", e.Attrs)
+	}
+
+	if e, err := g.GetEdge("c", "a"); err != nil || e.Attrs != nil {
+		t.Errorf("expected an edge without attributes, got %+v %v", e, err)
+	}
+
+	for _, keys := range [][2]string{{"a", "x"}, {"x", "a"}} {
+		if _, err := g.GetEdge(keys[0], keys[1]); err != ErrInvalidKey {
+			t.Errorf("%v: expected ErrInvalidKey, got %v", keys, err)
+		}
+		if err := g.SetEdgeAttr(keys[0], keys[1], "label", nil); err != ErrInvalidKey {
+			t.Errorf("%v: expected ErrInvalidKey, got %v", keys, err)
+		}
+	}
+	if _, err := g.GetEdge("b", "a"); err != ErrNoEdge {
+		t.Errorf("expected ErrNoEdge, got %v", err)
+	}
+	if err := g.SetEdgeAttr("b", "a", "label", nil); err != ErrNoEdge {
+		t.Errorf("expected ErrNoEdge, got %v", err)
+	}
+
+	// copies and gob keep the attributes
+	c := g.clone()
+	b, err := g.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := New()
+	if err := decoded.GobDecode(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for name, h := range map[string]*Graph{"clone": c, "gob": decoded} {
+		if e, _ := h.GetEdge("a", "b"); e.Attrs["label"] != "knows" {
+			t.Errorf("%s: expected the attribute, got %+v", name, e)
+		}
+	}
+
+	// removing the edge removes its attributes
+	g.Disconnect("a", "b")
+	g.Connect("a", "b", 1)
+	if e, _ := g.GetEdge("a", "b"); e.Attrs != nil {
+		t.Errorf("expected the attributes to be removed with the edge, got %v", e.Attrs)
+	}
+
+	g.SetEdgeAttr("c", "a", "label", "x")
+	g.Delete("a")
+	g.Set("a", 1)
+	g.Connect("c", "a", 1)
+	if e, _ := g.GetEdge("c", "a"); e.Attrs != nil {
+		t.Errorf("expected the attributes to be removed with the vertex, got %v", e.Attrs)
+	}
+}
+
+func TestEdgeAttrsEvents(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Connect("a", "b", 1)
+
+	var events []Event
+	g.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	g.SetEdgeAttr("a", "b", "label", "knows")
+	g.DeleteEdgeAttr("a", "b", "label")
+	g.DeleteEdgeAttr("a", "b", "label")
+	g.SetEdgeAttr("b", "a", "label", "knows")
+
+	expected := []Event{
+		{Type: EventSetEdgeAttr, Key: "a", ToKey: "b", Attr: "label", Value: "knows"},
+		{Type: EventDeleteEdgeAttr, Key: "a", ToKey: "b", Attr: "label"},
+	}
+	if !reflect.DeepEqual(events, expected) {
+		t.Errorf("expected %+v, got %+v", expected, events)
+	}
+
+	var b Batch
+	b.SetEdgeAttr("a", "b", "since", 2010)
+	b.SetEdgeAttr("b", "a", "since", 2010)
+	b.DeleteEdgeAttr("a", "x", "since")
+	errs := g.Apply(b)
+	if len(errs) != 2 || errs[0].(*BatchError).Index != 1 || errs[0].(*BatchError).Err != ErrNoEdge || errs[1].(*BatchError).Err != ErrInvalidKey {
+		t.Errorf("expected the missing edge and the invalid key to be reported, got %v", errs)
+	}
+	if e, _ := g.GetEdge("a", "b"); e.Attrs != nil {
+		t.Errorf("expected nothing to be applied, got %+v", e)
+	}
+
+	// edges connected, disconnected or deleted by earlier mutations are taken into account
+	b = nil
+	b.Connect("b", "a", 1)
+	b.SetEdgeAttr("b", "a", "since", 2010)
+	b.Disconnect("a", "b")
+	b.SetEdgeAttr("a", "b", "since", 2010)
+	b.Connect("a", "b", 1)
+	b.Delete("b")
+	b.Set("b", 2)
+	b.DeleteEdgeAttr("a", "b", "since")
+	errs = g.Validate(b)
+	if len(errs) != 2 || errs[0].(*BatchError).Index != 3 || errs[1].(*BatchError).Index != 7 || errs[1].(*BatchError).Err != ErrNoEdge {
+		t.Errorf("expected the attributes of the removed edges to be reported, got %v", errs)
+	}
+
+	if errs = g.Apply(b[:2]); len(errs) != 0 {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+	if e, _ := g.GetEdge("b", "a"); e.Attrs["since"] != 2010 {
+		t.Errorf("expected the attribute to be set by the batch, got %+v", e)
+	}
+}
+
+// edgeAttrsGraph returns a graph with attributes on one of its edges and checks graphs restored from it.
+func edgeAttrsGraph(t *testing.T, g *Graph) func(name string, h *Graph) {
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Set("c", 3)
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", 1)
+	g.SetEdgeAttr("a", "b", "label", "knows")
+	g.SetEdgeAttr("a", "b", "since", int64(2010))
+	g.SetEdgeAttr("b", "c", "label", "likes")
+	g.DeleteEdgeAttr("b", "c", "label")
+
+	return func(name string, h *Graph) {
+		expected := map[string]interface{}{"label": "knows", "since": int64(2010)}
+		if e, err := h.GetEdge("a", "b"); err != nil || !reflect.DeepEqual(e.Attrs, expected) {
+			t.Errorf("%s: expected attributes %v, got %+v %v", name, expected, e, err)
+		}
+		if e, err := h.GetEdge("b", "c"); err != nil || e.Attrs != nil {
+			t.Errorf("%s: expected no attributes, got %+v %v", name, e, err)
+		}
+	}
+}
+
+func TestEdgeAttrsPersistence(t *testing.T) {
+	dir, err := ioutil.TempDir("", "edgeattrs")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	t.Run("stream", func(t *testing.T) {
+		g := New()
+		check := edgeAttrsGraph(t, g)
+
+		buf := &bytes.Buffer{}
+		if _, err := g.WriteTo(buf); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		h := New()
+		if _, err := h.ReadFrom(buf); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		check("stream", h)
+	})
+
+	t.Run("store", func(t *testing.T) {
+		for _, opts := range [][]StoreOption{nil, {StoreDeferred()}} {
+			s := NewMemoryStore()
+			g, err := NewWithStore(s, opts...)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			check := edgeAttrsGraph(t, g)
+			if err := g.FlushDirty(); err != nil || g.StoreErr() != nil {
+				t.Fatalf("unexpected error %v %v", err, g.StoreErr())
+			}
+
+			h, err := NewWithStore(s)
+			if err != nil {
+				t.Fatalf("unexpected error %v", err)
+			}
+			check("store", h)
+
+			// attributes are removed with their edges
+			g.Delete("b")
+			g.Set("b", 2)
+			g.Connect("a", "b", 1)
+			g.FlushDirty()
+			if h, _ := NewWithStore(s); h != nil {
+				if e, _ := h.GetEdge("a", "b"); e.Attrs != nil {
+					t.Errorf("expected the attributes to be deleted from the store, got %v", e.Attrs)
+				}
+			}
+		}
+
+		// stores without support for attributes report them
+		g, _ := NewWithStore(plainStore{NewMemoryStore()})
+		g.Set("a", nil)
+		g.Set("b", nil)
+		g.Connect("a", "b", 1)
+		g.SetEdgeAttr("a", "b", "label", "knows")
+		if err := g.StoreErr(); err != ErrEdgeAttrsUnsupported {
+			t.Errorf("expected ErrEdgeAttrsUnsupported, got %v", err)
+		}
+	})
+
+	t.Run("mutation log", func(t *testing.T) {
+		path := filepath.Join(dir, "graph.log")
+
+		g, l, err := OpenMutationLog(path, 1<<20)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		check := edgeAttrsGraph(t, g)
+		if err := l.Close(); err != nil || l.Err() != nil {
+			t.Fatalf("unexpected error %v %v", err, l.Err())
+		}
+
+		h, l, err := OpenMutationLog(path, 1<<20)
+		if err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		check("mutation log", h)
+
+		// compaction rewrites the attributes
+		if err := l.Compact(); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		l.Close()
+		if h, l, err = OpenMutationLog(path, 1<<20); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+		l.Close()
+		check("compacted mutation log", h)
+	})
+
+	t.Run("replica", func(t *testing.T) {
+		g := New()
+		g.Set("x", nil)
+		r := NewReplica(g)
+		defer r.Close()
+
+		check := edgeAttrsGraph(t, g)
+		r.Sync()
+		check("replica", r.Graph())
+	})
+
+	t.Run("auto-save", func(t *testing.T) {
+		var m sync.Mutex
+		var saved []*Graph
+
+		g := New()
+		g.Set("a", 1)
+		g.Set("b", 2)
+		g.Connect("a", "b", 5)
+
+		stop := g.AutoSave(time.Millisecond, SnapshotSinkFunc(func(snapshot *Graph) error {
+			m.Lock()
+			saved = append(saved, snapshot)
+			m.Unlock()
+			return nil
+		}))
+
+		// wait for the first save
+		deadline := time.Now().Add(5 * time.Second)
+		for {
+			m.Lock()
+			n := len(saved)
+			m.Unlock()
+			if n > 0 {
+				break
+			}
+			if time.Now().After(deadline) {
+				t.Fatal("timed out waiting for the first save")
+			}
+			time.Sleep(time.Millisecond)
+		}
+
+		// an attribute change alone makes the graph change
+		g.SetEdgeAttr("a", "b", "label", "knows")
+		if err := stop(); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		m.Lock()
+		defer m.Unlock()
+		if e, _ := saved[len(saved)-1].GetEdge("a", "b"); e.Attrs["label"] != "knows" {
+			t.Errorf("expected the attribute to be saved, got %+v", e)
+		}
+	})
+}
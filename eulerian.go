@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"sort"
+)
+
+// EulerianPath returns a path which uses every edge of the graph exactly once, as the keys of the vertices along it, and false if there is no such path.
+// A directed graph has an Eulerian path if all vertices with edges are weakly connected, and at most one vertex has one more outgoing than incoming edge (where the path starts) and at most one has one more incoming than outgoing edge (where it ends), while all others have as many incoming as outgoing edges.
+// The path is found with Hierholzer's algorithm; among possible paths, the one following edges to smaller keys first is returned. A graph without edges has no path.
+func (g *Graph) EulerianPath() (path []string, ok bool) {
+	defer g.track("EulerianPath")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.eulerian(false)
+}
+
+// EulerianCircuit returns a closed path which uses every edge of the graph exactly once and ends where it starts, and false if there is no such circuit.
+// A directed graph has an Eulerian circuit if all vertices with edges are weakly connected and every vertex has as many incoming as outgoing edges. See EulerianPath.
+func (g *Graph) EulerianCircuit() (path []string, ok bool) {
+	defer g.track("EulerianCircuit")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.eulerian(true)
+}
+
+// eulerian is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+func (g *Graph) eulerian(circuit bool) (path []string, ok bool) {
+	var start *Vertex
+	edges := 0
+	starts, ends := 0, 0
+
+	// sorted outgoing edges of every vertex, consumed from the front
+	unused := map[*Vertex][]*Vertex{}
+
+	for _, v := range g.vertices {
+		outgoing, incoming := v.GetOutgoing(), v.GetIncoming()
+		edges += len(outgoing)
+
+		switch len(outgoing) - len(incoming) {
+		case 0:
+		case 1:
+			starts++
+			start = v
+		case -1:
+			ends++
+		default:
+			return nil, false
+		}
+
+		for neighbor := range outgoing {
+			unused[v] = append(unused[v], neighbor)
+		}
+		sort.Slice(unused[v], func(i, j int) bool {
+			return unused[v][i].key < unused[v][j].key
+		})
+	}
+
+	if edges == 0 || starts > 1 || ends > 1 || (circuit && starts > 0) {
+		return nil, false
+	}
+
+	// balanced graphs have a circuit, which starts at the smallest key with edges
+	if start == nil {
+		for v := range unused {
+			if len(unused[v]) > 0 && (start == nil || v.key < start.key) {
+				start = v
+			}
+		}
+	}
+
+	// Hierholzer's algorithm: follow unused edges until stuck, then backtrack, splicing in the cycles found on the way
+	stack := []*Vertex{start}
+	for len(stack) > 0 {
+		v := stack[len(stack)-1]
+
+		if len(unused[v]) > 0 {
+			stack = append(stack, unused[v][0])
+			unused[v] = unused[v][1:]
+			continue
+		}
+
+		stack = stack[:len(stack)-1]
+		path = append(path, v.key)
+	}
+
+	// edges not reachable from the start vertex are in another component
+	if len(path) != edges+1 {
+		return nil, false
+	}
+
+	// reverse into start → end order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
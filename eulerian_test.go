@@ -0,0 +1,71 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEulerianCircuit(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "isolated"} {
+		g.Set(key, nil)
+	}
+
+	// two cycles sharing vertex a
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("a", "d", 1)
+	g.Connect("d", "a", 1)
+
+	circuit, ok := g.EulerianCircuit()
+	if !ok || !reflect.DeepEqual(circuit, []string{"a", "b", "c", "a", "d", "a"}) {
+		t.Fatalf("unexpected circuit %v", circuit)
+	}
+
+	path, ok := g.EulerianPath()
+	if !ok || !reflect.DeepEqual(path, circuit) {
+		t.Fail()
+	}
+
+	// a balanced cycle in another component can't be reached
+	g.Set("e", nil)
+	g.Connect("isolated", "e", 1)
+	g.Connect("e", "isolated", 1)
+	if _, ok = g.EulerianCircuit(); ok {
+		t.Fail()
+	}
+}
+
+func TestEulerianPath(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c → a → d: starts at a, ends at d
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("a", "d", 1)
+
+	path, ok := g.EulerianPath()
+	if !ok || !reflect.DeepEqual(path, []string{"a", "b", "c", "a", "d"}) {
+		t.Fatalf("unexpected path %v", path)
+	}
+
+	if _, ok = g.EulerianCircuit(); ok {
+		t.Fail()
+	}
+
+	// d → b makes b unbalanced by two
+	g.Connect("d", "b", 1)
+	g.Disconnect("c", "a")
+	if _, ok = g.EulerianPath(); ok {
+		t.Fail()
+	}
+
+	if _, ok = New().EulerianPath(); ok {
+		t.Fail()
+	}
+}
@@ -0,0 +1,70 @@
+package graph
+
+// EventType identifies the kind of mutation described by an Event.
+type EventType int
+
+const (
+	// EventSet means a vertex was created or its value was updated.
+	EventSet EventType = iota
+
+	// EventDelete means a vertex and all its edges were deleted.
+	EventDelete
+
+	// EventConnect means an edge was created or its weight was updated.
+	EventConnect
+
+	// EventDisconnect means an edge was removed, with its attributes.
+	EventDisconnect
+
+	// EventSetEdgeAttr means an attribute of an edge was set, see SetEdgeAttr.
+	EventSetEdgeAttr
+
+	// EventDeleteEdgeAttr means an attribute of an edge was removed, see DeleteEdgeAttr.
+	EventDeleteEdgeAttr
+)
+
+// Event describes a single mutation of a graph.
+type Event struct {
+	Type   EventType
+	Key    string      // key of the vertex, or of the vertex the edge starts at
+	ToKey  string      // key of the vertex the edge ends at; only set for events of edges
+	Value  interface{} // new value of the vertex or edge attribute; only set for EventSet and EventSetEdgeAttr
+	Weight int         // new weight of the edge; only set for EventConnect
+	Attr   string      // name of the edge attribute; only set for EventSetEdgeAttr and EventDeleteEdgeAttr
+}
+
+// Subscribe registers fn to be called after every mutation of the graph and returns a function to cancel the subscription.
+// fn is called synchronously while the graph is locked, so it must not call any of the graph's methods. Events are passed one at a time, in the order the mutations were applied, even if vertices are connected or disconnected by several goroutines at the same time.
+func (g *Graph) Subscribe(fn func(Event)) (cancel func()) {
+	defer g.track("Subscribe")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	return g.subscribe(fn)
+}
+
+// subscribe is an internal function, does NOT lock the graph, should only be used in between Lock() and Unlock().
+func (g *Graph) subscribe(fn func(Event)) (cancel func()) {
+	if g.subscribers == nil {
+		g.subscribers = map[int]func(Event){}
+	}
+
+	id := g.nextSubscriber
+	g.nextSubscriber++
+
+	g.subscribers[id] = fn
+
+	return func() {
+		g.Lock()
+		delete(g.subscribers, id)
+		g.Unlock()
+	}
+}
+
+// emit passes e to all subscribers. Does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+func (g *Graph) emit(e Event) {
+	for _, fn := range g.subscribers {
+		fn(e)
+	}
+}
@@ -0,0 +1,92 @@
+package graph
+
+import (
+	"runtime"
+	"sync"
+	"testing"
+)
+
+func TestSubscribe(t *testing.T) {
+	g := New()
+
+	var events []Event
+	cancel := g.Subscribe(func(e Event) {
+		events = append(events, e)
+	})
+
+	g.Set("1", 123)
+	g.Set("2", 678)
+	g.Set("1", 456)
+	g.Connect("1", "2", 5)
+	g.Disconnect("1", "2")
+	g.Delete("2")
+
+	// invalid mutations don't emit events
+	g.Connect("1", "3", 1)
+	g.Delete("3")
+
+	expected := []Event{
+		{Type: EventSet, Key: "1", Value: 123},
+		{Type: EventSet, Key: "2", Value: 678},
+		{Type: EventSet, Key: "1", Value: 456},
+		{Type: EventConnect, Key: "1", ToKey: "2", Weight: 5},
+		{Type: EventDisconnect, Key: "1", ToKey: "2"},
+		{Type: EventDelete, Key: "2"},
+	}
+
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %d", len(expected), len(events))
+	}
+	for i := range expected {
+		if events[i] != expected[i] {
+			t.Errorf("expected %v, got %v", expected[i], events[i])
+		}
+	}
+
+	// no more events after cancelling
+	cancel()
+	g.Set("4", nil)
+
+	if len(events) != len(expected) {
+		t.Fail()
+	}
+}
+
+func TestSubscribeConcurrentOrder(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+
+	// replay the events of the edge a → b like a replica would
+	var m sync.Mutex
+	connected, weight := false, 0
+	g.Subscribe(func(e Event) {
+		runtime.Gosched()
+
+		m.Lock()
+		connected, weight = e.Type == EventConnect, e.Weight
+		m.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for w := 0; w < 4; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				if (i+w)%2 == 0 {
+					g.Connect("a", "b", w*1000+i)
+				} else {
+					g.Disconnect("a", "b")
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	m.Lock()
+	defer m.Unlock()
+	if ok, w := g.IsConnected("a", "b"); ok != connected || ok && w != weight {
+		t.Errorf("expected the last event to match the graph %v %d, got %v %d", ok, w, connected, weight)
+	}
+}
@@ -0,0 +1,348 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// ErrInvalidFlatBuffer is returned by NewFlatGraph if the buffer doesn't hold a graph in the format of graph.fbs.
+var ErrInvalidFlatBuffer = errors.New("graph: invalid FlatBuffer")
+
+// flatGraphIdentifier is the file identifier of graph.fbs.
+const flatGraphIdentifier = "GRPH"
+
+// Inline sizes of the tables and structs of graph.fbs.
+const (
+	flatRootSize   = 8  // vtable offset, vertices
+	flatVertexSize = 16 // vtable offset, key, value, outgoing
+	flatEdgeSize   = 16 // to, padding, weight
+)
+
+// MarshalFlatBuffer encodes the graph as a FlatBuffer with the schema in graph.fbs, for very large read-mostly graphs: NewFlatGraph answers queries directly on the encoded data, e.g. after mapping a file into memory, without decoding it.
+// Values are embedded in MessagePack, so they are restricted to the types supported by MarshalMsgpack.
+func (g *Graph) MarshalFlatBuffer() ([]byte, error) {
+	defer g.track("MarshalFlatBuffer")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := g.sortedKeys()
+	index := make(map[*Vertex]int, len(keys))
+	values := make([][]byte, len(keys))
+
+	for i, key := range keys {
+		v := g.vertices[key]
+		index[v] = i
+
+		enc := &msgpackEncoder{}
+		if err := enc.encode(v.Value()); err != nil {
+			return nil, fmt.Errorf("graph: encoding FlatBuffer: vertex %q: %v", key, err)
+		}
+		values[i] = enc.buf
+	}
+
+	// The buffer is written front to back: header, vtables, root table, vertex vector, vertex tables, then the strings and vectors they refer to, so all offsets point forward.
+	fb := &flatBuilder{}
+	fb.uint32(0) // offset of the root table, set below
+	fb.buf = append(fb.buf, flatGraphIdentifier...)
+
+	rootVTable := len(fb.buf)
+	fb.uint16(6, flatRootSize, 4)
+	vertexVTable := len(fb.buf)
+	fb.uint16(10, flatVertexSize, 4, 8, 12)
+
+	fb.align(4, 0)
+	root := len(fb.buf)
+	fb.setUint32(0, uint32(root))
+	fb.uint32(uint32(root - rootVTable))
+	fb.uint32(0) // offset of the vertex vector
+
+	fb.setOffset(root+4, len(fb.buf))
+	fb.uint32(uint32(len(keys)))
+	elements := len(fb.buf)
+	for range keys {
+		fb.uint32(0)
+	}
+
+	tables := len(fb.buf)
+	for i := range keys {
+		table := tables + i*flatVertexSize
+		fb.setOffset(elements+4*i, table)
+		fb.uint32(uint32(table-vertexVTable), 0, 0, 0)
+	}
+
+	for i, key := range keys {
+		table := tables + i*flatVertexSize
+
+		fb.align(4, 0)
+		fb.setOffset(table+4, len(fb.buf))
+		fb.uint32(uint32(len(key)))
+		fb.buf = append(fb.buf, key...)
+		fb.buf = append(fb.buf, 0)
+
+		fb.align(4, 0)
+		fb.setOffset(table+8, len(fb.buf))
+		fb.uint32(uint32(len(values[i])))
+		fb.buf = append(fb.buf, values[i]...)
+
+		outgoing := g.vertices[key].GetOutgoing()
+		targets := make([]int, 0, len(outgoing))
+		weights := make(map[int]int, len(outgoing))
+		for neighbor, weight := range outgoing {
+			targets = append(targets, index[neighbor])
+			weights[index[neighbor]] = weight
+		}
+		sort.Ints(targets)
+
+		// the edges following the length must be aligned to 8 bytes
+		fb.align(8, 4)
+		fb.setOffset(table+12, len(fb.buf))
+		fb.uint32(uint32(len(targets)))
+		for _, target := range targets {
+			fb.uint32(uint32(target), 0)
+			fb.buf = appendLittleEndian(fb.buf, uint64(int64(weights[target])), 8)
+		}
+	}
+
+	return fb.buf, nil
+}
+
+// flatBuilder writes the little-endian data of a FlatBuffer.
+type flatBuilder struct {
+	buf []byte
+}
+
+func (fb *flatBuilder) uint16(values ...uint16) {
+	for _, v := range values {
+		fb.buf = appendLittleEndian(fb.buf, uint64(v), 2)
+	}
+}
+
+func (fb *flatBuilder) uint32(values ...uint32) {
+	for _, v := range values {
+		fb.buf = appendLittleEndian(fb.buf, uint64(v), 4)
+	}
+}
+
+// setUint32 overwrites the uint32 at pos.
+func (fb *flatBuilder) setUint32(pos int, v uint32) {
+	appendLittleEndian(fb.buf[pos:pos], uint64(v), 4)
+}
+
+// setOffset makes the offset at pos point to target.
+func (fb *flatBuilder) setOffset(pos, target int) {
+	fb.setUint32(pos, uint32(target-pos))
+}
+
+// align pads the buffer with zeros until its length modulo n is rest.
+func (fb *flatBuilder) align(n, rest int) {
+	for len(fb.buf)%n != rest {
+		fb.buf = append(fb.buf, 0)
+	}
+}
+
+// FlatGraph is a read-only view of a graph encoded by MarshalFlatBuffer, or by any FlatBuffers implementation using graph.fbs. It answers queries directly on the buffer by binary search, without decoding it, so opening it takes constant time and memory.
+// The buffer must not be modified while the view is used. FlatGraph is safe for concurrent use. Corrupt buffers don't cause panics, but may give wrong results.
+type FlatGraph struct {
+	buf      []byte
+	vertices int // position of the first element of the vertex vector
+	n        int // number of vertices
+}
+
+// NewFlatGraph returns a view of the graph encoded in buf. Returns ErrInvalidFlatBuffer if buf doesn't start with a graph.fbs header.
+func NewFlatGraph(buf []byte) (*FlatGraph, error) {
+	f := &FlatGraph{buf: buf}
+
+	if len(buf) < 8 || string(buf[4:8]) != flatGraphIdentifier {
+		return nil, ErrInvalidFlatBuffer
+	}
+
+	root := int(f.uint32(0))
+	if root < 8 || root+4 > len(buf) {
+		return nil, ErrInvalidFlatBuffer
+	}
+
+	if field := f.field(root, 0); field != 0 {
+		vector := f.deref(field)
+		f.n = int(f.uint32(vector))
+		f.vertices = vector + 4
+
+		if vector == 0 || f.n > (len(buf)-f.vertices)/4 {
+			return nil, ErrInvalidFlatBuffer
+		}
+	}
+
+	return f, nil
+}
+
+// Len returns the number of vertices.
+func (f *FlatGraph) Len() int {
+	return f.n
+}
+
+// Keys returns the keys of all vertices in sorted order.
+func (f *FlatGraph) Keys() []string {
+	keys := make([]string, f.n)
+	for i := range keys {
+		keys[i] = string(f.key(f.vertex(i)))
+	}
+	return keys
+}
+
+// Get returns the value of the vertex with the specified key, decoded from MessagePack. Returns ErrInvalidKey if there is no such vertex.
+func (f *FlatGraph) Get(key string) (interface{}, error) {
+	i := f.find(key)
+	if i < 0 {
+		return nil, ErrInvalidKey
+	}
+
+	var b []byte
+	if field := f.field(f.vertex(i), 1); field != 0 {
+		b = f.bytes(f.deref(field))
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+
+	dec := &msgpackDecoder{buf: b}
+	value, err := dec.decode()
+	if err != nil {
+		return nil, fmt.Errorf("graph: decoding value of %q: %v", key, err)
+	}
+
+	return value, nil
+}
+
+// IsConnected returns true and the weight if there is an edge from the vertex with key fromKey to the vertex with key toKey, like Graph.IsConnected.
+func (f *FlatGraph) IsConnected(fromKey, toKey string) (exists bool, weight int) {
+	from, to := f.find(fromKey), f.find(toKey)
+	if from < 0 || to < 0 {
+		return false, 0
+	}
+
+	edges, m := f.edges(from)
+	j := sort.Search(m, func(j int) bool { return int(f.uint32(edges+j*flatEdgeSize)) >= to })
+	if j < m && int(f.uint32(edges+j*flatEdgeSize)) == to {
+		return true, int(int64(f.uint64(edges + j*flatEdgeSize + 8)))
+	}
+
+	return false, 0
+}
+
+// GetOutgoing returns the weights of the outgoing edges of the vertex with the specified key, by the keys of their targets. Returns ErrInvalidKey if there is no such vertex.
+func (f *FlatGraph) GetOutgoing(key string) (map[string]int, error) {
+	i := f.find(key)
+	if i < 0 {
+		return nil, ErrInvalidKey
+	}
+
+	edges, m := f.edges(i)
+	outgoing := make(map[string]int, m)
+	for j := 0; j < m; j++ {
+		edge := edges + j*flatEdgeSize
+		to := int(f.uint32(edge))
+		if to >= f.n {
+			continue
+		}
+		outgoing[string(f.key(f.vertex(to)))] = int(int64(f.uint64(edge + 8)))
+	}
+
+	return outgoing, nil
+}
+
+// find returns the index of the vertex with key, or -1.
+func (f *FlatGraph) find(key string) int {
+	i := sort.Search(f.n, func(i int) bool { return string(f.key(f.vertex(i))) >= key })
+	if i < f.n && string(f.key(f.vertex(i))) == key {
+		return i
+	}
+	return -1
+}
+
+// vertex returns the position of the table of the vertex with index i.
+func (f *FlatGraph) vertex(i int) int {
+	return f.deref(f.vertices + 4*i)
+}
+
+// key returns the key of the vertex table at pos.
+func (f *FlatGraph) key(pos int) []byte {
+	field := f.field(pos, 0)
+	if field == 0 {
+		return nil
+	}
+	return f.bytes(f.deref(field))
+}
+
+// edges returns the position of the first outgoing edge of the vertex with index i, and the number of edges.
+func (f *FlatGraph) edges(i int) (int, int) {
+	field := f.field(f.vertex(i), 2)
+	if field == 0 {
+		return 0, 0
+	}
+
+	vector := f.deref(field)
+	m := int(f.uint32(vector))
+	if vector == 0 || m > (len(f.buf)-vector-4)/flatEdgeSize {
+		return 0, 0
+	}
+	return vector + 4, m
+}
+
+// field returns the position of field number i of the table at pos, or 0 if the field is absent.
+func (f *FlatGraph) field(pos, i int) int {
+	vtable := pos - int(int32(f.uint32(pos)))
+	if vtable < 0 || vtable+4 > len(f.buf) || 4+2*i >= int(f.uint16(vtable)) {
+		return 0
+	}
+
+	offset := int(f.uint16(vtable + 4 + 2*i))
+	if offset == 0 {
+		return 0
+	}
+	return pos + offset
+}
+
+// deref returns the position an offset at pos points to, or 0 if it is out of range.
+func (f *FlatGraph) deref(pos int) int {
+	target := pos + int(f.uint32(pos))
+	if target <= pos || target >= len(f.buf) {
+		return 0
+	}
+	return target
+}
+
+// bytes returns the contents of the string or byte vector at pos.
+func (f *FlatGraph) bytes(pos int) []byte {
+	n := int(f.uint32(pos))
+	if pos == 0 || n > len(f.buf)-pos-4 {
+		return nil
+	}
+	return f.buf[pos+4 : pos+4+n]
+}
+
+// uint16, uint32 and uint64 read little-endian integers, returning 0 if they are out of range.
+
+func (f *FlatGraph) uint16(pos int) uint16 {
+	return uint16(f.uint(pos, 2))
+}
+
+func (f *FlatGraph) uint32(pos int) uint32 {
+	return uint32(f.uint(pos, 4))
+}
+
+func (f *FlatGraph) uint64(pos int) uint64 {
+	return f.uint(pos, 8)
+}
+
+func (f *FlatGraph) uint(pos, size int) uint64 {
+	if pos < 0 || pos > len(f.buf)-size {
+		return 0
+	}
+
+	var u uint64
+	for i := size - 1; i >= 0; i-- {
+		u = u<<8 | uint64(f.buf[pos+i])
+	}
+	return u
+}
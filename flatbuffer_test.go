@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFlatGraph(t *testing.T) {
+	g := New()
+	g.Set("b", "value")
+	g.Set("a", nil)
+	g.Set("c", []interface{}{1, true})
+	g.Set("d", 2.5)
+
+	g.Connect("a", "b", 5)
+	g.Connect("a", "d", -3)
+	g.Connect("a", "c", 1000000)
+	g.Connect("c", "a", 0)
+
+	buf, err := g.MarshalFlatBuffer()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	f, err := NewFlatGraph(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if f.Len() != 4 || !reflect.DeepEqual(f.Keys(), []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected keys %v", f.Keys())
+	}
+
+	for key, expected := range map[string]interface{}{
+		"a": nil,
+		"b": "value",
+		"c": []interface{}{int64(1), true},
+		"d": 2.5,
+	} {
+		value, err := f.Get(key)
+		if err != nil || !reflect.DeepEqual(value, expected) {
+			t.Errorf("%q: expected %#v, got %#v (%v)", key, expected, value, err)
+		}
+	}
+	if _, err := f.Get("x"); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+
+	for _, from := range []string{"a", "b", "c", "d", "x"} {
+		for _, to := range []string{"a", "b", "c", "d", "x"} {
+			expectedOK, expectedWeight := g.IsConnected(from, to)
+			if ok, weight := f.IsConnected(from, to); ok != expectedOK || weight != expectedWeight {
+				t.Errorf("%s → %s: expected %v %d, got %v %d", from, to, expectedOK, expectedWeight, ok, weight)
+			}
+		}
+	}
+
+	outgoing, err := f.GetOutgoing("a")
+	if err != nil || !reflect.DeepEqual(outgoing, map[string]int{"b": 5, "c": 1000000, "d": -3}) {
+		t.Errorf("unexpected outgoing edges %v (%v)", outgoing, err)
+	}
+	if _, err := f.GetOutgoing("x"); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+
+	empty, _ := New().MarshalFlatBuffer()
+	if f, err := NewFlatGraph(empty); err != nil || f.Len() != 0 {
+		t.Errorf("expected an empty view, got %v", err)
+	}
+}
+
+func TestFlatGraphCorrupt(t *testing.T) {
+	if _, err := NewFlatGraph([]byte("not a graph")); err != ErrInvalidFlatBuffer {
+		t.Errorf("expected ErrInvalidFlatBuffer, got %v", err)
+	}
+
+	g := New()
+	for _, key := range []string{"a", "b", "c"} {
+		g.Set(key, key)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+
+	buf, _ := g.MarshalFlatBuffer()
+
+	// truncated or damaged buffers must not cause panics
+	for i := range buf {
+		for _, damaged := range [][]byte{buf[:i], flip(buf, i)} {
+			f, err := NewFlatGraph(damaged)
+			if err != nil {
+				continue
+			}
+
+			f.Keys()
+			for _, key := range []string{"a", "b", "c"} {
+				f.Get(key)
+				f.GetOutgoing(key)
+				f.IsConnected(key, "b")
+			}
+		}
+	}
+}
+
+// flip returns a copy of b with the bits of the byte at i inverted.
+func flip(b []byte, i int) []byte {
+	c := append([]byte(nil), b...)
+	c[i] ^= 0xff
+	return c
+}
@@ -0,0 +1,36 @@
+package graph
+
+// GCFrom deletes every vertex which is not reachable from any of the vertices with the given keys by following outgoing edges, e.g. to reclaim orphaned entries of cache-like graphs.
+// Protected vertices (see Protect) are never deleted and count as roots, so everything reachable from them is kept, too.
+// Invalid root keys are ignored, so if none of them is valid and no vertex is protected, all vertices are deleted. Returns the number of vertices deleted.
+func (g *Graph) GCFrom(rootKeys []string) int {
+	defer g.track("GCFrom")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	roots := make([]*Vertex, 0, len(rootKeys)+len(g.protected))
+	for _, key := range rootKeys {
+		if v := g.get(key); v != nil {
+			roots = append(roots, v)
+		}
+	}
+	for v := range g.protected {
+		roots = append(roots, v)
+	}
+
+	reachable := g.reachable(roots)
+
+	var garbage []*Vertex
+	for _, v := range g.vertices {
+		if !reachable[v] {
+			garbage = append(garbage, v)
+		}
+	}
+
+	for _, v := range garbage {
+		g.remove(v)
+	}
+
+	return len(garbage)
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestGCFrom(t *testing.T) {
+	g := New()
+	for _, key := range []string{"root", "a", "b", "orphan", "orphan-child"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("root", "a", 1)
+	g.Connect("a", "b", 1)
+	g.Connect("b", "root", 1)
+	g.Connect("orphan", "orphan-child", 1)
+	g.Connect("orphan", "a", 1)
+	g.Tag("orphan", "stale")
+
+	deleted := []string{}
+	g.Subscribe(func(e Event) {
+		if e.Type == EventDelete {
+			deleted = append(deleted, e.Key)
+		}
+	})
+
+	if n := g.GCFrom([]string{"root", "missing"}); n != 2 {
+		t.Fatalf("expected 2 vertices to be deleted, got %d", n)
+	}
+	if g.Len() != 3 || len(deleted) != 2 {
+		t.Fail()
+	}
+	if _, err := g.Get("orphan"); err == nil {
+		t.Fail()
+	}
+	if len(g.Tagged("stale")) != 0 {
+		t.Fail()
+	}
+
+	// edges from deleted vertices are gone
+	a, _ := g.Get("a")
+	if len(a.GetIncoming()) != 1 {
+		t.Fail()
+	}
+
+	if n := g.GCFrom([]string{"root"}); n != 0 {
+		t.Fail()
+	}
+	if n := g.GCFrom(nil); n != 3 || g.Len() != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,77 @@
+package graph
+
+import (
+	"bytes"
+	"testing"
+	"time"
+)
+
+func TestWriteGEXF(t *testing.T) {
+	g := New()
+	g.Set("b", nil)
+	g.Set("a", "<A>")
+
+	g.Connect("a", "b", 5)
+	g.Connect("b", "a", -1)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteGEXF(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="directed" mode="static">
+    <nodes>
+      <node id="a" label="&lt;A&gt;"></node>
+      <node id="b" label="b"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" weight="5"></edge>
+      <edge id="1" source="b" target="a" weight="-1"></edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	day := func(d int) time.Time {
+		return time.Date(2020, 1, d, 0, 0, 0, 0, time.UTC)
+	}
+
+	buf.Reset()
+	err := g.WriteGEXF(buf,
+		GEXFVertexTimes(func(key string, value interface{}) (time.Time, time.Time) {
+			if key == "a" {
+				return day(1), time.Time{}
+			}
+			return day(2), day(9)
+		}),
+		GEXFEdgeTimes(func(e Edge) (time.Time, time.Time) {
+			return time.Time{}, day(e.Weight + 3)
+		}),
+	)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected = `<?xml version="1.0" encoding="UTF-8"?>
+<gexf xmlns="http://gexf.net/1.3" version="1.3">
+  <graph defaultedgetype="directed" mode="dynamic" timeformat="dateTime">
+    <nodes>
+      <node id="a" label="&lt;A&gt;" start="2020-01-01T00:00:00Z"></node>
+      <node id="b" label="b" start="2020-01-02T00:00:00Z" end="2020-01-09T00:00:00Z"></node>
+    </nodes>
+    <edges>
+      <edge id="0" source="a" target="b" weight="5" end="2020-01-08T00:00:00Z"></edge>
+      <edge id="1" source="b" target="a" weight="-1" end="2020-01-02T00:00:00Z"></edge>
+    </edges>
+  </graph>
+</gexf>
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+}
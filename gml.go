@@ -0,0 +1,343 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"html"
+	"io"
+	"io/ioutil"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// gmlPair is a key-value pair of a GML list. The value is an int64, float64, string or gmlList.
+type gmlPair struct {
+	key   string
+	value interface{}
+}
+
+// gmlList is a list of key-value pairs, e.g. the contents of a node [...] block.
+type gmlList []gmlPair
+
+// get returns the value of the first pair with key, or nil.
+func (l gmlList) get(key string) interface{} {
+	for _, pair := range l {
+		if pair.key == key {
+			return pair.value
+		}
+	}
+	return nil
+}
+
+// ReadGML parses a graph in the GML format, as written by NetworkX and used by many published datasets. Nodes become vertices keyed by their "label", or by their "id" if they have none; their "value" attribute, if any, becomes the vertex value (an int64, float64 or string).
+// Edges get their "weight" attribute as weight, which must be an integer, or 1 if they have none. Edges of undirected graphs, which is the default in GML, are connected in both directions. Other attributes are ignored.
+// If there are self-loops, they are enabled on the returned graph, see EnableSelfLoops. Returns an error if the data is malformed or an edge refers to an unknown node.
+func ReadGML(r io.Reader) (*Graph, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("graph: reading GML: %v", err)
+	}
+
+	root, err := parseGML(string(b))
+	if err != nil {
+		return nil, err
+	}
+
+	graphList, ok := root.get("graph").(gmlList)
+	if !ok {
+		return nil, fmt.Errorf("graph: reading GML: no graph")
+	}
+
+	directed := false
+	if d, ok := graphList.get("directed").(int64); ok && d != 0 {
+		directed = true
+	}
+
+	g := New()
+	keys := map[string]string{} // maps node ids to vertex keys
+
+	for _, pair := range graphList {
+		node, ok := pair.value.(gmlList)
+		if pair.key != "node" || !ok {
+			continue
+		}
+
+		id, ok := gmlID(node.get("id"))
+		if !ok {
+			return nil, fmt.Errorf("graph: reading GML: node without id")
+		}
+		if _, ok := keys[id]; ok {
+			return nil, fmt.Errorf("graph: reading GML: duplicate node id %s", id)
+		}
+
+		key := id
+		if label, ok := node.get("label").(string); ok {
+			key = label
+		}
+		if _, err := g.Get(key); err == nil {
+			return nil, fmt.Errorf("graph: reading GML: duplicate node label %q", key)
+		}
+
+		keys[id] = key
+		g.Set(key, node.get("value"))
+	}
+
+	for _, pair := range graphList {
+		edge, ok := pair.value.(gmlList)
+		if pair.key != "edge" || !ok {
+			continue
+		}
+
+		source, ok1 := gmlID(edge.get("source"))
+		target, ok2 := gmlID(edge.get("target"))
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("graph: reading GML: edge without source or target")
+		}
+
+		from, ok1 := keys[source]
+		to, ok2 := keys[target]
+		if !ok1 || !ok2 {
+			return nil, fmt.Errorf("graph: reading GML: edge %s → %s refers to an unknown node", source, target)
+		}
+		if from == to {
+			g.selfLoops = true
+		}
+
+		weight := 1
+		switch w := edge.get("weight").(type) {
+		case nil:
+		case int64:
+			weight = int(w)
+		case float64:
+			if w != math.Trunc(w) {
+				return nil, fmt.Errorf("graph: reading GML: edge %q → %q: invalid weight %v", from, to, w)
+			}
+			weight = int(w)
+		default:
+			return nil, fmt.Errorf("graph: reading GML: edge %q → %q: invalid weight %q", from, to, w)
+		}
+
+		g.Connect(from, to, weight)
+		if !directed {
+			g.Connect(to, from, weight)
+		}
+	}
+
+	return g, nil
+}
+
+// gmlID returns the string form of a node id, which may be an integer or a string.
+func gmlID(value interface{}) (string, bool) {
+	switch id := value.(type) {
+	case int64:
+		return strconv.FormatInt(id, 10), true
+	case string:
+		return id, true
+	}
+	return "", false
+}
+
+// parseGML parses GML source into its top-level list.
+func parseGML(src string) (gmlList, error) {
+	p := &gmlParser{src: src, line: 1}
+
+	list, err := p.list()
+	if err != nil {
+		return nil, err
+	}
+	if p.skip(); p.pos < len(p.src) {
+		return nil, p.errorf("unexpected %q", p.src[p.pos])
+	}
+
+	return list, nil
+}
+
+// gmlParser is a recursive descent parser for GML.
+type gmlParser struct {
+	src  string
+	pos  int
+	line int
+}
+
+// errorf returns a parse error at the current line.
+func (p *gmlParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: reading GML: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// skip skips whitespace and comments, which start with # and end at the end of the line.
+func (p *gmlParser) skip() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// list parses key-value pairs up to the end of the input or a closing bracket, which is not consumed.
+func (p *gmlParser) list() (gmlList, error) {
+	var list gmlList
+
+	for p.skip(); p.pos < len(p.src) && p.src[p.pos] != ']'; p.skip() {
+		start := p.pos
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isGMLLetter(p.src[p.pos]) || (p.pos > start && p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+			p.pos++
+		}
+		if p.pos == start {
+			return nil, p.errorf("expected key, found %q", p.src[p.pos])
+		}
+		key := p.src[start:p.pos]
+
+		p.skip()
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+
+		list = append(list, gmlPair{key, value})
+	}
+
+	return list, nil
+}
+
+// value parses a number, a string or a bracketed list.
+func (p *gmlParser) value() (interface{}, error) {
+	if p.pos >= len(p.src) {
+		return nil, p.errorf("unexpected end of input")
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '[':
+		p.pos++
+		list, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		if p.pos >= len(p.src) {
+			return nil, p.errorf("expected \"]\"")
+		}
+		p.pos++
+		return list, nil
+
+	case c == '"':
+		end := strings.IndexByte(p.src[p.pos+1:], '"')
+		if end < 0 {
+			return nil, p.errorf("unterminated string")
+		}
+		s := p.src[p.pos+1 : p.pos+1+end]
+		p.line += strings.Count(s, "\n")
+		p.pos += end + 2
+		return html.UnescapeString(s), nil
+
+	default:
+		start := p.pos
+		for p.pos < len(p.src) && strings.IndexByte("+-.0123456789eE", p.src[p.pos]) >= 0 {
+			p.pos++
+		}
+		s := p.src[start:p.pos]
+
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return i, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, nil
+		}
+		if s == "" {
+			return nil, p.errorf("expected value, found %q", c)
+		}
+		return nil, p.errorf("invalid number %q", s)
+	}
+}
+
+// isGMLLetter returns true if c is an ASCII letter.
+func isGMLLetter(c byte) bool {
+	return c|0x20 >= 'a' && c|0x20 <= 'z'
+}
+
+// WriteGML writes the graph to w in the GML format as a directed graph: vertices are nodes numbered in key order with their keys as labels, and their values as "value" attributes unless they are nil. Integers and floats are written as numbers, all other values as strings formatted with fmt.Sprint.
+// Edges keep their weights. The output can be read by ReadGML and NetworkX' read_gml.
+func (g *Graph) WriteGML(w io.Writer) error {
+	defer g.track("WriteGML")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	bw.WriteString("graph [\n  directed 1\n")
+
+	keys := g.sortedKeys()
+	ids := make(map[*Vertex]int, len(keys))
+
+	for i, key := range keys {
+		v := g.vertices[key]
+		ids[v] = i
+
+		fmt.Fprintf(bw, "  node [\n    id %d\n    label %s\n", i, gmlQuote(key))
+		if value := v.Value(); value != nil {
+			fmt.Fprintf(bw, "    value %s\n", gmlValue(value))
+		}
+		bw.WriteString("  ]\n")
+	}
+
+	for i, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		targets := make([]int, 0, len(outgoing))
+		weights := make(map[int]int, len(outgoing))
+		for neighbor, weight := range outgoing {
+			targets = append(targets, ids[neighbor])
+			weights[ids[neighbor]] = weight
+		}
+		sort.Ints(targets)
+
+		for _, target := range targets {
+			fmt.Fprintf(bw, "  edge [\n    source %d\n    target %d\n    weight %d\n  ]\n", i, target, weights[target])
+		}
+	}
+
+	bw.WriteString("]\n")
+
+	return bw.Flush()
+}
+
+// gmlValue formats a vertex value as a GML number or string.
+func gmlValue(value interface{}) string {
+	switch v := value.(type) {
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32:
+		return fmt.Sprint(v)
+	case float32:
+		return gmlFloat(float64(v))
+	case float64:
+		return gmlFloat(v)
+	}
+	return gmlQuote(fmt.Sprint(value))
+}
+
+// gmlFloat formats f as a GML real, which always contains a decimal point so it is read back as a float. Infinite and NaN values are written as strings.
+func gmlFloat(f float64) string {
+	if math.IsInf(f, 0) || math.IsNaN(f) {
+		return gmlQuote(fmt.Sprint(f))
+	}
+
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".eE") {
+		s += ".0"
+	}
+	return s
+}
+
+// gmlQuote returns s as a quoted GML string, escaping the characters which can't appear literally as HTML entities.
+func gmlQuote(s string) string {
+	return `"` + strings.NewReplacer("&", "&amp;", `"`, "&quot;").Replace(s) + `"`
+}
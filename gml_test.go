@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestGMLRoundTrip(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2.0)
+	g.Set("c", `"quoted" & more`)
+	g.Set("d", nil)
+
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", -2)
+	g.Connect("d", "a", 1)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteGML(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	read, err := ReadGML(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for key, expected := range map[string]interface{}{"a": int64(1), "b": 2.0, "c": `"quoted" & more`, "d": nil} {
+		v, err := read.Get(key)
+		if err != nil {
+			t.Fatalf("missing vertex %q", key)
+		}
+		if v.Value() != expected {
+			t.Errorf("%q: expected value %#v, got %#v", key, expected, v.Value())
+		}
+	}
+
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 5}, {From: "b", To: "c", Weight: -2}, {From: "d", To: "a", Weight: 1}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+	if ok, _ := read.IsConnected("b", "a"); ok {
+		t.Error("unexpected edge b → a")
+	}
+}
+
+func TestReadGML(t *testing.T) {
+	// as written by NetworkX for an undirected graph
+	src := `# comment
+graph [
+  node [
+    id 0
+    label "x"
+    graphics [ x 1.5 y -2 ]
+  ]
+  node [
+    id 1
+    label "y"
+  ]
+  node [
+    id 2
+  ]
+  edge [
+    source 0
+    target 1
+    weight 3.0
+  ]
+  edge [
+    source 1
+    target 2
+  ]
+]
+`
+
+	g, err := ReadGML(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if g.Len() != 3 {
+		t.Errorf("expected 3 vertices, got %d", g.Len())
+	}
+
+	for _, e := range []Edge{{From: "x", To: "y", Weight: 3}, {From: "y", To: "x", Weight: 3}, {From: "y", To: "2", Weight: 1}, {From: "2", To: "y", Weight: 1}} {
+		if ok, weight := g.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+}
+
+func TestReadGMLErrors(t *testing.T) {
+	for _, src := range []string{
+		`graph [ node [ id 0 ] edge [ source 0 target 1 ] ]`,
+		`graph [ node [ id 0 ] node [ id 1 ] edge [ source 0 target 1 weight 1.5 ] ]`,
+		`graph [ node [ id 0 ] node [ id 0 ] ]`,
+		`graph [ node [ label "a" ] ]`,
+		`graph [ node [ id 0 label "a ] ]`,
+		`graph [ node [ id 0 ]`,
+		`nodes [ ]`,
+	} {
+		if _, err := ReadGML(strings.NewReader(src)); err == nil {
+			t.Errorf("%s: expected an error", src)
+		}
+	}
+}
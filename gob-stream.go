@@ -0,0 +1,195 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/gob"
+	"fmt"
+	"io"
+)
+
+// streamVersion is the version of the format written by EncodeTo, see gobVersion. Version 2 added the attributes of edges.
+const streamVersion = 2
+
+// streamHeader starts a stream written by EncodeTo. It is followed by Vertices streamVertex records, then Edges streamEdge records.
+type streamHeader struct {
+	Vertices, Edges int
+	Version         int  // 0 in streams written before versions were introduced, which have the same records
+	SelfLoops       bool // whether the graph allowed self-loops, see EnableSelfLoops
+}
+
+// streamVertex is a vertex record of a stream written by EncodeTo.
+type streamVertex struct {
+	Key   string
+	Value interface{}
+}
+
+// streamEdge is an edge record of a stream written by EncodeTo.
+type streamEdge struct {
+	From, To string
+	Weight   int
+	Attrs    map[string]interface{} // attributes of the edge, see SetEdgeAttr; nil before version 2
+}
+
+// EncodeTo writes the graph to w as a gob stream of records, one per vertex and edge, instead of building the whole encoding in memory like GobEncode, so huge graphs can be serialized without holding their encoding in memory. All vertices are written before the edges, so DecodeFrom can connect edges as they arrive.
+// As with GobEncode, the concrete types of values and edge attributes must be registered with gob.Register.
+// The graph is copied while it is locked for reading, like Snapshot does, and the copy is written afterwards, so writers aren't blocked by a slow w. The copy takes memory proportional to the number of vertices and edges, but values aren't copied.
+func (g *Graph) EncodeTo(w io.Writer) error {
+	defer g.track("EncodeTo")()
+
+	g.RLock()
+	c := g.clone()
+	g.RUnlock()
+
+	bw := bufio.NewWriter(w)
+	enc := gob.NewEncoder(bw)
+
+	// the copy isn't shared, so it is read without locking
+	header := streamHeader{Vertices: len(c.vertices), Version: streamVersion, SelfLoops: c.selfLoops}
+	for _, v := range c.vertices {
+		header.Edges += len(v.outgoingEdges)
+	}
+	if err := enc.Encode(header); err != nil {
+		return err
+	}
+
+	for key, v := range c.vertices {
+		if err := enc.Encode(streamVertex{key, v.value}); err != nil {
+			return err
+		}
+	}
+
+	for key, v := range c.vertices {
+		for neighbor, weight := range v.outgoingEdges {
+			if err := enc.Encode(streamEdge{key, neighbor.key, weight, v.edgeAttrs[neighbor]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// DecodeFrom reads a stream written by EncodeTo from r into the graph's vertices and edges, merging them with existing ones. Every record is applied as soon as it is read, so memory use doesn't depend on the size of the stream.
+// If the encoded graph allowed self-loops, they are enabled, see EnableSelfLoops. Edges whose endpoints don't exist are reported by an *ImportError after reading the whole stream. Returns an error wrapping ErrUnsupportedVersion if the stream was written by a newer version of this package. Unless r is an io.ByteReader, it may be read beyond the end of the stream.
+func (g *Graph) DecodeFrom(r io.Reader) error {
+	defer g.track("DecodeFrom")()
+
+	dec := gob.NewDecoder(r)
+
+	var header streamHeader
+	if err := dec.Decode(&header); err != nil {
+		return fmt.Errorf("graph: decoding gob stream: %v", err)
+	}
+	if header.Version > streamVersion {
+		return fmt.Errorf("graph: decoding gob stream: %w %d", ErrUnsupportedVersion, header.Version)
+	}
+	if header.SelfLoops {
+		g.EnableSelfLoops()
+	}
+
+	for i := 0; i < header.Vertices; i++ {
+		var v streamVertex
+		if err := dec.Decode(&v); err != nil {
+			return fmt.Errorf("graph: decoding gob stream: vertex %d: %v", i+1, err)
+		}
+
+		if _, err := g.TrySet(v.Key, v.Value); err != nil {
+			return fmt.Errorf("graph: decoding gob stream: %v: %q", err, v.Key)
+		}
+	}
+
+	var dangling []DanglingReference
+
+	for i := 0; i < header.Edges; i++ {
+		var e streamEdge
+		if err := dec.Decode(&e); err != nil {
+			return fmt.Errorf("graph: decoding gob stream: edge %d: %v", i+1, err)
+		}
+
+		if !g.Connect(e.From, e.To, e.Weight) {
+			var missing []string
+			for _, key := range []string{e.From, e.To} {
+				if _, err := g.Get(key); err != nil {
+					missing = append(missing, key)
+				}
+			}
+			dangling = append(dangling, DanglingReference{fmt.Sprintf("gob stream edge %d", i+1), e.From, e.To, missing})
+			continue
+		}
+
+		for name, value := range e.Attrs {
+			g.SetEdgeAttr(e.From, e.To, name, value)
+		}
+	}
+
+	if len(dangling) > 0 {
+		return &ImportError{dangling}
+	}
+
+	return nil
+}
+
+// WriteTo writes the graph to w in the stream format of EncodeTo and returns the number of bytes written. With this method, graph implements the io.WriterTo interface, so it can be passed directly to files, network connections and compression writers, e.g. with io.Copy.
+func (g *Graph) WriteTo(w io.Writer) (int64, error) {
+	defer g.track("WriteTo")()
+
+	cw := &countingWriter{w: w}
+	err := g.EncodeTo(cw)
+
+	return cw.n, err
+}
+
+// ReadFrom reads a stream written by WriteTo or EncodeTo from r into the graph, like DecodeFrom, and returns the number of bytes read. With this method, graph implements the io.ReaderFrom interface.
+// Unless r is an io.ByteReader, it may be read beyond the end of the stream.
+func (g *Graph) ReadFrom(r io.Reader) (int64, error) {
+	defer g.track("ReadFrom")()
+
+	cr := &countingReader{r: r}
+	if br, ok := r.(io.ByteReader); ok {
+		// keep r a byte reader, so DecodeFrom doesn't read ahead
+		err := g.DecodeFrom(&countingByteReader{cr, br})
+		return cr.n, err
+	}
+
+	err := g.DecodeFrom(cr)
+
+	return cr.n, err
+}
+
+// countingWriter counts the bytes written to w.
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
+
+// countingReader counts the bytes read from r.
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (cr *countingReader) Read(p []byte) (int, error) {
+	n, err := cr.r.Read(p)
+	cr.n += int64(n)
+	return n, err
+}
+
+// countingByteReader is a countingReader of an io.ByteReader.
+type countingByteReader struct {
+	*countingReader
+	br io.ByteReader
+}
+
+func (cr *countingByteReader) ReadByte() (byte, error) {
+	c, err := cr.br.ReadByte()
+	if err == nil {
+		cr.n++
+	}
+	return c, err
+}
@@ -0,0 +1,174 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"io"
+	"testing"
+	"time"
+)
+
+func TestEncodeToDecodeFrom(t *testing.T) {
+	g := New()
+	g.Set("1", 123)
+	g.Set("2", "abc")
+	g.Set("3", nil)
+
+	g.Connect("1", "2", 5)
+	g.Connect("1", "3", -1)
+	g.Connect("3", "2", 9)
+
+	buf := &bytes.Buffer{}
+	if err := g.EncodeTo(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := g.EncodeTo(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// two streams in a row can be decoded one after the other from a byte reader
+	r := bufio.NewReader(buf)
+	for i := 0; i < 2; i++ {
+		read := New()
+		if err := read.DecodeFrom(r); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if read.Len() != 3 {
+			t.Errorf("expected 3 vertices, got %d", read.Len())
+		}
+		for key, expected := range map[string]interface{}{"1": 123, "2": "abc", "3": nil} {
+			if v, err := read.Get(key); err != nil || v.Value() != expected {
+				t.Errorf("%q: expected value %v", key, expected)
+			}
+		}
+		for _, e := range []Edge{{From: "1", To: "2", Weight: 5}, {From: "1", To: "3", Weight: -1}, {From: "3", To: "2", Weight: 9}} {
+			if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+				t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+			}
+		}
+	}
+}
+
+func TestDecodeFromDanglingEdges(t *testing.T) {
+	buf := &bytes.Buffer{}
+	enc := gob.NewEncoder(buf)
+	enc.Encode(streamHeader{Vertices: 1, Edges: 2})
+	enc.Encode(streamVertex{"a", nil})
+	enc.Encode(streamEdge{From: "a", To: "b", Weight: 1})
+	enc.Encode(streamEdge{From: "a", To: "a", Weight: 1})
+
+	err := New().DecodeFrom(buf)
+	importErr, ok := err.(*ImportError)
+	if !ok || len(importErr.References) != 2 || importErr.References[0].Missing[0] != "b" || len(importErr.References[1].Missing) != 0 {
+		t.Fatalf("expected report of dangling edges, got %v", err)
+	}
+
+	// truncated streams fail
+	buf.Reset()
+	g := New()
+	g.Set("a", 1)
+	g.EncodeTo(buf)
+	if err := New().DecodeFrom(bytes.NewReader(buf.Bytes()[:buf.Len()-3])); err == nil {
+		t.Error("expected an error for a truncated stream")
+	}
+}
+
+func TestDecodeFromVersion(t *testing.T) {
+	buf := &bytes.Buffer{}
+	gob.NewEncoder(buf).Encode(streamHeader{Version: streamVersion + 1})
+
+	if err := New().DecodeFrom(buf); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
+
+func TestWriteToReadFrom(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", "two")
+	g.Connect("a", "b", 3)
+
+	buf := &bytes.Buffer{}
+	n, err := g.WriteTo(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if n != int64(buf.Len()) {
+		t.Errorf("expected %d bytes written, got %d", buf.Len(), n)
+	}
+	length := buf.Len()
+	g.WriteTo(buf)
+
+	// byte readers are read up to the end of the stream only
+	var _ io.WriterTo = g
+	var _ io.ReaderFrom = g
+
+	h := New()
+	r := bufio.NewReader(buf)
+	if m, err := h.ReadFrom(r); err != nil || m != int64(length) {
+		t.Fatalf("expected %d bytes read, got %d %v", length, m, err)
+	}
+	if ok, weight := h.IsConnected("a", "b"); !ok || weight != 3 || h.Len() != 2 {
+		t.Errorf("expected the graph to be read, got %d vertices", h.Len())
+	}
+
+	if m, err := New().ReadFrom(r); err != nil || m != int64(length) {
+		t.Errorf("expected the second graph to be read, got %d %v", m, err)
+	}
+	if _, err := New().ReadFrom(bytes.NewReader(nil)); err == nil {
+		t.Error("expected an error for an empty stream")
+	}
+}
+
+// blockingWriter blocks its first write until released.
+type blockingWriter struct {
+	writing, release chan struct{}
+	bytes.Buffer
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	if w.writing != nil {
+		close(w.writing)
+		w.writing = nil
+		<-w.release
+	}
+	return w.Buffer.Write(p)
+}
+
+func TestEncodeToSlowWriter(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+
+	w := &blockingWriter{writing: make(chan struct{}), release: make(chan struct{})}
+	writing := w.writing
+	done := make(chan error)
+	go func() {
+		done <- g.EncodeTo(w)
+	}()
+
+	// the graph can be changed while the stream is written
+	<-writing
+	set := make(chan struct{})
+	go func() {
+		g.Set("b", 2)
+		close(set)
+	}()
+	select {
+	case <-set:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the graph not to be locked while writing")
+	}
+	close(w.release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the stream holds the graph as it was when writing started
+	h := New()
+	if err := h.DecodeFrom(&w.Buffer); err != nil || h.Len() != 1 {
+		t.Errorf("expected the copy with 1 vertex, got %d %v", h.Len(), err)
+	}
+}
@@ -4,12 +4,26 @@ import (
 	"bytes"
 	"encoding/gob"
 	"errors"
+	"fmt"
 )
 
+// gobMagic starts the data written by GobEncode, followed by a version byte. Its first byte can't start a gob stream, so data written before versions were introduced can be told apart.
+const gobMagic = "\x80gsg"
+
+// gobVersion is the version of the format written by GobEncode. It must be increased whenever graphGob changes in a way older versions can't read, adding a case to decodeGobVersion which migrates the old data.
+const gobVersion = 1
+
+// ErrUnsupportedVersion is returned when decoding data written in a newer version of a format than this one supports.
+var ErrUnsupportedVersion = errors.New("graph: unsupported format version")
+
 type graphGob struct {
-	inv      map[*Vertex]string
-	Vertices map[string]interface{}
-	Edges    map[string]map[string]int
+	inv       map[*Vertex]string
+	Vertices  map[string]interface{}
+	Edges     map[string]map[string]int
+	SelfLoops bool                                         // whether the graph allowed self-loops, see EnableSelfLoops; ignored by older versions of this package
+	EdgeAttrs map[string]map[string]map[string]interface{} // attributes of edges by their endpoints' keys, see SetEdgeAttr; ignored by older versions of this package
+	Labels    map[string][]string                          // labels of the vertices having any by their keys, see SetLabels; ignored by older versions of this package
+	Tags      map[string][]string                          // tags of the vertices having any by their keys, see Tag; ignored by older versions of this package
 }
 
 // add a key - vertex pair to the graphGob
@@ -24,10 +38,20 @@ func (g graphGob) add(v *Vertex) {
 		// save the edge connection to the neighbor into the edges map
 		g.Edges[v.key][neighbor.key] = weight
 	}
+
+	// save the attributes of the edges having any
+	for neighbor, attrs := range v.edgeAttrs {
+		if g.EdgeAttrs[v.key] == nil {
+			g.EdgeAttrs[v.key] = map[string]map[string]interface{}{}
+		}
+		g.EdgeAttrs[v.key][neighbor.key] = attrs
+	}
 }
 
-// GobEncode encodes the graph into a []byte. With this method, graph implements the gob.GobEncoder interface.
+// GobEncode encodes the graph into a []byte, starting with a header holding the version of the format, so data written by older versions of this package can still be decoded. With this method, graph implements the gob.GobEncoder interface.
 func (g *Graph) GobEncode() ([]byte, error) {
+	defer g.track("GobEncode")()
+
 	// build inverted map
 	inv := map[*Vertex]string{}
 	for key, v := range g.vertices {
@@ -36,46 +60,98 @@ func (g *Graph) GobEncode() ([]byte, error) {
 		}
 	}
 
-	gGob := graphGob{inv, map[string]interface{}{}, map[string]map[string]int{}}
+	gGob := graphGob{inv, map[string]interface{}{}, map[string]map[string]int{}, g.selfLoops, map[string]map[string]map[string]interface{}{}, map[string][]string{}, map[string][]string{}}
 
-	// add vertices and edges to gGob
+	// add vertices, edges, labels and tags to gGob
 	for _, v := range g.vertices {
 		gGob.add(v)
+		if labels := g.labels.sorted(v); labels != nil {
+			gGob.Labels[v.key] = labels
+		}
+		if tags := g.tags.sorted(v); tags != nil {
+			gGob.Tags[v.key] = tags
+		}
 	}
 
-	// encode gGob
-	buf := &bytes.Buffer{}
+	// encode gGob after the header
+	buf := bytes.NewBufferString(gobMagic)
+	buf.WriteByte(gobVersion)
 	enc := gob.NewEncoder(buf)
 	err := enc.Encode(gGob)
 
 	return buf.Bytes(), err
 }
 
-// GobDecode eecodes a []byte into the graph's vertices and edges. With this method, graph implements the gob.GobDecoder interface.
+// GobDecode decodes a []byte written by GobEncode in the current or any earlier version of the format into the graph's vertices, edges, labels and tags. With this method, graph implements the gob.GobDecoder interface.
+// If the encoded graph allowed self-loops, they are enabled, see EnableSelfLoops. Returns an error wrapping ErrUnsupportedVersion if the data was written by a newer version.
 func (g *Graph) GobDecode(b []byte) (err error) {
-	// decode into graphGob
-	gGob := &graphGob{}
-	buf := bytes.NewBuffer(b)
-	dec := gob.NewDecoder(buf)
+	defer g.track("GobDecode")()
+
+	// data written before the header was introduced is version 0
+	version := 0
+	if bytes.HasPrefix(b, []byte(gobMagic)) && len(b) > len(gobMagic) {
+		version = int(b[len(gobMagic)])
+		b = b[len(gobMagic)+1:]
+	}
 
-	err = dec.Decode(gGob)
+	gGob, err := decodeGobVersion(version, b)
 	if err != nil {
-		return
+		return err
 	}
 
+	if gGob.SelfLoops {
+		g.EnableSelfLoops()
+	}
+
+	im := g.NewImporter()
+
 	// set the vertices
 	for key, value := range gGob.Vertices {
-		g.Set(key, value)
+		if _, err := im.TrySet(key, value); err != nil {
+			return fmt.Errorf("graph: decoding gob: %v: %q", err, key)
+		}
 	}
 
-	// connect the vertices
+	// connect the vertices, reporting all edges with invalid endpoints
 	for key, neighbors := range gGob.Edges {
 		for otherKey, weight := range neighbors {
-			if ok := g.Connect(key, otherKey, weight); !ok {
-				return errors.New("invalid edge endpoints")
+			im.connect("gob", key, otherKey, weight)
+		}
+	}
+	err = im.Finalize()
+
+	// set the attributes of the edges connected
+	for key, neighbors := range gGob.EdgeAttrs {
+		for otherKey, attrs := range neighbors {
+			for name, value := range attrs {
+				g.SetEdgeAttr(key, otherKey, name, value)
 			}
 		}
 	}
 
-	return
+	for key, labels := range gGob.Labels {
+		g.SetLabels(key, labels...)
+	}
+	for key, tags := range gGob.Tags {
+		g.Tag(key, tags...)
+	}
+
+	return err
+}
+
+// decodeGobVersion decodes the gob data b written in the given version of the format, migrating it to the current one.
+func decodeGobVersion(version int, b []byte) (*graphGob, error) {
+	gGob := &graphGob{}
+
+	switch version {
+	case 0, 1:
+		// version 1 only added the header
+		if err := gob.NewDecoder(bytes.NewReader(b)).Decode(gGob); err != nil {
+			return nil, fmt.Errorf("graph: decoding gob: %v", err)
+		}
+	default:
+		return nil, fmt.Errorf("graph: decoding gob: %w %d", ErrUnsupportedVersion, version)
+	}
+
+	return gGob, nil
 }
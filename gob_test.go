@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"testing"
+)
+
+func TestGobVersions(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", "x")
+	g.Connect("a", "b", 3)
+
+	b, err := g.GobEncode()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if !bytes.HasPrefix(b, []byte(gobMagic+string(rune(gobVersion)))) {
+		t.Errorf("expected a version header, got % x", b[:8])
+	}
+
+	// data written before the header was introduced
+	legacy := &bytes.Buffer{}
+	err = gob.NewEncoder(legacy).Encode(graphGob{
+		Vertices: map[string]interface{}{"a": 1, "b": "x"},
+		Edges:    map[string]map[string]int{"a": {"b": 3}, "b": {}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, data := range [][]byte{b, legacy.Bytes()} {
+		read := New()
+		if err := read.GobDecode(data); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+
+		if v, err := read.Get("a"); err != nil || v.Value() != 1 {
+			t.Error("expected vertex a with value 1")
+		}
+		if ok, weight := read.IsConnected("a", "b"); !ok || weight != 3 {
+			t.Errorf("expected a → b with weight 3, got %v %d", ok, weight)
+		}
+	}
+
+	newer := append([]byte(gobMagic), gobVersion+1)
+	if err := New().GobDecode(append(newer, b[len(gobMagic)+1:]...)); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
@@ -5,17 +5,27 @@ package graph
 import (
 	"errors"
 	"sync"
+	"sync/atomic"
 )
 
+// ErrInvalidKey is returned when there is no vertex with the requested key.
+var ErrInvalidKey = errors.New("graph: invalid key")
+
 // Vertex reprsents a vertex in a graph
 type Vertex struct {
 	key           string
-	value         interface{}     // the stored value
-	incomingEdges map[*Vertex]int // maps the incoming edge to its weight
-	outgoingEdges map[*Vertex]int // maps the outgoing edge to its weight
+	value         interface{}                        // the stored value
+	incomingEdges map[*Vertex]int                    // maps the incoming edge to its weight
+	outgoingEdges map[*Vertex]int                    // maps the outgoing edge to its weight
+	edgeAttrs     map[*Vertex]map[string]interface{} // maps outgoing edges to their attributes, see SetEdgeAttr; nil if none has any
 	sync.RWMutex
 }
 
+// newVertex returns a vertex with the specified key and value and no edges.
+func newVertex(key string, value interface{}) *Vertex {
+	return &Vertex{key: key, value: value, incomingEdges: map[*Vertex]int{}, outgoingEdges: map[*Vertex]int{}}
+}
+
 // GetIncoming returns the map of incoming edges and their weights.
 func (v *Vertex) GetIncoming() map[*Vertex]int {
 	if v == nil {
@@ -70,63 +80,124 @@ func (v *Vertex) Value() interface{} {
 
 // Graph reprsents a structure containing multiple interconnected vertices
 type Graph struct {
-	vertices map[string]*Vertex // A map of all the vertices in this graph, indexed by their key.
+	vertices       map[string]*Vertex    // A map of all the vertices in this graph, indexed by their key.
+	subscribers    map[int]func(Event)   // Functions called on every mutation, indexed by subscription ID.
+	nextSubscriber int                   // ID of the next subscription.
+	pathCache      *pathCache            // Cache of shortest paths, nil if disabled.
+	tags           stringIndex           // Index of the vertices' tags, see Tag.
+	labels         stringIndex           // Index of the vertices' labels, see SetLabels.
+	instrumenter   atomic.Value          // Holds the instrumenterHolder to report operations to.
+	unique         *uniqueIndex          // Index of values which must be unique, nil if disabled.
+	keys           keyIndex              // Sorted vertex keys, rebuilt lazily.
+	protected      map[*Vertex]struct{}  // Vertices which must not be deleted, see Protect.
+	hierarchy      *contractionHierarchy // Built by BuildContractionHierarchy, nil if there is none.
+	snapshots      map[string]*Graph     // Snapshots stored in memory by TagSnapshot, indexed by name.
+	snapshotDir    string                // Directory TagSnapshot writes snapshots to, empty to keep them in memory.
+	store          *storeBinding         // Store mutations are written through to, nil if there is none, see NewWithStore.
+	versions       []committedVersion    // Versions stored by Commit, oldest first.
+	lastVersion    int                   // Number of the last version stored by Commit.
+	snapshotting   sync.Mutex            // Serializes TagSnapshot and Commit, see snapshot.
+	selfLoops      bool                  // Whether edges from a vertex to itself are allowed, see EnableSelfLoops.
+	edgeMutations  sync.Mutex            // Serializes edge mutations made under the read lock with their events, so events are emitted in the order of the mutations.
 	sync.RWMutex
 }
 
 // New initializes a new graph.
 func New() *Graph {
-	return &Graph{map[string]*Vertex{}, sync.RWMutex{}}
+	return &Graph{vertices: map[string]*Vertex{}}
 }
 
 // Len returns the number of vertices contained in the graph.
 func (g *Graph) Len() int {
+	defer g.track("Len")()
+
 	return len(g.vertices)
 }
 
 // Set creates a new vertex and stores the given value if there is no vertex with the specified key yet.
 // Otherwise, it updates the value, but leaves all connections intact.
+// If a unique index is enabled and another vertex holds the value already, nothing is changed; use TrySet to find out, see EnableUniqueIndex.
 func (g *Graph) Set(key string, value interface{}) {
+	defer g.track("Set")()
+
 	// lock graph until this method is finished to prevent changes made by other goroutines
 	g.Lock()
 	defer g.Unlock()
 
+	g.set(key, value)
+}
+
+// TrySet creates or updates the vertex with the specified key like Set, and reports the outcome: it returns true if a new vertex was created, and ErrDuplicateValue if the value was rejected because another vertex holds it already and a unique index is enabled, see EnableUniqueIndex.
+func (g *Graph) TrySet(key string, value interface{}) (bool, error) {
+	defer g.track("TrySet")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	return g.set(key, value)
+}
+
+// set is an internal function, does NOT lock the graph, should only be used in between Lock() and Unlock().
+// It creates or updates the vertex, and returns true if it was created.
+func (g *Graph) set(key string, value interface{}) (bool, error) {
+	if !g.unique.set(key, value) {
+		return false, ErrDuplicateValue
+	}
+
 	v := g.get(key)
 
 	// if no such node exists
 	if v == nil {
 		// create a new one
-		v = &Vertex{key, value, map[*Vertex]int{}, map[*Vertex]int{}, sync.RWMutex{}}
+		v = newVertex(key, value)
 
 		// and add it to the graph
 		g.vertices[key] = v
+		g.keys.insert(key)
 
-		return
+		g.emit(Event{Type: EventSet, Key: key, Value: value})
+
+		return true, nil
 	}
 
 	// else, just update the value
 	v.Lock()
 	v.value = value
 	v.Unlock()
+
+	g.emit(Event{Type: EventSet, Key: key, Value: value})
+
+	return false, nil
 }
 
-// Delete the vertex with the specified key. Return false if key is invalid.
+// Delete the vertex with the specified key. Return false if key is invalid or the vertex is protected, see Protect.
 func (g *Graph) Delete(key string) bool {
+	defer g.track("Delete")()
+
 	// lock graph until this method is finished to prevent changes made by other goroutines while this one is looping etc.
 	g.Lock()
 	defer g.Unlock()
 
 	// get vertex in question
 	v := g.get(key)
-	if v == nil {
+	if v == nil || g.isProtected(v) {
 		return false
 	}
 
+	g.remove(v)
+
+	return true
+}
+
+// remove is an internal function, does NOT lock the graph, should only be used in between Lock() and Unlock().
+// It deletes v and all its edges.
+func (g *Graph) remove(v *Vertex) {
 	// iterate over incomingEdges, remove edges from vertices
 	for neighbor := range v.incomingEdges {
 		// delete edge to the to-be-deleted vertex
 		neighbor.Lock()
 		delete(neighbor.outgoingEdges, v)
+		delete(neighbor.edgeAttrs, v)
 		neighbor.Unlock()
 	}
 
@@ -138,14 +209,24 @@ func (g *Graph) Delete(key string) bool {
 		neighbor.Unlock()
 	}
 
+	// remove the vertex' tags and labels from the indexes
+	g.tags.removeAll(v)
+	g.labels.removeAll(v)
+
+	g.unique.remove(v.key)
+	delete(g.protected, v)
+
 	// delete vertex
-	delete(g.vertices, key)
+	delete(g.vertices, v.key)
+	g.keys.remove(v.key)
 
-	return true
+	g.emit(Event{Type: EventDelete, Key: v.key})
 }
 
 // GetAll returns a slice containing all vertices. The slice is empty if the graph contains no nodes.
 func (g *Graph) GetAll() (all []*Vertex) {
+	defer g.track("GetAll")()
+
 	g.RLock()
 	for _, v := range g.vertices {
 		all = append(all, v)
@@ -157,12 +238,14 @@ func (g *Graph) GetAll() (all []*Vertex) {
 
 // Get returns the vertex with this key, or nil and an error if there is no vertex with this key.
 func (g *Graph) Get(key string) (v *Vertex, err error) {
+	defer g.track("Get")()
+
 	g.RLock()
 	v = g.get(key)
 	g.RUnlock()
 
 	if v == nil {
-		err = errors.New("graph: invalid key")
+		err = ErrInvalidKey
 	}
 
 	return
@@ -173,18 +256,70 @@ func (g *Graph) get(key string) *Vertex {
 	return g.vertices[key]
 }
 
-// Connect creates a directed edge between the vertices specified by fromKey and toKey. Returns false if one or both of the keys are invalid or if they are the same.
+// clone is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns a new graph with the same vertices and edges. Values are copied shallowly.
+func (g *Graph) clone() *Graph {
+	return g.subgraph(func(*Vertex) bool { return true })
+}
+
+// subgraph is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns a copy of the vertices for which keep returns true with their tags and labels, and the edges between them, including their attributes. Values and attributes are copied shallowly.
+func (g *Graph) subgraph(keep func(v *Vertex) bool) *Graph {
+	c := New()
+
+	for key, v := range g.vertices {
+		if keep(v) {
+			c.vertices[key] = newVertex(key, v.Value())
+			for tag := range g.tags.strings[v] {
+				c.tags.add(c.vertices[key], tag)
+			}
+			for label := range g.labels.strings[v] {
+				c.labels.add(c.vertices[key], label)
+			}
+		}
+	}
+
+	// the copy may contain self-loops, so it must allow them
+	c.selfLoops = g.selfLoops
+
+	for key, v := range g.vertices {
+		if c.vertices[key] == nil {
+			continue
+		}
+
+		for neighbor, weight := range v.GetOutgoing() {
+			if c.vertices[neighbor.key] == nil {
+				continue
+			}
+
+			c.vertices[key].outgoingEdges[c.vertices[neighbor.key]] = weight
+			c.vertices[neighbor.key].incomingEdges[c.vertices[key]] = weight
+
+			v.RLock()
+			for name, value := range v.edgeAttrs[neighbor] {
+				c.vertices[key].setEdgeAttr(c.vertices[neighbor.key], name, value)
+			}
+			v.RUnlock()
+		}
+	}
+
+	return c
+}
+
+// Connect creates a directed edge between the vertices specified by fromKey and toKey. Returns false if one or both of the keys are invalid or if they are the same, unless self-loops are enabled, see EnableSelfLoops.
 // If there already is a connection, it is overwritten with the new edge weight.
 func (g *Graph) Connect(fromKey string, toKey string, weight int) bool {
-	// recursive edges are forbidden
-	if fromKey == toKey {
-		return false
-	}
+	defer g.track("Connect")()
 
 	// lock graph for reading until this method is finished to prevent changes made by other goroutines while this one is running
 	g.RLock()
 	defer g.RUnlock()
 
+	// recursive edges are forbidden by default
+	if fromKey == toKey && !g.selfLoops {
+		return false
+	}
+
 	// get vertices and check for validity of keys
 	fromV := g.get(fromKey)
 	toV := g.get(toKey)
@@ -193,31 +328,49 @@ func (g *Graph) Connect(fromKey string, toKey string, weight int) bool {
 		return false
 	}
 
-	// add connection to both vertices
+	g.connect(fromV, toV, weight)
+
+	// success
+	return true
+}
+
+// connect is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It creates or updates the edge from fromV to toV.
+func (g *Graph) connect(fromV, toV *Vertex, weight int) {
+	// concurrent edge mutations must emit their events in the order they are applied
+	g.edgeMutations.Lock()
+	defer g.edgeMutations.Unlock()
+
+	// add connection to both vertices, locking a self-loop's vertex only once
 	fromV.Lock()
-	toV.Lock()
+	if toV != fromV {
+		toV.Lock()
+	}
 
 	fromV.outgoingEdges[toV] = weight
 	toV.incomingEdges[fromV] = weight
 
 	fromV.Unlock()
-	toV.Unlock()
+	if toV != fromV {
+		toV.Unlock()
+	}
 
-	// success
-	return true
+	g.emit(Event{Type: EventConnect, Key: fromV.key, ToKey: toV.key, Weight: weight})
 }
 
-// Disconnect removes an edge connecting the two vertices. Returns false if one or both of the keys are invalid or if they are the same.
+// Disconnect removes an edge connecting the two vertices. Returns false if one or both of the keys are invalid or if they are the same, unless self-loops are enabled, see EnableSelfLoops.
 func (g *Graph) Disconnect(fromKey string, toKey string) bool {
-	// recursive edges are forbidden
-	if fromKey == toKey {
-		return false
-	}
+	defer g.track("Disconnect")()
 
 	// lock graph for reading until this method is finished to prevent changes made by other goroutines while this one is running
 	g.RLock()
 	defer g.RUnlock()
 
+	// recursive edges are forbidden by default
+	if fromKey == toKey && !g.selfLoops {
+		return false
+	}
+
 	// get vertices and check for validity of keys
 	fromV := g.get(fromKey)
 	toV := g.get(toKey)
@@ -226,29 +379,49 @@ func (g *Graph) Disconnect(fromKey string, toKey string) bool {
 		return false
 	}
 
-	// delete the edge from both vertices
+	g.disconnect(fromV, toV)
+
+	return true
+}
+
+// disconnect is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It deletes the edge from fromV to toV.
+func (g *Graph) disconnect(fromV, toV *Vertex) {
+	// concurrent edge mutations must emit their events in the order they are applied
+	g.edgeMutations.Lock()
+	defer g.edgeMutations.Unlock()
+
+	// delete the edge from both vertices, locking a self-loop's vertex only once
 	fromV.Lock()
-	toV.Lock()
+	if toV != fromV {
+		toV.Lock()
+	}
 
 	delete(fromV.outgoingEdges, toV)
 	delete(toV.incomingEdges, fromV)
+	delete(fromV.edgeAttrs, toV)
 
 	fromV.Unlock()
-	toV.Unlock()
+	if toV != fromV {
+		toV.Unlock()
+	}
 
-	return true
+	g.emit(Event{Type: EventDisconnect, Key: fromV.key, ToKey: toV.key})
 }
 
 // IsConnected returns true and the edge weight if there is an edge from fromKey to toKey.
-// Returns false if one or both keys are invalid, if they are the same, or if there is no edge connecting them.
+// Returns false if one or both keys are invalid, if they are the same and self-loops are disabled, or if there is no edge connecting them.
 func (g *Graph) IsConnected(fromKey string, toKey string) (exists bool, weight int) {
+	defer g.track("IsConnected")()
+
+	g.RLock()
+
 	// sanity check
-	if fromKey == toKey {
+	if fromKey == toKey && !g.selfLoops {
+		g.RUnlock()
 		return
 	}
 
-	g.RLock()
-
 	fromV := g.get(fromKey)
 	if fromV == nil {
 		g.RUnlock()
@@ -265,8 +438,10 @@ func (g *Graph) IsConnected(fromKey string, toKey string) (exists bool, weight i
 
 	fromV.RLock()
 	defer fromV.RUnlock()
-	toV.RLock()
-	defer toV.RUnlock()
+	if toV != fromV {
+		toV.RLock()
+		defer toV.RUnlock()
+	}
 
 	// choose vertex with less edges (easier to find 1 in 10 than to find 1 in 100)
 	if len(fromV.outgoingEdges) < len(toV.incomingEdges) {
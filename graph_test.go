@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"encoding/gob"
 	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -242,3 +243,56 @@ func printVertices(vSlice map[string]*Vertex) {
 		}
 	}
 }
+
+func TestExportReachable(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, key+key)
+	}
+
+	// a → b → c, d → a, e unconnected
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("d", "a", 3)
+
+	sub, err := g.ExportReachable([]string{"b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sub.Len() != 2 {
+		t.Fatalf("expected 2 vertices, got %d", sub.Len())
+	}
+
+	// the subgraph can be written in any format, e.g. merged into a graph with other contents
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(sub); err != nil {
+		t.Fatal(err)
+	}
+	newG := New()
+	newG.Set("x", nil)
+	if err := gob.NewDecoder(buf).Decode(newG); err != nil {
+		t.Fatal(err)
+	}
+
+	if newG.Len() != 3 {
+		t.Fatalf("expected 3 vertices, got %d", newG.Len())
+	}
+	if v, err := newG.Get("c"); err != nil || v.Value() != "cc" {
+		t.Fail()
+	}
+	if ok, weight := newG.IsConnected("b", "c"); !ok || weight != 2 {
+		t.Fail()
+	}
+	if _, err := newG.Get("a"); err == nil {
+		t.Fail()
+	}
+
+	b, err := sub.MarshalJSON()
+	if err != nil || !strings.Contains(string(b), `"from":"b","to":"c"`) {
+		t.Errorf("unexpected JSON %s, %v", b, err)
+	}
+
+	if _, err := g.ExportReachable([]string{"a", "z"}); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
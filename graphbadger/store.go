@@ -0,0 +1,500 @@
+// Package graphbadger persists graphs in a Badger database, an LSM tree tuned for high write throughput, e.g. to ingest streams of edges. Use it with graph.NewWithStore:
+//
+//	s, err := graphbadger.Open("graph")
+//	...
+//	defer s.Close()
+//	g, err := graph.NewWithStore(s)
+//
+// Writes are collected in batches, which are committed when they are full, before reads, and by Flush and Close.
+package graphbadger
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"sort"
+	"sync"
+
+	badger "github.com/dgraph-io/badger/v4"
+	graph "github.com/samuelhug/graph-store"
+)
+
+// BatchSize is the number of writes collected before a batch is committed.
+const BatchSize = 4096
+
+// Prefixes of the keys in the database. Vertex values are stored gob encoded under vertexPrefix and the key. Every edge is stored twice, under outgoingPrefix and the keys of its source and target, and under incomingPrefix and the keys of its target and source, with the weight as value, so the neighbors of a vertex in both directions are found by a prefix scan.
+// The attributes of an edge are stored together as a gob encoded map under attrsPrefix and the keys of its source and target, so disconnecting an edge deletes them without a scan.
+const (
+	vertexPrefix   = 'v'
+	outgoingPrefix = 'o'
+	incomingPrefix = 'i'
+	attrsPrefix    = 'a'
+)
+
+// Store is a graph.EdgeAttrStore keeping vertices, edges and their attributes in a Badger database.
+// Writes are batched, so they are only durable after the batch is committed, see Flush. Connect doesn't check if the vertices exist, since the graph writing through to the store does.
+// Values are gob encoded, so their concrete types must be registered with gob.Register, except for the basic types.
+type Store struct {
+	db      *badger.DB
+	batch   *badger.WriteBatch // writes which are not committed yet, nil if there are none
+	pending int                // number of writes in batch
+	sync.Mutex
+}
+
+// Open opens the Badger database in the directory dir, creating it if it doesn't exist.
+func Open(dir string) (*Store, error) {
+	db, err := badger.Open(badger.DefaultOptions(dir).WithLoggingLevel(badger.WARNING))
+	if err != nil {
+		return nil, err
+	}
+
+	return New(db), nil
+}
+
+// New initializes a store using db.
+func New(db *badger.DB) *Store {
+	return &Store{db: db}
+}
+
+// Flush commits all pending writes.
+func (s *Store) Flush() error {
+	s.Lock()
+	defer s.Unlock()
+
+	return s.flush()
+}
+
+// flush is an internal function, does NOT lock the store, should only be used in between Lock() and Unlock().
+func (s *Store) flush() error {
+	if s.batch == nil {
+		return nil
+	}
+
+	err := s.batch.Flush()
+	s.batch = nil
+	s.pending = 0
+
+	return err
+}
+
+// write is an internal function, does NOT lock the store, should only be used in between Lock() and Unlock().
+// It adds the key and value to the batch, or a deletion of the key if value is nil, and commits the batch if it is full.
+// A write rejected by the batch, e.g. because the key is too large, only fails itself: the batch is kept, so the writes added before are still committed.
+func (s *Store) write(key, value []byte) error {
+	if s.batch == nil {
+		s.batch = s.db.NewWriteBatch()
+	}
+
+	var err error
+	if value == nil {
+		err = s.batch.Delete(key)
+	} else {
+		err = s.batch.Set(key, value)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.pending++
+	if s.pending >= BatchSize {
+		return s.flush()
+	}
+
+	return nil
+}
+
+// Close commits all pending writes and closes the database.
+func (s *Store) Close() error {
+	s.Lock()
+	defer s.Unlock()
+
+	err := s.flush()
+	if closeErr := s.db.Close(); err == nil {
+		err = closeErr
+	}
+
+	return err
+}
+
+// Get returns the value of the vertex with the specified key, and false if there is no such vertex.
+func (s *Store) Get(key string) (value interface{}, ok bool, err error) {
+	if err = s.Flush(); err != nil {
+		return
+	}
+
+	err = s.db.View(func(txn *badger.Txn) error {
+		item, err := txn.Get(vertexKey(key))
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		ok = true
+		return item.Value(func(b []byte) error {
+			value, err = decodeValue(key, b)
+			return err
+		})
+	})
+
+	return
+}
+
+// Set creates or updates the vertex with the specified key.
+func (s *Store) Set(key string, value interface{}) error {
+	b, err := encodeValue(key, value)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	return s.write(vertexKey(key), b)
+}
+
+// Delete deletes the vertex with the specified key and all its edges.
+func (s *Store) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	// collect the keys of the edges in both directions
+	var keys [][]byte
+	for _, prefix := range []byte{outgoingPrefix, incomingPrefix} {
+		reverse := byte(incomingPrefix)
+		if prefix == incomingPrefix {
+			reverse = outgoingPrefix
+		}
+
+		err := s.scan(prefix, key, func(neighbor string, _ int) error {
+			keys = append(keys, edgeKey(prefix, key, neighbor), edgeKey(reverse, neighbor, key))
+			if prefix == outgoingPrefix {
+				keys = append(keys, edgeKey(attrsPrefix, key, neighbor))
+			} else {
+				keys = append(keys, edgeKey(attrsPrefix, neighbor, key))
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, k := range append(keys, vertexKey(key)) {
+		if err := s.write(k, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Connect creates or updates an edge.
+func (s *Store) Connect(fromKey, toKey string, weight int) error {
+	w := make([]byte, 8)
+	binary.BigEndian.PutUint64(w, uint64(int64(weight)))
+
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.write(edgeKey(outgoingPrefix, fromKey, toKey), w); err != nil {
+		return err
+	}
+	return s.write(edgeKey(incomingPrefix, toKey, fromKey), w)
+}
+
+// Disconnect deletes an edge and its attributes.
+func (s *Store) Disconnect(fromKey, toKey string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	for _, k := range [][]byte{edgeKey(outgoingPrefix, fromKey, toKey), edgeKey(incomingPrefix, toKey, fromKey), edgeKey(attrsPrefix, fromKey, toKey)} {
+		if err := s.write(k, nil); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// SetEdgeAttr creates or updates an attribute of an edge. Returns graph.ErrNoEdge if the edge doesn't exist.
+// Since the attributes of an edge are stored together, it commits the pending writes to read them.
+func (s *Store) SetEdgeAttr(fromKey, toKey, name string, value interface{}) error {
+	return s.updateEdgeAttrs(fromKey, toKey, true, func(attrs map[string]interface{}) {
+		attrs[name] = value
+	})
+}
+
+// DeleteEdgeAttr deletes an attribute of an edge. Like SetEdgeAttr, it commits the pending writes.
+func (s *Store) DeleteEdgeAttr(fromKey, toKey, name string) error {
+	return s.updateEdgeAttrs(fromKey, toKey, false, func(attrs map[string]interface{}) {
+		delete(attrs, name)
+	})
+}
+
+// updateEdgeAttrs reads the attributes of the edge from fromKey to toKey, modifies them with fn and writes them back, deleting them if none are left. If mustExist is true, it returns graph.ErrNoEdge if there is no such edge.
+func (s *Store) updateEdgeAttrs(fromKey, toKey string, mustExist bool, fn func(attrs map[string]interface{})) error {
+	k := edgeKey(attrsPrefix, fromKey, toKey)
+	what := fmt.Sprintf("attributes of edge from %q to %q", fromKey, toKey)
+
+	s.Lock()
+	defer s.Unlock()
+
+	if err := s.flush(); err != nil {
+		return err
+	}
+
+	attrs := map[string]interface{}{}
+	err := s.db.View(func(txn *badger.Txn) error {
+		if mustExist {
+			if _, err := txn.Get(edgeKey(outgoingPrefix, fromKey, toKey)); err == badger.ErrKeyNotFound {
+				return graph.ErrNoEdge
+			} else if err != nil {
+				return err
+			}
+		}
+
+		item, err := txn.Get(k)
+		if err == badger.ErrKeyNotFound {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		return item.Value(func(b []byte) error {
+			attrs, err = decodeAttrs(what, b)
+			return err
+		})
+	})
+	if err != nil {
+		return err
+	}
+	if attrs == nil {
+		attrs = map[string]interface{}{}
+	}
+
+	fn(attrs)
+	if len(attrs) == 0 {
+		return s.write(k, nil)
+	}
+
+	b, err := encodeAttrs(what, attrs)
+	if err != nil {
+		return err
+	}
+	return s.write(k, b)
+}
+
+// Outgoing calls fn for every outgoing edge of the vertex with the specified key, stopping at the first error, which it returns. The edges are read by a prefix scan, without loading the graph.
+func (s *Store) Outgoing(key string, fn func(toKey string, weight int) error) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	return s.scan(outgoingPrefix, key, fn)
+}
+
+// Incoming calls fn for every incoming edge of the vertex with the specified key, stopping at the first error, which it returns. The edges are read by a prefix scan, without loading the graph.
+func (s *Store) Incoming(key string, fn func(fromKey string, weight int) error) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	return s.scan(incomingPrefix, key, fn)
+}
+
+// scan calls fn for every edge of key stored under prefix, with the key of the neighbor.
+func (s *Store) scan(prefix byte, key string, fn func(neighbor string, weight int) error) error {
+	start := edgeKey(prefix, key, "")
+
+	return s.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, start, func(k, w []byte) error {
+			weight, err := decodeWeight(k, w)
+			if err != nil {
+				return err
+			}
+			return fn(string(k[len(start):]), weight)
+		})
+	})
+}
+
+// IterVertices calls fn for every vertex in key order, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterVertices(fn func(key string, value interface{}) error) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	return s.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, []byte{vertexPrefix}, func(k, b []byte) error {
+			key := string(k[1:])
+			value, err := decodeValue(key, b)
+			if err != nil {
+				return err
+			}
+			return fn(key, value)
+		})
+	})
+}
+
+// IterEdges calls fn for every edge, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdges(fn func(fromKey, toKey string, weight int) error) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	return s.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, []byte{outgoingPrefix}, func(k, w []byte) error {
+			from, to, err := splitEdgeKey(k)
+			if err != nil {
+				return err
+			}
+
+			weight, err := decodeWeight(k, w)
+			if err != nil {
+				return err
+			}
+			return fn(from, to, weight)
+		})
+	})
+}
+
+// IterEdgeAttrs calls fn for every attribute of every edge, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdgeAttrs(fn func(fromKey, toKey, name string, value interface{}) error) error {
+	if err := s.Flush(); err != nil {
+		return err
+	}
+
+	return s.db.View(func(txn *badger.Txn) error {
+		return iterate(txn, []byte{attrsPrefix}, func(k, b []byte) error {
+			from, to, err := splitEdgeKey(k)
+			if err != nil {
+				return err
+			}
+
+			attrs, err := decodeAttrs(fmt.Sprintf("attributes of edge from %q to %q", from, to), b)
+			if err != nil {
+				return err
+			}
+
+			names := make([]string, 0, len(attrs))
+			for name := range attrs {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			for _, name := range names {
+				if err := fn(from, to, name, attrs[name]); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+	})
+}
+
+// iterate calls fn with every key starting with prefix and its value, in key order. The slices are only valid until fn returns.
+func iterate(txn *badger.Txn, prefix []byte, fn func(k, v []byte) error) error {
+	opts := badger.DefaultIteratorOptions
+	opts.Prefix = prefix
+
+	it := txn.NewIterator(opts)
+	defer it.Close()
+
+	for it.Seek(prefix); it.ValidForPrefix(prefix); it.Next() {
+		item := it.Item()
+		k := item.Key()
+		err := item.Value(func(v []byte) error {
+			return fn(k, v)
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// vertexKey returns the database key of the value of the vertex with the specified key.
+func vertexKey(key string) []byte {
+	return append([]byte{vertexPrefix}, key...)
+}
+
+// edgeKey returns the database key of the edge between key and neighbor stored under prefix. The key is length prefixed, so the edges of a vertex can't be confused with those of a vertex whose key starts with the same characters.
+func edgeKey(prefix byte, key, neighbor string) []byte {
+	b := make([]byte, 1+binary.MaxVarintLen64, 1+binary.MaxVarintLen64+len(key)+len(neighbor))
+	b[0] = prefix
+	b = b[:1+binary.PutUvarint(b[1:], uint64(len(key)))]
+	b = append(b, key...)
+	return append(b, neighbor...)
+}
+
+// splitEdgeKey returns the keys of the source and target of the edge stored under the database key k, see edgeKey.
+func splitEdgeKey(k []byte) (from, to string, err error) {
+	length, size := binary.Uvarint(k[1:])
+	if size <= 0 || length > uint64(len(k)-1-size) {
+		return "", "", fmt.Errorf("graphbadger: corrupt database: invalid edge key %q", k)
+	}
+
+	return string(k[1+size : 1+size+int(length)]), string(k[1+size+int(length):]), nil
+}
+
+// decodeWeight decodes the weight of the edge stored under the database key k.
+func decodeWeight(k, w []byte) (int, error) {
+	if len(w) != 8 {
+		return 0, fmt.Errorf("graphbadger: corrupt database: invalid weight of edge %q", k)
+	}
+	return int(int64(binary.BigEndian.Uint64(w))), nil
+}
+
+// storedValue wraps values, so gob encodes their concrete type.
+type storedValue struct {
+	Value interface{}
+}
+
+// encodeValue encodes the value of the vertex with the specified key.
+func encodeValue(key string, value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(storedValue{value}); err != nil {
+		return nil, fmt.Errorf("graphbadger: encoding value of %q: %v", key, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeValue decodes the value of the vertex with the specified key.
+func decodeValue(key string, b []byte) (interface{}, error) {
+	var v storedValue
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		return nil, fmt.Errorf("graphbadger: decoding value of %q: %v", key, err)
+	}
+	return v.Value, nil
+}
+
+// storedAttrs wraps the attributes of an edge, so gob encodes the concrete types of their values.
+type storedAttrs struct {
+	Attrs map[string]interface{}
+}
+
+// encodeAttrs encodes the attributes of an edge; what describes them in errors.
+func encodeAttrs(what string, attrs map[string]interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(storedAttrs{attrs}); err != nil {
+		return nil, fmt.Errorf("graphbadger: encoding %s: %v", what, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeAttrs decodes attributes encoded by encodeAttrs; what describes them in errors.
+func decodeAttrs(what string, b []byte) (map[string]interface{}, error) {
+	var a storedAttrs
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&a); err != nil {
+		return nil, fmt.Errorf("graphbadger: decoding %s: %v", what, err)
+	}
+	return a.Attrs, nil
+}
@@ -0,0 +1,292 @@
+package graphbadger
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	badger "github.com/dgraph-io/badger/v4"
+	graph "github.com/samuelhug/graph-store"
+)
+
+// edges returns all edges of s.
+func edges(t *testing.T, s *Store) []graph.Edge {
+	var edges []graph.Edge
+	err := s.IterEdges(func(fromKey, toKey string, weight int) error {
+		edges = append(edges, graph.Edge{From: fromKey, To: toKey, Weight: weight})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return edges
+}
+
+// edgeAttrs returns all edge attributes of s, formatted as "from→to name=value".
+func edgeAttrs(t *testing.T, s *Store) []string {
+	var attrs []string
+	err := s.IterEdgeAttrs(func(fromKey, toKey, name string, value interface{}) error {
+		attrs = append(attrs, fmt.Sprintf("%s→%s %s=%v", fromKey, toKey, name, value))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return attrs
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphbadger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Set("a", 1)
+	g.Set("ab", "two")
+	g.Set("b", nil)
+	g.Set("c", []int{4})
+	g.Connect("a", "ab", 5)
+	g.Connect("ab", "b", -2)
+	g.Connect("b", "a", 1<<40)
+	g.Connect("c", "a", 1)
+	g.Connect("a", "c", 1)
+	g.Disconnect("ab", "b")
+	g.Delete("c")
+
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the graph survives reopening the database
+	s, err = Open(dir)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer s.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if h.Len() != 3 {
+		t.Errorf("expected 3 vertices, got %d", h.Len())
+	}
+	for key, expected := range map[string]interface{}{"a": 1, "ab": "two", "b": nil} {
+		if v, err := h.Get(key); err != nil || v.Value() != expected {
+			t.Errorf("expected %v for %q, got %v", expected, key, v.Value())
+		}
+	}
+	if value, ok, err := s.Get("c"); value != nil || ok || err != nil {
+		t.Errorf("expected deleted vertex, got %v %v %v", value, ok, err)
+	}
+
+	expected := []graph.Edge{{From: "a", To: "ab", Weight: 5}, {From: "b", To: "a", Weight: 1 << 40}}
+	if edges := edges(t, s); !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected edges %v, got %v", expected, edges)
+	}
+
+	// neighbor scans don't confuse keys with common prefixes
+	var incoming []string
+	s.Incoming("a", func(fromKey string, weight int) error {
+		incoming = append(incoming, fmt.Sprintf("%s:%d", fromKey, weight))
+		return nil
+	})
+	if !reflect.DeepEqual(incoming, []string{fmt.Sprintf("b:%d", 1<<40)}) {
+		t.Errorf("unexpected incoming edges %v", incoming)
+	}
+
+	// deleting a vertex removes the edges from both directions
+	h.Delete("a")
+	if edges := edges(t, s); len(edges) != 0 {
+		t.Errorf("expected no edges, got %v", edges)
+	}
+	s.Outgoing("b", func(toKey string, _ int) error {
+		t.Errorf("unexpected edge to %q", toKey)
+		return nil
+	})
+}
+
+func TestStoreBatches(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s := New(db)
+	defer s.Close()
+
+	// ingest more edges than fit into a batch
+	n := BatchSize + 10
+	s.Set("hub", nil)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprint(i)
+		s.Set(key, i)
+		if err := s.Connect("hub", key, i); err != nil {
+			t.Fatalf("unexpected error %v", err)
+		}
+	}
+
+	count := 0
+	err = s.Outgoing("hub", func(toKey string, weight int) error {
+		if toKey != fmt.Sprint(weight) {
+			t.Errorf("unexpected edge to %q with weight %d", toKey, weight)
+		}
+		count++
+		return nil
+	})
+	if err != nil || count != n {
+		t.Errorf("expected %d edges, got %d %v", n, count, err)
+	}
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if g.Len() != n+1 {
+		t.Errorf("expected %d vertices, got %d", n+1, g.Len())
+	}
+
+	g.Delete("hub")
+	count = 0
+	s.Incoming("7", func(string, int) error {
+		count++
+		return nil
+	})
+	if count != 0 || len(edges(t, s)) != 0 {
+		t.Errorf("expected no edges after deleting the hub")
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s := New(db)
+	defer s.Close()
+
+	// unregistered types can't be encoded
+	type point struct{ X, Y int }
+	if err := s.Set("a", point{1, 2}); err == nil {
+		t.Error("expected an encoding error")
+	}
+
+	db.Update(func(txn *badger.Txn) error {
+		return txn.Set(vertexKey("a"), []byte("garbage"))
+	})
+	if _, err := graph.NewWithStore(s); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestStoreRejectedWrite(t *testing.T) {
+	db, err := badger.Open(badger.DefaultOptions("").WithInMemory(true).WithLogger(nil))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s := New(db)
+	defer s.Close()
+
+	if err := s.Set("a", 1); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// keys larger than Badger allows are rejected by the batch
+	if err := s.Set(strings.Repeat("x", 1<<16), 2); err == nil {
+		t.Fatal("expected an error for a key which is too large")
+	}
+
+	// the write acknowledged before is still committed
+	if err := s.Set("b", 3); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for key, expected := range map[string]int{"a": 1, "b": 3} {
+		if value, ok, err := s.Get(key); err != nil || !ok || value != expected {
+			t.Errorf("%q: expected %d, got %v %v %v", key, expected, value, ok, err)
+		}
+	}
+}
+
+func TestStoreEdgeAttrs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphbadger")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(dir)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		g.Set(key, key)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "a", 3)
+	g.SetEdgeAttr("a", "b", "label", "knows")
+	g.SetEdgeAttr("a", "b", "since", "2019")
+	g.SetEdgeAttr("a", "b", "source", "import")
+	g.DeleteEdgeAttr("a", "b", "source")
+	g.SetEdgeAttr("b", "c", "label", "follows")
+	g.SetEdgeAttr("c", "a", "label", "blocks")
+
+	// attributes are deleted with their edges
+	g.Disconnect("b", "c")
+	g.Connect("b", "c", 2)
+	g.Delete("c")
+
+	if err := s.SetEdgeAttr("b", "a", "label", "missing"); err != graph.ErrNoEdge {
+		t.Errorf("expected ErrNoEdge, got %v", err)
+	}
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// the attributes survive reopening the database
+	s, err = Open(dir)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer s.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e, err := h.GetEdge("a", "b"); err != nil || !reflect.DeepEqual(e.Attrs, map[string]interface{}{"label": "knows", "since": "2019"}) {
+		t.Errorf("unexpected edge %v, %v", e, err)
+	}
+
+	expected := []string{"a→b label=knows", "a→b since=2019"}
+	if attrs := edgeAttrs(t, s); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected attributes %v, got %v", expected, attrs)
+	}
+
+	h.Delete("b")
+	if attrs := edgeAttrs(t, s); len(attrs) != 0 {
+		t.Errorf("expected no attributes, got %v", attrs)
+	}
+}
@@ -0,0 +1,368 @@
+// Package graphbolt persists graphs in a bbolt database, so they survive restarts without writing snapshots. Use it with graph.NewWithStore:
+//
+//	s, err := graphbolt.Open("graph.db")
+//	...
+//	defer s.Close()
+//	g, err := graph.NewWithStore(s)
+package graphbolt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"fmt"
+	"time"
+
+	graph "github.com/samuelhug/graph-store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Names of the buckets. Vertex values are stored gob encoded in the vertices bucket by key. The outgoing and incoming buckets contain a nested bucket for every vertex with edges, mapping neighbor keys to the edge weights, so deleting a vertex finds all its edges without scanning.
+// The attrs bucket contains a nested bucket for every vertex with outgoing edges having attributes, which contains a nested bucket for every such edge, mapping attribute names to gob encoded values.
+var (
+	verticesBucket = []byte("vertices")
+	outgoingBucket = []byte("outgoing")
+	incomingBucket = []byte("incoming")
+	attrsBucket    = []byte("attrs")
+)
+
+// Store is a graph.EdgeAttrStore keeping vertices, edges and their attributes in a bbolt database. Every mutation is committed in its own transaction.
+// Values are gob encoded, so their concrete types must be registered with gob.Register, except for the basic types. Keys and attribute names must not be empty, since bbolt rejects empty keys.
+type Store struct {
+	db *bolt.DB
+}
+
+// Open opens the bbolt database at path, creating it if it doesn't exist. The database is locked until the store is closed, Open fails if another process doesn't release the lock within a second.
+func Open(path string) (*Store, error) {
+	db, err := bolt.Open(path, 0600, &bolt.Options{Timeout: time.Second})
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := New(db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// New initializes a store using db, creating the buckets if they don't exist.
+func New(db *bolt.DB) (*Store, error) {
+	err := db.Update(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{verticesBucket, outgoingBucket, incomingBucket, attrsBucket} {
+			if _, err := tx.CreateBucketIfNotExists(name); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &Store{db}, nil
+}
+
+// Close closes the database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Get returns the value of the vertex with the specified key, and false if there is no such vertex.
+func (s *Store) Get(key string) (value interface{}, ok bool, err error) {
+	err = s.db.View(func(tx *bolt.Tx) error {
+		b := tx.Bucket(verticesBucket).Get([]byte(key))
+		if b == nil {
+			return nil
+		}
+
+		ok = true
+		value, err = decodeValue(fmt.Sprintf("value of %q", key), b)
+		return err
+	})
+
+	return
+}
+
+// Set creates or updates the vertex with the specified key.
+func (s *Store) Set(key string, value interface{}) error {
+	b, err := encodeValue(fmt.Sprintf("value of %q", key), value)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(verticesBucket).Put([]byte(key), b)
+	})
+}
+
+// Delete deletes the vertex with the specified key and all its edges.
+func (s *Store) Delete(key string) error {
+	k := []byte(key)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		outgoing := tx.Bucket(outgoingBucket)
+		incoming := tx.Bucket(incomingBucket)
+		attrs := tx.Bucket(attrsBucket)
+
+		// remove the attributes of the edges first, while the incoming edges are still known
+		if neighbors := incoming.Bucket(k); neighbors != nil {
+			err := neighbors.ForEach(func(neighbor, _ []byte) error {
+				return deleteNested(attrs, neighbor, k)
+			})
+			if err != nil {
+				return err
+			}
+		}
+		if attrs.Bucket(k) != nil {
+			if err := attrs.DeleteBucket(k); err != nil {
+				return err
+			}
+		}
+
+		// remove the edges from the buckets of the neighbors, then the vertex' own buckets
+		if err := unlink(outgoing, incoming, k); err != nil {
+			return err
+		}
+		if err := unlink(incoming, outgoing, k); err != nil {
+			return err
+		}
+
+		return tx.Bucket(verticesBucket).Delete(k)
+	})
+}
+
+// unlink deletes the nested bucket of key in edges, and key from the nested buckets of its neighbors in reverse.
+func unlink(edges, reverse *bolt.Bucket, key []byte) error {
+	neighbors := edges.Bucket(key)
+	if neighbors == nil {
+		return nil
+	}
+
+	err := neighbors.ForEach(func(neighbor, _ []byte) error {
+		return deleteEdge(reverse, neighbor, key)
+	})
+	if err != nil {
+		return err
+	}
+
+	return edges.DeleteBucket(key)
+}
+
+// Connect creates or updates an edge. Returns graph.ErrInvalidKey if one of the vertices doesn't exist.
+func (s *Store) Connect(fromKey, toKey string, weight int) error {
+	from, to := []byte(fromKey), []byte(toKey)
+
+	w := make([]byte, 8)
+	binary.BigEndian.PutUint64(w, uint64(int64(weight)))
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		vertices := tx.Bucket(verticesBucket)
+		if vertices.Get(from) == nil || vertices.Get(to) == nil {
+			return graph.ErrInvalidKey
+		}
+
+		outgoing, err := tx.Bucket(outgoingBucket).CreateBucketIfNotExists(from)
+		if err != nil {
+			return err
+		}
+		incoming, err := tx.Bucket(incomingBucket).CreateBucketIfNotExists(to)
+		if err != nil {
+			return err
+		}
+
+		if err := outgoing.Put(to, w); err != nil {
+			return err
+		}
+		return incoming.Put(from, w)
+	})
+}
+
+// Disconnect deletes an edge and its attributes.
+func (s *Store) Disconnect(fromKey, toKey string) error {
+	from, to := []byte(fromKey), []byte(toKey)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteNested(tx.Bucket(attrsBucket), from, to); err != nil {
+			return err
+		}
+		if err := deleteEdge(tx.Bucket(outgoingBucket), from, to); err != nil {
+			return err
+		}
+		return deleteEdge(tx.Bucket(incomingBucket), to, from)
+	})
+}
+
+// deleteEdge deletes neighbor from the nested bucket of key in edges, and the nested bucket itself if it becomes empty.
+func deleteEdge(edges *bolt.Bucket, key, neighbor []byte) error {
+	b := edges.Bucket(key)
+	if b == nil {
+		return nil
+	}
+
+	if err := b.Delete(neighbor); err != nil {
+		return err
+	}
+
+	if k, _ := b.Cursor().First(); k == nil {
+		return edges.DeleteBucket(key)
+	}
+	return nil
+}
+
+// deleteNested deletes the nested bucket of neighbor in the nested bucket of key in b, and the latter if it becomes empty.
+func deleteNested(b *bolt.Bucket, key, neighbor []byte) error {
+	nested := b.Bucket(key)
+	if nested == nil || nested.Bucket(neighbor) == nil {
+		return nil
+	}
+
+	if err := nested.DeleteBucket(neighbor); err != nil {
+		return err
+	}
+
+	if k, _ := nested.Cursor().First(); k == nil {
+		return b.DeleteBucket(key)
+	}
+	return nil
+}
+
+// SetEdgeAttr creates or updates an attribute of an edge. Returns graph.ErrNoEdge if the edge doesn't exist.
+func (s *Store) SetEdgeAttr(fromKey, toKey, name string, value interface{}) error {
+	from, to := []byte(fromKey), []byte(toKey)
+
+	b, err := encodeValue(fmt.Sprintf("attribute %q of edge from %q to %q", name, fromKey, toKey), value)
+	if err != nil {
+		return err
+	}
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		if neighbors := tx.Bucket(outgoingBucket).Bucket(from); neighbors == nil || neighbors.Get(to) == nil {
+			return graph.ErrNoEdge
+		}
+
+		attrs, err := tx.Bucket(attrsBucket).CreateBucketIfNotExists(from)
+		if err != nil {
+			return err
+		}
+		if attrs, err = attrs.CreateBucketIfNotExists(to); err != nil {
+			return err
+		}
+
+		return attrs.Put([]byte(name), b)
+	})
+}
+
+// DeleteEdgeAttr deletes an attribute of an edge.
+func (s *Store) DeleteEdgeAttr(fromKey, toKey, name string) error {
+	from, to := []byte(fromKey), []byte(toKey)
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		attrs := tx.Bucket(attrsBucket)
+
+		edge := attrs.Bucket(from)
+		if edge != nil {
+			edge = edge.Bucket(to)
+		}
+		if edge == nil {
+			return nil
+		}
+
+		if err := edge.Delete([]byte(name)); err != nil {
+			return err
+		}
+
+		if k, _ := edge.Cursor().First(); k == nil {
+			return deleteNested(attrs, from, to)
+		}
+		return nil
+	})
+}
+
+// IterVertices calls fn for every vertex in key order, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterVertices(fn func(key string, value interface{}) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(verticesBucket).ForEach(func(k, b []byte) error {
+			value, err := decodeValue(fmt.Sprintf("value of %q", k), b)
+			if err != nil {
+				return err
+			}
+			return fn(string(k), value)
+		})
+	})
+}
+
+// IterEdges calls fn for every edge sorted by keys, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdges(fn func(fromKey, toKey string, weight int) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		outgoing := tx.Bucket(outgoingBucket)
+
+		return outgoing.ForEach(func(from, _ []byte) error {
+			neighbors := outgoing.Bucket(from)
+			if neighbors == nil {
+				return fmt.Errorf("graphbolt: corrupt database: %q is not a bucket of outgoing edges", from)
+			}
+
+			return neighbors.ForEach(func(to, w []byte) error {
+				if len(w) != 8 {
+					return fmt.Errorf("graphbolt: corrupt database: invalid weight of edge from %q to %q", from, to)
+				}
+				return fn(string(from), string(to), int(int64(binary.BigEndian.Uint64(w))))
+			})
+		})
+	})
+}
+
+// storedValue wraps values, so gob encodes their concrete type.
+type storedValue struct {
+	Value interface{}
+}
+
+// encodeValue encodes a value, e.g. of a vertex or an edge attribute; what describes it in errors.
+func encodeValue(what string, value interface{}) ([]byte, error) {
+	buf := &bytes.Buffer{}
+	if err := gob.NewEncoder(buf).Encode(storedValue{value}); err != nil {
+		return nil, fmt.Errorf("graphbolt: encoding %s: %v", what, err)
+	}
+	return buf.Bytes(), nil
+}
+
+// decodeValue decodes a value encoded by encodeValue; what describes it in errors.
+func decodeValue(what string, b []byte) (interface{}, error) {
+	var v storedValue
+	if err := gob.NewDecoder(bytes.NewReader(b)).Decode(&v); err != nil {
+		return nil, fmt.Errorf("graphbolt: decoding %s: %v", what, err)
+	}
+	return v.Value, nil
+}
+
+// IterEdgeAttrs calls fn for every attribute of every edge sorted by keys and names, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdgeAttrs(fn func(fromKey, toKey, name string, value interface{}) error) error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		attrs := tx.Bucket(attrsBucket)
+
+		return attrs.ForEach(func(from, _ []byte) error {
+			edges := attrs.Bucket(from)
+			if edges == nil {
+				return fmt.Errorf("graphbolt: corrupt database: %q is not a bucket of edge attributes", from)
+			}
+
+			return edges.ForEach(func(to, _ []byte) error {
+				edge := edges.Bucket(to)
+				if edge == nil {
+					return fmt.Errorf("graphbolt: corrupt database: attributes of edge from %q to %q are not a bucket", from, to)
+				}
+
+				return edge.ForEach(func(name, b []byte) error {
+					value, err := decodeValue(fmt.Sprintf("attribute %q of edge from %q to %q", name, from, to), b)
+					if err != nil {
+						return err
+					}
+					return fn(string(from), string(to), string(name), value)
+				})
+			})
+		})
+	})
+}
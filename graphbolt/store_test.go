@@ -0,0 +1,218 @@
+package graphbolt
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	graph "github.com/samuelhug/graph-store"
+	bolt "go.etcd.io/bbolt"
+)
+
+// edges returns all edges of s.
+func edges(t *testing.T, s *Store) []graph.Edge {
+	var edges []graph.Edge
+	err := s.IterEdges(func(fromKey, toKey string, weight int) error {
+		edges = append(edges, graph.Edge{From: fromKey, To: toKey, Weight: weight})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return edges
+}
+
+// edgeAttrs returns all edge attributes of s, formatted as "from→to name=value".
+func edgeAttrs(t *testing.T, s *Store) []string {
+	var attrs []string
+	err := s.IterEdgeAttrs(func(fromKey, toKey, name string, value interface{}) error {
+		attrs = append(attrs, fmt.Sprintf("%s→%s %s=%v", fromKey, toKey, name, value))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return attrs
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphbolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Set("a", 1)
+	g.Set("b", "two")
+	g.Set("c", nil)
+	g.Set("d", []int{4})
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", -2)
+	g.Connect("c", "a", 1<<40)
+	g.Connect("d", "a", 1)
+	g.Connect("a", "d", 1)
+	g.Disconnect("b", "c")
+	g.Delete("d")
+
+	if err := s.Connect("a", "x", 1); err != graph.ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the graph survives reopening the database
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer s.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if h.Len() != 3 {
+		t.Errorf("expected 3 vertices, got %d", h.Len())
+	}
+	for key, expected := range map[string]interface{}{"a": 1, "b": "two", "c": nil} {
+		if v, err := h.Get(key); err != nil || v.Value() != expected {
+			t.Errorf("expected %v for %q, got %v", expected, key, v.Value())
+		}
+	}
+	if value, ok, err := s.Get("d"); value != nil || ok || err != nil {
+		t.Errorf("expected deleted vertex, got %v %v %v", value, ok, err)
+	}
+
+	expected := []graph.Edge{{From: "a", To: "b", Weight: 5}, {From: "c", To: "a", Weight: 1 << 40}}
+	if edges := edges(t, s); !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected edges %v, got %v", expected, edges)
+	}
+
+	// deleting a vertex removes the edges from both directions
+	h.Delete("a")
+	if edges := edges(t, s); len(edges) != 0 {
+		t.Errorf("expected no edges, got %v", edges)
+	}
+	s.db.View(func(tx *bolt.Tx) error {
+		for _, name := range [][]byte{outgoingBucket, incomingBucket} {
+			if k, _ := tx.Bucket(name).Cursor().First(); k != nil {
+				t.Errorf("expected bucket %s to be empty, found %q", name, k)
+			}
+		}
+		return nil
+	})
+}
+
+func TestStoreErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphbolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	s, err := Open(filepath.Join(dir, "graph.db"))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer s.Close()
+
+	// unregistered types can't be encoded
+	type point struct{ X, Y int }
+	if err := s.Set("a", point{1, 2}); err == nil {
+		t.Error("expected an encoding error")
+	}
+
+	s.db.Update(func(tx *bolt.Tx) error {
+		return tx.Bucket(verticesBucket).Put([]byte("a"), []byte("garbage"))
+	})
+	if _, err := graph.NewWithStore(s); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestStoreEdgeAttrs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphbolt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.db")
+
+	s, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		g.Set(key, key)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "a", 3)
+	g.SetEdgeAttr("a", "b", "label", "knows")
+	g.SetEdgeAttr("a", "b", "since", "2019")
+	g.SetEdgeAttr("a", "b", "source", "import")
+	g.DeleteEdgeAttr("a", "b", "source")
+	g.SetEdgeAttr("b", "c", "label", "follows")
+	g.SetEdgeAttr("c", "a", "label", "blocks")
+
+	// attributes are deleted with their edges
+	g.Disconnect("b", "c")
+	g.Connect("b", "c", 2)
+	g.Delete("c")
+
+	if err := s.SetEdgeAttr("b", "a", "label", "missing"); err != graph.ErrNoEdge {
+		t.Errorf("expected ErrNoEdge, got %v", err)
+	}
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	if err := s.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	// the attributes survive reopening the database
+	s, err = Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer s.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e, err := h.GetEdge("a", "b"); err != nil || !reflect.DeepEqual(e.Attrs, map[string]interface{}{"label": "knows", "since": "2019"}) {
+		t.Errorf("unexpected edge %v, %v", e, err)
+	}
+
+	expected := []string{"a→b label=knows", "a→b since=2019"}
+	if attrs := edgeAttrs(t, s); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected attributes %v, got %v", expected, attrs)
+	}
+
+	h.Delete("b")
+	if attrs := edgeAttrs(t, s); len(attrs) != 0 {
+		t.Errorf("expected no attributes, got %v", attrs)
+	}
+}
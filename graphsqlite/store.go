@@ -0,0 +1,297 @@
+// Package graphsqlite persists graphs in SQLite tables, so stored graphs can be inspected and fixed with plain SQL. Use it with graph.NewWithStore and a database opened with any SQLite driver for database/sql:
+//
+//	db, err := sql.Open("sqlite3", "graph.db")
+//	...
+//	s, err := graphsqlite.New(db)
+//	...
+//	g, err := graph.NewWithStore(s)
+//
+// Vertices are stored in the table vertices(key, value), with values encoded as JSON, or NULL for nil. Edges are stored in the table edges("from", "to", weight), and their attributes in the table edge_attrs("from", "to", name, value), with values encoded like those of vertices.
+package graphsqlite
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	graph "github.com/samuelhug/graph-store"
+)
+
+// schema creates the tables if they don't exist. The indexes on "to" let Delete find incoming edges and their attributes without a table scan.
+const schema = `
+CREATE TABLE IF NOT EXISTS vertices (
+	key TEXT PRIMARY KEY NOT NULL,
+	value TEXT
+);
+CREATE TABLE IF NOT EXISTS edges (
+	"from" TEXT NOT NULL REFERENCES vertices (key),
+	"to" TEXT NOT NULL REFERENCES vertices (key),
+	weight INTEGER NOT NULL DEFAULT 0,
+	PRIMARY KEY ("from", "to")
+);
+CREATE INDEX IF NOT EXISTS edges_to ON edges ("to");
+CREATE TABLE IF NOT EXISTS edge_attrs (
+	"from" TEXT NOT NULL,
+	"to" TEXT NOT NULL,
+	name TEXT NOT NULL,
+	value TEXT,
+	PRIMARY KEY ("from", "to", name),
+	FOREIGN KEY ("from", "to") REFERENCES edges ("from", "to")
+);
+CREATE INDEX IF NOT EXISTS edge_attrs_to ON edge_attrs ("to");
+`
+
+// Store is a graph.EdgeAttrStore keeping vertices, edges and their attributes in SQLite tables.
+// Values are encoded as JSON, so they are decoded like by encoding/json: numbers as float64, arrays as []interface{} and objects as map[string]interface{}.
+// Writes are serialized, since SQLite fails concurrent ones with SQLITE_BUSY.
+type Store struct {
+	db *sql.DB
+	sync.Mutex
+}
+
+// New initializes a store using db, creating the tables if they don't exist. The caller remains responsible for closing db.
+func New(db *sql.DB) (*Store, error) {
+	if _, err := db.Exec(schema); err != nil {
+		return nil, fmt.Errorf("graphsqlite: creating tables: %v", err)
+	}
+
+	return &Store{db: db}, nil
+}
+
+// Get returns the value of the vertex with the specified key, and false if there is no such vertex.
+func (s *Store) Get(key string) (interface{}, bool, error) {
+	var value sql.NullString
+
+	err := s.db.QueryRow(`SELECT value FROM vertices WHERE key = ?`, key).Scan(&value)
+	if err == sql.ErrNoRows {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+
+	v, err := decodeValue(fmt.Sprintf("value of %q", key), value)
+	return v, err == nil, err
+}
+
+// Set creates or updates the vertex with the specified key.
+func (s *Store) Set(key string, value interface{}) error {
+	encoded, err := encodeValue(fmt.Sprintf("value of %q", key), value)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	_, err = s.db.Exec(`INSERT INTO vertices (key, value) VALUES (?, ?) ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, encoded)
+	return err
+}
+
+// Delete deletes the vertex with the specified key and all its edges.
+func (s *Store) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM edge_attrs WHERE "from" = ? OR "to" = ?`, key, key); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM edges WHERE "from" = ? OR "to" = ?`, key, key); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM vertices WHERE key = ?`, key); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Connect creates or updates an edge. Returns graph.ErrInvalidKey if one of the vertices doesn't exist.
+func (s *Store) Connect(fromKey, toKey string, weight int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	result, err := s.db.Exec(`INSERT INTO edges ("from", "to", weight)
+		SELECT ?, ?, ? WHERE EXISTS (SELECT 1 FROM vertices WHERE key = ?) AND EXISTS (SELECT 1 FROM vertices WHERE key = ?)
+		ON CONFLICT ("from", "to") DO UPDATE SET weight = excluded.weight`, fromKey, toKey, weight, fromKey, toKey)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return graph.ErrInvalidKey
+	}
+
+	return nil
+}
+
+// Disconnect deletes an edge and its attributes.
+func (s *Store) Disconnect(fromKey, toKey string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM edge_attrs WHERE "from" = ? AND "to" = ?`, fromKey, toKey); err != nil {
+		return err
+	}
+	if _, err := tx.Exec(`DELETE FROM edges WHERE "from" = ? AND "to" = ?`, fromKey, toKey); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// SetEdgeAttr creates or updates an attribute of an edge. Returns graph.ErrNoEdge if the edge doesn't exist.
+func (s *Store) SetEdgeAttr(fromKey, toKey, name string, value interface{}) error {
+	encoded, err := encodeValue(fmt.Sprintf("attribute %q of edge from %q to %q", name, fromKey, toKey), value)
+	if err != nil {
+		return err
+	}
+
+	s.Lock()
+	defer s.Unlock()
+
+	result, err := s.db.Exec(`INSERT INTO edge_attrs ("from", "to", name, value)
+		SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM edges WHERE "from" = ? AND "to" = ?)
+		ON CONFLICT ("from", "to", name) DO UPDATE SET value = excluded.value`, fromKey, toKey, name, encoded, fromKey, toKey)
+	if err != nil {
+		return err
+	}
+
+	if n, err := result.RowsAffected(); err != nil {
+		return err
+	} else if n == 0 {
+		return graph.ErrNoEdge
+	}
+
+	return nil
+}
+
+// DeleteEdgeAttr deletes an attribute of an edge.
+func (s *Store) DeleteEdgeAttr(fromKey, toKey, name string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	_, err := s.db.Exec(`DELETE FROM edge_attrs WHERE "from" = ? AND "to" = ? AND name = ?`, fromKey, toKey, name)
+	return err
+}
+
+// IterVertices calls fn for every vertex in key order, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterVertices(fn func(key string, value interface{}) error) error {
+	rows, err := s.db.Query(`SELECT key, value FROM vertices ORDER BY key`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var key string
+		var value sql.NullString
+		if err := rows.Scan(&key, &value); err != nil {
+			return err
+		}
+
+		v, err := decodeValue(fmt.Sprintf("value of %q", key), value)
+		if err != nil {
+			return err
+		}
+		if err := fn(key, v); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// IterEdges calls fn for every edge sorted by keys, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdges(fn func(fromKey, toKey string, weight int) error) error {
+	rows, err := s.db.Query(`SELECT "from", "to", weight FROM edges ORDER BY "from", "to"`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to string
+		var weight int64
+		if err := rows.Scan(&from, &to, &weight); err != nil {
+			return err
+		}
+
+		if int64(int(weight)) != weight {
+			return fmt.Errorf("graphsqlite: weight %d of edge from %q to %q out of range", weight, from, to)
+		}
+		if err := fn(from, to, int(weight)); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// IterEdgeAttrs calls fn for every attribute of every edge sorted by keys and names, stopping at the first error, which it returns. fn must not modify the store.
+func (s *Store) IterEdgeAttrs(fn func(fromKey, toKey, name string, value interface{}) error) error {
+	rows, err := s.db.Query(`SELECT "from", "to", name, value FROM edge_attrs ORDER BY "from", "to", name`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var from, to, name string
+		var value sql.NullString
+		if err := rows.Scan(&from, &to, &name, &value); err != nil {
+			return err
+		}
+
+		v, err := decodeValue(fmt.Sprintf("attribute %q of edge from %q to %q", name, from, to), value)
+		if err != nil {
+			return err
+		}
+		if err := fn(from, to, name, v); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// encodeValue encodes a value, e.g. of a vertex or an edge attribute, as JSON, or NULL if it is nil; what describes it in errors.
+func encodeValue(what string, value interface{}) (sql.NullString, error) {
+	if value == nil {
+		return sql.NullString{}, nil
+	}
+
+	b, err := json.Marshal(value)
+	if err != nil {
+		return sql.NullString{}, fmt.Errorf("graphsqlite: encoding %s: %v", what, err)
+	}
+	return sql.NullString{String: string(b), Valid: true}, nil
+}
+
+// decodeValue decodes a value encoded by encodeValue; what describes it in errors.
+func decodeValue(what string, value sql.NullString) (interface{}, error) {
+	if !value.Valid {
+		return nil, nil
+	}
+
+	var v interface{}
+	if err := json.Unmarshal([]byte(value.String), &v); err != nil {
+		return nil, fmt.Errorf("graphsqlite: decoding %s: %v", what, err)
+	}
+	return v, nil
+}
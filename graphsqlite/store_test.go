@@ -0,0 +1,216 @@
+package graphsqlite
+
+import (
+	"database/sql"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+	graph "github.com/samuelhug/graph-store"
+)
+
+// edges returns all edges of s.
+func edges(t *testing.T, s *Store) []graph.Edge {
+	var edges []graph.Edge
+	err := s.IterEdges(func(fromKey, toKey string, weight int) error {
+		edges = append(edges, graph.Edge{From: fromKey, To: toKey, Weight: weight})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return edges
+}
+
+// open opens a store in the database file at path.
+func open(t *testing.T, path string) (*sql.DB, *Store) {
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	s, err := New(db)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	return db, s
+}
+
+// edgeAttrs returns all edge attributes of s, formatted as "from→to name=value".
+func edgeAttrs(t *testing.T, s *Store) []string {
+	var attrs []string
+	err := s.IterEdgeAttrs(func(fromKey, toKey, name string, value interface{}) error {
+		attrs = append(attrs, fmt.Sprintf("%s→%s %s=%v", fromKey, toKey, name, value))
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return attrs
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphsqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.db")
+
+	db, s := open(t, path)
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Set("a", 1)
+	g.Set("b", "two")
+	g.Set("c", nil)
+	g.Set("d", []interface{}{"x"})
+	g.Set("a", 1.5)
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", -2)
+	g.Connect("c", "a", 1<<40)
+	g.Connect("d", "a", 1)
+	g.Connect("a", "d", 1)
+	g.Connect("a", "b", 6)
+	g.Disconnect("b", "c")
+	g.Delete("d")
+
+	if err := s.Connect("a", "x", 1); err != graph.ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	db.Close()
+
+	// the graph survives reopening the database
+	db, s = open(t, path)
+	defer db.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if h.Len() != 3 {
+		t.Errorf("expected 3 vertices, got %d", h.Len())
+	}
+	for key, expected := range map[string]interface{}{"a": 1.5, "b": "two", "c": nil} {
+		if v, err := h.Get(key); err != nil || v.Value() != expected {
+			t.Errorf("expected %v for %q, got %v", expected, key, v.Value())
+		}
+	}
+	if value, ok, err := s.Get("d"); value != nil || ok || err != nil {
+		t.Errorf("expected deleted vertex, got %v %v %v", value, ok, err)
+	}
+
+	expected := []graph.Edge{{From: "a", To: "b", Weight: 6}, {From: "c", To: "a", Weight: 1 << 40}}
+	if edges := edges(t, s); !reflect.DeepEqual(edges, expected) {
+		t.Errorf("expected edges %v, got %v", expected, edges)
+	}
+
+	// the tables can be fixed with plain SQL
+	if _, err := db.Exec(`UPDATE vertices SET value = '{"fixed": true}' WHERE key = 'b'; UPDATE edges SET weight = 7`); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if value, ok, err := s.Get("b"); !ok || err != nil || !reflect.DeepEqual(value, map[string]interface{}{"fixed": true}) {
+		t.Errorf("unexpected value %v %v %v", value, ok, err)
+	}
+
+	// deleting a vertex removes the edges from both directions
+	h.Delete("a")
+	if edges := edges(t, s); len(edges) != 0 {
+		t.Errorf("expected no edges, got %v", edges)
+	}
+}
+
+func TestStoreErrors(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer db.Close()
+	db.SetMaxOpenConns(1)
+
+	s, err := New(db)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if err := s.Set("a", func() {}); err == nil {
+		t.Error("expected an encoding error")
+	}
+
+	db.Exec(`INSERT INTO vertices (key, value) VALUES ('a', 'garbage')`)
+	if _, err := graph.NewWithStore(s); err == nil {
+		t.Error("expected a decoding error")
+	}
+}
+
+func TestStoreEdgeAttrs(t *testing.T) {
+	dir, err := ioutil.TempDir("", "graphsqlite")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.db")
+
+	db, s := open(t, path)
+
+	g, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, key := range []string{"a", "b", "c"} {
+		g.Set(key, key)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "a", 3)
+	g.SetEdgeAttr("a", "b", "label", "knows")
+	g.SetEdgeAttr("a", "b", "since", "2019")
+	g.SetEdgeAttr("a", "b", "source", "import")
+	g.DeleteEdgeAttr("a", "b", "source")
+	g.SetEdgeAttr("b", "c", "label", "follows")
+	g.SetEdgeAttr("c", "a", "label", "blocks")
+
+	// attributes are deleted with their edges
+	g.Disconnect("b", "c")
+	g.Connect("b", "c", 2)
+	g.Delete("c")
+
+	if err := s.SetEdgeAttr("b", "a", "label", "missing"); err != graph.ErrNoEdge {
+		t.Errorf("expected ErrNoEdge, got %v", err)
+	}
+	if err := g.StoreErr(); err != nil {
+		t.Fatalf("unexpected store error %v", err)
+	}
+	db.Close()
+	// the attributes survive reopening the database
+	db, s = open(t, path)
+	defer db.Close()
+
+	h, err := graph.NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if e, err := h.GetEdge("a", "b"); err != nil || !reflect.DeepEqual(e.Attrs, map[string]interface{}{"label": "knows", "since": "2019"}) {
+		t.Errorf("unexpected edge %v, %v", e, err)
+	}
+
+	expected := []string{"a→b label=knows", "a→b since=2019"}
+	if attrs := edgeAttrs(t, s); !reflect.DeepEqual(attrs, expected) {
+		t.Errorf("expected attributes %v, got %v", expected, attrs)
+	}
+
+	h.Delete("b")
+	if attrs := edgeAttrs(t, s); len(attrs) != 0 {
+		t.Errorf("expected no attributes, got %v", attrs)
+	}
+}
@@ -0,0 +1,169 @@
+// Package graphtest provides assertions for tests of code using graphs, reporting failures with readable descriptions of what was expected and found.
+package graphtest
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	graph "github.com/samuelhug/graph-store"
+)
+
+// T is the part of testing.TB used to report failures, so assertions can be used with *testing.T, *testing.B and custom reporters.
+type T interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+// Assertions checks properties of a graph, reporting failures to a T. Each assertion returns the Assertions, so they can be chained:
+//
+//	graphtest.Assert(t, g).HasPath("a", "c").EdgeWeight("a", "b", 2).IsDAG()
+type Assertions struct {
+	t T
+	g *graph.Graph
+}
+
+// Assert returns assertions on g reporting failures to t.
+func Assert(t T, g *graph.Graph) *Assertions {
+	return &Assertions{t, g}
+}
+
+// HasPath asserts that there is a path from the vertex with key from to the vertex with key to.
+func (a *Assertions) HasPath(from, to string) *Assertions {
+	a.t.Helper()
+
+	if _, err := a.g.ShortestPath(from, to); err != nil {
+		a.t.Errorf("expected a path from %q to %q, but found none: %v", from, to, err)
+	}
+
+	return a
+}
+
+// PathEquals asserts that the shortest path from the vertex with key from to the vertex with key to, as found by ShortestPath, consists of the expected keys in start → end order.
+func (a *Assertions) PathEquals(from, to string, expected ...string) *Assertions {
+	a.t.Helper()
+
+	path, err := a.g.ShortestPath(from, to)
+	if err != nil {
+		a.t.Errorf("shortest path from %q to %q:\n\texpected: %s\n\tactual:   none (%v)", from, to, formatPath(expected), err)
+		return a
+	}
+
+	if !equalStrings(path, expected) {
+		a.t.Errorf("shortest path from %q to %q:\n\texpected: %s\n\tactual:   %s", from, to, formatPath(expected), formatPath(path))
+	}
+
+	return a
+}
+
+// IsDAG asserts that the graph has no cycles. A failure names an edge closing a cycle.
+func (a *Assertions) IsDAG() *Assertions {
+	a.t.Helper()
+
+	if _, err := a.g.TopologicalSort(); err == nil {
+		return a
+	}
+
+	keys := a.g.KeysWithPrefix("")
+
+	var back graph.Edge
+	a.g.DFS(keys[0], graph.VisitAll(), graph.OnEdge(func(e graph.Edge, class graph.EdgeClass) bool {
+		if class == graph.BackEdge {
+			back = e
+			return false
+		}
+		return true
+	}))
+
+	a.t.Errorf("expected a DAG, but the edge %q → %q closes a cycle", back.From, back.To)
+
+	return a
+}
+
+// ComponentsEqual asserts that the weakly connected components of the graph consist of exactly the expected keys. The order of components and of keys within them doesn't matter.
+func (a *Assertions) ComponentsEqual(expected ...[]string) *Assertions {
+	a.t.Helper()
+
+	actual := map[string]bool{}
+	for _, component := range a.g.WeaklyConnectedComponents() {
+		actual[formatComponent(component)] = true
+	}
+
+	var missing, unexpected []string
+	for _, component := range expected {
+		c := formatComponent(component)
+		if actual[c] {
+			delete(actual, c)
+		} else {
+			missing = append(missing, c)
+		}
+	}
+	for c := range actual {
+		unexpected = append(unexpected, c)
+	}
+	sort.Strings(missing)
+	sort.Strings(unexpected)
+
+	if len(missing) > 0 || len(unexpected) > 0 {
+		a.t.Errorf("weakly connected components differ:\n\tmissing:    %s\n\tunexpected: %s", formatList(missing), formatList(unexpected))
+	}
+
+	return a
+}
+
+// EdgeWeight asserts that there is an edge from the vertex with key from to the vertex with key to with the given weight.
+func (a *Assertions) EdgeWeight(from, to string, weight int) *Assertions {
+	a.t.Helper()
+
+	ok, actual := a.g.IsConnected(from, to)
+	switch {
+	case !ok:
+		a.t.Errorf("expected an edge %q → %q with weight %d, but there is none", from, to, weight)
+	case actual != weight:
+		a.t.Errorf("expected the edge %q → %q to have weight %d, but it has %d", from, to, weight, actual)
+	}
+
+	return a
+}
+
+// formatPath formats the keys of a path like "a → b → c".
+func formatPath(path []string) string {
+	quoted := make([]string, len(path))
+	for i, key := range path {
+		quoted[i] = fmt.Sprintf("%q", key)
+	}
+	return strings.Join(quoted, " → ")
+}
+
+// formatComponent formats the keys of a component in sorted order like {"a", "b"}.
+func formatComponent(keys []string) string {
+	sorted := append([]string(nil), keys...)
+	sort.Strings(sorted)
+
+	quoted := make([]string, len(sorted))
+	for i, key := range sorted {
+		quoted[i] = fmt.Sprintf("%q", key)
+	}
+	return "{" + strings.Join(quoted, ", ") + "}"
+}
+
+// formatList joins formatted items, or returns "none" for an empty list.
+func formatList(items []string) string {
+	if len(items) == 0 {
+		return "none"
+	}
+	return strings.Join(items, " ")
+}
+
+// equalStrings returns whether a and b contain the same strings in the same order.
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
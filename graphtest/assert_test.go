@@ -0,0 +1,72 @@
+package graphtest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	graph "github.com/samuelhug/graph-store"
+)
+
+// recorder is a T recording failures.
+type recorder struct {
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...interface{}) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func TestAssertions(t *testing.T) {
+	g := graph.New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, nil)
+	}
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 2)
+	g.Connect("a", "c", 5)
+
+	// passing assertions
+	r := &recorder{}
+	Assert(r, g).
+		HasPath("a", "c").
+		PathEquals("a", "c", "a", "b", "c").
+		IsDAG().
+		ComponentsEqual([]string{"c", "b", "a"}, []string{"d"}).
+		EdgeWeight("b", "c", 2)
+
+	if len(r.failures) != 0 {
+		t.Fatalf("unexpected failures %v", r.failures)
+	}
+
+	// failing assertions
+	g.Connect("c", "a", 1)
+
+	Assert(r, g).
+		HasPath("a", "d").
+		PathEquals("a", "c", "a", "c").
+		IsDAG().
+		ComponentsEqual([]string{"a", "b", "c", "d"}).
+		EdgeWeight("a", "b", 3).
+		EdgeWeight("b", "a", 1)
+
+	expected := []string{
+		`expected a path from "a" to "d", but found none`,
+		"shortest path from \"a\" to \"c\":\n\texpected: \"a\" → \"c\"\n\tactual:   \"a\" → \"b\" → \"c\"",
+		`expected a DAG, but the edge "c" → "a" closes a cycle`,
+		"weakly connected components differ:\n\tmissing:    {\"a\", \"b\", \"c\", \"d\"}\n\tunexpected: {\"a\", \"b\", \"c\"} {\"d\"}",
+		`expected the edge "a" → "b" to have weight 3, but it has 1`,
+		`expected an edge "b" → "a" with weight 1, but there is none`,
+	}
+
+	if len(r.failures) != len(expected) {
+		t.Fatalf("expected %d failures, got %d: %q", len(expected), len(r.failures), r.failures)
+	}
+	for i, failure := range r.failures {
+		if !strings.HasPrefix(failure, expected[i]) {
+			t.Errorf("expected failure %q, got %q", expected[i], failure)
+		}
+	}
+}
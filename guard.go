@@ -0,0 +1,37 @@
+package graph
+
+import (
+	"fmt"
+	"runtime/debug"
+)
+
+// PanicError is returned by guarded operations when a user-supplied callback (like a heuristic or visitor) panicked.
+type PanicError struct {
+	Value interface{} // the value passed to panic
+	Stack []byte      // stack trace of the panicking goroutine
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("graph: callback panicked: %v", e.Value)
+}
+
+// Guard calls fn and returns a *PanicError if it panics, instead of crashing the program.
+// It can be wrapped around any graph operation taking callbacks (like TraverseTagged or Simulation.Step), since all of them release their locks when a callback panics.
+func Guard(fn func()) (err error) {
+	defer recoverPanic(&err)
+
+	fn()
+
+	return
+}
+
+// recoverPanic stores a recovered panic in err as *PanicError. It has to be deferred directly.
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		if pe, ok := r.(*PanicError); ok {
+			*err = pe
+			return
+		}
+		*err = &PanicError{r, debug.Stack()}
+	}
+}
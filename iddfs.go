@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"sort"
+)
+
+// IDDFS finds the path with the fewest edges from the vertex with key startKey to the vertex with key endKey using iterative deepening depth-first search: depth-limited searches with limits 0, 1, 2, ... up to maxDepth.
+// Unlike A*, it only keeps the current path in memory, at the cost of visiting vertices near the start repeatedly. Weights are ignored. The path is returned in start → end order, together with its depth, the number of edges on it.
+// Returns ErrInvalidKey if one of the keys is invalid and ErrNoPath if there is no path of at most maxDepth edges.
+func (g *Graph) IDDFS(startKey, endKey string, maxDepth int) (path []string, depth int, err error) {
+	defer g.track("IDDFS")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(startKey)
+	end := g.get(endKey)
+
+	if start == nil || end == nil {
+		return nil, 0, ErrInvalidKey
+	}
+
+	s := &iddfsSearch{end: end, onPath: map[*Vertex]bool{}}
+
+	for depth = 0; depth <= maxDepth; depth++ {
+		s.cutoff = false
+
+		if s.search(start, depth) {
+			return s.path, depth, nil
+		}
+
+		// no vertex was left unexplored because of the limit, so deeper searches won't find anything either
+		if !s.cutoff {
+			break
+		}
+	}
+
+	return nil, 0, ErrNoPath
+}
+
+// iddfsSearch holds the state of an iterative deepening depth-first search.
+type iddfsSearch struct {
+	end    *Vertex
+	path   []string         // keys of the current path
+	onPath map[*Vertex]bool // vertices on the current path, which must not be revisited
+	cutoff bool             // whether the current depth limit stopped the search somewhere
+}
+
+// search looks for a path of exactly limit more edges from v to the end vertex, not using vertices on the current path. Returns true if one was found; the path is complete then.
+func (s *iddfsSearch) search(v *Vertex, limit int) bool {
+	s.path = append(s.path, v.key)
+	s.onPath[v] = true
+
+	if limit == 0 {
+		if v == s.end {
+			return true
+		}
+		if len(v.GetOutgoing()) > 0 {
+			s.cutoff = true
+		}
+	} else {
+		neighbors := make([]*Vertex, 0, len(v.GetOutgoing()))
+		for neighbor := range v.GetOutgoing() {
+			if !s.onPath[neighbor] {
+				neighbors = append(neighbors, neighbor)
+			}
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			if s.search(neighbor, limit-1) {
+				return true
+			}
+		}
+	}
+
+	s.path = s.path[:len(s.path)-1]
+	delete(s.onPath, v)
+
+	return false
+}
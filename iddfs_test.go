@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestIDDFS(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c → d → e, shortcut a → f → d, cycle b → a
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 1)
+	g.Connect("a", "f", 100)
+	g.Connect("f", "d", 100)
+	g.Connect("b", "a", 1)
+
+	path, depth, err := g.IDDFS("a", "e", 10)
+	if err != nil || depth != 3 || !reflect.DeepEqual(path, []string{"a", "f", "d", "e"}) {
+		t.Errorf("unexpected path %v with depth %d (%v)", path, depth, err)
+	}
+
+	if path, depth, err = g.IDDFS("a", "a", 10); err != nil || depth != 0 || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Fail()
+	}
+
+	if _, _, err = g.IDDFS("a", "e", 2); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+	if _, _, err = g.IDDFS("e", "a", 100); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+	if _, _, err = g.IDDFS("a", "x", 1); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
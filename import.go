@@ -0,0 +1,325 @@
+package graph
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// MappingSpec describes how the fields of source records (CSV rows or JSON objects) become vertices and edges when importing data.
+// A record can describe a vertex, an edge, or both. Nested JSON fields are addressed with dotted paths like "user.id".
+type MappingSpec struct {
+	KeyField   string // field holding the key of the vertex described by a record; records without it describe no vertex
+	ValueField string // field holding the vertex' value; if empty, vertices get a nil value unless Value is set
+
+	// Value, if set, computes a vertex' value from the whole record instead of ValueField.
+	Value func(record map[string]interface{}) interface{}
+
+	FromField     string // field holding the key of the vertex an edge starts at
+	ToField       string // field holding the key of the vertex an edge ends at; records missing either field describe no edge
+	WeightField   string // field holding the edge weight; if empty or missing in a record, DefaultWeight is used
+	DefaultWeight int
+
+	// CreateMissing makes edges to or from vertices no record describes create those vertices with a nil value. Otherwise, the import fails with an *ImportError listing all such edges, after connecting all others.
+	CreateMissing bool
+}
+
+// ImportCSV imports the rows of CSV data into the graph as specified by spec. The first row must contain the field names.
+// Vertices are set in row order; edges are connected after all rows were read, so they may refer to vertices from later rows.
+func (g *Graph) ImportCSV(r io.Reader, spec MappingSpec) error {
+	defer g.track("ImportCSV")()
+
+	reader := csv.NewReader(r)
+
+	header, err := reader.Read()
+	if err != nil {
+		return fmt.Errorf("graph: reading CSV header: %v", err)
+	}
+
+	im := newImporter(g, spec)
+
+	for {
+		row, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("graph: reading CSV: %v", err)
+		}
+
+		record := make(map[string]interface{}, len(header))
+		for i, field := range header {
+			if i < len(row) {
+				record[field] = row[i]
+			}
+		}
+
+		if err = im.add(record); err != nil {
+			return err
+		}
+	}
+
+	return im.Finalize()
+}
+
+// ImportJSON imports JSON objects into the graph as specified by spec. The data can either be an array of objects or a stream of objects (e.g. one per line).
+// Vertices are set in input order; edges are connected after all objects were read, so they may refer to vertices from later objects.
+func (g *Graph) ImportJSON(r io.Reader, spec MappingSpec) error {
+	defer g.track("ImportJSON")()
+
+	br := bufio.NewReader(r)
+
+	// find out if the data is an array by looking at the first non-space character
+	var first byte
+	for {
+		b, err := br.ReadByte()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("graph: reading JSON: %v", err)
+		}
+		if b != ' ' && b != '\t' && b != '\r' && b != '\n' {
+			first = b
+			br.UnreadByte()
+			break
+		}
+	}
+
+	dec := json.NewDecoder(br)
+	dec.UseNumber()
+
+	if first == '[' {
+		// skip opening bracket
+		if _, err := dec.Token(); err != nil {
+			return fmt.Errorf("graph: reading JSON: %v", err)
+		}
+	}
+
+	im := newImporter(g, spec)
+
+	for {
+		if first == '[' && !dec.More() {
+			break
+		}
+
+		record := map[string]interface{}{}
+		err := dec.Decode(&record)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("graph: reading JSON: %v", err)
+		}
+
+		if err = im.add(record); err != nil {
+			return err
+		}
+	}
+
+	return im.Finalize()
+}
+
+// Importer imports vertices and edges in two phases: vertices are set immediately, while edges are buffered until Finalize, so they may refer to vertices set later.
+type Importer struct {
+	g     *Graph
+	edges []importedEdge // edges to connect once all vertices were set
+
+	// CreateMissing makes Finalize create vertices referenced by edges but never set, instead of reporting them.
+	CreateMissing bool
+
+	// Placeholder, if set, computes the values of vertices created because of CreateMissing. Otherwise they get a nil value.
+	Placeholder func(key string) interface{}
+
+	// EnableSelfLoops makes Finalize enable self-loops on the graph if an edge connects a vertex to itself, see Graph.EnableSelfLoops, instead of reporting the edge.
+	EnableSelfLoops bool
+}
+
+type importedEdge struct {
+	source         string
+	fromKey, toKey string
+	weight         int
+}
+
+// DanglingReference describes an edge Finalize couldn't connect.
+type DanglingReference struct {
+	Source         string   // where the edge came from, e.g. "record 3"
+	FromKey, ToKey string   // endpoints of the edge
+	Missing        []string // keys of the endpoints that don't exist; empty if the edge was rejected for another reason, e.g. being a self-loop
+}
+
+// ImportError is returned by Finalize if edges couldn't be connected. It lists all of them, not just the first.
+type ImportError struct {
+	References []DanglingReference
+}
+
+func (err *ImportError) Error() string {
+	r := err.References[0]
+
+	reason := "invalid edge"
+	if len(r.Missing) > 0 {
+		reason = "missing " + strings.Join(r.Missing, ", ")
+	}
+
+	msg := fmt.Sprintf("graph: %s: invalid edge endpoints %q → %q (%s)", r.Source, r.FromKey, r.ToKey, reason)
+	if len(err.References) > 1 {
+		msg += fmt.Sprintf(" and %d more", len(err.References)-1)
+	}
+
+	return msg
+}
+
+// NewImporter initializes an importer into the graph.
+func (g *Graph) NewImporter() *Importer {
+	defer g.track("NewImporter")()
+
+	return &Importer{g: g}
+}
+
+// Set sets the vertex with the specified key to value, just like Graph.Set.
+func (im *Importer) Set(key string, value interface{}) {
+	im.g.Set(key, value)
+}
+
+// TrySet sets the vertex with the specified key to value, just like Graph.TrySet. Returns ErrDuplicateValue if the value was rejected by a unique index.
+func (im *Importer) TrySet(key string, value interface{}) (bool, error) {
+	return im.g.TrySet(key, value)
+}
+
+// Connect buffers an edge to be connected by Finalize. The vertices don't need to exist yet.
+func (im *Importer) Connect(fromKey, toKey string, weight int) {
+	im.connect(fmt.Sprintf("edge %d", len(im.edges)+1), fromKey, toKey, weight)
+}
+
+// connect buffers an edge, describing where it came from with source.
+func (im *Importer) connect(source, fromKey, toKey string, weight int) {
+	im.edges = append(im.edges, importedEdge{source, fromKey, toKey, weight})
+}
+
+// Finalize connects the buffered edges. Edges referring to vertices which don't exist either create them (if CreateMissing is set) or are skipped; all skipped edges are reported by an *ImportError.
+// The importer can be reused afterwards.
+func (im *Importer) Finalize() error {
+	var dangling []DanglingReference
+
+	for _, e := range im.edges {
+		if e.fromKey == e.toKey && im.EnableSelfLoops {
+			im.g.EnableSelfLoops()
+		}
+
+		var missing []string
+		for _, key := range []string{e.fromKey, e.toKey} {
+			if _, err := im.g.Get(key); err == nil {
+				continue
+			}
+
+			if !im.CreateMissing {
+				missing = append(missing, key)
+				continue
+			}
+
+			var value interface{}
+			if im.Placeholder != nil {
+				value = im.Placeholder(key)
+			}
+			if _, err := im.g.TrySet(key, value); err != nil {
+				missing = append(missing, key)
+			}
+		}
+
+		if len(missing) > 0 || !im.g.Connect(e.fromKey, e.toKey, e.weight) {
+			dangling = append(dangling, DanglingReference{e.source, e.fromKey, e.toKey, missing})
+		}
+	}
+
+	im.edges = nil
+
+	if len(dangling) > 0 {
+		return &ImportError{dangling}
+	}
+
+	return nil
+}
+
+// importer applies records to a graph according to a MappingSpec.
+type importer struct {
+	*Importer
+	spec MappingSpec
+	n    int // number of records seen
+}
+
+func newImporter(g *Graph, spec MappingSpec) *importer {
+	return &importer{&Importer{g: g, CreateMissing: spec.CreateMissing}, spec, 0}
+}
+
+// add sets the vertex described by record and buffers the edge it describes.
+func (im *importer) add(record map[string]interface{}) error {
+	im.n++
+
+	if key, ok := lookupField(record, im.spec.KeyField); ok {
+		var value interface{}
+		if im.spec.Value != nil {
+			value = im.spec.Value(record)
+		} else if im.spec.ValueField != "" {
+			value, _ = lookupField(record, im.spec.ValueField)
+		}
+
+		if _, err := im.TrySet(fieldString(key), value); err != nil {
+			return fmt.Errorf("graph: record %d: %v: %q", im.n, err, fieldString(key))
+		}
+	}
+
+	from, ok := lookupField(record, im.spec.FromField)
+	if !ok {
+		return nil
+	}
+
+	to, ok := lookupField(record, im.spec.ToField)
+	if !ok {
+		return nil
+	}
+
+	weight := im.spec.DefaultWeight
+	if w, ok := lookupField(record, im.spec.WeightField); ok {
+		var err error
+		if weight, err = strconv.Atoi(fieldString(w)); err != nil {
+			return fmt.Errorf("graph: record %d: invalid weight %q", im.n, fieldString(w))
+		}
+	}
+
+	im.connect(fmt.Sprintf("record %d", im.n), fieldString(from), fieldString(to), weight)
+
+	return nil
+}
+
+// lookupField returns the value of the field at the dotted path in record. Null values and empty strings count as missing.
+func lookupField(record map[string]interface{}, path string) (interface{}, bool) {
+	if path == "" {
+		return nil, false
+	}
+
+	var value interface{} = record
+	for _, name := range strings.Split(path, ".") {
+		object, ok := value.(map[string]interface{})
+		if !ok {
+			return nil, false
+		}
+
+		if value, ok = object[name]; !ok || value == nil || value == "" {
+			return nil, false
+		}
+	}
+
+	return value, true
+}
+
+// fieldString formats a field value as a string for use as a key or weight.
+func fieldString(value interface{}) string {
+	if s, ok := value.(string); ok {
+		return s
+	}
+	return fmt.Sprint(value)
+}
@@ -0,0 +1,173 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportCSV(t *testing.T) {
+	data := `id,name,parent,cost
+1,root,,
+2,child,1,5
+3,grandchild,2,
+`
+
+	g := New()
+	err := g.ImportCSV(strings.NewReader(data), MappingSpec{
+		KeyField:      "id",
+		ValueField:    "name",
+		FromField:     "parent",
+		ToField:       "id",
+		WeightField:   "cost",
+		DefaultWeight: 1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Len() != 3 {
+		t.Fail()
+	}
+	if v, _ := g.Get("2"); v.Value() != "child" {
+		t.Fail()
+	}
+	if ok, weight := g.IsConnected("1", "2"); !ok || weight != 5 {
+		t.Fail()
+	}
+	if ok, weight := g.IsConnected("2", "3"); !ok || weight != 1 {
+		t.Fail()
+	}
+
+	// edges to unknown vertices
+	err = New().ImportCSV(strings.NewReader("from,to\na,b\n"), MappingSpec{FromField: "from", ToField: "to"})
+	if err == nil {
+		t.Fail()
+	}
+
+	g = New()
+	err = g.ImportCSV(strings.NewReader("from,to\na,b\n"), MappingSpec{FromField: "from", ToField: "to", CreateMissing: true})
+	if err != nil || g.Len() != 2 {
+		t.Fail()
+	}
+
+	// invalid weight
+	err = New().ImportCSV(strings.NewReader("from,to,w\na,b,x\n"), MappingSpec{FromField: "from", ToField: "to", WeightField: "w", CreateMissing: true})
+	if err == nil {
+		t.Fail()
+	}
+}
+
+func TestImportJSON(t *testing.T) {
+	spec := MappingSpec{
+		KeyField: "user.id",
+		Value: func(record map[string]interface{}) interface{} {
+			return record["user"].(map[string]interface{})["name"]
+		},
+		FromField:   "user.id",
+		ToField:     "follows",
+		WeightField: "since",
+	}
+
+	array := `[
+		{"user": {"id": "a", "name": "Alice"}, "follows": "b", "since": 2019},
+		{"user": {"id": "b", "name": "Bob"}}
+	]`
+
+	stream := `{"user": {"id": "a", "name": "Alice"}, "follows": "b", "since": 2019}
+{"user": {"id": "b", "name": "Bob"}}
+`
+
+	for _, data := range []string{array, stream} {
+		g := New()
+		if err := g.ImportJSON(strings.NewReader(data), spec); err != nil {
+			t.Fatal(err)
+		}
+
+		if g.Len() != 2 {
+			t.Fail()
+		}
+		if v, _ := g.Get("a"); v.Value() != "Alice" {
+			t.Fail()
+		}
+		if ok, weight := g.IsConnected("a", "b"); !ok || weight != 2019 {
+			t.Fail()
+		}
+	}
+
+	// empty input
+	if err := New().ImportJSON(strings.NewReader("  \n"), spec); err != nil {
+		t.Fail()
+	}
+
+	if err := New().ImportJSON(strings.NewReader(`[{"user": 1}`), spec); err == nil {
+		t.Fail()
+	}
+}
+
+func TestImporter(t *testing.T) {
+	g := New()
+	im := g.NewImporter()
+
+	// edges may come before their vertices
+	im.Connect("a", "b", 1)
+	im.Connect("b", "x", 2)
+	im.Connect("y", "z", 3)
+	im.Set("a", 1)
+	im.Set("b", 2)
+
+	err := im.Finalize()
+	importErr, ok := err.(*ImportError)
+	if !ok {
+		t.Fatalf("expected *ImportError, got %v", err)
+	}
+
+	expected := []DanglingReference{
+		{"edge 2", "b", "x", []string{"x"}},
+		{"edge 3", "y", "z", []string{"y", "z"}},
+	}
+	if !reflect.DeepEqual(importErr.References, expected) {
+		t.Fatalf("unexpected report %v", importErr.References)
+	}
+	if !strings.Contains(err.Error(), "and 1 more") {
+		t.Error(err)
+	}
+
+	// valid edges are connected anyway
+	if ok, _ := g.IsConnected("a", "b"); !ok {
+		t.Fail()
+	}
+	if g.Len() != 2 {
+		t.Fail()
+	}
+
+	// placeholders
+	im.CreateMissing = true
+	im.Placeholder = func(key string) interface{} { return "placeholder " + key }
+	im.Connect("b", "x", 2)
+	if err = im.Finalize(); err != nil {
+		t.Fatal(err)
+	}
+	if v, err := g.Get("x"); err != nil || v.Value() != "placeholder x" {
+		t.Fail()
+	}
+}
+
+func TestGobDecodeDanglingEdges(t *testing.T) {
+	buf := &bytes.Buffer{}
+	err := gob.NewEncoder(buf).Encode(graphGob{
+		Vertices: map[string]interface{}{"a": nil},
+		Edges:    map[string]map[string]int{"a": {"b": 1}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = New().GobDecode(buf.Bytes())
+	importErr, ok := err.(*ImportError)
+	if !ok || len(importErr.References) != 1 || importErr.References[0].Missing[0] != "b" {
+		t.Fatalf("expected report of dangling edge, got %v", err)
+	}
+}
@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"time"
+)
+
+// Instrumenter is notified about every call of a public method of a graph, e.g. to collect metrics or log slow operations.
+// The op passed to it is the method's name, like "Set" or "ShortestPathWithHeuristic". Operations calling other public methods (like Apply) are reported along with the nested ones.
+// Instrumenters are called concurrently by all goroutines using the graph.
+type Instrumenter interface {
+	OnOpStart(op string)
+	OnOpEnd(op string, d time.Duration)
+}
+
+// instrumenterHolder allows storing a nil Instrumenter in an atomic.Value.
+type instrumenterHolder struct {
+	i Instrumenter
+}
+
+// SetInstrumenter makes the graph report all operations to i. Passing nil disables instrumentation.
+func (g *Graph) SetInstrumenter(i Instrumenter) {
+	g.instrumenter.Store(instrumenterHolder{i})
+}
+
+// noop is returned by track if instrumentation is disabled.
+func noop() {}
+
+// track reports the start of op to the graph's instrumenter and returns a function reporting its end, to be deferred.
+func (g *Graph) track(op string) func() {
+	h, _ := g.instrumenter.Load().(instrumenterHolder)
+	if h.i == nil {
+		return noop
+	}
+
+	h.i.OnOpStart(op)
+	start := time.Now()
+
+	return func() {
+		h.i.OnOpEnd(op, time.Since(start))
+	}
+}
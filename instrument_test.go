@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+type opRecorder struct {
+	started map[string]int
+	ended   map[string]int
+	sync.Mutex
+}
+
+func (r *opRecorder) OnOpStart(op string) {
+	r.Lock()
+	r.started[op]++
+	r.Unlock()
+}
+
+func (r *opRecorder) OnOpEnd(op string, d time.Duration) {
+	r.Lock()
+	r.ended[op]++
+	r.Unlock()
+}
+
+func TestInstrumenter(t *testing.T) {
+	g := New()
+
+	r := &opRecorder{started: map[string]int{}, ended: map[string]int{}}
+	g.SetInstrumenter(r)
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Connect("1", "2", 1)
+	g.ShortestPathWithHeuristic("1", "2", noHeuristic)
+
+	// nested operations are reported, too
+	b := Batch{}
+	b.Delete("2")
+	g.Apply(b)
+
+	expected := map[string]int{"Set": 2, "Connect": 1, "ShortestPathWithHeuristic": 1, "Apply": 1, "Validate": 1, "Delete": 1}
+	for op, n := range expected {
+		if r.started[op] != n || r.ended[op] != n {
+			t.Errorf("%s: expected %d calls, got %d/%d", op, n, r.started[op], r.ended[op])
+		}
+	}
+
+	// disable
+	g.SetInstrumenter(nil)
+	g.Set("3", nil)
+
+	if r.started["Set"] != 2 {
+		t.Fail()
+	}
+}
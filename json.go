@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// graphJSON is the JSON representation of a graph:
+//
+//	{
+//		"vertices": {"a": 1, "b": "x"},
+//		"edges": [{"from": "a", "to": "b", "weight": 5}]
+//	}
+//
+// Vertices map keys to values; edges are sorted by their endpoints' keys.
+type graphJSON struct {
+	Vertices map[string]interface{} `json:"vertices"`
+	Edges    []edgeJSON             `json:"edges"`
+}
+
+// edgeJSON is the JSON representation of an edge, see graphJSON.
+type edgeJSON struct {
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Weight int    `json:"weight"`
+}
+
+// MarshalJSON encodes the graph as an object with a "vertices" object mapping keys to values, and an "edges" array of objects with "from", "to" and "weight" fields, sorted by key. With this method, graph implements the json.Marshaler interface.
+// Values are encoded with encoding/json, so they must be marshalable.
+func (g *Graph) MarshalJSON() ([]byte, error) {
+	defer g.track("MarshalJSON")()
+
+	g.RLock()
+
+	gJSON := graphJSON{map[string]interface{}{}, []edgeJSON{}}
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+		gJSON.Vertices[key] = v.Value()
+
+		for neighbor, weight := range v.GetOutgoing() {
+			gJSON.Edges = append(gJSON.Edges, edgeJSON{key, neighbor.key, weight})
+		}
+	}
+
+	g.RUnlock()
+
+	sort.Slice(gJSON.Edges, func(i, j int) bool {
+		a, b := gJSON.Edges[i], gJSON.Edges[j]
+		return a.From < b.From || (a.From == b.From && a.To < b.To)
+	})
+
+	return json.Marshal(gJSON)
+}
+
+// UnmarshalJSON decodes the format written by MarshalJSON into the graph's vertices and edges, merging them with existing ones. With this method, graph implements the json.Unmarshaler interface.
+// Values are decoded into the types used by encoding/json for interface{} values, e.g. float64 for numbers. Edges may refer to vertices already in the graph; all edges with invalid endpoints are reported by an *ImportError. If an edge connects a vertex to itself, self-loops are enabled, see EnableSelfLoops.
+func (g *Graph) UnmarshalJSON(b []byte) error {
+	defer g.track("UnmarshalJSON")()
+
+	gJSON := &graphJSON{}
+	if err := json.Unmarshal(b, gJSON); err != nil {
+		return fmt.Errorf("graph: decoding json: %v", err)
+	}
+
+	im := g.NewImporter()
+	im.EnableSelfLoops = true
+
+	for key, value := range gJSON.Vertices {
+		if _, err := im.TrySet(key, value); err != nil {
+			return fmt.Errorf("graph: decoding json: %v: %q", err, key)
+		}
+	}
+
+	for i, e := range gJSON.Edges {
+		im.connect(fmt.Sprintf("json edge %d", i+1), e.From, e.To, e.Weight)
+	}
+
+	return im.Finalize()
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSON(t *testing.T) {
+	g := New()
+	g.Set("1", 123)
+	g.Set("2", "abc")
+	g.Set("3", nil)
+
+	g.Connect("2", "3", 9)
+	g.Connect("1", "3", 1)
+	g.Connect("1", "2", 5)
+
+	b, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `{"vertices":{"1":123,"2":"abc","3":null},"edges":[{"from":"1","to":"2","weight":5},{"from":"1","to":"3","weight":1},{"from":"2","to":"3","weight":9}]}`
+	if string(b) != expected {
+		t.Errorf("expected %s, got %s", expected, b)
+	}
+
+	newG := New()
+	if err := json.Unmarshal(b, newG); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if len(newG.vertices) != 3 {
+		t.Errorf("expected 3 vertices, got %d", len(newG.vertices))
+	}
+
+	// numbers are decoded as float64
+	if v, _ := newG.Get("1"); v.Value() != 123.0 {
+		t.Errorf("unexpected value %v", v.Value())
+	}
+	if v, _ := newG.Get("2"); v.Value() != "abc" {
+		t.Errorf("unexpected value %v", v.Value())
+	}
+
+	for _, e := range []Edge{{From: "1", To: "2", Weight: 5}, {From: "1", To: "3", Weight: 1}, {From: "2", To: "3", Weight: 9}} {
+		if ok, weight := newG.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+}
+
+func TestJSONInvalid(t *testing.T) {
+	g := New()
+
+	err := json.Unmarshal([]byte(`{"vertices":{"a":1},"edges":[{"from":"a","to":"b","weight":1}]}`), g)
+
+	importErr, ok := err.(*ImportError)
+	if !ok || len(importErr.References) != 1 || importErr.References[0].Missing[0] != "b" {
+		t.Errorf("expected an import error for b, got %v", err)
+	}
+
+	if err := json.Unmarshal([]byte(`{"vertices":[]}`), g); err == nil {
+		t.Error("expected an error for a malformed document")
+	}
+}
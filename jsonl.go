@@ -0,0 +1,129 @@
+package graph
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// jsonlVertex is a vertex record of the JSON Lines format:
+//
+//	{"type":"vertex","key":"a","value":1}
+type jsonlVertex struct {
+	Type  string      `json:"type"`
+	Key   string      `json:"key"`
+	Value interface{} `json:"value"`
+}
+
+// jsonlEdge is an edge record of the JSON Lines format:
+//
+//	{"type":"edge","from":"a","to":"b","weight":5}
+type jsonlEdge struct {
+	Type   string `json:"type"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Weight int    `json:"weight"`
+}
+
+// jsonlRecord is any record of the JSON Lines format, with pointers to tell missing fields apart.
+type jsonlRecord struct {
+	Type   string      `json:"type"`
+	Key    *string     `json:"key"`
+	Value  interface{} `json:"value"`
+	From   *string     `json:"from"`
+	To     *string     `json:"to"`
+	Weight int         `json:"weight"`
+}
+
+// StreamJSONL writes the graph to w in the JSON Lines format, one JSON object per line: a record like {"type":"vertex","key":"a","value":1} for every vertex, then a record like {"type":"edge","from":"a","to":"b","weight":5} for every edge, in key order.
+// The records are written as they are encoded, so the output can be piped into other tools as it is produced. Values are encoded with encoding/json, so they must be marshalable. The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) StreamJSONL(w io.Writer) error {
+	defer g.track("StreamJSONL")()
+
+	bw := bufio.NewWriter(w)
+	enc := json.NewEncoder(bw)
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := g.sortedKeys()
+
+	for _, key := range keys {
+		if err := enc.Encode(jsonlVertex{"vertex", key, g.vertices[key].Value()}); err != nil {
+			return fmt.Errorf("graph: encoding JSONL: vertex %q: %v", key, err)
+		}
+	}
+
+	for _, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			if err := enc.Encode(jsonlEdge{"edge", key, neighbor.key, outgoing[neighbor]}); err != nil {
+				return err
+			}
+		}
+	}
+
+	return bw.Flush()
+}
+
+// LoadJSONL reads records in the format written by StreamJSONL from r into the graph's vertices and edges, merging them with existing ones. Blank lines are skipped, and edges without a weight get weight 0.
+// Every record is applied as soon as its line is read, so data can be ingested incrementally from a pipe. Edges may refer to vertices from later lines: edges whose endpoints don't exist yet are connected at the end of the input, and those still invalid are reported by an *ImportError. If an edge connects a vertex to itself, self-loops are enabled at the end of the input, see EnableSelfLoops.
+// Values are decoded into the types used by encoding/json for interface{} values, e.g. float64 for numbers.
+func (g *Graph) LoadJSONL(r io.Reader) error {
+	defer g.track("LoadJSONL")()
+
+	br := bufio.NewReader(r)
+	im := g.NewImporter()
+	im.EnableSelfLoops = true
+
+	for line := 1; ; line++ {
+		b, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			return fmt.Errorf("graph: reading JSONL: line %d: %v", line, err)
+		}
+
+		if b = bytes.TrimSpace(b); len(b) > 0 {
+			var record jsonlRecord
+			if err := json.Unmarshal(b, &record); err != nil {
+				return fmt.Errorf("graph: reading JSONL: line %d: %v", line, err)
+			}
+
+			switch record.Type {
+			case "vertex":
+				if record.Key == nil {
+					return fmt.Errorf("graph: reading JSONL: line %d: vertex without key", line)
+				}
+				if _, err := im.TrySet(*record.Key, record.Value); err != nil {
+					return fmt.Errorf("graph: reading JSONL: line %d: %v: %q", line, err, *record.Key)
+				}
+
+			case "edge":
+				if record.From == nil || record.To == nil {
+					return fmt.Errorf("graph: reading JSONL: line %d: edge without endpoints", line)
+				}
+				if !g.Connect(*record.From, *record.To, record.Weight) {
+					im.connect(fmt.Sprintf("line %d", line), *record.From, *record.To, record.Weight)
+				}
+
+			default:
+				return fmt.Errorf("graph: reading JSONL: line %d: unknown record type %q", line, record.Type)
+			}
+		}
+
+		if err == io.EOF {
+			break
+		}
+	}
+
+	return im.Finalize()
+}
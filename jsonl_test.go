@@ -0,0 +1,101 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestStreamJSONL(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", "x")
+	g.Set("c", nil)
+
+	g.Connect("a", "b", 5)
+	g.Connect("a", "c", -1)
+	g.Connect("c", "a", 0)
+
+	buf := &bytes.Buffer{}
+	if err := g.StreamJSONL(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `{"type":"vertex","key":"a","value":1}
+{"type":"vertex","key":"b","value":"x"}
+{"type":"vertex","key":"c","value":null}
+{"type":"edge","from":"a","to":"b","weight":5}
+{"type":"edge","from":"a","to":"c","weight":-1}
+{"type":"edge","from":"c","to":"a","weight":0}
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	read := New()
+	if err := read.LoadJSONL(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for key, value := range map[string]interface{}{"a": 1.0, "b": "x", "c": nil} {
+		if v, err := read.Get(key); err != nil || !reflect.DeepEqual(v.Value(), value) {
+			t.Errorf("%q: expected value %v", key, value)
+		}
+	}
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 5}, {From: "a", To: "c", Weight: -1}, {From: "c", To: "a", Weight: 0}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+}
+
+func TestLoadJSONL(t *testing.T) {
+	// edges may come before their vertices, blank lines and a missing final newline are fine
+	input := `{"type":"edge","from":"a","to":"b","weight":2}
+
+{"type":"vertex","key":"a","value":{"x":[1,2]}}
+{"type":"vertex","key":"b"}
+{"type":"edge","from":"b","to":"a"}`
+
+	g := New()
+	if err := g.LoadJSONL(strings.NewReader(input)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 2 {
+		t.Errorf("expected edge of weight 2, got %v %d", ok, weight)
+	}
+	if ok, weight := g.IsConnected("b", "a"); !ok || weight != 0 {
+		t.Errorf("expected edge of weight 0, got %v %d", ok, weight)
+	}
+	if v, _ := g.Get("a"); !reflect.DeepEqual(v.Value(), map[string]interface{}{"x": []interface{}{1.0, 2.0}}) {
+		t.Errorf("unexpected value %v", v.Value())
+	}
+
+	// dangling edges are reported after loading everything else, self-loops enable them
+	g = New()
+	err := g.LoadJSONL(strings.NewReader(`{"type":"vertex","key":"a"}
+{"type":"edge","from":"a","to":"z","weight":1}
+{"type":"edge","from":"a","to":"a","weight":1}
+`))
+	importErr, ok := err.(*ImportError)
+	if !ok || len(importErr.References) != 1 || importErr.References[0].Source != "line 2" || importErr.References[0].Missing[0] != "z" {
+		t.Errorf("expected report of dangling edges, got %v", err)
+	}
+	if ok, _ := g.IsConnected("a", "a"); !ok {
+		t.Error("expected the self-loop to be connected")
+	}
+
+	for _, input := range []string{
+		`{"type":"vertex"}`,
+		`{"type":"edge","from":"a"}`,
+		`{"type":"other"}`,
+		`{"type":"edge","from":"a","to":"b","weight":1.5}`,
+		`not json`,
+	} {
+		if err := New().LoadJSONL(strings.NewReader(input)); err == nil {
+			t.Errorf("%q: expected an error", input)
+		} else if _, ok := err.(*ImportError); ok {
+			t.Errorf("%q: expected a syntax error, got %v", input, err)
+		}
+	}
+}
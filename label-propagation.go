@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"math/rand"
+	"sort"
+)
+
+// CommunitiesLabelPropagation detects communities using asynchronous label propagation, treating every edge as undirected and using the edge weights as affinities.
+// Every vertex starts in a community of its own; then, in random order, each vertex joins the community with the largest total weight among its neighbors, until no vertex changes its community or maxIter rounds were run.
+// Random choices are made with a source seeded with seed, so the result is deterministic for the same graph and seed. Returns the sorted keys of each community, sorted by their first key.
+func (g *Graph) CommunitiesLabelPropagation(seed int64, maxIter int) (communities [][]string) {
+	defer g.track("CommunitiesLabelPropagation")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	// number the vertices in key order, so the result doesn't depend on map iteration
+	keys := make([]string, 0, len(g.vertices))
+	for key := range g.vertices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	index := make(map[*Vertex]int, len(keys))
+	for i, key := range keys {
+		index[g.vertices[key]] = i
+	}
+
+	type neighbor struct {
+		i      int
+		weight int
+	}
+
+	// undirected adjacency lists, summing the weights of edges in both directions
+	neighbors := make([][]neighbor, len(keys))
+	for i, key := range keys {
+		weights := map[int]int{}
+		v := g.vertices[key]
+		for u, weight := range v.GetOutgoing() {
+			weights[index[u]] += weight
+		}
+		for u, weight := range v.GetIncoming() {
+			weights[index[u]] += weight
+		}
+
+		for j, weight := range weights {
+			neighbors[i] = append(neighbors[i], neighbor{j, weight})
+		}
+		sort.Slice(neighbors[i], func(a, b int) bool {
+			return neighbors[i][a].i < neighbors[i][b].i
+		})
+	}
+
+	labels := make([]int, len(keys))
+	order := make([]int, len(keys))
+	for i := range labels {
+		labels[i] = i
+		order[i] = i
+	}
+
+	r := rand.New(rand.NewSource(seed))
+
+	for iteration := 0; iteration < maxIter; iteration++ {
+		r.Shuffle(len(order), func(a, b int) {
+			order[a], order[b] = order[b], order[a]
+		})
+
+		changed := false
+		for _, i := range order {
+			if len(neighbors[i]) == 0 {
+				continue
+			}
+
+			// labels are updated in place, so later vertices see the new labels of earlier ones
+			totals := map[int]int{}
+			for _, n := range neighbors[i] {
+				totals[labels[n.i]] += n.weight
+			}
+
+			best := []int{}
+			for label, total := range totals {
+				if len(best) == 0 || total > totals[best[0]] {
+					best = append(best[:0], label)
+				} else if total == totals[best[0]] {
+					best = append(best, label)
+				}
+			}
+
+			// keep the current label if it is among the best, otherwise break ties randomly
+			keep := false
+			for _, label := range best {
+				if label == labels[i] {
+					keep = true
+				}
+			}
+			if keep {
+				continue
+			}
+
+			sort.Ints(best)
+			labels[i] = best[r.Intn(len(best))]
+			changed = true
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	byLabel := map[int][]string{}
+	for i, label := range labels {
+		byLabel[label] = append(byLabel[label], keys[i])
+	}
+
+	// keys were added in order, so every community is sorted already
+	for _, community := range byLabel {
+		communities = append(communities, community)
+	}
+	sort.Slice(communities, func(i, j int) bool {
+		return communities[i][0] < communities[j][0]
+	})
+
+	return
+}
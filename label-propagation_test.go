@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestCommunitiesLabelPropagation(t *testing.T) {
+	g := New()
+
+	// two cliques of 5 vertices, joined by a single light edge, and an isolated vertex
+	for c := 0; c < 2; c++ {
+		for i := 0; i < 5; i++ {
+			g.Set(strconv.Itoa(c)+strconv.Itoa(i), nil)
+		}
+		for i := 0; i < 5; i++ {
+			for j := i + 1; j < 5; j++ {
+				g.Connect(strconv.Itoa(c)+strconv.Itoa(i), strconv.Itoa(c)+strconv.Itoa(j), 10)
+			}
+		}
+	}
+	g.Connect("04", "10", 1)
+	g.Set("x", nil)
+
+	communities := g.CommunitiesLabelPropagation(42, 100)
+
+	expected := [][]string{
+		{"00", "01", "02", "03", "04"},
+		{"10", "11", "12", "13", "14"},
+		{"x"},
+	}
+	if !reflect.DeepEqual(communities, expected) {
+		t.Fatalf("unexpected communities %v", communities)
+	}
+
+	// deterministic for the same seed
+	for i := 0; i < 5; i++ {
+		if !reflect.DeepEqual(g.CommunitiesLabelPropagation(7, 100), g.CommunitiesLabelPropagation(7, 100)) {
+			t.Fail()
+		}
+	}
+
+	if len(New().CommunitiesLabelPropagation(1, 10)) != 0 {
+		t.Fail()
+	}
+}
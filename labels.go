@@ -0,0 +1,53 @@
+package graph
+
+// SetLabels replaces the labels of the vertex with the specified key with the given ones, e.g. "person" and "admin" to describe what kind of entity it is. Passing no labels removes all of them. Returns false if the key is invalid.
+// Unlike tags, which are added and removed one by one, labels are always set as a whole. Vertices are indexed by their labels, so ByLabel is fast.
+// Labels are copied with the graph and encoded by GobEncode, but not reported to subscribers, so they are not part of events, replicas, mutation logs or stores.
+func (g *Graph) SetLabels(key string, labels ...string) bool {
+	defer g.track("SetLabels")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	g.labels.removeAll(v)
+	for _, label := range labels {
+		g.labels.add(v, label)
+	}
+
+	return true
+}
+
+// Labels returns the sorted labels of the vertex with the specified key. The slice is empty if the key is invalid or the vertex has no labels.
+func (g *Graph) Labels(key string) []string {
+	defer g.track("Labels")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.labels.sorted(g.get(key))
+}
+
+// HasLabel returns true if the vertex with the specified key has the given label.
+func (g *Graph) HasLabel(key, label string) bool {
+	defer g.track("HasLabel")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.labels.has(g.get(key), label)
+}
+
+// ByLabel returns a slice containing all vertices with the given label, taking time proportional to their number. The slice is empty if there are no such vertices.
+func (g *Graph) ByLabel(label string) []*Vertex {
+	defer g.track("ByLabel")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.labels.lookup(label)
+}
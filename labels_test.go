@@ -0,0 +1,109 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestLabels(t *testing.T) {
+	g := New()
+	g.Set("alice", nil)
+	g.Set("bob", nil)
+	g.Set("carol", nil)
+
+	if !g.SetLabels("alice", "person", "admin") || !g.SetLabels("bob", "person") {
+		t.Fatal("expected labels to be set")
+	}
+	if g.SetLabels("dave", "person") {
+		t.Error("expected false for an invalid key")
+	}
+
+	// byLabel returns the sorted keys of the vertices with label
+	byLabel := func(label string) (keys []string) {
+		for _, v := range g.ByLabel(label) {
+			keys = append(keys, v.Key())
+		}
+		sort.Strings(keys)
+		return
+	}
+
+	if keys := byLabel("person"); !reflect.DeepEqual(keys, []string{"alice", "bob"}) {
+		t.Errorf("expected alice and bob, got %v", keys)
+	}
+	if labels := g.Labels("alice"); !reflect.DeepEqual(labels, []string{"admin", "person"}) {
+		t.Errorf("unexpected labels %v", labels)
+	}
+	if !g.HasLabel("alice", "admin") || g.HasLabel("bob", "admin") || g.HasLabel("dave", "person") {
+		t.Error("unexpected HasLabel result")
+	}
+
+	// labels are replaced as a whole
+	g.SetLabels("alice", "admin")
+	if keys := byLabel("person"); !reflect.DeepEqual(keys, []string{"bob"}) {
+		t.Errorf("expected only bob, got %v", keys)
+	}
+	g.SetLabels("bob")
+	if len(g.ByLabel("person")) != 0 || len(g.Labels("bob")) != 0 {
+		t.Error("expected bob's labels to be removed")
+	}
+
+	// labels are independent of tags
+	g.Tag("carol", "admin")
+	if len(g.ByLabel("admin")) != 1 || g.HasTag("alice", "admin") {
+		t.Error("expected labels and tags to be separate")
+	}
+
+	// merged vertices pass their labels on, deleted ones leave the index
+	g.SetLabels("bob", "person")
+	g.MergeVertices("carol", "bob")
+	if keys := byLabel("person"); !reflect.DeepEqual(keys, []string{"carol"}) {
+		t.Errorf("expected carol to inherit the label, got %v", keys)
+	}
+	g.Delete("alice")
+	if len(g.ByLabel("admin")) != 0 {
+		t.Error("expected the deleted vertex to be removed from the index")
+	}
+}
+
+func TestLabelsCopied(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.SetLabels("a", "person", "admin")
+	g.Tag("a", "new")
+
+	c := g.clone()
+	b, err := g.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	decoded := New()
+	if err := decoded.GobDecode(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for name, h := range map[string]*Graph{"clone": c, "gob": decoded} {
+		if labels := h.Labels("a"); !reflect.DeepEqual(labels, []string{"admin", "person"}) {
+			t.Errorf("%s: expected the labels, got %v", name, labels)
+		}
+		if labeled := h.ByLabel("person"); len(labeled) != 1 || labeled[0].Key() != "a" {
+			t.Errorf("%s: expected the copy to be indexed, got %v", name, labeled)
+		}
+		if len(h.Labels("b")) != 0 {
+			t.Errorf("%s: expected no other labels", name)
+		}
+		if tags := h.Tags("a"); !reflect.DeepEqual(tags, []string{"new"}) {
+			t.Errorf("%s: expected the tags to be copied as well, got %v", name, tags)
+		}
+		if tagged := h.Tagged("new"); len(tagged) != 1 || tagged[0].Key() != "a" {
+			t.Errorf("%s: expected the copied tags to be indexed, got %v", name, tagged)
+		}
+	}
+
+	// the copy is independent
+	c.SetLabels("a")
+	if !g.HasLabel("a", "person") {
+		t.Error("expected the original labels to be unchanged")
+	}
+}
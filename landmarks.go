@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Landmarks holds the distances between every vertex and a few landmark vertices, from which Heuristic derives lower bounds of the distances between any two vertices (the ALT technique: A*, landmarks and the triangle inequality).
+// The distances are not updated when the graph changes; after changing edges, select new landmarks, since outdated ones may overestimate distances.
+type Landmarks struct {
+	keys []string           // keys of the landmarks
+	from []map[string]int64 // distances from each landmark to the vertices it reaches
+	to   []map[string]int64 // distances to each landmark from the vertices reaching it
+}
+
+// SelectLandmarks chooses n landmark vertices (or all vertices if there are fewer) and computes the distances between them and every vertex, for use as an A* heuristic with ShortestPathWithHeuristic or WithHeuristic.
+// Landmarks are chosen one by one, each as far as possible from those chosen before, since landmarks behind the end of a path give the best bounds. Vertices that can't reach or be reached by any landmark yet are chosen first.
+// Returns a *WeightError wrapping ErrNegativeWeight if the graph has negative edge weights, for which the bounds would be wrong.
+func (g *Graph) SelectLandmarks(n int) (*Landmarks, error) {
+	defer g.track("SelectLandmarks")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	for _, v := range g.vertices {
+		for neighbor, weight := range v.GetOutgoing() {
+			if weight < 0 {
+				return nil, &WeightError{v.key, neighbor.key, weight, ErrNegativeWeight}
+			}
+		}
+	}
+
+	l := &Landmarks{}
+	keys := g.sortedKeys()
+
+	// smallest sum of the distances from and to a landmark of every vertex; missing if none reaches or is reached by it
+	nearest := map[string]int64{}
+
+	for len(l.keys) < n && len(l.keys) < len(keys) {
+		landmark := l.farthest(g, keys, nearest)
+
+		from, _ := g.directedDijkstra(landmark, nil, nil, false)
+		to, _ := g.directedDijkstra(landmark, nil, nil, true)
+
+		l.keys = append(l.keys, landmark.key)
+		l.from = append(l.from, keyDistances(from))
+		l.to = append(l.to, keyDistances(to))
+
+		for _, key := range keys {
+			df, okFrom := l.from[len(l.from)-1][key]
+			dt, okTo := l.to[len(l.to)-1][key]
+			if !okFrom || !okTo {
+				continue
+			}
+
+			if d, ok := nearest[key]; !ok || df+dt < d {
+				nearest[key] = df + dt
+			}
+		}
+	}
+
+	return l, nil
+}
+
+// farthest is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the vertex to choose as the next landmark: the first vertex not covered by any landmark yet, or else the one with the largest distance to its nearest landmark. The first landmark is the vertex farthest from the vertex with the smallest key.
+func (l *Landmarks) farthest(g *Graph, keys []string, nearest map[string]int64) *Vertex {
+	if len(l.keys) == 0 {
+		dist, _ := g.dijkstra(g.vertices[keys[0]], nil, nil)
+		nearest = keyDistances(dist)
+	}
+
+	var farthest string
+	max := int64(-1)
+
+	for _, key := range keys {
+		if l.isLandmark(key) {
+			continue
+		}
+
+		d, ok := nearest[key]
+		if !ok && len(l.keys) > 0 {
+			return g.vertices[key]
+		}
+		if ok && d > max {
+			farthest, max = key, d
+		}
+	}
+
+	return g.vertices[farthest]
+}
+
+// keyDistances converts distances indexed by vertex into distances indexed by key.
+func keyDistances(dist map[*Vertex]int64) map[string]int64 {
+	byKey := make(map[string]int64, len(dist))
+	for v, d := range dist {
+		byKey[v.key] = d
+	}
+	return byKey
+}
+
+// isLandmark returns whether the vertex with the specified key is a landmark.
+func (l *Landmarks) isLandmark(key string) bool {
+	for _, landmark := range l.keys {
+		if landmark == key {
+			return true
+		}
+	}
+	return false
+}
+
+// Keys returns the sorted keys of the landmarks.
+func (l *Landmarks) Keys() []string {
+	keys := append([]string(nil), l.keys...)
+	sort.Strings(keys)
+	return keys
+}
+
+// Heuristic returns a lower bound of the distance from the vertex with key to the vertex with endKey, using the triangle inequality: for every landmark L, the distance is at least d(L, end) - d(L, key) and d(key, L) - d(end, L).
+// The bound never overestimates the distance as long as the graph hasn't changed since the landmarks were selected, so A* still finds shortest paths with it. It is 0 for unknown keys.
+func (l *Landmarks) Heuristic(key, endKey string) int {
+	var bound int64
+
+	for i := range l.keys {
+		if dv, ok := l.from[i][key]; ok {
+			if dt, ok := l.from[i][endKey]; ok && dt-dv > bound {
+				bound = dt - dv
+			}
+		}
+
+		if dv, ok := l.to[i][key]; ok {
+			if dt, ok := l.to[i][endKey]; ok && dv-dt > bound {
+				bound = dv - dt
+			}
+		}
+	}
+
+	if !fitsInt(bound) {
+		return maxInt
+	}
+
+	return int(bound)
+}
@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestLandmarks(t *testing.T) {
+	g := New()
+
+	r := rand.New(rand.NewSource(2))
+	n := 40
+	for i := 0; i < n; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+	for i := 0; i < 3*n; i++ {
+		g.Connect(strconv.Itoa(r.Intn(n)), strconv.Itoa(r.Intn(n)), r.Intn(20))
+	}
+
+	l, err := g.SelectLandmarks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(l.Keys()) != 4 {
+		t.Fatalf("expected 4 landmarks, got %v", l.Keys())
+	}
+
+	cost := func(path []string) int {
+		sum := 0
+		for i := 1; i < len(path); i++ {
+			_, weight := g.IsConnected(path[i-1], path[i])
+			sum += weight
+		}
+		return sum
+	}
+
+	informed := 0
+	for i := 0; i < n; i++ {
+		for j := 0; j < n; j++ {
+			start, end := strconv.Itoa(i), strconv.Itoa(j)
+
+			expected, err := g.ShortestPath(start, end)
+			path, altErr := g.ShortestPath(start, end, WithHeuristic(l.Heuristic))
+			if err != altErr {
+				t.Fatalf("%s → %s: expected error %v, got %v", start, end, err, altErr)
+			}
+			if err != nil {
+				continue
+			}
+
+			// the heuristic never overestimates
+			if h := l.Heuristic(start, end); h > cost(expected) {
+				t.Fatalf("%s → %s: heuristic %d exceeds distance %d", start, end, h, cost(expected))
+			} else if h > 0 {
+				informed++
+			}
+
+			if cost(path) != cost(expected) {
+				t.Fatalf("%s → %s: expected a path of cost %d, got %v", start, end, cost(expected), path)
+			}
+		}
+	}
+
+	if informed == 0 {
+		t.Error("expected the heuristic to give some non-trivial bounds")
+	}
+
+	if l.Heuristic("x", "0") != 0 {
+		t.Fail()
+	}
+
+	if l, _ = g.SelectLandmarks(2 * n); len(l.Keys()) != n {
+		t.Fail()
+	}
+
+	g.Connect("0", "1", -1)
+	if _, err = g.SelectLandmarks(1); err == nil {
+		t.Error("expected an error for a negative weight")
+	}
+}
@@ -0,0 +1,146 @@
+package graph
+
+// LowestCommonAncestors returns the sorted keys of all lowest common ancestors of the vertices with keys aKey and bKey in a DAG: the vertices from which both can be reached, and from which no other such vertex can be reached. Every vertex counts as its own ancestor, so if a can reach b, the result is just a.
+// Unlike in trees, there may be several lowest common ancestors, e.g. two packages which both depend on the same pair of libraries. The result is empty if the vertices have no common ancestor.
+// Returns ErrInvalidKey if one of the keys is invalid and ErrCycle if the graph isn't a DAG. See BuildLCAIndex for repeated queries.
+func (g *Graph) LowestCommonAncestors(aKey, bKey string) ([]string, error) {
+	defer g.track("LowestCommonAncestors")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	a := g.get(aKey)
+	b := g.get(bKey)
+
+	if a == nil || b == nil {
+		return nil, ErrInvalidKey
+	}
+
+	if _, err := g.topologicalOrder(); err != nil {
+		return nil, err
+	}
+
+	common := g.breadthFirst([]*Vertex{a}, true, nil)
+	fromB := g.breadthFirst([]*Vertex{b}, true, nil)
+	for v := range common {
+		if !fromB[v] {
+			delete(common, v)
+		}
+	}
+
+	// the common ancestors are closed under taking ancestors, so one is lowest if none of its children is one
+	lowest := map[*Vertex]bool{}
+	for v := range common {
+		isLowest := true
+		for child := range v.GetOutgoing() {
+			if common[child] {
+				isLowest = false
+				break
+			}
+		}
+
+		if isLowest {
+			lowest[v] = true
+		}
+	}
+
+	return sortedVertexKeys(lowest), nil
+}
+
+// LCAIndex answers lowest common ancestor queries on a DAG without traversing it, see BuildLCAIndex.
+type LCAIndex struct {
+	keys      []string
+	index     map[string]int
+	children  [][]int
+	ancestors [][]uint64 // bit set of the ancestors of each vertex, including itself
+}
+
+// BuildLCAIndex preprocesses the graph, which must be a DAG, for repeated LowestCommonAncestors queries, e.g. on large dependency graphs: it stores the set of ancestors of every vertex, using |V|²/8 bytes of memory.
+// The index is a snapshot, it doesn't reflect changes made to the graph afterwards. Returns ErrCycle if the graph isn't a DAG.
+func (g *Graph) BuildLCAIndex() (*LCAIndex, error) {
+	defer g.track("BuildLCAIndex")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	order, err := g.topologicalOrder()
+	if err != nil {
+		return nil, err
+	}
+
+	// vertices are numbered in key order, so results can be collected sorted
+	keys := append([]string(nil), g.sortedKeys()...)
+	idx := &LCAIndex{
+		keys:      keys,
+		index:     make(map[string]int, len(keys)),
+		children:  make([][]int, len(keys)),
+		ancestors: make([][]uint64, len(keys)),
+	}
+	for i, key := range keys {
+		idx.index[key] = i
+	}
+
+	words := (len(keys) + 63) / 64
+
+	// parents come first in topological order, so their sets are complete when they are merged
+	for _, v := range order {
+		i := idx.index[v.key]
+
+		set := make([]uint64, words)
+		set[i/64] |= 1 << uint(i%64)
+		for parent := range v.GetIncoming() {
+			for w, bits := range idx.ancestors[idx.index[parent.key]] {
+				set[w] |= bits
+			}
+		}
+		idx.ancestors[i] = set
+
+		for child := range v.GetOutgoing() {
+			idx.children[i] = append(idx.children[i], idx.index[child.key])
+		}
+	}
+
+	return idx, nil
+}
+
+// LowestCommonAncestors returns the sorted keys of all lowest common ancestors of the vertices with keys aKey and bKey at the time the index was built, see Graph.LowestCommonAncestors. Returns ErrInvalidKey if one of the keys is not in the index.
+func (idx *LCAIndex) LowestCommonAncestors(aKey, bKey string) ([]string, error) {
+	a, ok := idx.index[aKey]
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+	b, ok := idx.index[bKey]
+	if !ok {
+		return nil, ErrInvalidKey
+	}
+
+	common := make([]uint64, len(idx.ancestors[a]))
+	for w := range common {
+		common[w] = idx.ancestors[a][w] & idx.ancestors[b][w]
+	}
+
+	has := func(i int) bool {
+		return common[i/64]&(1<<uint(i%64)) != 0
+	}
+
+	lowest := []string{}
+	for i := range idx.keys {
+		if !has(i) {
+			continue
+		}
+
+		isLowest := true
+		for _, child := range idx.children[i] {
+			if has(child) {
+				isLowest = false
+				break
+			}
+		}
+
+		if isLowest {
+			lowest = append(lowest, idx.keys[i])
+		}
+	}
+
+	return lowest, nil
+}
@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLowestCommonAncestors(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f", "x"} {
+		g.Set(key, nil)
+	}
+
+	// a → c, a → d, b → c, b → d, c → e, d → f, x isolated: c and d have two lowest common ancestors
+	g.Connect("a", "c", 1)
+	g.Connect("a", "d", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("b", "d", 1)
+	g.Connect("c", "e", 1)
+	g.Connect("d", "f", 1)
+
+	idx, err := g.BuildLCAIndex()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for _, c := range []struct {
+		a, b     string
+		expected []string
+	}{
+		{"c", "d", []string{"a", "b"}},
+		{"e", "f", []string{"a", "b"}},
+		{"a", "e", []string{"a"}},
+		{"e", "c", []string{"c"}},
+		{"d", "d", []string{"d"}},
+		{"a", "b", []string{}},
+		{"e", "x", []string{}},
+	} {
+		lcas, err := g.LowestCommonAncestors(c.a, c.b)
+		if err != nil || !reflect.DeepEqual(lcas, c.expected) {
+			t.Errorf("%s, %s: expected %v, got %v (%v)", c.a, c.b, c.expected, lcas, err)
+		}
+
+		lcas, err = idx.LowestCommonAncestors(c.a, c.b)
+		if err != nil || !reflect.DeepEqual(lcas, c.expected) {
+			t.Errorf("index %s, %s: expected %v, got %v (%v)", c.a, c.b, c.expected, lcas, err)
+		}
+	}
+
+	if _, err := g.LowestCommonAncestors("a", "y"); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, err := idx.LowestCommonAncestors("y", "a"); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey from index, got %v", err)
+	}
+
+	g.Connect("e", "a", 1)
+
+	if _, err := g.LowestCommonAncestors("c", "d"); err != ErrCycle {
+		t.Errorf("expected ErrCycle, got %v", err)
+	}
+	if _, err := g.BuildLCAIndex(); err != ErrCycle {
+		t.Errorf("expected ErrCycle from BuildLCAIndex, got %v", err)
+	}
+}
@@ -0,0 +1,155 @@
+package graph
+
+import (
+	"sort"
+)
+
+// Matrix is a square matrix of float64 values exported from a graph. It implements the Dims and At methods of gonum's mat.Matrix; use mat.NewDense(n, n, m.Data()) for a DenseMatrix or DoNonZero for a SparseMatrix to convert it.
+type Matrix interface {
+	Dims() (r, c int)
+	At(i, j int) float64
+}
+
+// MatrixFormat selects how AdjacencyMatrix and Laplacian store the matrix.
+type MatrixFormat int
+
+const (
+	// Dense stores all entries in a DenseMatrix, which needs O(|V|²) memory.
+	Dense MatrixFormat = iota
+
+	// Sparse stores only non-zero entries in a SparseMatrix, which needs O(|V| + |E|) memory.
+	Sparse
+)
+
+// DenseMatrix is a Matrix storing all entries in row-major order.
+type DenseMatrix struct {
+	n    int
+	data []float64
+}
+
+// Dims returns the number of rows and columns.
+func (m *DenseMatrix) Dims() (r, c int) {
+	return m.n, m.n
+}
+
+// At returns the entry in row i and column j.
+func (m *DenseMatrix) At(i, j int) float64 {
+	return m.data[i*m.n+j]
+}
+
+// Data returns the entries in row-major order, as expected by gonum's mat.NewDense.
+func (m *DenseMatrix) Data() []float64 {
+	return m.data
+}
+
+// SparseMatrix is a Matrix storing only non-zero entries in compressed sparse row format.
+type SparseMatrix struct {
+	n       int
+	rows    []int // index of the first entry of each row in columns and values, plus the total number of entries
+	columns []int
+	values  []float64
+}
+
+// Dims returns the number of rows and columns.
+func (m *SparseMatrix) Dims() (r, c int) {
+	return m.n, m.n
+}
+
+// At returns the entry in row i and column j.
+func (m *SparseMatrix) At(i, j int) float64 {
+	columns := m.columns[m.rows[i]:m.rows[i+1]]
+	if k := sort.SearchInts(columns, j); k < len(columns) && columns[k] == j {
+		return m.values[m.rows[i]+k]
+	}
+	return 0
+}
+
+// DoNonZero calls fn for every non-zero entry in row-major order, like the method of gonum's sparse matrices.
+func (m *SparseMatrix) DoNonZero(fn func(i, j int, v float64)) {
+	for i := 0; i < m.n; i++ {
+		for k := m.rows[i]; k < m.rows[i+1]; k++ {
+			fn(i, m.columns[k], m.values[k])
+		}
+	}
+}
+
+// AdjacencyMatrix returns the weighted adjacency matrix of the graph in the specified format, along with the keys of the vertices in the order of the rows and columns, which is sorted.
+// The entry in row i and column j is the weight of the edge from the i-th to the j-th vertex, or 0 if there is none.
+func (g *Graph) AdjacencyMatrix(format MatrixFormat) (Matrix, []string) {
+	defer g.track("AdjacencyMatrix")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.matrix(format, false)
+}
+
+// Laplacian returns the weighted Laplacian matrix L = D - A of the graph in the specified format, along with the keys of the vertices in the order of the rows and columns, which is sorted.
+// A is the adjacency matrix and D the diagonal matrix of the vertices' weighted out-degrees, i.e. the sums of the weights of their outgoing edges. For graphs with edges in both directions of equal weight, this is the usual symmetric Laplacian.
+func (g *Graph) Laplacian(format MatrixFormat) (Matrix, []string) {
+	defer g.track("Laplacian")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.matrix(format, true)
+}
+
+// matrix is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It builds the adjacency matrix, or the Laplacian if laplacian is true.
+func (g *Graph) matrix(format MatrixFormat, laplacian bool) (Matrix, []string) {
+	keys := append([]string(nil), g.sortedKeys()...)
+
+	index := make(map[*Vertex]int, len(keys))
+	for i, key := range keys {
+		index[g.vertices[key]] = i
+	}
+
+	m := &SparseMatrix{n: len(keys), rows: make([]int, 0, len(keys)+1)}
+
+	for i, key := range keys {
+		m.rows = append(m.rows, len(m.columns))
+
+		var columns []int
+		row := map[int]float64{}
+		selfLoop := false
+		for neighbor, weight := range g.vertices[key].GetOutgoing() {
+			j := index[neighbor]
+			columns = append(columns, j)
+			selfLoop = selfLoop || j == i
+
+			if laplacian {
+				// a self-loop adds to the degree as much as it subtracts from the adjacency, so its diagonal entry stays the same
+				row[j] -= float64(weight)
+				row[i] += float64(weight)
+			} else {
+				row[j] = float64(weight)
+			}
+		}
+
+		// the Laplacian has a diagonal entry even without a self-loop
+		if _, ok := row[i]; ok && !selfLoop {
+			columns = append(columns, i)
+		}
+		sort.Ints(columns)
+
+		for _, j := range columns {
+			if row[j] != 0 {
+				m.columns = append(m.columns, j)
+				m.values = append(m.values, row[j])
+			}
+		}
+	}
+	m.rows = append(m.rows, len(m.columns))
+
+	if format == Sparse {
+		return m, keys
+	}
+
+	dense := &DenseMatrix{m.n, make([]float64, m.n*m.n)}
+	m.DoNonZero(func(i, j int, v float64) {
+		dense.data[i*m.n+j] = v
+	})
+
+	return dense, keys
+}
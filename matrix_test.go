@@ -0,0 +1,73 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAdjacencyMatrix(t *testing.T) {
+	g := New()
+	for _, key := range []string{"c", "a", "b"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("a", "b", 2)
+	g.Connect("b", "a", 2)
+	g.Connect("b", "c", 3)
+	g.Connect("c", "a", 1)
+
+	expected := [][]float64{
+		{0, 2, 0},
+		{2, 0, 3},
+		{1, 0, 0},
+	}
+	expectedLaplacian := [][]float64{
+		{2, -2, 0},
+		{-2, 5, -3},
+		{-1, 0, 1},
+	}
+
+	toSlices := func(m Matrix) [][]float64 {
+		r, c := m.Dims()
+		rows := make([][]float64, r)
+		for i := range rows {
+			rows[i] = make([]float64, c)
+			for j := range rows[i] {
+				rows[i][j] = m.At(i, j)
+			}
+		}
+		return rows
+	}
+
+	for _, format := range []MatrixFormat{Dense, Sparse} {
+		m, keys := g.AdjacencyMatrix(format)
+		if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+			t.Fatalf("unexpected key order %v", keys)
+		}
+		if rows := toSlices(m); !reflect.DeepEqual(rows, expected) {
+			t.Errorf("format %d: expected adjacency matrix %v, got %v", format, expected, rows)
+		}
+
+		l, _ := g.Laplacian(format)
+		if rows := toSlices(l); !reflect.DeepEqual(rows, expectedLaplacian) {
+			t.Errorf("format %d: expected Laplacian %v, got %v", format, expectedLaplacian, rows)
+		}
+	}
+
+	m, _ := g.AdjacencyMatrix(Dense)
+	if data := m.(*DenseMatrix).Data(); !reflect.DeepEqual(data, []float64{0, 2, 0, 2, 0, 3, 1, 0, 0}) {
+		t.Errorf("unexpected data %v", data)
+	}
+
+	m, _ = g.AdjacencyMatrix(Sparse)
+	n := 0
+	m.(*SparseMatrix).DoNonZero(func(i, j int, v float64) {
+		if v != expected[i][j] {
+			t.Errorf("unexpected entry %v at %d, %d", v, i, j)
+		}
+		n++
+	})
+	if n != 4 {
+		t.Errorf("expected 4 non-zero entries, got %d", n)
+	}
+}
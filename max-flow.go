@@ -0,0 +1,135 @@
+package graph
+
+// flowEdge is an edge of the residual network used by MaxFlow.
+type flowEdge struct {
+	to       int // index of the vertex the edge leads to
+	reverse  int // index of the reverse edge in the adjacency list of to
+	capacity int // remaining capacity
+	original int // capacity of the edge in the graph; 0 for reverse edges
+}
+
+// MaxFlow computes a maximum flow from the vertex with key sourceKey to the vertex with key sinkKey using Dinic's algorithm, using the edge weights as capacities (negative weights are treated as 0).
+// It returns the value of the flow and the flow assigned to every edge carrying any, as from key → to key → flow. Returns ErrInvalidKey if one of the keys is invalid.
+func (g *Graph) MaxFlow(sourceKey, sinkKey string) (value int, flow map[string]map[string]int, err error) {
+	defer g.track("MaxFlow")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	source := g.get(sourceKey)
+	sink := g.get(sinkKey)
+
+	if source == nil || sink == nil {
+		return 0, nil, ErrInvalidKey
+	}
+
+	flow = map[string]map[string]int{}
+	if source == sink {
+		return
+	}
+
+	// number the vertices and build the residual network
+	vertices := make([]*Vertex, 0, len(g.vertices))
+	index := map[*Vertex]int{}
+	for _, v := range g.vertices {
+		index[v] = len(vertices)
+		vertices = append(vertices, v)
+	}
+
+	adjacency := make([][]flowEdge, len(vertices))
+	for i, v := range vertices {
+		for neighbor, capacity := range v.GetOutgoing() {
+			if capacity < 0 {
+				capacity = 0
+			}
+
+			j := index[neighbor]
+			adjacency[i] = append(adjacency[i], flowEdge{j, len(adjacency[j]), capacity, capacity})
+			adjacency[j] = append(adjacency[j], flowEdge{i, len(adjacency[i]) - 1, 0, 0})
+		}
+	}
+
+	s, t := index[source], index[sink]
+	level := make([]int, len(vertices))
+	next := make([]int, len(vertices)) // next edge to try per vertex in the current phase
+
+	// bfs builds the level graph and returns true if the sink is still reachable
+	bfs := func() bool {
+		for i := range level {
+			level[i] = -1
+		}
+		level[s] = 0
+
+		queue := []int{s}
+		for len(queue) > 0 {
+			u := queue[0]
+			queue = queue[1:]
+
+			for _, e := range adjacency[u] {
+				if e.capacity > 0 && level[e.to] < 0 {
+					level[e.to] = level[u] + 1
+					queue = append(queue, e.to)
+				}
+			}
+		}
+
+		return level[t] >= 0
+	}
+
+	// dfs pushes up to limit units of flow from u to the sink along the level graph
+	var dfs func(u, limit int) int
+	dfs = func(u, limit int) int {
+		if u == t {
+			return limit
+		}
+
+		for ; next[u] < len(adjacency[u]); next[u]++ {
+			e := &adjacency[u][next[u]]
+			if e.capacity <= 0 || level[e.to] != level[u]+1 {
+				continue
+			}
+
+			pushed := limit
+			if e.capacity < pushed {
+				pushed = e.capacity
+			}
+
+			if pushed = dfs(e.to, pushed); pushed > 0 {
+				e.capacity -= pushed
+				adjacency[e.to][e.reverse].capacity += pushed
+				return pushed
+			}
+		}
+
+		return 0
+	}
+
+	for bfs() {
+		for i := range next {
+			next[i] = 0
+		}
+
+		for {
+			pushed := dfs(s, int(^uint(0)>>1))
+			if pushed == 0 {
+				break
+			}
+			value += pushed
+		}
+	}
+
+	// collect the flow on the original edges
+	for i, edges := range adjacency {
+		for _, e := range edges {
+			if e.original > 0 && e.original > e.capacity {
+				from, to := vertices[i].key, vertices[e.to].key
+				if flow[from] == nil {
+					flow[from] = map[string]int{}
+				}
+				flow[from][to] = e.original - e.capacity
+			}
+		}
+	}
+
+	return
+}
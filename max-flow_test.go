@@ -0,0 +1,68 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestMaxFlow(t *testing.T) {
+	g := New()
+
+	for _, key := range []string{"s", "a", "b", "c", "d", "t"} {
+		g.Set(key, nil)
+	}
+
+	// classic example with a maximum flow of 23
+	g.Connect("s", "a", 16)
+	g.Connect("s", "b", 13)
+	g.Connect("a", "b", 10)
+	g.Connect("b", "a", 4)
+	g.Connect("a", "c", 12)
+	g.Connect("c", "b", 9)
+	g.Connect("b", "d", 14)
+	g.Connect("d", "c", 7)
+	g.Connect("c", "t", 20)
+	g.Connect("d", "t", 4)
+
+	value, flow, err := g.MaxFlow("s", "t")
+	if err != nil || value != 23 {
+		t.Fatalf("expected flow of 23, got %d (%v)", value, err)
+	}
+
+	// flow is conserved at every inner vertex and respects capacities
+	balance := map[string]int{}
+	for from, neighbors := range flow {
+		for to, f := range neighbors {
+			if _, capacity := g.IsConnected(from, to); f > capacity || f <= 0 {
+				t.Errorf("invalid flow %d on %s → %s", f, from, to)
+			}
+			balance[from] -= f
+			balance[to] += f
+		}
+	}
+
+	for key, b := range balance {
+		switch key {
+		case "s":
+			if b != -23 {
+				t.Fail()
+			}
+		case "t":
+			if b != 23 {
+				t.Fail()
+			}
+		default:
+			if b != 0 {
+				t.Errorf("flow not conserved at %s", key)
+			}
+		}
+	}
+
+	// no path
+	if value, _, _ := g.MaxFlow("t", "s"); value != 0 {
+		t.Fail()
+	}
+
+	if _, _, err := g.MaxFlow("s", "x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
@@ -0,0 +1,75 @@
+package graph
+
+// ShortestPathMaxHops returns the cheapest path from the vertex with key startKey to the vertex with key endKey which uses at most maxHops edges, in start → end order, e.g. for network protocols bounding path lengths.
+// It runs maxHops rounds of the Bellman-Ford algorithm, taking O(maxHops·|E|) time. Weights must not be negative, or the path may visit vertices more than once.
+// Returns ErrInvalidKey if one of the keys is invalid and ErrNoPath if there is no path with at most maxHops edges.
+func (g *Graph) ShortestPathMaxHops(startKey, endKey string, maxHops int) ([]string, error) {
+	defer g.track("ShortestPathMaxHops")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(startKey)
+	end := g.get(endKey)
+
+	if start == nil || end == nil {
+		return nil, ErrInvalidKey
+	}
+
+	// dist holds the costs of the cheapest paths found so far; improved[h] the predecessors of the vertices whose cost improved using h+1 edges
+	dist := map[*Vertex]int64{start: 0}
+	var improved []map[*Vertex]*Vertex
+
+	frontier := map[*Vertex]bool{start: true}
+	for hop := 0; hop < maxHops && len(frontier) > 0; hop++ {
+		next := map[*Vertex]int64{}
+		prev := map[*Vertex]*Vertex{}
+
+		// only vertices whose cost improved in the last round can improve their neighbors
+		for v := range frontier {
+			for neighbor, weight := range v.GetOutgoing() {
+				if addOverflows(dist[v], int64(weight)) {
+					continue
+				}
+				d := dist[v] + int64(weight)
+
+				if known, ok := dist[neighbor]; ok && known <= d {
+					continue
+				}
+				if known, ok := next[neighbor]; ok && known <= d {
+					continue
+				}
+
+				next[neighbor] = d
+				prev[neighbor] = v
+			}
+		}
+
+		frontier = map[*Vertex]bool{}
+		for v, d := range next {
+			dist[v] = d
+			frontier[v] = true
+		}
+		improved = append(improved, prev)
+	}
+
+	if _, ok := dist[end]; !ok {
+		return nil, ErrNoPath
+	}
+
+	// walk back through the rounds, taking a step whenever the vertex was improved in that round
+	path := []string{end.key}
+	for v, hop := end, len(improved)-1; hop >= 0; hop-- {
+		if p, ok := improved[hop][v]; ok {
+			v = p
+			path = append(path, v.key)
+		}
+	}
+
+	// reverse into start → end order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, nil
+}
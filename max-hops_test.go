@@ -0,0 +1,44 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathMaxHops(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c → d → e costs 4, a → d → e costs 11, a → e costs 20
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 1)
+	g.Connect("a", "d", 10)
+	g.Connect("a", "e", 20)
+	g.Connect("b", "a", 1)
+
+	for hops, expected := range map[int][]string{
+		1:  {"a", "e"},
+		2:  {"a", "d", "e"},
+		3:  {"a", "d", "e"},
+		4:  {"a", "b", "c", "d", "e"},
+		10: {"a", "b", "c", "d", "e"},
+	} {
+		if path, err := g.ShortestPathMaxHops("a", "e", hops); err != nil || !reflect.DeepEqual(path, expected) {
+			t.Errorf("%d hops: expected %v, got %v (%v)", hops, expected, path, err)
+		}
+	}
+
+	if path, err := g.ShortestPathMaxHops("a", "a", 0); err != nil || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Fail()
+	}
+	if _, err := g.ShortestPathMaxHops("b", "e", 1); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+	if _, err := g.ShortestPathMaxHops("a", "x", 1); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
@@ -0,0 +1,152 @@
+package graph
+
+// WeightPolicy resolves the weight of an edge when merging or splitting vertices produces two edges between the same vertices. It is passed the weight of the edge already there and of the edge being added.
+type WeightPolicy func(existing, added int) int
+
+var (
+	// MinWeight keeps the smaller weight, so no shortest path gets longer.
+	MinWeight WeightPolicy = func(existing, added int) int {
+		if added < existing {
+			return added
+		}
+		return existing
+	}
+
+	// MaxWeight keeps the larger weight.
+	MaxWeight WeightPolicy = func(existing, added int) int {
+		if added > existing {
+			return added
+		}
+		return existing
+	}
+
+	// SumWeights adds up the weights, e.g. for edges counting interactions.
+	SumWeights WeightPolicy = func(existing, added int) int {
+		return existing + added
+	}
+)
+
+// MergeOptions configures MergeVerticesWith.
+type MergeOptions struct {
+	// Weights resolves conflicting edges. If it is nil, MinWeight is used.
+	Weights WeightPolicy
+
+	// Value computes the value of the merged vertex from the values of the vertex merged into and of one vertex merged from; it is called once for each vertex merged from, in order. If it is nil, the value of the vertex merged into is kept.
+	Value func(into, from interface{}) interface{}
+}
+
+// MergeVertices merges the vertices with the keys in from into the vertex with key into, keeping into's value and the smaller weight of conflicting edges. See MergeVerticesWith.
+func (g *Graph) MergeVertices(into string, from ...string) error {
+	defer g.track("MergeVertices")()
+
+	return g.mergeVertices(MergeOptions{}, into, from)
+}
+
+// MergeVerticesWith merges the vertices with the keys in from into the vertex with key into, e.g. to clean up duplicates after a fuzzy import: all edges of the merged vertices are re-pointed to into with their attributes, their values, tags and labels are merged into it, and they are deleted.
+// Edges between the merged vertices are dropped, since they would become self-loops. The merge is atomic; it fails without changing the graph with ErrInvalidKey if one of the keys is invalid, ErrProtected if a vertex merged from is protected, and ErrDuplicateValue if the merged value is rejected by a unique index.
+func (g *Graph) MergeVerticesWith(opts MergeOptions, into string, from ...string) error {
+	defer g.track("MergeVerticesWith")()
+
+	return g.mergeVertices(opts, into, from)
+}
+
+// mergeVertices implements MergeVerticesWith. It locks the graph.
+func (g *Graph) mergeVertices(opts MergeOptions, into string, from []string) error {
+	if opts.Weights == nil {
+		opts.Weights = MinWeight
+	}
+
+	g.Lock()
+	defer g.Unlock()
+
+	target := g.get(into)
+	if target == nil {
+		return ErrInvalidKey
+	}
+
+	// validate everything before changing anything
+	merged := map[*Vertex]bool{target: true}
+	var sources []*Vertex
+
+	value := target.Value()
+	for _, key := range from {
+		v := g.get(key)
+		if v == nil {
+			return ErrInvalidKey
+		}
+		if merged[v] {
+			continue
+		}
+		if g.isProtected(v) {
+			return ErrProtected
+		}
+
+		merged[v] = true
+		sources = append(sources, v)
+
+		if opts.Value != nil {
+			value = opts.Value(value, v.Value())
+		}
+	}
+
+	unique := g.unique.copy()
+	for _, v := range sources {
+		unique.remove(v.key)
+	}
+	if !unique.set(into, value) {
+		return ErrDuplicateValue
+	}
+
+	for _, v := range sources {
+		for neighbor, weight := range v.GetOutgoing() {
+			if !merged[neighbor] {
+				v.RLock()
+				attrs := v.getEdgeAttrs(neighbor)
+				v.RUnlock()
+
+				g.connectResolved(target, neighbor, weight, attrs, opts.Weights)
+			}
+		}
+		for neighbor, weight := range v.GetIncoming() {
+			if !merged[neighbor] {
+				neighbor.RLock()
+				attrs := neighbor.getEdgeAttrs(v)
+				neighbor.RUnlock()
+
+				g.connectResolved(neighbor, target, weight, attrs, opts.Weights)
+			}
+		}
+
+		g.tags.addAll(target, v)
+		g.labels.addAll(target, v)
+
+		g.remove(v)
+	}
+
+	g.unique.set(into, value)
+	target.Lock()
+	target.value = value
+	target.Unlock()
+
+	g.emit(Event{Type: EventSet, Key: into, Value: value})
+
+	return nil
+}
+
+// connectResolved is an internal function, does NOT lock the graph, should only be used in between Lock() and Unlock().
+// It connects fromV to toV, resolving the weight with policy if they are connected already, and adds the attributes attrs, keeping those the edge has already.
+func (g *Graph) connectResolved(fromV, toV *Vertex, weight int, attrs map[string]interface{}, policy WeightPolicy) {
+	fromV.RLock()
+	existing, ok := fromV.outgoingEdges[toV]
+	for name := range fromV.edgeAttrs[toV] {
+		delete(attrs, name)
+	}
+	fromV.RUnlock()
+
+	if ok {
+		weight = policy(existing, weight)
+	}
+
+	g.connect(fromV, toV, weight)
+	g.setEdgeAttrs(fromV, toV, attrs)
+}
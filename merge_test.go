@@ -0,0 +1,117 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestMergeVertices(t *testing.T) {
+	g := New()
+	for _, key := range []string{"alice", "Alice", "a.", "x", "y"} {
+		g.Set(key, []string{key})
+	}
+
+	g.Connect("alice", "x", 5)
+	g.Connect("Alice", "x", 3)
+	g.Connect("a.", "y", 1)
+	g.Connect("y", "Alice", 2)
+	g.Connect("Alice", "alice", 1)
+	g.Tag("a.", "imported")
+
+	err := g.MergeVerticesWith(MergeOptions{
+		Weights: SumWeights,
+		Value: func(into, from interface{}) interface{} {
+			return append(into.([]string), from.([]string)...)
+		},
+	}, "alice", "Alice", "a.", "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if g.Len() != 3 {
+		t.Fatalf("expected 3 vertices, got %d", g.Len())
+	}
+
+	v, _ := g.Get("alice")
+	if names := v.Value().([]string); len(names) != 3 || names[1] != "Alice" || names[2] != "a." {
+		t.Errorf("unexpected value %v", names)
+	}
+
+	expected := map[[2]string]int{{"alice", "x"}: 8, {"alice", "y"}: 1, {"y", "alice"}: 2}
+	if g.Stats().Edges != len(expected) {
+		t.Errorf("expected %d edges, got %d", len(expected), g.Stats().Edges)
+	}
+	for edge, weight := range expected {
+		if ok, w := g.IsConnected(edge[0], edge[1]); !ok || w != weight {
+			t.Errorf("expected edge %v with weight %d, got %d", edge, weight, w)
+		}
+	}
+
+	if !g.HasTag("alice", "imported") {
+		t.Fail()
+	}
+}
+
+func TestMergeVerticesAtomic(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Set("c", 3)
+	g.Connect("b", "c", 1)
+	g.Protect("c")
+
+	if err := g.MergeVertices("a", "b", "missing"); err != ErrInvalidKey {
+		t.Fail()
+	}
+	if err := g.MergeVertices("a", "b", "c"); err != ErrProtected {
+		t.Fail()
+	}
+	if g.Len() != 3 {
+		t.Fatal("failed merge changed the graph")
+	}
+
+	// the default keeps the value merged into and the smaller weight
+	g.Connect("a", "c", 5)
+	if err := g.MergeVertices("a", "b"); err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := g.Get("a"); v.Value() != 1 {
+		t.Fail()
+	}
+	if _, w := g.IsConnected("a", "c"); w != 1 {
+		t.Fail()
+	}
+}
+
+func TestMergeVerticesEdgeAttrs(t *testing.T) {
+	g := New()
+	for _, key := range []string{"alice", "Alice", "x", "y"} {
+		g.Set(key, key)
+	}
+	g.Connect("alice", "x", 1)
+	g.Connect("Alice", "x", 2)
+	g.Connect("y", "Alice", 3)
+	g.SetEdgeAttr("alice", "x", "since", 2019)
+	g.SetEdgeAttr("Alice", "x", "since", 2021)
+	g.SetEdgeAttr("Alice", "x", "source", "import")
+	g.SetEdgeAttr("y", "Alice", "label", "knows")
+
+	r := NewReplica(g)
+	defer r.Close()
+
+	if err := g.MergeVertices("alice", "Alice"); err != nil {
+		t.Fatal(err)
+	}
+	r.Sync()
+
+	for _, h := range []*Graph{g, r.Graph()} {
+		// both edges existed, so their attributes are merged, keeping those of the edge merged into
+		e, err := h.GetEdge("alice", "x")
+		if err != nil || !reflect.DeepEqual(e.Attrs, map[string]interface{}{"since": 2019, "source": "import"}) {
+			t.Errorf("unexpected merged edge %v, %v", e, err)
+		}
+		if e, err = h.GetEdge("y", "alice"); err != nil || e.Attrs["label"] != "knows" {
+			t.Errorf("expected the re-pointed edge's attributes to be kept, got %v, %v", e, err)
+		}
+	}
+}
@@ -4,8 +4,8 @@ package graph
 type Item struct {
 	v                 *Vertex // vertex this meta data belongs to
 	prev              *Vertex // previous waypoint in the shortest path from start to here
-	distanceFromStart int     // distance form start to this vertex using the shortest known path
-	priority          int     // The priority of the item in the queue (= estimated distance from end vertex). Low value means high priority.
+	distanceFromStart int64   // distance form start to this vertex using the shortest known path; int64, so sums of large int weights don't overflow on 32-bit platforms
+	priority          int64   // The priority of the item in the queue (= estimated distance from end vertex). Low value means high priority.
 	index             int     // The index of the item in the heap. You do not need to set this, it's done automatically in Push(). DO NOT CHANGE!
 }
 
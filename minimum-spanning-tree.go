@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"sort"
+)
+
+// MinimumSpanningTree computes a minimum spanning tree of the graph using Kruskal's algorithm, treating every edge as undirected. If vertices are connected in both directions, the lighter edge is used.
+// It returns a new graph containing all vertices (with their values) and only the edges of the tree, in their original direction, together with the tree's total weight.
+// If the graph is not weakly connected, the result is a minimum spanning forest with one tree per component.
+func (g *Graph) MinimumSpanningTree() (mst *Graph, weight int) {
+	defer g.track("MinimumSpanningTree")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	type edge struct {
+		from, to *Vertex
+		weight   int
+	}
+
+	// collect undirected edges, keeping the lighter direction
+	lightest := map[[2]*Vertex]edge{}
+	for _, v := range g.vertices {
+		for neighbor, w := range v.GetOutgoing() {
+			pair := [2]*Vertex{v, neighbor}
+			if v.key > neighbor.key {
+				pair = [2]*Vertex{neighbor, v}
+			}
+
+			if e, ok := lightest[pair]; !ok || w < e.weight || (w == e.weight && v.key < e.from.key) {
+				lightest[pair] = edge{v, neighbor, w}
+			}
+		}
+	}
+
+	edges := make([]edge, 0, len(lightest))
+	for _, e := range lightest {
+		edges = append(edges, e)
+	}
+
+	// sort by weight, breaking ties by keys for a deterministic result
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].weight != edges[j].weight {
+			return edges[i].weight < edges[j].weight
+		}
+		if edges[i].from.key != edges[j].from.key {
+			return edges[i].from.key < edges[j].from.key
+		}
+		return edges[i].to.key < edges[j].to.key
+	})
+
+	mst = New()
+	for key, v := range g.vertices {
+		mst.vertices[key] = newVertex(key, v.Value())
+	}
+
+	components := newDisjointSet()
+	for _, e := range edges {
+		if !components.union(e.from, e.to) {
+			// edge would close a cycle
+			continue
+		}
+
+		from, to := mst.vertices[e.from.key], mst.vertices[e.to.key]
+		from.outgoingEdges[to] = e.weight
+		to.incomingEdges[from] = e.weight
+
+		weight += e.weight
+	}
+
+	return
+}
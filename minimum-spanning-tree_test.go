@@ -0,0 +1,64 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestMinimumSpanningTree(t *testing.T) {
+	g := New()
+
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Set("c", 3)
+	g.Set("d", 4)
+	g.Set("e", 5)
+
+	g.Connect("a", "b", 4)
+	g.Connect("b", "a", 1) // lighter direction is used
+	g.Connect("a", "c", 3)
+	g.Connect("c", "b", 2)
+	g.Connect("c", "d", 5)
+	g.Connect("b", "d", 7)
+
+	mst, weight := g.MinimumSpanningTree()
+
+	// b→a, c→b, c→d; e is isolated
+	if weight != 8 {
+		t.Errorf("expected weight 8, got %d", weight)
+	}
+
+	if mst.Len() != 5 {
+		t.Fail()
+	}
+
+	if ok, w := mst.IsConnected("b", "a"); !ok || w != 1 {
+		t.Fail()
+	}
+	if ok, w := mst.IsConnected("c", "b"); !ok || w != 2 {
+		t.Fail()
+	}
+	if ok, w := mst.IsConnected("c", "d"); !ok || w != 5 {
+		t.Fail()
+	}
+	if ok, _ := mst.IsConnected("a", "b"); ok {
+		t.Fail()
+	}
+	if ok, _ := mst.IsConnected("a", "c"); ok {
+		t.Fail()
+	}
+
+	// values are copied
+	if v, _ := mst.Get("e"); v.Value() != 5 {
+		t.Fail()
+	}
+
+	// original graph is unchanged
+	if ok, _ := g.IsConnected("b", "d"); !ok {
+		t.Fail()
+	}
+
+	// forest for disconnected graphs
+	if len(mst.WeaklyConnectedComponents()) != 2 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !darwin && !dragonfly && !freebsd && !linux && !netbsd && !openbsd && !solaris
+// +build !darwin,!dragonfly,!freebsd,!linux,!netbsd,!openbsd,!solaris
+
+package graph
+
+import "io/ioutil"
+
+// mmapFile reads the file at path into memory, on systems without mmap support.
+func mmapFile(path string) ([]byte, error) {
+	return ioutil.ReadFile(path)
+}
+
+// munmap releases data returned by mmapFile, which is left to the garbage collector.
+func munmap(data []byte) error {
+	return nil
+}
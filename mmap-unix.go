@@ -0,0 +1,39 @@
+//go:build darwin || dragonfly || freebsd || linux || netbsd || openbsd || solaris
+// +build darwin dragonfly freebsd linux netbsd openbsd solaris
+
+package graph
+
+import (
+	"os"
+	"syscall"
+)
+
+// mmapFile maps the file at path into memory read-only.
+func mmapFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	// empty files can't be mapped, and don't hold a graph
+	size := info.Size()
+	if size == 0 {
+		return nil, ErrInvalidFlatBuffer
+	}
+	if int64(int(size)) != size {
+		return nil, syscall.EFBIG
+	}
+
+	return syscall.Mmap(int(f.Fd()), 0, int(size), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+// munmap unmaps data returned by mmapFile.
+func munmap(data []byte) error {
+	return syscall.Munmap(data)
+}
@@ -0,0 +1,387 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// MarshalMsgpack encodes the graph in MessagePack, with the same logical schema as GobEncode but readable from any language: a map with a "vertices" map from keys to values, and an "edges" map from keys to maps from neighbor keys to weights.
+// Values may be nil, booleans, integers, floats, strings, []byte, []interface{} and map[string]interface{} of these; other types fail with an error. Maps are written with sorted keys, so the output is deterministic.
+func (g *Graph) MarshalMsgpack() ([]byte, error) {
+	defer g.track("MarshalMsgpack")()
+
+	g.RLock()
+
+	vertices := make(map[string]interface{}, len(g.vertices))
+	edges := make(map[string]interface{}, len(g.vertices))
+
+	for key, v := range g.vertices {
+		vertices[key] = v.Value()
+
+		neighbors := map[string]interface{}{}
+		for neighbor, weight := range v.GetOutgoing() {
+			neighbors[neighbor.key] = weight
+		}
+		edges[key] = neighbors
+	}
+
+	g.RUnlock()
+
+	enc := &msgpackEncoder{}
+	if err := enc.encode(map[string]interface{}{"vertices": vertices, "edges": edges}); err != nil {
+		return nil, err
+	}
+
+	return enc.buf, nil
+}
+
+// UnmarshalMsgpack decodes the format written by MarshalMsgpack into the graph's vertices and edges, merging them with existing ones.
+// Integers are decoded as int64 (or uint64 if they don't fit), floats as float64, binary data as []byte, arrays as []interface{} and maps as map[string]interface{}. All edges with invalid endpoints are reported by an *ImportError. If an edge connects a vertex to itself, self-loops are enabled, see EnableSelfLoops.
+func (g *Graph) UnmarshalMsgpack(b []byte) error {
+	defer g.track("UnmarshalMsgpack")()
+
+	dec := &msgpackDecoder{buf: b}
+	root, err := dec.decode()
+	if err == nil && dec.pos < len(b) {
+		err = fmt.Errorf("trailing data")
+	}
+	if err != nil {
+		return fmt.Errorf("graph: decoding MessagePack: %v", err)
+	}
+
+	object, ok := root.(map[string]interface{})
+	if !ok {
+		return fmt.Errorf("graph: decoding MessagePack: expected a map")
+	}
+	vertices, ok1 := object["vertices"].(map[string]interface{})
+	edges, ok2 := object["edges"].(map[string]interface{})
+	if (!ok1 && object["vertices"] != nil) || (!ok2 && object["edges"] != nil) {
+		return fmt.Errorf("graph: decoding MessagePack: vertices and edges must be maps")
+	}
+
+	im := g.NewImporter()
+	im.EnableSelfLoops = true
+
+	for key, value := range vertices {
+		if _, err := im.TrySet(key, value); err != nil {
+			return fmt.Errorf("graph: decoding MessagePack: %v: %q", err, key)
+		}
+	}
+
+	for key, neighbors := range edges {
+		neighborMap, ok := neighbors.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("graph: decoding MessagePack: edges of %q must be a map", key)
+		}
+
+		for otherKey, weight := range neighborMap {
+			w, ok := weight.(int64)
+			if !ok || int64(int(w)) != w {
+				return fmt.Errorf("graph: decoding MessagePack: invalid weight %v of edge %q → %q", weight, key, otherKey)
+			}
+			im.connect("msgpack", key, otherKey, int(w))
+		}
+	}
+
+	return im.Finalize()
+}
+
+// msgpackEncoder appends MessagePack encoded values to buf.
+type msgpackEncoder struct {
+	buf []byte
+}
+
+// encode appends v, using the smallest representation for integers and lengths.
+func (e *msgpackEncoder) encode(v interface{}) error {
+	switch v := v.(type) {
+	case nil:
+		e.buf = append(e.buf, 0xc0)
+	case bool:
+		if v {
+			e.buf = append(e.buf, 0xc3)
+		} else {
+			e.buf = append(e.buf, 0xc2)
+		}
+	case int:
+		e.encodeInt(int64(v))
+	case int8:
+		e.encodeInt(int64(v))
+	case int16:
+		e.encodeInt(int64(v))
+	case int32:
+		e.encodeInt(int64(v))
+	case int64:
+		e.encodeInt(v)
+	case uint:
+		e.encodeUint(uint64(v))
+	case uint8:
+		e.encodeUint(uint64(v))
+	case uint16:
+		e.encodeUint(uint64(v))
+	case uint32:
+		e.encodeUint(uint64(v))
+	case uint64:
+		e.encodeUint(v)
+	case float32:
+		e.buf = append(e.buf, 0xca)
+		e.buf = appendBigEndian(e.buf, uint64(math.Float32bits(v)), 4)
+	case float64:
+		e.buf = append(e.buf, 0xcb)
+		e.buf = appendBigEndian(e.buf, math.Float64bits(v), 8)
+	case string:
+		e.encodeLength(len(v), 0xa0, 32, 0xd9, 0xda, 0xdb)
+		e.buf = append(e.buf, v...)
+	case []byte:
+		e.encodeLength(len(v), 0, 0, 0xc4, 0xc5, 0xc6)
+		e.buf = append(e.buf, v...)
+	case []interface{}:
+		e.encodeLength(len(v), 0x90, 16, 0, 0xdc, 0xdd)
+		for _, item := range v {
+			if err := e.encode(item); err != nil {
+				return err
+			}
+		}
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		e.encodeLength(len(v), 0x80, 16, 0, 0xde, 0xdf)
+		for _, key := range keys {
+			e.encode(key)
+			if err := e.encode(v[key]); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("graph: encoding MessagePack: unsupported type %T", v)
+	}
+
+	return nil
+}
+
+// encodeInt appends a signed integer.
+func (e *msgpackEncoder) encodeInt(i int64) {
+	switch {
+	case i >= 0:
+		e.encodeUint(uint64(i))
+	case i >= -32:
+		e.buf = append(e.buf, byte(i))
+	case i >= math.MinInt8:
+		e.buf = append(e.buf, 0xd0, byte(i))
+	case i >= math.MinInt16:
+		e.buf = append(e.buf, 0xd1)
+		e.buf = appendBigEndian(e.buf, uint64(i), 2)
+	case i >= math.MinInt32:
+		e.buf = append(e.buf, 0xd2)
+		e.buf = appendBigEndian(e.buf, uint64(i), 4)
+	default:
+		e.buf = append(e.buf, 0xd3)
+		e.buf = appendBigEndian(e.buf, uint64(i), 8)
+	}
+}
+
+// encodeUint appends an unsigned integer.
+func (e *msgpackEncoder) encodeUint(u uint64) {
+	switch {
+	case u < 128:
+		e.buf = append(e.buf, byte(u))
+	case u <= math.MaxUint8:
+		e.buf = append(e.buf, 0xcc, byte(u))
+	case u <= math.MaxUint16:
+		e.buf = append(e.buf, 0xcd)
+		e.buf = appendBigEndian(e.buf, u, 2)
+	case u <= math.MaxUint32:
+		e.buf = append(e.buf, 0xce)
+		e.buf = appendBigEndian(e.buf, u, 4)
+	default:
+		e.buf = append(e.buf, 0xcf)
+		e.buf = appendBigEndian(e.buf, u, 8)
+	}
+}
+
+// encodeLength appends the header of a string, binary, array or map of length n: a fixed format (fix | n) if n < fixLimit, otherwise the format with an 8, 16 or 32 bit length. Formats which don't exist for a type are 0.
+func (e *msgpackEncoder) encodeLength(n int, fix byte, fixLimit int, f8, f16, f32 byte) {
+	switch {
+	case n < fixLimit:
+		e.buf = append(e.buf, fix|byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		e.buf = append(e.buf, f8, byte(n))
+	case n <= math.MaxUint16:
+		e.buf = append(e.buf, f16)
+		e.buf = appendBigEndian(e.buf, uint64(n), 2)
+	default:
+		e.buf = append(e.buf, f32)
+		e.buf = appendBigEndian(e.buf, uint64(n), 4)
+	}
+}
+
+// msgpackDecoder decodes MessagePack values from buf, starting at pos.
+type msgpackDecoder struct {
+	buf []byte
+	pos int
+}
+
+// read returns the next n bytes.
+func (d *msgpackDecoder) read(n int) ([]byte, error) {
+	if n < 0 || n > len(d.buf)-d.pos {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+	b := d.buf[d.pos : d.pos+n]
+	d.pos += n
+	return b, nil
+}
+
+// readUint reads a big-endian unsigned integer of size bytes.
+func (d *msgpackDecoder) readUint(size int) (uint64, error) {
+	b, err := d.read(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var u uint64
+	for _, c := range b {
+		u = u<<8 | uint64(c)
+	}
+	return u, nil
+}
+
+// decode decodes the next value.
+func (d *msgpackDecoder) decode() (interface{}, error) {
+	b, err := d.read(1)
+	if err != nil {
+		return nil, err
+	}
+	c := b[0]
+
+	switch {
+	case c <= 0x7f:
+		return int64(c), nil
+	case c >= 0xe0:
+		return int64(int8(c)), nil
+	case c&0xf0 == 0x80:
+		return d.decodeMap(int(c & 0x0f))
+	case c&0xf0 == 0x90:
+		return d.decodeArray(int(c & 0x0f))
+	case c&0xe0 == 0xa0:
+		s, err := d.read(int(c & 0x1f))
+		return string(s), err
+	}
+
+	switch c {
+	case 0xc0:
+		return nil, nil
+	case 0xc2:
+		return false, nil
+	case 0xc3:
+		return true, nil
+
+	case 0xc4, 0xc5, 0xc6:
+		n, err := d.readUint(1 << (c - 0xc4))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.read(int(n))
+		return append([]byte(nil), s...), err
+
+	case 0xca:
+		u, err := d.readUint(4)
+		return float64(math.Float32frombits(uint32(u))), err
+	case 0xcb:
+		u, err := d.readUint(8)
+		return math.Float64frombits(u), err
+
+	case 0xcc, 0xcd, 0xce, 0xcf:
+		u, err := d.readUint(1 << (c - 0xcc))
+		if u > math.MaxInt64 {
+			return u, err
+		}
+		return int64(u), err
+
+	case 0xd0, 0xd1, 0xd2, 0xd3:
+		size := 1 << (c - 0xd0)
+		u, err := d.readUint(size)
+		// sign-extend
+		shift := uint(64 - 8*size)
+		return int64(u<<shift) >> shift, err
+
+	case 0xd9, 0xda, 0xdb:
+		n, err := d.readUint(1 << (c - 0xd9))
+		if err != nil {
+			return nil, err
+		}
+		s, err := d.read(int(n))
+		return string(s), err
+
+	case 0xdc, 0xdd:
+		n, err := d.readUint(2 << (c - 0xdc))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeArray(int(n))
+
+	case 0xde, 0xdf:
+		n, err := d.readUint(2 << (c - 0xde))
+		if err != nil {
+			return nil, err
+		}
+		return d.decodeMap(int(n))
+	}
+
+	return nil, fmt.Errorf("unsupported format 0x%02x", c)
+}
+
+// decodeArray decodes n values.
+func (d *msgpackDecoder) decodeArray(n int) ([]interface{}, error) {
+	// every value takes at least one byte
+	if n > len(d.buf)-d.pos {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+
+	items := make([]interface{}, n)
+	for i := range items {
+		item, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		items[i] = item
+	}
+
+	return items, nil
+}
+
+// decodeMap decodes n key-value pairs. Keys must be strings.
+func (d *msgpackDecoder) decodeMap(n int) (map[string]interface{}, error) {
+	if n > len(d.buf)-d.pos {
+		return nil, fmt.Errorf("unexpected end of data")
+	}
+
+	m := make(map[string]interface{}, n)
+	for i := 0; i < n; i++ {
+		key, err := d.decode()
+		if err != nil {
+			return nil, err
+		}
+		s, ok := key.(string)
+		if !ok {
+			return nil, fmt.Errorf("unsupported map key %v", key)
+		}
+
+		if m[s], err = d.decode(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
+
+// appendBigEndian appends the size lowest bytes of u to buf, most significant first.
+func appendBigEndian(buf []byte, u uint64, size int) []byte {
+	for shift := 8 * (size - 1); shift >= 0; shift -= 8 {
+		buf = append(buf, byte(u>>uint(shift)))
+	}
+	return buf
+}
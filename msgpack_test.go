@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestMsgpackRoundTrip(t *testing.T) {
+	values := map[string]interface{}{
+		"nil":    nil,
+		"bool":   true,
+		"small":  -5,
+		"int8":   int8(-100),
+		"int16":  -1000,
+		"int32":  -100000,
+		"int64":  int64(math.MinInt64),
+		"uint8":  200,
+		"uint16": 60000,
+		"uint32": uint32(4000000000),
+		"uint64": uint64(math.MaxUint64),
+		"float":  1.5,
+		"string": strings.Repeat("x", 300),
+		"bytes":  []byte{1, 2, 3},
+		"list":   []interface{}{"a", 1},
+		"map":    map[string]interface{}{"nested": false},
+	}
+
+	g := New()
+	for key, value := range values {
+		g.Set(key, value)
+	}
+	g.Connect("nil", "bool", -70000)
+	g.Connect("bool", "nil", 3)
+	g.Connect("small", "map", 1)
+
+	b, err := g.MarshalMsgpack()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	again, _ := g.MarshalMsgpack()
+	if !bytes.Equal(b, again) {
+		t.Error("expected deterministic output")
+	}
+
+	read := New()
+	if err := read.UnmarshalMsgpack(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// integers are decoded as int64, unless they only fit into uint64
+	expected := map[string]interface{}{
+		"nil":    nil,
+		"bool":   true,
+		"small":  int64(-5),
+		"int8":   int64(-100),
+		"int16":  int64(-1000),
+		"int32":  int64(-100000),
+		"int64":  int64(math.MinInt64),
+		"uint8":  int64(200),
+		"uint16": int64(60000),
+		"uint32": int64(4000000000),
+		"uint64": uint64(math.MaxUint64),
+		"float":  1.5,
+		"string": strings.Repeat("x", 300),
+		"bytes":  []byte{1, 2, 3},
+		"list":   []interface{}{"a", int64(1)},
+		"map":    map[string]interface{}{"nested": false},
+	}
+
+	for key, value := range expected {
+		v, err := read.Get(key)
+		if err != nil {
+			t.Fatalf("missing vertex %q", key)
+		}
+		if !reflect.DeepEqual(v.Value(), value) {
+			t.Errorf("%q: expected %#v, got %#v", key, value, v.Value())
+		}
+	}
+
+	for _, e := range []Edge{{From: "nil", To: "bool", Weight: -70000}, {From: "bool", To: "nil", Weight: 3}, {From: "small", To: "map", Weight: 1}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+}
+
+func TestUnmarshalMsgpack(t *testing.T) {
+	// {"vertices": {"a": 1, "b": nil}, "edges": {"a": {"b": 2}}}, as encoded by other implementations
+	b := []byte{
+		0x82,
+		0xa8, 'v', 'e', 'r', 't', 'i', 'c', 'e', 's', 0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0xc0,
+		0xa5, 'e', 'd', 'g', 'e', 's', 0x81, 0xa1, 'a', 0x81, 0xa1, 'b', 0x02,
+	}
+
+	g := New()
+	if err := g.UnmarshalMsgpack(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 2 {
+		t.Errorf("expected a → b with weight 2, got %v %d", ok, weight)
+	}
+
+	for _, invalid := range [][]byte{
+		b[:len(b)-1],
+		append(append([]byte(nil), b...), 0xc0),
+		{0x81, 0x01, 0xc0},
+		{0x81, 0xa5, 'e', 'd', 'g', 'e', 's', 0x81, 0xa1, 'a', 0x81, 0xa1, 'b', 0xc3},
+		{0xdd, 0xff, 0xff, 0xff, 0xff},
+		{0xd4, 0x01, 0x00},
+	} {
+		if err := New().UnmarshalMsgpack(invalid); err == nil {
+			t.Errorf("% x: expected an error", invalid)
+		}
+	}
+
+	if err := New().UnmarshalMsgpack([]byte{0x81, 0xa5, 'e', 'd', 'g', 'e', 's', 0x81, 0xa1, 'a', 0x81, 0xa1, 'b', 0x01}); err == nil {
+		t.Error("expected an import error for missing vertices")
+	}
+
+	g.Set("c", struct{}{})
+	if _, err := g.MarshalMsgpack(); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
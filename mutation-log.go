@@ -0,0 +1,346 @@
+package graph
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// mutationLogMagic starts mutation log files, followed by a version byte and the records. Every record is the length and CRC-32C checksum of its data as 4 byte big-endian integers, followed by the data: the type, keys, value and weight of an Event encoded in MessagePack, followed by the name of the attribute for events of edge attributes.
+const mutationLogMagic = "\x80gsl"
+
+// mutationLogVersion is the version of the mutation log format. Version 2 added the records of edge attributes.
+const mutationLogVersion = 2
+
+// MutationLog persists a graph by appending every mutation to a file, which is cheaper than writing snapshots for write-heavy graphs. Opening the log replays it, and a background compactor rewrites the log with the current contents of the graph when it grows too large, so restarts stay fast.
+// Records are written without syncing, so they survive crashes of the process, but not necessarily of the system, see Sync. A record torn by a crash is dropped when the log is opened.
+type MutationLog struct {
+	g          *Graph
+	path       string
+	file       *os.File
+	size       int64          // size of file
+	threshold  int64          // size compaction is started at
+	compacting bool           // true while a compaction is copying the graph
+	pending    []byte         // records appended while compacting, which must be copied to the new file
+	err        error          // first error writing the log
+	cancel     func()         // cancels the subscription to the graph's mutations
+	trigger    chan struct{}  // signals the compactor that the log exceeds the threshold
+	done       sync.WaitGroup // waits for the compactor to stop
+	sync.Mutex
+}
+
+// OpenMutationLog replays the mutation log at path into a new graph, creating the log if it doesn't exist, and appends all further mutations of the graph to it.
+// When the log exceeds threshold bytes and has grown to twice its size after the last compaction, it is compacted in the background: the graph is copied while it is locked, the copy is written to a new log, and the new log replaces the old one by renaming.
+// Values are encoded in MessagePack, so they are restricted to the types supported by MarshalMsgpack. Mutations which can't be written are reported by Err. If the log contains self-loops, they are enabled on the graph, see EnableSelfLoops.
+func OpenMutationLog(path string, threshold int64) (*Graph, *MutationLog, error) {
+	g := New()
+
+	b, err := ioutil.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, nil, err
+	}
+
+	valid := 0
+	if len(b) > 0 {
+		if valid, err = replayMutationLog(g, b); err != nil {
+			return nil, nil, err
+		}
+	} else {
+		header := append([]byte(mutationLogMagic), mutationLogVersion)
+		if err := writeFileAtomic(path, header); err != nil {
+			return nil, nil, err
+		}
+		valid = len(header)
+	}
+
+	file, err := os.OpenFile(path, os.O_RDWR, 0)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// drop a torn record at the end, so new records follow the last complete one
+	if valid < len(b) {
+		if err := file.Truncate(int64(valid)); err != nil {
+			file.Close()
+			return nil, nil, err
+		}
+	}
+	if _, err := file.Seek(int64(valid), 0); err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	l := &MutationLog{g: g, path: path, file: file, size: int64(valid), threshold: threshold, trigger: make(chan struct{}, 1)}
+	l.cancel = g.Subscribe(l.append)
+
+	l.done.Add(1)
+	go l.run(int64(valid))
+
+	return g, l, nil
+}
+
+// replayMutationLog applies the records of the log b to g and returns the length of the valid part of b, excluding a torn record at the end.
+func replayMutationLog(g *Graph, b []byte) (int, error) {
+	header := len(mutationLogMagic) + 1
+	if len(b) < header || string(b[:len(mutationLogMagic)]) != mutationLogMagic {
+		return 0, fmt.Errorf("graph: reading mutation log: not a mutation log")
+	}
+	if version := b[len(mutationLogMagic)]; version > mutationLogVersion {
+		return 0, fmt.Errorf("graph: reading mutation log: %w %d", ErrUnsupportedVersion, version)
+	}
+
+	pos := header
+	for pos+8 <= len(b) {
+		length, checksum := int(binary.BigEndian.Uint32(b[pos:])), binary.BigEndian.Uint32(b[pos+4:])
+		if length > len(b)-pos-8 || crc32.Checksum(b[pos+8:pos+8+length], snapshotCRCTable) != checksum {
+			break
+		}
+
+		e, err := decodeMutation(b[pos+8 : pos+8+length])
+		if err != nil {
+			return 0, fmt.Errorf("graph: reading mutation log: offset %d: %v", pos, err)
+		}
+
+		// self-loops are only logged if the graph allowed them
+		if e.Type == EventConnect && e.Key == e.ToKey {
+			g.selfLoops = true
+		}
+		g.apply(e)
+
+		pos += 8 + length
+	}
+
+	return pos, nil
+}
+
+// appendMutation appends the record of e to b.
+func appendMutation(b []byte, e Event) ([]byte, error) {
+	enc := &msgpackEncoder{}
+	enc.encodeInt(int64(e.Type))
+	enc.encode(e.Key)
+	enc.encode(e.ToKey)
+	if err := enc.encode(e.Value); err != nil {
+		if e.Type == EventSetEdgeAttr {
+			return b, fmt.Errorf("graph: writing mutation log: edge %q -> %q: attribute %q: %v", e.Key, e.ToKey, e.Attr, err)
+		}
+		return b, fmt.Errorf("graph: writing mutation log: vertex %q: %v", e.Key, err)
+	}
+	enc.encodeInt(int64(e.Weight))
+	if e.Type == EventSetEdgeAttr || e.Type == EventDeleteEdgeAttr {
+		enc.encode(e.Attr)
+	}
+
+	b = appendBigEndian(b, uint64(len(enc.buf)), 4)
+	b = appendBigEndian(b, uint64(crc32.Checksum(enc.buf, snapshotCRCTable)), 4)
+	return append(b, enc.buf...), nil
+}
+
+// decodeMutation decodes the data of a record.
+func decodeMutation(b []byte) (Event, error) {
+	var e Event
+	dec := &msgpackDecoder{buf: b}
+
+	fields := make([]interface{}, 5)
+	for i := range fields {
+		var err error
+		if fields[i], err = dec.decode(); err != nil {
+			return e, err
+		}
+	}
+
+	typ, ok1 := fields[0].(int64)
+	key, ok2 := fields[1].(string)
+	toKey, ok3 := fields[2].(string)
+	weight, ok4 := fields[4].(int64)
+	if !ok1 || !ok2 || !ok3 || !ok4 {
+		return e, errors.New("invalid record")
+	}
+	e = Event{Type: EventType(typ), Key: key, ToKey: toKey, Value: fields[3], Weight: int(weight)}
+
+	// events of edge attributes are followed by the name of the attribute
+	if e.Type == EventSetEdgeAttr || e.Type == EventDeleteEdgeAttr {
+		attr, err := dec.decode()
+		name, ok := attr.(string)
+		if err != nil || !ok {
+			return Event{}, errors.New("invalid record")
+		}
+		e.Attr = name
+	}
+	if dec.pos != len(b) {
+		return Event{}, errors.New("invalid record")
+	}
+
+	return e, nil
+}
+
+// append writes the record of e to the log. It is called while the graph is locked.
+func (l *MutationLog) append(e Event) {
+	l.Lock()
+	defer l.Unlock()
+
+	if l.err != nil {
+		return
+	}
+
+	b, err := appendMutation(nil, e)
+	if err == nil {
+		_, err = l.file.Write(b)
+	}
+	if err != nil {
+		l.err = err
+		return
+	}
+
+	l.size += int64(len(b))
+	if l.compacting {
+		l.pending = append(l.pending, b...)
+	}
+
+	if l.size > l.threshold {
+		select {
+		case l.trigger <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// run compacts the log when triggered, until the trigger channel is closed. base is the size of the log after the last compaction.
+func (l *MutationLog) run(base int64) {
+	defer l.done.Done()
+
+	for range l.trigger {
+		l.Lock()
+		grown := l.size >= 2*base && l.err == nil
+		l.Unlock()
+
+		if grown {
+			if size, err := l.compact(); err == nil {
+				base = size
+			}
+		}
+	}
+}
+
+// Compact rewrites the log with the current contents of the graph. It is called in the background automatically, see OpenMutationLog.
+func (l *MutationLog) Compact() error {
+	_, err := l.compact()
+	return err
+}
+
+// compact rewrites the log and returns its new size.
+func (l *MutationLog) compact() (int64, error) {
+	// copy the graph and start collecting the records appended meanwhile
+	l.g.Lock()
+	c := l.g.clone()
+	l.Lock()
+	if l.compacting {
+		l.Unlock()
+		l.g.Unlock()
+		return 0, errors.New("graph: mutation log is being compacted")
+	}
+	l.compacting = true
+	l.Unlock()
+	l.g.Unlock()
+
+	b, err := c.mutationLog()
+
+	l.Lock()
+	defer l.Unlock()
+
+	l.compacting = false
+	pending := l.pending
+	l.pending = nil
+
+	if err != nil {
+		return 0, err
+	}
+	if l.err != nil {
+		return 0, l.err
+	}
+
+	b = append(b, pending...)
+
+	tmp, err := ioutil.TempFile(filepath.Dir(l.path), ".tmp-")
+	if err != nil {
+		return 0, err
+	}
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Sync()
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), l.path)
+	}
+	if err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return 0, err
+	}
+
+	l.file.Close()
+	l.file = tmp
+	l.size = int64(len(b))
+
+	return l.size, nil
+}
+
+// mutationLog is an internal function, does NOT lock the graph, should only be used on graphs which aren't shared, e.g. a clone.
+// It returns a mutation log creating the graph's vertices and edges, including the edges' attributes.
+func (g *Graph) mutationLog() ([]byte, error) {
+	buf := append([]byte(mutationLogMagic), mutationLogVersion)
+
+	var err error
+	for _, key := range g.sortedKeys() {
+		if buf, err = appendMutation(buf, Event{Type: EventSet, Key: key, Value: g.vertices[key].value}); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, key := range g.sortedKeys() {
+		for neighbor, weight := range g.vertices[key].outgoingEdges {
+			if buf, err = appendMutation(buf, Event{Type: EventConnect, Key: key, ToKey: neighbor.key, Weight: weight}); err != nil {
+				return nil, err
+			}
+			for name, value := range g.vertices[key].edgeAttrs[neighbor] {
+				if buf, err = appendMutation(buf, Event{Type: EventSetEdgeAttr, Key: key, ToKey: neighbor.key, Attr: name, Value: value}); err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return buf, nil
+}
+
+// Err returns the first error writing a mutation to the log. The log should be considered out of sync with the graph from then on, and no more mutations are written.
+func (l *MutationLog) Err() error {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.err
+}
+
+// Sync commits the log to stable storage, so the mutations written so far survive crashes of the system.
+func (l *MutationLog) Sync() error {
+	l.Lock()
+	defer l.Unlock()
+
+	return l.file.Sync()
+}
+
+// Close stops logging the graph's mutations, waits for a running compaction and closes the log file. The graph stays usable.
+func (l *MutationLog) Close() error {
+	l.cancel()
+
+	close(l.trigger)
+	l.done.Wait()
+
+	l.Lock()
+	defer l.Unlock()
+
+	return l.file.Close()
+}
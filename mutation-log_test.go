@@ -0,0 +1,185 @@
+package graph
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestMutationLog(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mutationlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.log")
+
+	g, l, err := OpenMutationLog(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Set("a", 1)
+	g.Set("b", "two")
+	g.Set("c", nil)
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", -1)
+	g.Connect("c", "a", 2)
+	g.Disconnect("b", "c")
+	g.Delete("c")
+	g.Set("a", 10)
+
+	if err := l.Err(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// mutations after closing aren't logged
+	g.Set("d", nil)
+
+	// reopening replays the log
+	h, l, err := OpenMutationLog(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if h.Len() != 2 {
+		t.Errorf("expected 2 vertices, got %d", h.Len())
+	}
+	if v, _ := h.Get("a"); v.Value() != int64(10) {
+		t.Errorf("expected updated value, got %v", v.Value())
+	}
+	if ok, weight := h.IsConnected("a", "b"); !ok || weight != 5 {
+		t.Errorf("expected edge, got %v %d", ok, weight)
+	}
+
+	// compaction shrinks the log, keeping the contents
+	for i := 0; i < 100; i++ {
+		h.Set("a", i)
+	}
+	info, _ := os.Stat(path)
+	if err := l.Compact(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	h.Set("e", "after compaction")
+	compacted, _ := os.Stat(path)
+	if compacted.Size() >= info.Size() {
+		t.Errorf("expected compaction to shrink the log from %d bytes, got %d", info.Size(), compacted.Size())
+	}
+	l.Close()
+
+	h, l, err = OpenMutationLog(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer l.Close()
+	if v, _ := h.Get("a"); h.Len() != 3 || v.Value() != int64(99) {
+		t.Errorf("expected the compacted contents, got %d vertices and value %v", h.Len(), v.Value())
+	}
+	if ok, _ := h.IsConnected("a", "b"); !ok {
+		t.Error("expected edge after compaction")
+	}
+}
+
+func TestMutationLogBackgroundCompaction(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mutationlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.log")
+
+	g, l, err := OpenMutationLog(path, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// concurrent writers while the compactor runs
+	done := make(chan struct{})
+	for w := 0; w < 4; w++ {
+		go func(w int) {
+			for i := 0; i < 500; i++ {
+				g.Set(fmt.Sprint(w), i)
+			}
+			done <- struct{}{}
+		}(w)
+	}
+	for w := 0; w < 4; w++ {
+		<-done
+	}
+
+	// wait for the compactor to catch up
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		info, _ := os.Stat(path)
+		if info.Size() < 4*1024 || time.Now().After(deadline) {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	info, _ := os.Stat(path)
+	if info.Size() >= 4*1024 {
+		t.Errorf("expected the log to be compacted, got %d bytes", info.Size())
+	}
+
+	h, l, err := OpenMutationLog(path, 1024)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer l.Close()
+	for w := 0; w < 4; w++ {
+		if v, err := h.Get(fmt.Sprint(w)); err != nil || v.Value() != int64(499) {
+			t.Errorf("expected the last value of writer %d, got %v", w, v.Value())
+		}
+	}
+}
+
+func TestMutationLogTornRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "mutationlog")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.log")
+
+	g, l, err := OpenMutationLog(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Set("a", 1)
+	g.Set("b", 2)
+	l.Close()
+
+	// cut the last record in half, as if the process crashed while writing it
+	b, _ := ioutil.ReadFile(path)
+	ioutil.WriteFile(path, b[:len(b)-3], 0600)
+
+	g, l, err = OpenMutationLog(path, 1<<20)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if g.Len() != 1 {
+		t.Errorf("expected the torn record to be dropped, got %d vertices", g.Len())
+	}
+
+	// new records follow the last complete one
+	g.Set("c", 3)
+	l.Close()
+	g, l, _ = OpenMutationLog(path, 1<<20)
+	defer l.Close()
+	if _, err := g.Get("c"); err != nil || g.Len() != 2 {
+		t.Errorf("expected a and c, got %d vertices", g.Len())
+	}
+
+	ioutil.WriteFile(path, []byte("not a log"), 0600)
+	if _, _, err := OpenMutationLog(path, 1<<20); err == nil {
+		t.Error("expected an error for other files")
+	}
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"math"
+)
+
+// Metric selects how NeighborhoodSimilarity compares the neighborhoods of two vertices.
+type Metric int
+
+const (
+	// Cosine is the cosine similarity of the weighted out-neighbor vectors, between -1 and 1 (between 0 and 1 for positive weights).
+	Cosine Metric = iota
+
+	// DotProduct is the sum of the products of the weights of edges to common out-neighbors.
+	DotProduct
+
+	// Jaccard is the number of common out-neighbors divided by the number of out-neighbors of either vertex, ignoring weights.
+	Jaccard
+
+	// WeightedJaccard is the sum of the smaller weights divided by the sum of the larger weights of the edges to all out-neighbors of either vertex, where missing edges count as weight 0. Weights must not be negative.
+	WeightedJaccard
+)
+
+// NeighborhoodSimilarity compares the out-neighbors of the vertices with keys a and b: every vertex is described by a vector mapping its out-neighbors to the edge weights, and the vectors are compared using metric.
+// Vertices with similar neighborhoods are candidates for being the same entity, e.g. in entity resolution. Similarities involving a vertex without out-neighbors are 0. Returns ErrInvalidKey if one of the keys is invalid.
+func (g *Graph) NeighborhoodSimilarity(a, b string, metric Metric) (float64, error) {
+	defer g.track("NeighborhoodSimilarity")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	va, vb := g.get(a), g.get(b)
+	if va == nil || vb == nil {
+		return 0, ErrInvalidKey
+	}
+
+	x, y := va.GetOutgoing(), vb.GetOutgoing()
+	if len(x) == 0 || len(y) == 0 {
+		return 0, nil
+	}
+
+	switch metric {
+	case DotProduct:
+		return dotProduct(x, y), nil
+
+	case Jaccard:
+		common := 0
+		for v := range x {
+			if _, ok := y[v]; ok {
+				common++
+			}
+		}
+		return float64(common) / float64(len(x)+len(y)-common), nil
+
+	case WeightedJaccard:
+		var min, max float64
+		for v, wx := range x {
+			wy := y[v]
+			min += math.Min(float64(wx), float64(wy))
+			max += math.Max(float64(wx), float64(wy))
+		}
+		for v, wy := range y {
+			if _, ok := x[v]; !ok {
+				max += float64(wy)
+			}
+		}
+		if max == 0 {
+			return 0, nil
+		}
+		return min / max, nil
+	}
+
+	norm := math.Sqrt(dotProduct(x, x)) * math.Sqrt(dotProduct(y, y))
+	if norm == 0 {
+		return 0, nil
+	}
+
+	return dotProduct(x, y) / norm, nil
+}
+
+// dotProduct returns the dot product of two weighted neighbor vectors.
+func dotProduct(x, y map[*Vertex]int) float64 {
+	sum := 0.0
+	for v, wx := range x {
+		if wy, ok := y[v]; ok {
+			sum += float64(wx) * float64(wy)
+		}
+	}
+	return sum
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"math"
+	"testing"
+)
+
+func TestNeighborhoodSimilarity(t *testing.T) {
+	g := New()
+	for _, key := range []string{"alice", "a.smith", "bob", "x", "y", "z", "loner"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("alice", "x", 1)
+	g.Connect("alice", "y", 2)
+	g.Connect("a.smith", "x", 1)
+	g.Connect("a.smith", "y", 2)
+	g.Connect("a.smith", "z", 2)
+	g.Connect("bob", "z", 3)
+
+	expected := []struct {
+		a, b   string
+		metric Metric
+		value  float64
+	}{
+		{"alice", "a.smith", Cosine, 5 / (math.Sqrt(5) * 3)},
+		{"alice", "bob", Cosine, 0},
+		{"alice", "a.smith", DotProduct, 5},
+		{"a.smith", "bob", DotProduct, 6},
+		{"alice", "a.smith", Jaccard, 2.0 / 3},
+		{"a.smith", "bob", Jaccard, 1.0 / 3},
+		{"alice", "a.smith", WeightedJaccard, 3.0 / 5},
+		{"a.smith", "bob", WeightedJaccard, 2.0 / 6},
+		{"alice", "loner", Cosine, 0},
+	}
+
+	for _, e := range expected {
+		value, err := g.NeighborhoodSimilarity(e.a, e.b, e.metric)
+		if err != nil || math.Abs(value-e.value) > 1e-9 {
+			t.Errorf("%s, %s, metric %d: expected %f, got %f (%v)", e.a, e.b, e.metric, e.value, value, err)
+		}
+	}
+
+	if s, _ := g.NeighborhoodSimilarity("alice", "alice", Cosine); math.Abs(s-1) > 1e-9 {
+		t.Fail()
+	}
+	if _, err := g.NeighborhoodSimilarity("alice", "nobody", Jaccard); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"math"
+	"time"
+)
+
+// PageRank computes the PageRank of every vertex by running the given number of power iterations with the given damping factor (usually 0.85).
+// A random surfer follows each outgoing edge of a vertex with equal probability, regardless of the weights. The ranks of vertices without outgoing edges are distributed evenly over all vertices. The ranks sum up to 1.
+func (g *Graph) PageRank(damping float64, iterations int) map[string]float64 {
+	defer g.track("PageRank")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.pageRank(damping, iterations, func(from, to *Vertex) float64 {
+		return 1
+	})
+}
+
+// TemporalPageRank computes a PageRank in which recent edges count more than old ones, e.g. to find trending vertices in an interaction graph.
+// timestamp returns the time of the edge from fromKey to toKey. A random surfer follows an edge with a probability proportional to 0.5^(age/halfLife), where age is the time between the timestamp and now; edges from the future count as if they were created now.
+// Otherwise it behaves like PageRank. timestamp is called while the graph is locked, so it must not call any of the graph's methods.
+func (g *Graph) TemporalPageRank(damping float64, iterations int, now time.Time, halfLife time.Duration, timestamp func(fromKey, toKey string) time.Time) map[string]float64 {
+	defer g.track("TemporalPageRank")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.pageRank(damping, iterations, func(from, to *Vertex) float64 {
+		age := now.Sub(timestamp(from.key, to.key))
+		if age < 0 {
+			age = 0
+		}
+
+		return math.Exp2(-float64(age) / float64(halfLife))
+	})
+}
+
+// pageRank is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// The probability of following an edge is proportional to its weight as returned by weight.
+func (g *Graph) pageRank(damping float64, iterations int, weight func(from, to *Vertex) float64) map[string]float64 {
+	vertices, index := g.indexVertices()
+	n := float64(len(vertices))
+
+	// precompute the transition probabilities, since weight might be expensive
+	type transition struct {
+		to          int
+		probability float64
+	}
+	transitions := make([][]transition, len(vertices))
+
+	for i, v := range vertices {
+		total := 0.0
+		for neighbor := range v.GetOutgoing() {
+			w := weight(v, neighbor)
+			if w > 0 {
+				transitions[i] = append(transitions[i], transition{index[neighbor], w})
+				total += w
+			}
+		}
+
+		for j := range transitions[i] {
+			transitions[i][j].probability /= total
+		}
+	}
+
+	rank := make([]float64, len(vertices))
+	for i := range rank {
+		rank[i] = 1 / n
+	}
+
+	for iteration := 0; iteration < iterations; iteration++ {
+		// rank of vertices without (weighted) outgoing edges
+		dangling := 0.0
+		for i := range vertices {
+			if len(transitions[i]) == 0 {
+				dangling += rank[i]
+			}
+		}
+
+		next := make([]float64, len(vertices))
+		for i := range next {
+			next[i] = (1-damping)/n + damping*dangling/n
+		}
+
+		for i, ts := range transitions {
+			for _, t := range ts {
+				next[t.to] += damping * rank[i] * t.probability
+			}
+		}
+
+		rank = next
+	}
+
+	ranks := make(map[string]float64, len(vertices))
+	for i, v := range vertices {
+		ranks[v.key] = rank[i]
+	}
+
+	return ranks
+}
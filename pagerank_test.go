@@ -0,0 +1,72 @@
+package graph
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestPageRank(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+	g.Set("d", nil)
+
+	// everyone links to a, a links to b
+	g.Connect("b", "a", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("d", "a", 1)
+	g.Connect("a", "b", 1)
+
+	ranks := g.PageRank(0.85, 50)
+
+	sum := 0.0
+	for _, rank := range ranks {
+		sum += rank
+	}
+	if math.Abs(sum-1) > 1e-9 {
+		t.Errorf("ranks sum up to %f", sum)
+	}
+
+	if !(ranks["a"] > ranks["b"] && ranks["b"] > ranks["c"]) {
+		t.Errorf("unexpected ranks %v", ranks)
+	}
+	if math.Abs(ranks["c"]-ranks["d"]) > 1e-9 {
+		t.Fail()
+	}
+
+	if len(New().PageRank(0.85, 10)) != 0 {
+		t.Fail()
+	}
+}
+
+func TestTemporalPageRank(t *testing.T) {
+	g := New()
+	g.Set("hub", nil)
+	g.Set("old", nil)
+	g.Set("new", nil)
+
+	g.Connect("hub", "old", 1)
+	g.Connect("hub", "new", 1)
+
+	now := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+	timestamps := map[string]time.Time{
+		"old": now.Add(-30 * 24 * time.Hour),
+		"new": now.Add(-time.Hour),
+	}
+
+	ranks := g.TemporalPageRank(0.85, 50, now, 24*time.Hour, func(fromKey, toKey string) time.Time {
+		return timestamps[toKey]
+	})
+
+	if ranks["new"] <= ranks["old"] {
+		t.Errorf("expected the recent edge to count more: %v", ranks)
+	}
+
+	// without decay, both are equal
+	plain := g.PageRank(0.85, 50)
+	if math.Abs(plain["new"]-plain["old"]) > 1e-9 {
+		t.Fail()
+	}
+}
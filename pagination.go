@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"encoding/base64"
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrInvalidCursor is returned when a cursor passed to ListVertices is malformed.
+var ErrInvalidCursor = errors.New("graph: invalid cursor")
+
+// keyIndex holds the sorted keys of a graph's vertices. It is rebuilt lazily after vertices were created or deleted, unless it is maintained incrementally (see EnableOrderedIndex), and has its own lock, so it can be rebuilt by readers of the graph.
+type keyIndex struct {
+	sorted  []string
+	valid   bool
+	ordered bool // whether the index is updated on every change instead of being rebuilt
+	sync.Mutex
+}
+
+// invalidate marks the index as outdated. Does NOT lock the index; the graph must be locked for writing.
+func (k *keyIndex) invalidate() {
+	k.valid = false
+	k.sorted = nil
+}
+
+// insert records that a vertex with key was created. Does NOT lock the index; the graph must be locked for writing.
+func (k *keyIndex) insert(key string) {
+	if !k.ordered || !k.valid {
+		k.invalidate()
+		return
+	}
+
+	i := sort.SearchStrings(k.sorted, key)
+	k.sorted = append(k.sorted, "")
+	copy(k.sorted[i+1:], k.sorted[i:])
+	k.sorted[i] = key
+}
+
+// remove records that the vertex with key was deleted. Does NOT lock the index; the graph must be locked for writing.
+func (k *keyIndex) remove(key string) {
+	if !k.ordered || !k.valid {
+		k.invalidate()
+		return
+	}
+
+	if i := sort.SearchStrings(k.sorted, key); i < len(k.sorted) && k.sorted[i] == key {
+		k.sorted = append(k.sorted[:i], k.sorted[i+1:]...)
+	}
+}
+
+// sortedKeys is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the sorted keys of all vertices, which must not be modified.
+func (g *Graph) sortedKeys() []string {
+	g.keys.Lock()
+	defer g.keys.Unlock()
+
+	if !g.keys.valid {
+		sorted := make([]string, 0, len(g.vertices))
+		for key := range g.vertices {
+			sorted = append(sorted, key)
+		}
+		sort.Strings(sorted)
+
+		g.keys.sorted = sorted
+		g.keys.valid = true
+	}
+
+	return g.keys.sorted
+}
+
+// ListVertices returns up to limit vertices in key order, starting after the position described by cursor, and the cursor to pass to get the next page. The first page is requested with an empty cursor; an empty next cursor means there are no more vertices.
+// Cursors are opaque, URL-safe strings which stay valid while the graph is modified, so callers like HTTP handlers don't have to hold locks between requests: vertices created or deleted in the meantime are included or skipped according to their keys. Returns ErrInvalidCursor if the cursor is malformed.
+func (g *Graph) ListVertices(cursor string, limit int) (vertices []*Vertex, next string, err error) {
+	defer g.track("ListVertices")()
+
+	after, err := decodeCursor(cursor)
+	if err != nil {
+		return nil, "", err
+	}
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := g.sortedKeys()
+
+	i := 0
+	if cursor != "" {
+		i = sort.Search(len(keys), func(i int) bool { return keys[i] > after })
+	}
+
+	for ; i < len(keys) && len(vertices) < limit; i++ {
+		vertices = append(vertices, g.vertices[keys[i]])
+	}
+
+	if i < len(keys) && len(vertices) > 0 {
+		next = encodeCursor(vertices[len(vertices)-1].key)
+	}
+
+	return
+}
+
+// encodeCursor returns a cursor pointing behind the given key.
+func encodeCursor(key string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte("k" + key))
+}
+
+// decodeCursor returns the key a cursor points behind. The empty cursor points to the beginning.
+func decodeCursor(cursor string) (string, error) {
+	if cursor == "" {
+		return "", nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil || len(b) == 0 || b[0] != 'k' {
+		return "", ErrInvalidCursor
+	}
+
+	return string(b[1:]), nil
+}
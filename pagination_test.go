@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestListVertices(t *testing.T) {
+	g := New()
+	for i := 0; i < 25; i++ {
+		g.Set(fmt.Sprintf("%02d", i), i)
+	}
+
+	var keys []string
+	cursor := ""
+	pages := 0
+
+	for {
+		vertices, next, err := g.ListVertices(cursor, 10)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pages++
+
+		for _, v := range vertices {
+			keys = append(keys, v.Key())
+		}
+
+		// modifications between pages don't disturb the iteration
+		if pages == 1 {
+			g.Delete("05")
+			g.Delete("15")
+			g.Set("12a", nil)
+		}
+
+		if next == "" {
+			break
+		}
+		cursor = next
+	}
+
+	if pages != 3 || len(keys) != 25 {
+		t.Fatalf("unexpected %d pages with keys %v", pages, keys)
+	}
+	if keys[0] != "00" || keys[5] != "05" || keys[12] != "12" || keys[13] != "12a" || keys[14] != "13" || keys[16] != "16" {
+		t.Fatalf("unexpected keys %v", keys)
+	}
+
+	// empty graph
+	if vertices, next, _ := New().ListVertices("", 10); len(vertices) != 0 || next != "" {
+		t.Fail()
+	}
+
+	if _, _, err := g.ListVertices("not a cursor!", 10); err != ErrInvalidCursor {
+		t.Fail()
+	}
+}
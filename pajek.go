@@ -0,0 +1,254 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ReadPajek parses a graph in Pajek's .net format. Vertices are keyed by their labels, or by their numbers if they have none, and get nil values; the sections *Arcs and *Arcslist describe directed edges, *Edges and *Edgeslist undirected ones, which are connected in both directions.
+// Edges get their weights, which must be integers, or 1 if they have none. Coordinates and drawing attributes are ignored, as are lines starting with %.
+// If there are self-loops, they are enabled on the returned graph, see EnableSelfLoops. Returns an error if the data is malformed, labels are duplicated or an edge refers to an unknown vertex.
+func ReadPajek(r io.Reader) (*Graph, error) {
+	g := New()
+
+	var keys []string // vertex keys by number - 1
+	section := ""
+	line := 0
+
+	errorf := func(format string, args ...interface{}) error {
+		return fmt.Errorf("graph: reading Pajek: line %d: %s", line, fmt.Sprintf(format, args...))
+	}
+
+	// vertex returns the key of the vertex with the given number
+	vertex := func(field string) (string, error) {
+		n, err := strconv.Atoi(field)
+		if err != nil || n < 1 || n > len(keys) {
+			return "", errorf("invalid vertex %q", field)
+		}
+		return keys[n-1], nil
+	}
+
+	connect := func(from, to string, weight int) error {
+		if from == to {
+			g.selfLoops = true
+		}
+
+		g.Connect(from, to, weight)
+		if section == "*edges" || section == "*edgeslist" {
+			g.Connect(to, from, weight)
+		}
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(nil, 1<<24)
+
+	for scanner.Scan() {
+		line++
+
+		fields, err := pajekFields(scanner.Text())
+		if err != nil {
+			return nil, errorf("%v", err)
+		}
+		if len(fields) == 0 || strings.HasPrefix(fields[0], "%") {
+			continue
+		}
+
+		if strings.HasPrefix(fields[0], "*") {
+			section = strings.ToLower(fields[0])
+
+			switch section {
+			case "*vertices":
+				if keys != nil || len(fields) < 2 {
+					return nil, errorf("invalid *Vertices line")
+				}
+				n, err := strconv.Atoi(fields[1])
+				if err != nil || n < 0 {
+					return nil, errorf("invalid number of vertices %q", fields[1])
+				}
+
+				// vertices without a line of their own are keyed by their numbers
+				keys = make([]string, n)
+				for i := range keys {
+					keys[i] = strconv.Itoa(i + 1)
+				}
+
+			case "*arcs", "*edges", "*arcslist", "*edgeslist":
+				if keys == nil {
+					return nil, errorf("%s before *Vertices", fields[0])
+				}
+
+			default:
+				return nil, errorf("unsupported section %s", fields[0])
+			}
+			continue
+		}
+
+		switch section {
+		case "*vertices":
+			n, err := strconv.Atoi(fields[0])
+			if err != nil || n < 1 || n > len(keys) {
+				return nil, errorf("invalid vertex %q", fields[0])
+			}
+			if len(fields) > 1 {
+				keys[n-1] = fields[1]
+			}
+
+		case "*arcs", "*edges":
+			// all vertices are known once the edges start
+			if err := pajekSetVertices(g, keys); err != nil {
+				return nil, errorf("%v", err)
+			}
+
+			if len(fields) < 2 {
+				return nil, errorf("invalid edge")
+			}
+			from, err := vertex(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			to, err := vertex(fields[1])
+			if err != nil {
+				return nil, err
+			}
+
+			weight := 1
+			if len(fields) > 2 {
+				f, err := strconv.ParseFloat(fields[2], 64)
+				if err != nil || f != math.Trunc(f) {
+					return nil, errorf("invalid weight %q", fields[2])
+				}
+				weight = int(f)
+			}
+
+			if err := connect(from, to, weight); err != nil {
+				return nil, err
+			}
+
+		case "*arcslist", "*edgeslist":
+			if err := pajekSetVertices(g, keys); err != nil {
+				return nil, errorf("%v", err)
+			}
+
+			from, err := vertex(fields[0])
+			if err != nil {
+				return nil, err
+			}
+			for _, field := range fields[1:] {
+				to, err := vertex(field)
+				if err != nil {
+					return nil, err
+				}
+				if err := connect(from, to, 1); err != nil {
+					return nil, err
+				}
+			}
+
+		default:
+			return nil, errorf("data outside of a section")
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("graph: reading Pajek: %v", err)
+	}
+
+	if err := pajekSetVertices(g, keys); err != nil {
+		return nil, fmt.Errorf("graph: reading Pajek: %v", err)
+	}
+
+	return g, nil
+}
+
+// pajekSetVertices sets the vertices with the given keys in g, unless it already has vertices. Returns an error if a key is duplicated.
+func pajekSetVertices(g *Graph, keys []string) error {
+	if g.Len() > 0 || len(keys) == 0 {
+		return nil
+	}
+
+	for _, key := range keys {
+		if _, err := g.Get(key); err == nil {
+			return fmt.Errorf("duplicate vertex label %q", key)
+		}
+		g.Set(key, nil)
+	}
+
+	return nil
+}
+
+// pajekFields splits a line into whitespace-separated fields, keeping quoted labels together without the quotes.
+func pajekFields(line string) ([]string, error) {
+	var fields []string
+
+	for line = strings.TrimSpace(line); line != ""; line = strings.TrimSpace(line) {
+		if line[0] == '"' {
+			end := strings.IndexByte(line[1:], '"')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated label")
+			}
+			fields = append(fields, line[1:end+1])
+			line = line[end+2:]
+			continue
+		}
+
+		end := strings.IndexAny(line, " \t")
+		if end < 0 {
+			end = len(line)
+		}
+		fields = append(fields, line[:end])
+		line = line[end:]
+	}
+
+	return fields, nil
+}
+
+// WritePajek writes the graph to w in Pajek's .net format: vertices are numbered in key order and labeled with their keys, edges are written as *Arcs with their weights. Values are not written.
+// Returns an error without writing anything if a key contains a double quote or line break, which Pajek labels can't represent.
+func (g *Graph) WritePajek(w io.Writer) error {
+	defer g.track("WritePajek")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := g.sortedKeys()
+	for _, key := range keys {
+		if strings.ContainsAny(key, "\"\r\n") {
+			return fmt.Errorf("graph: writing Pajek: key %q can't be written as a label", key)
+		}
+	}
+
+	ids := make(map[*Vertex]int, len(keys))
+	for i, key := range keys {
+		ids[g.vertices[key]] = i + 1
+	}
+
+	bw := bufio.NewWriter(w)
+
+	fmt.Fprintf(bw, "*Vertices %d\n", len(keys))
+	for i, key := range keys {
+		fmt.Fprintf(bw, "%d \"%s\"\n", i+1, key)
+	}
+
+	bw.WriteString("*Arcs\n")
+	for i, key := range keys {
+		outgoing := g.vertices[key].GetOutgoing()
+
+		targets := make([]int, 0, len(outgoing))
+		weights := make(map[int]int, len(outgoing))
+		for neighbor, weight := range outgoing {
+			targets = append(targets, ids[neighbor])
+			weights[ids[neighbor]] = weight
+		}
+		sort.Ints(targets)
+
+		for _, target := range targets {
+			fmt.Fprintf(bw, "%d %d %d\n", i+1, target, weights[target])
+		}
+	}
+
+	return bw.Flush()
+}
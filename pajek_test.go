@@ -0,0 +1,96 @@
+package graph
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestPajekRoundTrip(t *testing.T) {
+	g := New()
+	for _, key := range []string{"b", "a", "c d"} {
+		g.Set(key, key)
+	}
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c d", -2)
+	g.Connect("c d", "a", 1)
+
+	buf := &bytes.Buffer{}
+	if err := g.WritePajek(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := "*Vertices 3\n1 \"a\"\n2 \"b\"\n3 \"c d\"\n*Arcs\n1 2 5\n2 3 -2\n3 1 1\n"
+	if buf.String() != expected {
+		t.Errorf("expected %q, got %q", expected, buf.String())
+	}
+
+	read, err := ReadPajek(buf)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if read.Len() != 3 {
+		t.Errorf("expected 3 vertices, got %d", read.Len())
+	}
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 5}, {From: "b", To: "c d", Weight: -2}, {From: "c d", To: "a", Weight: 1}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+
+	g.Set(`say "hi"`, nil)
+	if err := g.WritePajek(&bytes.Buffer{}); err == nil {
+		t.Error("expected an error for a key with quotes")
+	}
+}
+
+func TestReadPajek(t *testing.T) {
+	src := `% a benchmark graph
+*Vertices 5
+ 1 "x" 0.1 0.2 0.5 ic Red
+ 2 "y"
+ 4 "w"
+*Edges
+ 1 2 3.0
+*Arcslist
+ 4 1 2 5
+`
+
+	g, err := ReadPajek(strings.NewReader(src))
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if g.Len() != 5 {
+		t.Errorf("expected 5 vertices, got %d", g.Len())
+	}
+
+	for _, e := range []Edge{{From: "x", To: "y", Weight: 3}, {From: "y", To: "x", Weight: 3}, {From: "w", To: "x", Weight: 1}, {From: "w", To: "y", Weight: 1}, {From: "w", To: "5", Weight: 1}} {
+		if ok, weight := g.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+	if ok, _ := g.IsConnected("x", "w"); ok {
+		t.Error("unexpected edge x → w")
+	}
+	if _, err := g.Get("3"); err != nil {
+		t.Error("expected vertex 3 without a line to be keyed by its number")
+	}
+}
+
+func TestReadPajekErrors(t *testing.T) {
+	for _, src := range []string{
+		"*Arcs\n1 2\n",
+		"*Vertices 2\n*Arcs\n1 3\n",
+		"*Vertices 2\n*Arcs\n1 2 1.5\n",
+		"*Vertices 2\n1 \"a\"\n2 \"a\"\n",
+		"*Vertices 2\n1 \"a\n",
+		"*Matrix\n",
+		"1 2\n",
+	} {
+		if _, err := ReadPajek(strings.NewReader(src)); err == nil {
+			t.Errorf("%q: expected an error", src)
+		}
+	}
+}
@@ -0,0 +1,324 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"math"
+)
+
+// parquetRowGroupSize is the maximum number of rows of the row groups written by the Parquet exporters.
+const parquetRowGroupSize = 1 << 16
+
+// parquetMagic starts and ends Parquet files.
+const parquetMagic = "PAR1"
+
+// Constants of the Parquet format, see parquet.thrift in the Parquet repository.
+const (
+	parquetInt64     = 2
+	parquetByteArray = 6
+
+	parquetRequired = 0
+	parquetOptional = 1
+
+	parquetUTF8 = 0 // converted type
+
+	parquetPlain = 0
+	parquetRLE   = 3
+
+	parquetUncompressed = 0
+
+	parquetDataPage = 0
+)
+
+// Types of the Thrift compact protocol.
+const (
+	thriftI32    = 5
+	thriftI64    = 6
+	thriftBinary = 8
+	thriftList   = 9
+	thriftStruct = 12
+)
+
+// WriteParquetVertices writes the vertex table of the graph to w as a Parquet file, e.g. for DuckDB or Spark: a key column of strings and an optional value column of the values encoded as JSON strings, with nil values as nulls, in key order and in row groups of up to 65536 rows.
+// Pages are PLAIN encoded and uncompressed. Values are encoded with encoding/json, so they must be marshalable. The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) WriteParquetVertices(w io.Writer) error {
+	defer g.track("WriteParquetVertices")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	pw := newParquetWriter(w, vertexColumns())
+	if err := g.columnarVertices(parquetRowGroupSize, pw.rowGroup); err != nil {
+		return fmt.Errorf("graph: encoding Parquet: %v", err)
+	}
+
+	return pw.end()
+}
+
+// WriteParquetEdges writes the edge table of the graph to w as a Parquet file: from and to columns of strings and a weight column of 64 bit integers, sorted by keys and in row groups of up to 65536 rows.
+// Pages are PLAIN encoded and uncompressed. The graph is read-locked while writing, so it is written consistently.
+func (g *Graph) WriteParquetEdges(w io.Writer) error {
+	defer g.track("WriteParquetEdges")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	pw := newParquetWriter(w, edgeColumns())
+	if err := g.columnarEdges(parquetRowGroupSize, pw.rowGroup); err != nil {
+		return fmt.Errorf("graph: encoding Parquet: %v", err)
+	}
+
+	return pw.end()
+}
+
+// parquetChunk describes a column chunk written by parquetWriter.
+type parquetChunk struct {
+	offset, size int64 // position and size of the data page, including its header
+}
+
+// parquetRowGroup describes a row group written by parquetWriter.
+type parquetRowGroup struct {
+	rows   int64
+	chunks []parquetChunk
+}
+
+// parquetWriter writes a Parquet file with one data page per column chunk, keeping only the metadata for the footer.
+type parquetWriter struct {
+	w         *bufio.Writer
+	offset    int64
+	columns   []*columnarColumn
+	rowGroups []parquetRowGroup
+}
+
+func newParquetWriter(w io.Writer, columns []*columnarColumn) *parquetWriter {
+	pw := &parquetWriter{w: bufio.NewWriter(w), columns: columns}
+	pw.write([]byte(parquetMagic))
+	return pw
+}
+
+// write writes b, keeping track of the offset.
+func (pw *parquetWriter) write(b []byte) {
+	pw.w.Write(b)
+	pw.offset += int64(len(b))
+}
+
+// rowGroup writes a row group with the rows of columns.
+func (pw *parquetWriter) rowGroup(rows int, columns []*columnarColumn) error {
+	group := parquetRowGroup{rows: int64(rows)}
+
+	for _, c := range columns {
+		var data []byte
+
+		// definition levels of optional columns: 1 for values, 0 for nulls, bit-packed in groups of 8 and prefixed by their length
+		if c.nullable {
+			levels := appendProtoVarint(nil, uint64((rows+7)/8)<<1|1)
+			levels = append(levels, make([]byte, (rows+7)/8)...)
+			header := len(levels) - (rows+7)/8
+			for i, valid := range c.valid {
+				if valid {
+					levels[header+i/8] |= 1 << uint(i%8)
+				}
+			}
+			data = appendLittleEndian(data, uint64(len(levels)), 4)
+			data = append(data, levels...)
+		}
+
+		if c.integer {
+			for _, i := range c.ints {
+				data = appendLittleEndian(data, uint64(i), 8)
+			}
+		} else {
+			for i, s := range c.strings {
+				if c.valid == nil || c.valid[i] {
+					data = appendLittleEndian(data, uint64(len(s)), 4)
+					data = append(data, s...)
+				}
+			}
+		}
+
+		if len(data) > math.MaxInt32 {
+			return fmt.Errorf("column %q exceeds 2 GiB in a row group", c.name)
+		}
+
+		tw := newThriftWriter()
+		tw.i32(1, parquetDataPage)
+		tw.i32(2, int32(len(data))) // uncompressed size
+		tw.i32(3, int32(len(data))) // compressed size
+		tw.structBegin(5)           // data page header
+		tw.i32(1, int32(rows))
+		tw.i32(2, parquetPlain)
+		tw.i32(3, parquetRLE) // definition levels
+		tw.i32(4, parquetRLE) // repetition levels
+		tw.structEnd()
+		tw.stop()
+
+		group.chunks = append(group.chunks, parquetChunk{pw.offset, int64(len(tw.buf) + len(data))})
+		pw.write(tw.buf)
+		pw.write(data)
+	}
+
+	pw.rowGroups = append(pw.rowGroups, group)
+
+	return nil
+}
+
+// end writes the footer and flushes the file.
+func (pw *parquetWriter) end() error {
+	var rows int64
+	for _, group := range pw.rowGroups {
+		rows += group.rows
+	}
+
+	tw := newThriftWriter()
+	tw.i32(1, 1) // version
+
+	tw.listBegin(2, thriftStruct, len(pw.columns)+1) // schema
+	tw.elementBegin()
+	tw.binary(4, "schema")
+	tw.i32(5, int32(len(pw.columns)))
+	tw.structEnd()
+	for _, c := range pw.columns {
+		tw.elementBegin()
+		if c.integer {
+			tw.i32(1, parquetInt64)
+		} else {
+			tw.i32(1, parquetByteArray)
+		}
+		if c.nullable {
+			tw.i32(3, parquetOptional)
+		} else {
+			tw.i32(3, parquetRequired)
+		}
+		tw.binary(4, c.name)
+		if !c.integer {
+			tw.i32(6, parquetUTF8)
+		}
+		tw.structEnd()
+	}
+
+	tw.i64(3, rows)
+
+	tw.listBegin(4, thriftStruct, len(pw.rowGroups))
+	for _, group := range pw.rowGroups {
+		tw.elementBegin()
+
+		var size int64
+		tw.listBegin(1, thriftStruct, len(group.chunks))
+		for i, chunk := range group.chunks {
+			c := pw.columns[i]
+			size += chunk.size
+
+			tw.elementBegin()
+			tw.i64(2, chunk.offset)
+			tw.structBegin(3) // column metadata
+			if c.integer {
+				tw.i32(1, parquetInt64)
+			} else {
+				tw.i32(1, parquetByteArray)
+			}
+			tw.listBegin(2, thriftI32, 2) // encodings
+			tw.buf = appendProtoVarint(tw.buf, zigzag(parquetPlain))
+			tw.buf = appendProtoVarint(tw.buf, zigzag(parquetRLE))
+			tw.listBegin(3, thriftBinary, 1) // path
+			tw.buf = appendProtoVarint(tw.buf, uint64(len(c.name)))
+			tw.buf = append(tw.buf, c.name...)
+			tw.i32(4, parquetUncompressed)
+			tw.i64(5, group.rows)
+			tw.i64(6, chunk.size)
+			tw.i64(7, chunk.size)
+			tw.i64(9, chunk.offset)
+			tw.structEnd()
+			tw.structEnd()
+		}
+
+		tw.i64(2, size)
+		tw.i64(3, group.rows)
+		tw.structEnd()
+	}
+
+	tw.binary(6, "graph-store")
+	tw.stop()
+
+	pw.write(tw.buf)
+	pw.write(appendLittleEndian(nil, uint64(len(tw.buf)), 4))
+	pw.write([]byte(parquetMagic))
+
+	return pw.w.Flush()
+}
+
+// thriftWriter encodes structs in the Thrift compact protocol.
+type thriftWriter struct {
+	buf  []byte
+	last []int // id of the last field of every struct being written, innermost last
+}
+
+func newThriftWriter() *thriftWriter {
+	return &thriftWriter{last: []int{0}}
+}
+
+// field appends the header of field id of type typ: the difference to the previous field id if it's small, otherwise the id itself.
+func (tw *thriftWriter) field(id int, typ byte) {
+	last := &tw.last[len(tw.last)-1]
+	if delta := id - *last; delta > 0 && delta <= 15 {
+		tw.buf = append(tw.buf, byte(delta<<4)|typ)
+	} else {
+		tw.buf = append(tw.buf, typ)
+		tw.buf = appendProtoVarint(tw.buf, zigzag(int64(id)))
+	}
+	*last = id
+}
+
+func (tw *thriftWriter) i32(id int, v int32) {
+	tw.field(id, thriftI32)
+	tw.buf = appendProtoVarint(tw.buf, zigzag(int64(v)))
+}
+
+func (tw *thriftWriter) i64(id int, v int64) {
+	tw.field(id, thriftI64)
+	tw.buf = appendProtoVarint(tw.buf, zigzag(v))
+}
+
+func (tw *thriftWriter) binary(id int, s string) {
+	tw.field(id, thriftBinary)
+	tw.buf = appendProtoVarint(tw.buf, uint64(len(s)))
+	tw.buf = append(tw.buf, s...)
+}
+
+// structBegin starts a struct field, which must be ended by structEnd.
+func (tw *thriftWriter) structBegin(id int) {
+	tw.field(id, thriftStruct)
+	tw.last = append(tw.last, 0)
+}
+
+// listBegin starts a list field of n elements of type typ, which must follow.
+func (tw *thriftWriter) listBegin(id int, typ byte, n int) {
+	tw.field(id, thriftList)
+	if n < 15 {
+		tw.buf = append(tw.buf, byte(n<<4)|typ)
+	} else {
+		tw.buf = append(tw.buf, 0xf0|typ)
+		tw.buf = appendProtoVarint(tw.buf, uint64(n))
+	}
+}
+
+// elementBegin starts a struct element of a list, which must be ended by structEnd.
+func (tw *thriftWriter) elementBegin() {
+	tw.last = append(tw.last, 0)
+}
+
+// structEnd ends a struct started by structBegin or elementBegin.
+func (tw *thriftWriter) structEnd() {
+	tw.stop()
+	tw.last = tw.last[:len(tw.last)-1]
+}
+
+// stop appends the end marker of a struct.
+func (tw *thriftWriter) stop() {
+	tw.buf = append(tw.buf, 0)
+}
+
+// zigzag maps signed to unsigned integers, so small absolute values have short varints.
+func zigzag(i int64) uint64 {
+	return uint64(i<<1) ^ uint64(i>>63)
+}
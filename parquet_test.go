@@ -0,0 +1,232 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+// thriftReader decodes structs of the Thrift compact protocol into maps from field ids to int64, string, []interface{} or nested maps.
+type thriftReader struct {
+	buf []byte
+	pos int
+}
+
+func (tr *thriftReader) varint() uint64 {
+	n, size := readProtoVarint(tr.buf[tr.pos:])
+	if size == 0 {
+		panic("invalid varint")
+	}
+	tr.pos += size
+	return n
+}
+
+func (tr *thriftReader) value(typ byte) interface{} {
+	switch typ {
+	case 1, 2:
+		return typ == 1
+	case thriftI32, thriftI64:
+		n := tr.varint()
+		return int64(n>>1) ^ -int64(n&1)
+	case thriftBinary:
+		n := int(tr.varint())
+		s := string(tr.buf[tr.pos : tr.pos+n])
+		tr.pos += n
+		return s
+	case thriftList:
+		header := tr.buf[tr.pos]
+		tr.pos++
+		n := int(header >> 4)
+		if n == 15 {
+			n = int(tr.varint())
+		}
+		list := make([]interface{}, n)
+		for i := range list {
+			list[i] = tr.value(header & 0x0f)
+		}
+		return list
+	case thriftStruct:
+		return tr.structure()
+	}
+	panic(fmt.Sprintf("unexpected type %d", typ))
+}
+
+func (tr *thriftReader) structure() map[int]interface{} {
+	fields := map[int]interface{}{}
+	id := 0
+	for {
+		header := tr.buf[tr.pos]
+		tr.pos++
+		if header == 0 {
+			return fields
+		}
+		if header>>4 != 0 {
+			id += int(header >> 4)
+		} else {
+			n := tr.varint()
+			id = int(int64(n>>1) ^ -int64(n&1))
+		}
+		fields[id] = tr.value(header & 0x0f)
+	}
+}
+
+// readParquet decodes a Parquet file written by parquetWriter and returns the schema elements and all rows.
+func readParquet(t *testing.T, b []byte) (schema []map[int]interface{}, rows [][]interface{}) {
+	t.Helper()
+
+	if len(b) < 12 || string(b[:4]) != parquetMagic || string(b[len(b)-4:]) != parquetMagic {
+		t.Fatalf("missing magic")
+	}
+	length := int((&FlatGraph{buf: b}).uint32(len(b) - 8))
+	footer := &thriftReader{buf: b[len(b)-8-length : len(b)-8]}
+	meta := footer.structure()
+	if footer.pos != length {
+		t.Fatalf("footer of %d bytes, decoded %d", length, footer.pos)
+	}
+
+	for _, element := range meta[2].([]interface{}) {
+		schema = append(schema, element.(map[int]interface{}))
+	}
+	columns := schema[1:]
+
+	for _, group := range meta[4].([]interface{}) {
+		groupRows := int(group.(map[int]interface{})[3].(int64))
+		batch := make([][]interface{}, groupRows)
+
+		for i, chunk := range group.(map[int]interface{})[1].([]interface{}) {
+			chunkMeta := chunk.(map[int]interface{})[3].(map[int]interface{})
+			offset := int(chunkMeta[9].(int64))
+			if chunkMeta[5].(int64) != int64(groupRows) || !reflect.DeepEqual(chunkMeta[3], []interface{}{columns[i][4]}) {
+				t.Fatalf("column %d: unexpected metadata %v", i, chunkMeta)
+			}
+
+			page := &thriftReader{buf: b, pos: offset}
+			header := page.structure()
+			data := b[page.pos : page.pos+int(header[3].(int64))]
+			if int64(page.pos+len(data)-offset) != chunkMeta[7].(int64) {
+				t.Fatalf("column %d: chunk size mismatch", i)
+			}
+			if header[5].(map[int]interface{})[1].(int64) != int64(groupRows) {
+				t.Fatalf("column %d: page value count mismatch", i)
+			}
+
+			r := &FlatGraph{buf: data}
+			pos := 0
+
+			valid := make([]bool, groupRows)
+			for row := range valid {
+				valid[row] = true
+			}
+			if columns[i][3].(int64) == parquetOptional {
+				levels := &thriftReader{buf: data, pos: 4}
+				if run := levels.varint(); run != uint64((groupRows+7)/8)<<1|1 {
+					t.Fatalf("column %d: unexpected run header %d", i, run)
+				}
+				for row := range valid {
+					valid[row] = data[levels.pos+row/8]&(1<<uint(row%8)) != 0
+				}
+				pos = 4 + int(r.uint32(0))
+			}
+
+			for row := range batch {
+				switch {
+				case !valid[row]:
+					batch[row] = append(batch[row], nil)
+				case columns[i][1].(int64) == parquetInt64:
+					batch[row] = append(batch[row], int64(r.uint64(pos)))
+					pos += 8
+				default:
+					n := int(r.uint32(pos))
+					batch[row] = append(batch[row], string(data[pos+4:pos+4+n]))
+					pos += 4 + n
+				}
+			}
+			if pos != len(data) {
+				t.Fatalf("column %d: %d bytes of trailing data", i, len(data)-pos)
+			}
+		}
+
+		rows = append(rows, batch...)
+	}
+
+	if meta[3].(int64) != int64(len(rows)) {
+		t.Fatalf("expected %d rows, got %d", meta[3], len(rows))
+	}
+
+	return schema, rows
+}
+
+func TestWriteParquet(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", nil)
+	g.Set("c", map[string]interface{}{"x": "y"})
+
+	g.Connect("a", "b", 5)
+	g.Connect("a", "c", -1)
+	g.Connect("c", "a", 1<<40)
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteParquetVertices(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	schema, rows := readParquet(t, buf.Bytes())
+	expectedSchema := []map[int]interface{}{
+		{4: "schema", 5: int64(2)},
+		{1: int64(parquetByteArray), 3: int64(parquetRequired), 4: "key", 6: int64(parquetUTF8)},
+		{1: int64(parquetByteArray), 3: int64(parquetOptional), 4: "value", 6: int64(parquetUTF8)},
+	}
+	if !reflect.DeepEqual(schema, expectedSchema) {
+		t.Errorf("expected schema %v, got %v", expectedSchema, schema)
+	}
+	expected := [][]interface{}{{"a", "1"}, {"b", nil}, {"c", `{"x":"y"}`}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+
+	buf.Reset()
+	if err := g.WriteParquetEdges(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	schema, rows = readParquet(t, buf.Bytes())
+	if len(schema) != 4 || schema[3][4] != "weight" || schema[3][1] != int64(parquetInt64) {
+		t.Errorf("unexpected schema %v", schema)
+	}
+	expected = [][]interface{}{{"a", "b", int64(5)}, {"a", "c", int64(-1)}, {"c", "a", int64(1 << 40)}}
+	if !reflect.DeepEqual(rows, expected) {
+		t.Errorf("expected %v, got %v", expected, rows)
+	}
+
+	// empty graphs have a schema and no row groups
+	buf.Reset()
+	if err := New().WriteParquetVertices(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if schema, rows := readParquet(t, buf.Bytes()); len(schema) != 3 || len(rows) != 0 {
+		t.Errorf("expected schema only, got %v %v", schema, rows)
+	}
+}
+
+func TestWriteParquetRowGroups(t *testing.T) {
+	g := New()
+	for i := 0; i < parquetRowGroupSize+10; i++ {
+		value := interface{}(i)
+		if i%3 == 0 {
+			value = nil
+		}
+		g.Set(fmt.Sprintf("%06d", i), value)
+	}
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteParquetVertices(buf); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	_, rows := readParquet(t, buf.Bytes())
+	if len(rows) != parquetRowGroupSize+10 || rows[parquetRowGroupSize+1][1] != fmt.Sprint(parquetRowGroupSize+1) || rows[parquetRowGroupSize+2][1] != nil {
+		t.Errorf("expected %d rows across row groups, got %d", parquetRowGroupSize+10, len(rows))
+	}
+}
@@ -0,0 +1,163 @@
+package graph
+
+import (
+	"container/list"
+	"sync"
+)
+
+// pathCache is an LRU cache of shortest paths, indexed by the vertices on them so that mutations only evict the paths they affect.
+type pathCache struct {
+	size    int
+	lru     *list.List                            // cached entries, most recently used first
+	entries map[[2]string]*list.Element           // maps start and end key to an element of lru
+	byKey   map[string]map[*list.Element]struct{} // maps vertex keys to the elements whose paths contain them
+	cancel  func()                                // cancels the subscription to the graph's events
+	version int                                   // incremented on every invalidation
+	sync.Mutex
+}
+
+// pathCacheEntry is a cached path in start → end order.
+type pathCacheEntry struct {
+	startKey, endKey string
+	path             []string
+}
+
+// EnablePathCache makes the graph remember up to size shortest paths found by ShortestPath, ShortestPathWithHeuristic and ShortestPathBidirectional and answer repeated queries for the same start and end vertex from the cache.
+// Cached paths are evicted when a vertex or edge on them is deleted; creating an edge or changing its weight empties the whole cache, since it might shorten any path.
+// Because cached paths are returned regardless of the heuristic passed to a query, the cache should only be used with heuristics that never overestimate distances.
+func (g *Graph) EnablePathCache(size int) {
+	defer g.track("EnablePathCache")()
+
+	c := &pathCache{
+		size:    size,
+		lru:     list.New(),
+		entries: map[[2]string]*list.Element{},
+		byKey:   map[string]map[*list.Element]struct{}{},
+	}
+
+	g.Lock()
+	old := g.pathCache
+	c.cancel = g.subscribe(c.invalidate)
+	g.pathCache = c
+	g.Unlock()
+
+	// cancelling locks the graph
+	if old != nil {
+		old.cancel()
+	}
+}
+
+// DisablePathCache disables and empties the cache of shortest paths.
+func (g *Graph) DisablePathCache() {
+	defer g.track("DisablePathCache")()
+
+	g.Lock()
+	c := g.pathCache
+	g.pathCache = nil
+	g.Unlock()
+
+	// cancelling locks the graph
+	if c != nil {
+		c.cancel()
+	}
+}
+
+// get returns a copy of the cached path from startKey to endKey if there is one, and the cache's current version, which has to be passed to put when storing a newly found path. Safe to call on a nil cache.
+func (c *pathCache) get(startKey, endKey string) (path []string, ok bool, version int) {
+	if c == nil {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	e, ok := c.entries[[2]string{startKey, endKey}]
+	if !ok {
+		return nil, false, c.version
+	}
+
+	c.lru.MoveToFront(e)
+
+	return append([]string(nil), e.Value.(*pathCacheEntry).path...), true, c.version
+}
+
+// put stores a copy of path (in start → end order), unless the cache was invalidated since the given version was returned by get, since path might be outdated then. Safe to call on a nil cache.
+func (c *pathCache) put(path []string, version int) {
+	if c == nil || len(path) == 0 || c.size <= 0 {
+		return
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if version != c.version {
+		return
+	}
+
+	startKey, endKey := path[0], path[len(path)-1]
+	if _, ok := c.entries[[2]string{startKey, endKey}]; ok {
+		return
+	}
+
+	e := c.lru.PushFront(&pathCacheEntry{startKey, endKey, append([]string(nil), path...)})
+	c.entries[[2]string{startKey, endKey}] = e
+
+	for _, key := range path {
+		if c.byKey[key] == nil {
+			c.byKey[key] = map[*list.Element]struct{}{}
+		}
+		c.byKey[key][e] = struct{}{}
+	}
+
+	// evict least recently used path
+	if c.lru.Len() > c.size {
+		c.remove(c.lru.Back())
+	}
+}
+
+// remove evicts e. Does NOT lock the cache.
+func (c *pathCache) remove(e *list.Element) {
+	entry := c.lru.Remove(e).(*pathCacheEntry)
+	delete(c.entries, [2]string{entry.startKey, entry.endKey})
+
+	for _, key := range entry.path {
+		delete(c.byKey[key], e)
+		if len(c.byKey[key]) == 0 {
+			delete(c.byKey, key)
+		}
+	}
+}
+
+// invalidate evicts all paths affected by the mutation described by e.
+func (c *pathCache) invalidate(e Event) {
+	c.Lock()
+	defer c.Unlock()
+
+	c.version++
+
+	switch e.Type {
+	case EventDelete:
+		// evict paths through the deleted vertex
+		for el := range c.byKey[e.Key] {
+			c.remove(el)
+		}
+
+	case EventDisconnect:
+		// evict paths using the removed edge
+		for el := range c.byKey[e.Key] {
+			path := el.Value.(*pathCacheEntry).path
+			for i := 1; i < len(path); i++ {
+				if path[i-1] == e.Key && path[i] == e.ToKey {
+					c.remove(el)
+					break
+				}
+			}
+		}
+
+	case EventConnect:
+		// a new or cheaper edge might shorten any path
+		c.lru.Init()
+		c.entries = map[[2]string]*list.Element{}
+		c.byKey = map[string]map[*list.Element]struct{}{}
+	}
+}
@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestPathCache(t *testing.T) {
+	g := New()
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Set("3", nil)
+	g.Set("4", nil)
+
+	g.Connect("1", "2", 1)
+	g.Connect("2", "4", 1)
+	g.Connect("1", "3", 2)
+	g.Connect("3", "4", 2)
+
+	g.EnablePathCache(2)
+
+	path, ok := g.ShortestPathBidirectional("1", "4", noHeuristic)
+	if !ok || len(path) != 3 || path[1] != "2" {
+		t.Fail()
+	}
+
+	// cached paths are answered without searching, so the heuristic is never called
+	calls := 0
+	countingHeuristic := func(key, endKey string) int {
+		calls++
+		return 0
+	}
+
+	path, ok = g.ShortestPathWithHeuristic("1", "4", countingHeuristic)
+	if !ok || calls != 0 || len(path) != 3 || path[0] != "4" || path[1] != "2" || path[2] != "1" {
+		t.Fail()
+	}
+
+	// returned paths are copies
+	path[1] = "x"
+	path, _ = g.ShortestPathBidirectional("1", "4", noHeuristic)
+	if path[1] != "2" {
+		t.Fail()
+	}
+
+	// removing an edge on the path evicts it
+	g.Disconnect("2", "4")
+
+	path, ok = g.ShortestPathBidirectional("1", "4", noHeuristic)
+	if !ok || len(path) != 3 || path[1] != "3" {
+		t.Fail()
+	}
+
+	// deleting a vertex on the path evicts it
+	g.Delete("3")
+
+	if _, ok = g.ShortestPathBidirectional("1", "4", noHeuristic); ok {
+		t.Fail()
+	}
+
+	// new edges might create shorter paths
+	g.Connect("2", "4", 1)
+	g.ShortestPathBidirectional("1", "4", noHeuristic)
+	g.Connect("1", "4", 1)
+
+	path, ok = g.ShortestPathBidirectional("1", "4", noHeuristic)
+	if !ok || len(path) != 2 {
+		t.Fail()
+	}
+
+	// least recently used paths are evicted
+	g.ShortestPathBidirectional("1", "2", noHeuristic)
+	g.ShortestPathBidirectional("2", "4", noHeuristic)
+
+	if len(g.pathCache.entries) != 2 {
+		t.Fail()
+	}
+	if _, ok, _ := g.pathCache.get("1", "4"); ok {
+		t.Fail()
+	}
+
+	g.DisablePathCache()
+	if g.pathCache != nil || len(g.subscribers) != 0 {
+		t.Fail()
+	}
+}
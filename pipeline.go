@@ -0,0 +1,238 @@
+package graph
+
+import (
+	"sort"
+)
+
+// pipelinePageSize is the number of source vertices a Pipeline reads at once.
+const pipelinePageSize = 1024
+
+// Pipeline streams the vertices and edges of a graph into another graph, transforming them on the way, e.g. for ETL between graph stores.
+// Transformations are added by chaining calls like NewPipeline(src).Filter(...).MapWeights(...).Contract(...) and are applied in that order; Into runs the pipeline.
+type Pipeline struct {
+	src    *Graph
+	stages []pipelineStage
+}
+
+// pipelineStage is a transformation of a Pipeline. Either function may be nil if the stage doesn't affect vertices or edges.
+type pipelineStage struct {
+	vertex func(r *pipelineVertex) bool // transforms a vertex in place, returns false to drop it
+	edge   func(e *pipelineEdge) bool   // transforms an edge in place, returns false to drop it
+	policy WeightPolicy                 // resolves edges between the same vertices produced by the stage, nil if it can't produce any
+}
+
+// pipelineVertex is a vertex flowing through a Pipeline.
+type pipelineVertex struct {
+	key   string
+	value interface{}
+}
+
+// pipelineEdge is an edge flowing through a Pipeline, with its endpoints.
+type pipelineEdge struct {
+	from, to pipelineVertex
+	weight   int
+}
+
+// NewPipeline initializes a pipeline reading from src, without any transformations.
+func NewPipeline(src *Graph) *Pipeline {
+	return &Pipeline{src: src}
+}
+
+// add returns a copy of the pipeline with stage appended, so pipelines can be branched.
+func (p *Pipeline) add(stage pipelineStage) *Pipeline {
+	stages := append(p.stages[:len(p.stages):len(p.stages)], stage)
+	return &Pipeline{p.src, stages}
+}
+
+// Filter drops the vertices for which keep returns false, along with their edges.
+func (p *Pipeline) Filter(keep func(key string, value interface{}) bool) *Pipeline {
+	return p.add(pipelineStage{
+		vertex: func(r *pipelineVertex) bool {
+			return keep(r.key, r.value)
+		},
+		edge: func(e *pipelineEdge) bool {
+			return keep(e.from.key, e.from.value) && keep(e.to.key, e.to.value)
+		},
+	})
+}
+
+// FilterEdges drops the edges for which keep returns false.
+func (p *Pipeline) FilterEdges(keep func(e Edge) bool) *Pipeline {
+	return p.add(pipelineStage{
+		edge: func(e *pipelineEdge) bool {
+			return keep(Edge{From: e.from.key, To: e.to.key, Weight: e.weight})
+		},
+	})
+}
+
+// MapValues replaces the value of every vertex by the one returned by fn.
+func (p *Pipeline) MapValues(fn func(key string, value interface{}) interface{}) *Pipeline {
+	return p.add(pipelineStage{
+		vertex: func(r *pipelineVertex) bool {
+			r.value = fn(r.key, r.value)
+			return true
+		},
+		edge: func(e *pipelineEdge) bool {
+			e.from.value = fn(e.from.key, e.from.value)
+			e.to.value = fn(e.to.key, e.to.value)
+			return true
+		},
+	})
+}
+
+// MapWeights replaces the weight of every edge by the one returned by fn.
+func (p *Pipeline) MapWeights(fn func(e Edge) int) *Pipeline {
+	return p.add(pipelineStage{
+		edge: func(e *pipelineEdge) bool {
+			e.weight = fn(Edge{From: e.from.key, To: e.to.key, Weight: e.weight})
+			return true
+		},
+	})
+}
+
+// Contract merges vertices into groups: group returns the key of the vertex a vertex becomes part of. The group vertex gets the value of its first member in key order.
+// Edges within a group are dropped; edges between the same groups are combined using weights, which is MinWeight if nil.
+func (p *Pipeline) Contract(group func(key string, value interface{}) string, weights WeightPolicy) *Pipeline {
+	if weights == nil {
+		weights = MinWeight
+	}
+
+	return p.add(pipelineStage{
+		vertex: func(r *pipelineVertex) bool {
+			r.key = group(r.key, r.value)
+			return true
+		},
+		edge: func(e *pipelineEdge) bool {
+			e.from.key = group(e.from.key, e.from.value)
+			e.to.key = group(e.to.key, e.to.value)
+			return e.from.key != e.to.key
+		},
+		policy: weights,
+	})
+}
+
+// Into runs the pipeline, setting the transformed vertices and connecting the transformed edges in dst. Vertices and edges already in dst are kept; edges ending up between the same vertices as an existing one are combined with it using the policy of the last Contract stage, or replace it if there is none.
+// The source is read in pages of sorted keys, each under a short read lock, so memory use is bounded by the page size and the size of dst; changes made to the source concurrently may or may not be seen. All vertices are set before any edge is connected.
+// Returns ErrDuplicateValue if a value is rejected by a unique index of dst, and ErrInvalidKey if a transformed edge refers to a vertex which is not in dst, e.g. because the edge was kept by FilterEdges but an endpoint was dropped, or is a self-loop while dst doesn't allow them.
+func (p *Pipeline) Into(dst *Graph) error {
+	var policy WeightPolicy
+	for _, stage := range p.stages {
+		if stage.policy != nil {
+			policy = stage.policy
+		}
+	}
+
+	// vertices set by this run, so that group vertices keep the value of their first member
+	set := map[string]struct{}{}
+
+	err := p.each(func(vertices []pipelineVertex, _ []pipelineEdge) error {
+		for _, r := range vertices {
+			if !p.transformVertex(&r) {
+				continue
+			}
+			if _, ok := set[r.key]; ok {
+				continue
+			}
+
+			if _, err := dst.TrySet(r.key, r.value); err != nil {
+				return err
+			}
+			set[r.key] = struct{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return p.each(func(_ []pipelineVertex, edges []pipelineEdge) error {
+		dst.Lock()
+		defer dst.Unlock()
+
+		for _, e := range edges {
+			if !p.transformEdge(&e) {
+				continue
+			}
+
+			fromV, toV := dst.get(e.from.key), dst.get(e.to.key)
+			if fromV == nil || toV == nil || (fromV == toV && !dst.selfLoops) {
+				return ErrInvalidKey
+			}
+
+			if policy != nil {
+				dst.connectResolved(fromV, toV, e.weight, nil, policy)
+			} else {
+				dst.connect(fromV, toV, e.weight)
+			}
+		}
+
+		return nil
+	})
+}
+
+// transformVertex runs r through all stages. Returns false if it was dropped.
+func (p *Pipeline) transformVertex(r *pipelineVertex) bool {
+	for _, stage := range p.stages {
+		if stage.vertex != nil && !stage.vertex(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// transformEdge runs e through all stages. Returns false if it was dropped.
+func (p *Pipeline) transformEdge(e *pipelineEdge) bool {
+	for _, stage := range p.stages {
+		if stage.edge != nil && !stage.edge(e) {
+			return false
+		}
+	}
+	return true
+}
+
+// each calls fn with the vertices and outgoing edges of the source, one page of sorted keys at a time. It stops at the first error returned by fn.
+func (p *Pipeline) each(fn func(vertices []pipelineVertex, edges []pipelineEdge) error) error {
+	for after, first := "", true; ; first = false {
+		vertices, edges, last := p.page(after, first)
+		if len(vertices) == 0 {
+			return nil
+		}
+
+		if err := fn(vertices, edges); err != nil {
+			return err
+		}
+
+		after = last
+	}
+}
+
+// page copies up to pipelinePageSize vertices of the source whose keys follow after (or the first ones if first is true), and their outgoing edges. It returns the last key copied.
+func (p *Pipeline) page(after string, first bool) (vertices []pipelineVertex, edges []pipelineEdge, last string) {
+	p.src.RLock()
+	defer p.src.RUnlock()
+
+	keys := p.src.sortedKeys()
+
+	i := 0
+	if !first {
+		i = sort.SearchStrings(keys, after)
+		if i < len(keys) && keys[i] == after {
+			i++
+		}
+	}
+
+	for ; i < len(keys) && len(vertices) < pipelinePageSize; i++ {
+		v := p.src.vertices[keys[i]]
+		from := pipelineVertex{v.key, v.Value()}
+		vertices = append(vertices, from)
+
+		for neighbor, weight := range v.GetOutgoing() {
+			edges = append(edges, pipelineEdge{from, pipelineVertex{neighbor.key, neighbor.Value()}, weight})
+		}
+
+		last = v.key
+	}
+
+	return
+}
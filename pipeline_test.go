@@ -0,0 +1,103 @@
+package graph
+
+import (
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPipeline(t *testing.T) {
+	src := New()
+	for _, key := range []string{"de:berlin", "de:hamburg", "fr:paris", "fr:lyon", "tmp:scratch"} {
+		src.Set(key, strings.ToUpper(key))
+	}
+
+	src.Connect("de:berlin", "de:hamburg", 3)
+	src.Connect("de:berlin", "fr:paris", 10)
+	src.Connect("de:hamburg", "fr:paris", 7)
+	src.Connect("de:hamburg", "fr:lyon", 8)
+	src.Connect("fr:lyon", "de:berlin", 9)
+	src.Connect("tmp:scratch", "de:berlin", 1)
+
+	country := func(key string, value interface{}) string {
+		return key[:strings.Index(key, ":")]
+	}
+
+	dst := New()
+	err := NewPipeline(src).
+		Filter(func(key string, value interface{}) bool { return !strings.HasPrefix(key, "tmp:") }).
+		MapWeights(func(e Edge) int { return 2 * e.Weight }).
+		Contract(country, SumWeights).
+		Into(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if dst.Len() != 2 {
+		t.Fatalf("expected 2 vertices, got %d", dst.Len())
+	}
+	if v, _ := dst.Get("de"); v.Value() != "DE:BERLIN" {
+		t.Errorf("expected the value of the first member, got %v", v.Value())
+	}
+	if ok, w := dst.IsConnected("de", "fr"); !ok || w != 2*(10+7+8) {
+		t.Errorf("expected summed weight %d, got %d", 2*(10+7+8), w)
+	}
+	if ok, w := dst.IsConnected("fr", "de"); !ok || w != 18 {
+		t.Fail()
+	}
+
+	// the source is unchanged, and pipelines can be branched
+	base := NewPipeline(src).FilterEdges(func(e Edge) bool { return e.Weight < 9 })
+	copied, mapped := New(), New()
+
+	if err = base.Into(copied); err != nil {
+		t.Fatal(err)
+	}
+	if err = base.MapValues(func(key string, value interface{}) interface{} { return len(key) }).Into(mapped); err != nil {
+		t.Fatal(err)
+	}
+
+	if copied.Len() != 5 || src.Len() != 5 {
+		t.Fail()
+	}
+	if ok, _ := copied.IsConnected("fr:lyon", "de:berlin"); ok {
+		t.Error("expected the edge to be filtered")
+	}
+	if ok, w := copied.IsConnected("de:hamburg", "fr:lyon"); !ok || w != 8 {
+		t.Fail()
+	}
+	if v, _ := mapped.Get("fr:paris"); v.Value() != 8 {
+		t.Fail()
+	}
+	if v, _ := copied.Get("fr:paris"); v.Value() != "FR:PARIS" {
+		t.Fail()
+	}
+}
+
+func TestPipelinePages(t *testing.T) {
+	src := New()
+	n := 3*pipelinePageSize + 7
+	for i := 0; i < n; i++ {
+		src.Set(strconv.Itoa(i), i)
+		if i > 0 {
+			src.Connect(strconv.Itoa(i-1), strconv.Itoa(i), i)
+		}
+	}
+
+	dst := New()
+	if err := NewPipeline(src).Into(dst); err != nil {
+		t.Fatal(err)
+	}
+
+	if !dst.IsIsomorphic(src, CompareWeights()) || dst.Len() != n {
+		t.Fail()
+	}
+
+	// values rejected by a unique index
+	dst = New()
+	dst.EnableUniqueIndex(nil)
+	err := NewPipeline(src).MapValues(func(string, interface{}) interface{} { return 0 }).Into(dst)
+	if err != ErrDuplicateValue {
+		t.Errorf("expected ErrDuplicateValue, got %v", err)
+	}
+}
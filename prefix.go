@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"sort"
+	"strings"
+)
+
+// EnableOrderedIndex keeps the sorted index of keys used by KeysWithPrefix, ListVertices and others up to date on every creation and deletion of a vertex, instead of rebuilding it on the next query after such changes.
+// This makes queries on frequently changing graphs fast, at the cost of O(|V|) work for each vertex created or deleted.
+func (g *Graph) EnableOrderedIndex() {
+	defer g.track("EnableOrderedIndex")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	g.keys.ordered = true
+	g.sortedKeys()
+}
+
+// DisableOrderedIndex makes the sorted index of keys rebuilt lazily again, see EnableOrderedIndex.
+func (g *Graph) DisableOrderedIndex() {
+	defer g.track("DisableOrderedIndex")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	g.keys.ordered = false
+}
+
+// KeysWithPrefix returns the sorted keys starting with prefix, e.g. all keys in the "user:" namespace of a hierarchical key scheme. The keys are found by binary search in the sorted index of keys, see EnableOrderedIndex.
+func (g *Graph) KeysWithPrefix(prefix string) []string {
+	defer g.track("KeysWithPrefix")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	keys := g.sortedKeys()
+
+	i := sort.SearchStrings(keys, prefix)
+	n := sort.Search(len(keys)-i, func(n int) bool {
+		return !strings.HasPrefix(keys[i+n], prefix)
+	})
+
+	return append([]string(nil), keys[i:i+n]...)
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestKeysWithPrefix(t *testing.T) {
+	for _, ordered := range []bool{false, true} {
+		g := New()
+		if ordered {
+			g.EnableOrderedIndex()
+		}
+
+		for _, key := range []string{"user:2", "group:admins", "user:1", "user", "users:x", "group:users"} {
+			g.Set(key, nil)
+		}
+
+		if keys := g.KeysWithPrefix("user:"); !reflect.DeepEqual(keys, []string{"user:1", "user:2"}) {
+			t.Errorf("unexpected keys %v", keys)
+		}
+		if keys := g.KeysWithPrefix("group:"); !reflect.DeepEqual(keys, []string{"group:admins", "group:users"}) {
+			t.Errorf("unexpected keys %v", keys)
+		}
+		if keys := g.KeysWithPrefix("admin:"); len(keys) != 0 {
+			t.Errorf("unexpected keys %v", keys)
+		}
+		if keys := g.KeysWithPrefix(""); len(keys) != 6 {
+			t.Errorf("unexpected keys %v", keys)
+		}
+
+		// changes are reflected, whether the index is maintained or rebuilt
+		g.Delete("user:1")
+		g.Set("user:0", nil)
+		g.Set("user:3", nil)
+		g.Delete("users:x")
+
+		if keys := g.KeysWithPrefix("user"); !reflect.DeepEqual(keys, []string{"user", "user:0", "user:2", "user:3"}) {
+			t.Errorf("unexpected keys %v", keys)
+		}
+		if ordered && !g.keys.valid {
+			t.Error("expected the ordered index to be maintained")
+		}
+
+		vertices, _, _ := g.ListVertices("", 10)
+		if len(vertices) != 6 || vertices[0].Key() != "group:admins" {
+			t.Fail()
+		}
+	}
+}
@@ -0,0 +1,50 @@
+package graph
+
+import (
+	"errors"
+	"time"
+)
+
+// ErrCanceled is returned by long-running computations when their ProgressFunc asked to stop.
+var ErrCanceled = errors.New("graph: canceled")
+
+// Progress describes how far a long-running computation has got.
+type Progress struct {
+	Done, Total int           // number of vertices processed and to be processed
+	Elapsed     time.Duration // time since the computation started
+}
+
+// Percent returns the percentage of vertices processed.
+func (p Progress) Percent() float64 {
+	if p.Total == 0 {
+		return 100
+	}
+	return 100 * float64(p.Done) / float64(p.Total)
+}
+
+// ProgressFunc is called by long-running computations after each vertex processed, e.g. to update a progress bar. Returning false cancels the computation, which then fails with ErrCanceled.
+// It is called while the graph is locked, so it must not modify the graph.
+type ProgressFunc func(p Progress) bool
+
+// progressTracker reports the progress of a computation to a ProgressFunc, which may be nil.
+type progressTracker struct {
+	fn    ProgressFunc
+	done  int
+	total int
+	start time.Time
+}
+
+func newProgressTracker(fn ProgressFunc, total int) *progressTracker {
+	return &progressTracker{fn: fn, total: total, start: time.Now()}
+}
+
+// step records that another vertex was processed. Returns ErrCanceled if the computation should stop.
+func (p *progressTracker) step() error {
+	p.done++
+
+	if p.fn != nil && !p.fn(Progress{p.done, p.total, time.Since(p.start)}) {
+		return ErrCanceled
+	}
+
+	return nil
+}
@@ -0,0 +1,52 @@
+package graph
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestProgress(t *testing.T) {
+	g := New()
+	for i := 0; i < 4; i++ {
+		g.Set(strconv.Itoa(i), nil)
+		if i > 0 {
+			g.Connect(strconv.Itoa(i-1), strconv.Itoa(i), 1)
+			g.Connect(strconv.Itoa(i), strconv.Itoa(i-1), 1)
+		}
+	}
+
+	var percents []float64
+	record := func(p Progress) bool {
+		if p.Total != 4 || p.Elapsed < 0 {
+			t.Errorf("unexpected progress %+v", p)
+		}
+		percents = append(percents, p.Percent())
+		return true
+	}
+
+	if d, err := g.Diameter(WithProgress(record)); err != nil || d != 3 {
+		t.Fail()
+	}
+	if expected := []float64{25, 50, 75, 100}; !reflect.DeepEqual(percents, expected) {
+		t.Errorf("expected progress %v, got %v", expected, percents)
+	}
+
+	percents = nil
+	scores, err := g.BetweennessCentralityWithProgress(record)
+	if err != nil || !reflect.DeepEqual(scores, g.BetweennessCentrality()) || len(percents) != 4 {
+		t.Fail()
+	}
+
+	// cancellation after the second vertex
+	cancel := func(p Progress) bool {
+		return p.Done < 2
+	}
+
+	if _, err = g.Radius(WithProgress(cancel)); err != ErrCanceled {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+	if scores, err = g.BetweennessCentralityWithProgress(cancel); err != ErrCanceled || scores != nil {
+		t.Errorf("expected ErrCanceled, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+package graph
+
+import (
+	"errors"
+)
+
+// ErrProtected is returned when a protected vertex would be deleted, see Protect.
+var ErrProtected = errors.New("graph: vertex is protected")
+
+// Protect marks the vertex with the specified key as protected, so Delete, batches and GCFrom refuse to delete it, e.g. to prevent accidental removal of root or anchor vertices. Use ForceDelete to delete it anyway.
+// Returns false if the key is invalid.
+func (g *Graph) Protect(key string) bool {
+	defer g.track("Protect")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	if g.protected == nil {
+		g.protected = map[*Vertex]struct{}{}
+	}
+	g.protected[v] = struct{}{}
+
+	return true
+}
+
+// Unprotect removes the protection of the vertex with the specified key. Returns false if the key is invalid.
+func (g *Graph) Unprotect(key string) bool {
+	defer g.track("Unprotect")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	delete(g.protected, v)
+
+	return true
+}
+
+// IsProtected returns true if the vertex with the specified key is protected.
+func (g *Graph) IsProtected(key string) bool {
+	defer g.track("IsProtected")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.isProtected(g.get(key))
+}
+
+// isProtected is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+func (g *Graph) isProtected(v *Vertex) bool {
+	_, ok := g.protected[v]
+	return ok
+}
+
+// ForceDelete deletes the vertex with the specified key even if it is protected. Returns false if the key is invalid.
+func (g *Graph) ForceDelete(key string) bool {
+	defer g.track("ForceDelete")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	g.remove(v)
+
+	return true
+}
@@ -0,0 +1,53 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestProtect(t *testing.T) {
+	g := New()
+	g.Set("root", nil)
+	g.Set("child", nil)
+	g.Set("orphan", nil)
+	g.Connect("root", "child", 1)
+
+	if !g.Protect("root") || g.Protect("missing") {
+		t.Fail()
+	}
+	if !g.IsProtected("root") || g.IsProtected("child") {
+		t.Fail()
+	}
+
+	if g.Delete("root") {
+		t.Fail()
+	}
+
+	// protected vertices are roots of the garbage collection
+	if n := g.GCFrom(nil); n != 1 || g.Len() != 2 {
+		t.Fatalf("expected only the orphan to be collected, got %d", n)
+	}
+
+	batch := Batch{}
+	batch.Delete("child")
+	batch.Delete("root")
+	errs := g.Validate(batch)
+	if len(errs) != 1 || errs[0].(*BatchError).Index != 1 || errs[0].(*BatchError).Err != ErrProtected {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+
+	if !g.ForceDelete("root") || g.Len() != 1 {
+		t.Fail()
+	}
+
+	// protection ends with the vertex
+	g.Set("root", nil)
+	if g.IsProtected("root") {
+		t.Fail()
+	}
+
+	g.Protect("root")
+	g.Unprotect("root")
+	if !g.Delete("root") {
+		t.Fail()
+	}
+}
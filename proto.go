@@ -0,0 +1,389 @@
+package graph
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Wire types of the Protocol Buffers encoding.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+// MarshalProto encodes the graph as a Graph message of the Protocol Buffers schema in graph.proto, so it can be sent over gRPC or read by code generated for any language. Vertices and edges are written in key order, so the output is deterministic.
+// Values may be nil, booleans, integers, floats, strings, []byte, []interface{} and map[string]interface{} of these; other types fail with an error.
+func (g *Graph) MarshalProto() ([]byte, error) {
+	defer g.track("MarshalProto")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	var b []byte
+	var edges []byte
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+
+		vertex := appendProtoString(nil, 1, key)
+		if value := v.Value(); value != nil {
+			encoded, err := encodeProtoValue(value)
+			if err != nil {
+				return nil, fmt.Errorf("graph: encoding proto: vertex %q: %v", key, err)
+			}
+			vertex = appendProtoBytes(vertex, 2, encoded)
+		}
+		b = appendProtoBytes(b, 1, vertex)
+
+		outgoing := v.GetOutgoing()
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			edge := appendProtoString(nil, 1, key)
+			edge = appendProtoString(edge, 2, neighbor.key)
+			if weight := outgoing[neighbor]; weight != 0 {
+				edge = appendProtoTag(edge, 3, protoVarint)
+				edge = appendProtoVarint(edge, uint64(int64(weight)))
+			}
+			edges = appendProtoBytes(edges, 2, edge)
+		}
+	}
+
+	return append(b, edges...), nil
+}
+
+// UnmarshalProto decodes a Graph message of the schema in graph.proto into the graph's vertices and edges, merging them with existing ones. Unknown fields are skipped, so data written by newer versions of the schema can be read.
+// Signed integers are decoded as int64, unsigned ones as uint64, floats as float64, lists as []interface{} and maps as map[string]interface{}. All edges with invalid endpoints are reported by an *ImportError. If an edge connects a vertex to itself, self-loops are enabled, see EnableSelfLoops.
+func (g *Graph) UnmarshalProto(b []byte) error {
+	defer g.track("UnmarshalProto")()
+
+	type vertex struct {
+		key   string
+		value interface{}
+	}
+	var vertices []vertex
+	var edges []Edge
+
+	err := parseProto(b, func(field, wire int, n uint64, data []byte) error {
+		switch {
+		case field == 1 && wire == protoBytes:
+			var v vertex
+			err := parseProto(data, func(field, wire int, n uint64, data []byte) error {
+				var err error
+				switch {
+				case field == 1 && wire == protoBytes:
+					v.key = string(data)
+				case field == 2 && wire == protoBytes:
+					v.value, err = decodeProtoValue(data)
+				}
+				return err
+			})
+			vertices = append(vertices, v)
+			return err
+
+		case field == 2 && wire == protoBytes:
+			var e Edge
+			err := parseProto(data, func(field, wire int, n uint64, data []byte) error {
+				switch {
+				case field == 1 && wire == protoBytes:
+					e.From = string(data)
+				case field == 2 && wire == protoBytes:
+					e.To = string(data)
+				case field == 3 && wire == protoVarint:
+					if int64(int(int64(n))) != int64(n) {
+						return fmt.Errorf("weight %d out of range", int64(n))
+					}
+					e.Weight = int(int64(n))
+				}
+				return nil
+			})
+			edges = append(edges, e)
+			return err
+		}
+
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("graph: decoding proto: %v", err)
+	}
+
+	im := g.NewImporter()
+	im.EnableSelfLoops = true
+
+	for _, v := range vertices {
+		if _, err := im.TrySet(v.key, v.value); err != nil {
+			return fmt.Errorf("graph: decoding proto: %v: %q", err, v.key)
+		}
+	}
+
+	for i, e := range edges {
+		im.connect(fmt.Sprintf("proto edge %d", i+1), e.From, e.To, e.Weight)
+	}
+
+	return im.Finalize()
+}
+
+// encodeProtoValue encodes v as a Value message.
+func encodeProtoValue(v interface{}) ([]byte, error) {
+	var b []byte
+
+	switch v := v.(type) {
+	case nil:
+	case bool:
+		b = appendProtoTag(b, 1, protoVarint)
+		if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	case int:
+		b = appendProtoSint(b, int64(v))
+	case int8:
+		b = appendProtoSint(b, int64(v))
+	case int16:
+		b = appendProtoSint(b, int64(v))
+	case int32:
+		b = appendProtoSint(b, int64(v))
+	case int64:
+		b = appendProtoSint(b, v)
+	case uint:
+		b = appendProtoUint(b, uint64(v))
+	case uint8:
+		b = appendProtoUint(b, uint64(v))
+	case uint16:
+		b = appendProtoUint(b, uint64(v))
+	case uint32:
+		b = appendProtoUint(b, uint64(v))
+	case uint64:
+		b = appendProtoUint(b, v)
+	case float32:
+		b = appendProtoTag(b, 4, protoFixed64)
+		b = appendLittleEndian(b, math.Float64bits(float64(v)), 8)
+	case float64:
+		b = appendProtoTag(b, 4, protoFixed64)
+		b = appendLittleEndian(b, math.Float64bits(v), 8)
+	case string:
+		b = appendProtoString(b, 5, v)
+	case []byte:
+		b = appendProtoBytes(b, 6, v)
+	case []interface{}:
+		var list []byte
+		for _, item := range v {
+			encoded, err := encodeProtoValue(item)
+			if err != nil {
+				return nil, err
+			}
+			list = appendProtoBytes(list, 1, encoded)
+		}
+		b = appendProtoBytes(b, 7, list)
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for key := range v {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+
+		var entries []byte
+		for _, key := range keys {
+			encoded, err := encodeProtoValue(v[key])
+			if err != nil {
+				return nil, err
+			}
+			entry := appendProtoString(nil, 1, key)
+			entry = appendProtoBytes(entry, 2, encoded)
+			entries = appendProtoBytes(entries, 1, entry)
+		}
+		b = appendProtoBytes(b, 8, entries)
+	default:
+		return nil, fmt.Errorf("unsupported type %T", v)
+	}
+
+	return b, nil
+}
+
+// decodeProtoValue decodes a Value message. The last field of the oneof wins, as in generated code.
+func decodeProtoValue(b []byte) (interface{}, error) {
+	var value interface{}
+
+	err := parseProto(b, func(field, wire int, n uint64, data []byte) error {
+		var err error
+
+		switch {
+		case field == 1 && wire == protoVarint:
+			value = n != 0
+		case field == 2 && wire == protoVarint:
+			value = int64(n>>1) ^ -int64(n&1)
+		case field == 3 && wire == protoVarint:
+			value = n
+		case field == 4 && wire == protoFixed64:
+			value = math.Float64frombits(n)
+		case field == 5 && wire == protoBytes:
+			value = string(data)
+		case field == 6 && wire == protoBytes:
+			value = append([]byte{}, data...)
+
+		case field == 7 && wire == protoBytes:
+			list := []interface{}{}
+			err = parseProto(data, func(field, wire int, n uint64, data []byte) error {
+				if field != 1 || wire != protoBytes {
+					return nil
+				}
+				item, err := decodeProtoValue(data)
+				list = append(list, item)
+				return err
+			})
+			value = list
+
+		case field == 8 && wire == protoBytes:
+			entries := map[string]interface{}{}
+			err = parseProto(data, func(field, wire int, n uint64, data []byte) error {
+				if field != 1 || wire != protoBytes {
+					return nil
+				}
+
+				var key string
+				var entry interface{}
+				err := parseProto(data, func(field, wire int, n uint64, data []byte) error {
+					var err error
+					switch {
+					case field == 1 && wire == protoBytes:
+						key = string(data)
+					case field == 2 && wire == protoBytes:
+						entry, err = decodeProtoValue(data)
+					}
+					return err
+				})
+				entries[key] = entry
+				return err
+			})
+			value = entries
+
+		case field >= 1 && field <= 8:
+			err = fmt.Errorf("field %d of Value has wire type %d", field, wire)
+		}
+
+		return err
+	})
+
+	return value, err
+}
+
+// parseProto calls fn with the number, wire type and contents of each field of the message b: the value of varint and fixed fields, or the data of length-delimited ones. It stops at the first error returned by fn.
+func parseProto(b []byte, fn func(field, wire int, n uint64, data []byte) error) error {
+	for len(b) > 0 {
+		tag, size := readProtoVarint(b)
+		if size == 0 || tag>>3 == 0 || tag>>3 > math.MaxInt32 {
+			return fmt.Errorf("invalid field tag")
+		}
+		b = b[size:]
+
+		field, wire := int(tag>>3), int(tag&7)
+		var n uint64
+		var data []byte
+
+		switch wire {
+		case protoVarint:
+			if n, size = readProtoVarint(b); size == 0 {
+				return fmt.Errorf("invalid varint in field %d", field)
+			}
+			b = b[size:]
+
+		case protoFixed64, protoFixed32:
+			size = 8
+			if wire == protoFixed32 {
+				size = 4
+			}
+			if len(b) < size {
+				return fmt.Errorf("unexpected end of data in field %d", field)
+			}
+			for i := size - 1; i >= 0; i-- {
+				n = n<<8 | uint64(b[i])
+			}
+			b = b[size:]
+
+		case protoBytes:
+			length, size := readProtoVarint(b)
+			if size == 0 || length > uint64(len(b)-size) {
+				return fmt.Errorf("invalid length of field %d", field)
+			}
+			data = b[size : size+int(length)]
+			b = b[size+int(length):]
+
+		default:
+			return fmt.Errorf("unsupported wire type %d in field %d", wire, field)
+		}
+
+		if err := fn(field, wire, n, data); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// readProtoVarint decodes the varint at the start of b and returns it and its size, or a size of 0 if it is invalid.
+func readProtoVarint(b []byte) (uint64, int) {
+	var n uint64
+	for i := 0; i < len(b) && i < 10; i++ {
+		n |= uint64(b[i]&0x7f) << uint(7*i)
+		if b[i] < 0x80 {
+			return n, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// appendProtoVarint appends n as a varint.
+func appendProtoVarint(b []byte, n uint64) []byte {
+	for n >= 0x80 {
+		b = append(b, byte(n)|0x80)
+		n >>= 7
+	}
+	return append(b, byte(n))
+}
+
+// appendProtoTag appends the tag of a field.
+func appendProtoTag(b []byte, field, wire int) []byte {
+	return appendProtoVarint(b, uint64(field)<<3|uint64(wire))
+}
+
+// appendProtoBytes appends a length-delimited field, e.g. a nested message.
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendProtoVarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+// appendProtoString appends a string field.
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = appendProtoVarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// appendProtoSint appends the int_value field of a Value, zigzag encoded.
+func appendProtoSint(b []byte, i int64) []byte {
+	b = appendProtoTag(b, 2, protoVarint)
+	return appendProtoVarint(b, zigzag(i))
+}
+
+// appendProtoUint appends the uint_value field of a Value.
+func appendProtoUint(b []byte, u uint64) []byte {
+	b = appendProtoTag(b, 3, protoVarint)
+	return appendProtoVarint(b, u)
+}
+
+// appendLittleEndian appends the size lowest bytes of u to buf, least significant first.
+func appendLittleEndian(buf []byte, u uint64, size int) []byte {
+	for i := 0; i < size; i++ {
+		buf = append(buf, byte(u>>uint(8*i)))
+	}
+	return buf
+}
@@ -0,0 +1,107 @@
+package graph
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestProtoRoundTrip(t *testing.T) {
+	g := New()
+	g.Set("nil", nil)
+	g.Set("bool", false)
+	g.Set("int", -3)
+	g.Set("min", int64(math.MinInt64))
+	g.Set("uint", uint8(200))
+	g.Set("float", float32(0.5))
+	g.Set("string", "")
+	g.Set("bytes", []byte{0, 1})
+	g.Set("list", []interface{}{nil, "x", 2})
+	g.Set("map", map[string]interface{}{"a": 1.25, "b": []interface{}{}})
+
+	g.Connect("nil", "bool", -7)
+	g.Connect("bool", "nil", 0)
+	g.Connect("int", "map", math.MaxInt32)
+
+	b, err := g.MarshalProto()
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	again, _ := g.MarshalProto()
+	if !bytes.Equal(b, again) {
+		t.Error("expected deterministic output")
+	}
+
+	read := New()
+	if err := read.UnmarshalProto(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for key, expected := range map[string]interface{}{
+		"nil":    nil,
+		"bool":   false,
+		"int":    int64(-3),
+		"min":    int64(math.MinInt64),
+		"uint":   uint64(200),
+		"float":  0.5,
+		"string": "",
+		"bytes":  []byte{0, 1},
+		"list":   []interface{}{nil, "x", int64(2)},
+		"map":    map[string]interface{}{"a": 1.25, "b": []interface{}{}},
+	} {
+		v, err := read.Get(key)
+		if err != nil {
+			t.Fatalf("missing vertex %q", key)
+		}
+		if !reflect.DeepEqual(v.Value(), expected) {
+			t.Errorf("%q: expected %#v, got %#v", key, expected, v.Value())
+		}
+	}
+
+	for _, e := range []Edge{{From: "nil", To: "bool", Weight: -7}, {From: "bool", To: "nil", Weight: 0}, {From: "int", To: "map", Weight: math.MaxInt32}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+}
+
+func TestUnmarshalProto(t *testing.T) {
+	// vertices a (value "x") and b, an edge a → b of weight 2 and an unknown field 9, as written by generated code
+	b := []byte{
+		0x0a, 0x08, 0x0a, 0x01, 'a', 0x12, 0x03, 0x2a, 0x01, 'x',
+		0x0a, 0x03, 0x0a, 0x01, 'b',
+		0x12, 0x08, 0x0a, 0x01, 'a', 0x12, 0x01, 'b', 0x18, 0x02,
+		0x48, 0x01,
+	}
+
+	g := New()
+	if err := g.UnmarshalProto(b); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if v, _ := g.Get("a"); v == nil || v.Value() != "x" {
+		t.Error("expected a with value x")
+	}
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 2 {
+		t.Errorf("expected a → b with weight 2, got %v %d", ok, weight)
+	}
+
+	for _, invalid := range [][]byte{
+		b[:len(b)-3],
+		{0x0a, 0x05, 0x0a},
+		{0x0b},
+		{0x00, 0x01},
+		{0x0a, 0x05, 0x12, 0x03, 0x08, 0x01, 0x01},
+		{0x12, 0x08, 0x0a, 0x01, 'a', 0x12, 0x01, 'b', 0x18, 0x02},
+	} {
+		if err := New().UnmarshalProto(invalid); err == nil {
+			t.Errorf("% x: expected an error", invalid)
+		}
+	}
+
+	g.Set("c", struct{}{})
+	if _, err := g.MarshalProto(); err == nil {
+		t.Error("expected an error for an unsupported value")
+	}
+}
@@ -0,0 +1,758 @@
+package graph
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// RDFWeightPredicate is the prefix of the predicates written for edges by default: an edge of weight 5 becomes a triple with the predicate urn:graph-store:weight:5. By default, ImportRDF reads the weights back from such predicates.
+const RDFWeightPredicate = "urn:graph-store:weight:"
+
+// RDFValuePredicate is the predicate of the triples written by default for vertex values which aren't maps.
+const RDFValuePredicate = "urn:graph-store:value"
+
+// Datatypes of typed literals written for numbers and booleans.
+const (
+	xsdInteger = "http://www.w3.org/2001/XMLSchema#integer"
+	xsdDecimal = "http://www.w3.org/2001/XMLSchema#decimal"
+	xsdDouble  = "http://www.w3.org/2001/XMLSchema#double"
+	xsdBoolean = "http://www.w3.org/2001/XMLSchema#boolean"
+)
+
+// RDFLiteral is a literal object of an RDF triple.
+type RDFLiteral struct {
+	Lexical  string // the lexical form, e.g. "42"
+	Language string // the language tag, if any
+	Datatype string // the datatype IRI, empty for plain strings
+}
+
+// RDFMapping describes how RDF triples map to vertices and edges. Subjects and objects which are IRIs or blank nodes become vertices keyed by the IRI or by _: and the blank node label. Every field is optional; nil functions are replaced by the default behavior.
+type RDFMapping struct {
+	// Base, if set, is stripped from IRIs starting with it to get keys when importing, and prepended to keys which aren't absolute IRIs when exporting, so keys can be short.
+	Base string
+
+	// Edge decides whether a triple whose object is an IRI or blank node becomes an edge, and its weight. By default, all of them do, with the weight encoded in predicates starting with RDFWeightPredicate, or 1.
+	Edge func(subject, predicate, object string) (weight int, ok bool)
+
+	// Literal returns the new value of the subject's vertex for a triple whose object is a literal, given its current value. By default, the value is a map[string]interface{} from predicates to the lexical forms of the literals.
+	Literal func(value interface{}, predicate string, literal RDFLiteral) interface{}
+
+	// Predicate returns the predicate of the triple written for an edge. By default, the weight is encoded in a predicate starting with RDFWeightPredicate.
+	Predicate func(e Edge) string
+
+	// Literals returns the triples with literal objects written for a vertex, by predicate. By default, maps with string keys produce a plain literal per entry formatted with fmt.Sprint, other values a single triple with the predicate RDFValuePredicate, and nil values none.
+	Literals func(key string, value interface{}) map[string]RDFLiteral
+}
+
+// defaults returns the mapping with nil functions replaced by the defaults.
+func (m RDFMapping) defaults() RDFMapping {
+	if m.Edge == nil {
+		m.Edge = func(_, predicate, _ string) (int, bool) {
+			if strings.HasPrefix(predicate, RDFWeightPredicate) {
+				if weight, err := strconv.Atoi(predicate[len(RDFWeightPredicate):]); err == nil {
+					return weight, true
+				}
+			}
+			return 1, true
+		}
+	}
+
+	if m.Literal == nil {
+		m.Literal = func(value interface{}, predicate string, literal RDFLiteral) interface{} {
+			properties, ok := value.(map[string]interface{})
+			if !ok {
+				properties = map[string]interface{}{}
+			}
+			properties[predicate] = literal.Lexical
+			return properties
+		}
+	}
+
+	if m.Predicate == nil {
+		m.Predicate = func(e Edge) string {
+			return RDFWeightPredicate + strconv.Itoa(e.Weight)
+		}
+	}
+
+	if m.Literals == nil {
+		m.Literals = func(_ string, value interface{}) map[string]RDFLiteral {
+			switch v := value.(type) {
+			case nil:
+				return nil
+			case map[string]interface{}:
+				literals := make(map[string]RDFLiteral, len(v))
+				for predicate, item := range v {
+					literals[predicate] = RDFLiteral{Lexical: fmt.Sprint(item)}
+				}
+				return literals
+			}
+			return map[string]RDFLiteral{RDFValuePredicate: rdfLiteral(value)}
+		}
+	}
+
+	return m
+}
+
+// rdfLiteral returns the typed literal for a number or boolean, or a plain literal formatted with fmt.Sprint for other values.
+func rdfLiteral(value interface{}) RDFLiteral {
+	switch v := value.(type) {
+	case bool:
+		return RDFLiteral{Lexical: strconv.FormatBool(v), Datatype: xsdBoolean}
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		return RDFLiteral{Lexical: fmt.Sprint(v), Datatype: xsdInteger}
+	case float32:
+		return RDFLiteral{Lexical: strconv.FormatFloat(float64(v), 'E', -1, 32), Datatype: xsdDouble}
+	case float64:
+		return RDFLiteral{Lexical: strconv.FormatFloat(v, 'E', -1, 64), Datatype: xsdDouble}
+	}
+	return RDFLiteral{Lexical: fmt.Sprint(value)}
+}
+
+// ImportRDF reads RDF triples in Turtle or N-Triples (which is a subset of Turtle) from r into the graph as described by m, creating vertices with nil values for subjects and objects which don't exist yet.
+// The parser supports prefixes, base IRIs, predicate and object lists, the keyword a, blank node labels and all literal forms; anonymous blank nodes ([...]) and collections ((...)) are not supported. Triples from a vertex to itself are skipped unless self-loops are enabled, see EnableSelfLoops.
+func (g *Graph) ImportRDF(r io.Reader, m RDFMapping) error {
+	defer g.track("ImportRDF")()
+
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("graph: reading RDF: %v", err)
+	}
+
+	m = m.defaults()
+
+	p := &turtleParser{src: string(b), line: 1, prefixes: map[string]string{}}
+
+	key := func(t turtleToken) string {
+		if t.kind == turtleBlank {
+			return t.text
+		}
+		if m.Base != "" && strings.HasPrefix(t.text, m.Base) && len(t.text) > len(m.Base) {
+			return t.text[len(m.Base):]
+		}
+		return t.text
+	}
+
+	vertex := func(key string) (*Vertex, error) {
+		if v, err := g.Get(key); err == nil {
+			return v, nil
+		}
+		if _, err := g.TrySet(key, nil); err != nil {
+			return nil, fmt.Errorf("graph: reading RDF: %v: %q", err, key)
+		}
+		return g.Get(key)
+	}
+
+	return p.parse(func(subject, predicate, object turtleToken) error {
+		from := key(subject)
+		v, err := vertex(from)
+		if err != nil {
+			return err
+		}
+
+		if object.kind == turtleLiteral {
+			if _, err := g.TrySet(from, m.Literal(v.Value(), predicate.text, object.literal)); err != nil {
+				return fmt.Errorf("graph: reading RDF: %v: %q", err, from)
+			}
+			return nil
+		}
+
+		to := key(object)
+		if _, err := vertex(to); err != nil {
+			return err
+		}
+
+		if weight, ok := m.Edge(from, predicate.text, to); ok && from != to {
+			g.Connect(from, to, weight)
+		}
+		return nil
+	})
+}
+
+// WriteNTriples writes the graph to w as RDF in N-Triples format, as described by m: the triples with literal objects of each vertex, then one triple for each of its edges, in key order.
+func (g *Graph) WriteNTriples(w io.Writer, m RDFMapping) error {
+	defer g.track("WriteNTriples")()
+
+	bw := bufio.NewWriter(w)
+
+	g.RLock()
+	g.rdfTriples(m.defaults(), func(subject string, predicates []string, objects []string) {
+		for i := range predicates {
+			fmt.Fprintf(bw, "%s %s %s .\n", subject, predicates[i], objects[i])
+		}
+	})
+	g.RUnlock()
+
+	return bw.Flush()
+}
+
+// WriteTurtle writes the graph to w as RDF in Turtle format, as described by m, with the triples grouped by subject in key order. IRIs are written in full.
+func (g *Graph) WriteTurtle(w io.Writer, m RDFMapping) error {
+	defer g.track("WriteTurtle")()
+
+	bw := bufio.NewWriter(w)
+
+	g.RLock()
+	g.rdfTriples(m.defaults(), func(subject string, predicates []string, objects []string) {
+		if len(predicates) == 0 {
+			return
+		}
+
+		fmt.Fprintf(bw, "%s", subject)
+		for i := range predicates {
+			sep := " ;\n   "
+			if i == 0 {
+				sep = ""
+			}
+			fmt.Fprintf(bw, "%s %s %s", sep, predicates[i], objects[i])
+		}
+		bw.WriteString(" .\n")
+	})
+	g.RUnlock()
+
+	return bw.Flush()
+}
+
+// rdfTriples is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It calls fn with the formatted triples of each vertex in key order: the literals by predicate, then the edges by target key.
+func (g *Graph) rdfTriples(m RDFMapping, fn func(subject string, predicates []string, objects []string)) {
+	term := func(key string) string {
+		if strings.HasPrefix(key, "_:") {
+			return key
+		}
+		if m.Base != "" && !isAbsoluteIRI(key) {
+			key = m.Base + key
+		}
+		return rdfIRI(key)
+	}
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+		var predicates, objects []string
+
+		literals := m.Literals(key, v.Value())
+		names := make([]string, 0, len(literals))
+		for predicate := range literals {
+			names = append(names, predicate)
+		}
+		sort.Strings(names)
+
+		for _, predicate := range names {
+			predicates = append(predicates, rdfIRI(predicate))
+			objects = append(objects, literals[predicate].String())
+		}
+
+		outgoing := v.GetOutgoing()
+		neighbors := make([]*Vertex, 0, len(outgoing))
+		for neighbor := range outgoing {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool { return neighbors[i].key < neighbors[j].key })
+
+		for _, neighbor := range neighbors {
+			predicates = append(predicates, rdfIRI(m.Predicate(Edge{From: key, To: neighbor.key, Weight: outgoing[neighbor]})))
+			objects = append(objects, term(neighbor.key))
+		}
+
+		fn(term(key), predicates, objects)
+	}
+}
+
+// String formats the literal in N-Triples and Turtle syntax.
+func (l RDFLiteral) String() string {
+	var sb strings.Builder
+
+	sb.WriteByte('"')
+	for _, r := range l.Lexical {
+		switch r {
+		case '"':
+			sb.WriteString(`\"`)
+		case '\\':
+			sb.WriteString(`\\`)
+		case '\n':
+			sb.WriteString(`\n`)
+		case '\r':
+			sb.WriteString(`\r`)
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+
+	if l.Language != "" {
+		sb.WriteString("@" + l.Language)
+	} else if l.Datatype != "" {
+		sb.WriteString("^^" + rdfIRI(l.Datatype))
+	}
+
+	return sb.String()
+}
+
+// rdfIRI formats iri as an IRI reference, escaping the characters which aren't allowed in it.
+func rdfIRI(iri string) string {
+	var sb strings.Builder
+
+	sb.WriteByte('<')
+	for _, r := range iri {
+		if r <= ' ' || strings.ContainsRune("<>\"{}|^`\\", r) {
+			fmt.Fprintf(&sb, `\u%04X`, r)
+		} else {
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('>')
+
+	return sb.String()
+}
+
+// isAbsoluteIRI returns true if iri starts with a scheme like http:.
+func isAbsoluteIRI(iri string) bool {
+	for i, c := range iri {
+		switch {
+		case c|0x20 >= 'a' && c|0x20 <= 'z':
+		case i > 0 && (c >= '0' && c <= '9' || c == '+' || c == '-' || c == '.'):
+		case i > 0 && c == ':':
+			return true
+		default:
+			return false
+		}
+	}
+	return false
+}
+
+// turtleKind is the kind of a turtleToken.
+type turtleKind int
+
+const (
+	turtleEOF     turtleKind = iota
+	turtleIRI                // an IRI, resolved against the base and prefixes
+	turtleBlank              // a blank node, with its label prefixed by _:
+	turtleLiteral            // a literal
+	turtlePunct              // one of . ; ,
+	turtleKeyword            // a, @prefix, @base, PREFIX or BASE
+)
+
+// turtleToken is a token of the Turtle language.
+type turtleToken struct {
+	kind    turtleKind
+	text    string
+	literal RDFLiteral
+}
+
+// turtleParser parses the supported subset of Turtle.
+type turtleParser struct {
+	src      string
+	pos      int
+	line     int
+	base     string
+	prefixes map[string]string
+}
+
+// errorf returns a parse error at the current line.
+func (p *turtleParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("graph: reading RDF: line %d: %s", p.line, fmt.Sprintf(format, args...))
+}
+
+// parse calls fn with every triple, stopping at the first error.
+func (p *turtleParser) parse(fn func(subject, predicate, object turtleToken) error) error {
+	for {
+		t, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case t.kind == turtleEOF:
+			return nil
+
+		case t.kind == turtleKeyword && t.text != "a":
+			if err := p.directive(t.text); err != nil {
+				return err
+			}
+
+		case t.kind == turtleIRI || t.kind == turtleBlank:
+			if err := p.predicateObjectList(t, fn); err != nil {
+				return err
+			}
+
+		default:
+			return p.errorf("expected subject, found %q", t.text)
+		}
+	}
+}
+
+// directive parses the rest of a @prefix, @base, PREFIX or BASE directive.
+func (p *turtleParser) directive(keyword string) error {
+	prefix := ""
+	if keyword == "@prefix" || keyword == "PREFIX" {
+		p.skip()
+		if prefix = p.word(); !strings.HasSuffix(prefix, ":") || strings.IndexByte(prefix, ':') != len(prefix)-1 {
+			return p.errorf("expected prefix, found %q", prefix)
+		}
+	}
+
+	p.skip()
+	if p.pos >= len(p.src) || p.src[p.pos] != '<' {
+		return p.errorf("expected IRI after %s", keyword)
+	}
+	iri, err := p.iriRef()
+	if err != nil {
+		return err
+	}
+
+	if prefix != "" {
+		p.prefixes[prefix] = iri
+	} else {
+		p.base = iri
+	}
+
+	// the SPARQL style directives have no final dot
+	if keyword[0] == '@' {
+		if t, err := p.next(); err != nil || t.kind != turtlePunct || t.text != "." {
+			return p.errorf("expected \".\" after %s", keyword)
+		}
+	}
+
+	return nil
+}
+
+// predicateObjectList parses the predicates and objects following subject, up to and including the final dot.
+func (p *turtleParser) predicateObjectList(subject turtleToken, fn func(subject, predicate, object turtleToken) error) error {
+	for {
+		predicate, err := p.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case predicate.kind == turtleKeyword && predicate.text == "a":
+			predicate = turtleToken{kind: turtleIRI, text: "http://www.w3.org/1999/02/22-rdf-syntax-ns#type"}
+		case predicate.kind == turtlePunct && predicate.text == ".":
+			// a trailing semicolon before the dot
+			return nil
+		case predicate.kind != turtleIRI:
+			return p.errorf("expected predicate, found %q", predicate.text)
+		}
+
+		sep := ","
+		for sep == "," {
+			object, err := p.next()
+			if err != nil {
+				return err
+			}
+			if object.kind != turtleIRI && object.kind != turtleBlank && object.kind != turtleLiteral {
+				return p.errorf("expected object, found %q", object.text)
+			}
+
+			if err := fn(subject, predicate, object); err != nil {
+				return err
+			}
+
+			t, err := p.next()
+			if err != nil {
+				return err
+			}
+			if t.kind != turtlePunct {
+				return p.errorf("expected \".\", \";\" or \",\", found %q", t.text)
+			}
+			sep = t.text
+		}
+
+		if sep == "." {
+			return nil
+		}
+
+		// skip repeated semicolons
+		for p.skip(); p.pos < len(p.src) && p.src[p.pos] == ';'; p.skip() {
+			p.pos++
+		}
+	}
+}
+
+// skip skips whitespace and comments.
+func (p *turtleParser) skip() {
+	for p.pos < len(p.src) {
+		switch c := p.src[p.pos]; {
+		case c == '\n':
+			p.line++
+			p.pos++
+		case c == ' ' || c == '\t' || c == '\r':
+			p.pos++
+		case c == '#':
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		default:
+			return
+		}
+	}
+}
+
+// next returns the next token.
+func (p *turtleParser) next() (turtleToken, error) {
+	p.skip()
+	if p.pos >= len(p.src) {
+		return turtleToken{kind: turtleEOF}, nil
+	}
+
+	switch c := p.src[p.pos]; {
+	case c == '.' && (p.pos+1 >= len(p.src) || p.src[p.pos+1] < '0' || p.src[p.pos+1] > '9'), c == ';', c == ',':
+		p.pos++
+		return turtleToken{kind: turtlePunct, text: string(c)}, nil
+
+	case c == '<':
+		iri, err := p.iriRef()
+		if err != nil {
+			return turtleToken{}, err
+		}
+		return turtleToken{kind: turtleIRI, text: iri}, nil
+
+	case c == '"' || c == '\'':
+		return p.literal()
+
+	case c == '[' || c == '(':
+		return turtleToken{}, p.errorf("anonymous blank nodes and collections are not supported")
+
+	case c == '@':
+		word := p.word()
+		if word != "@prefix" && word != "@base" {
+			return turtleToken{}, p.errorf("unexpected %q", word)
+		}
+		return turtleToken{kind: turtleKeyword, text: word}, nil
+	}
+
+	word := p.word()
+
+	switch {
+	case word == "":
+		return turtleToken{}, p.errorf("unexpected %q", p.src[p.pos])
+
+	case strings.HasPrefix(word, "_:"):
+		return turtleToken{kind: turtleBlank, text: word}, nil
+
+	case word == "a", strings.EqualFold(word, "PREFIX"), strings.EqualFold(word, "BASE"):
+		if word != "a" {
+			word = strings.ToUpper(word)
+		}
+		return turtleToken{kind: turtleKeyword, text: word}, nil
+
+	case word == "true" || word == "false":
+		return turtleToken{kind: turtleLiteral, text: word, literal: RDFLiteral{Lexical: word, Datatype: xsdBoolean}}, nil
+
+	case strings.IndexByte(word, ':') >= 0:
+		i := strings.IndexByte(word, ':')
+		prefix, local := word[:i+1], word[i+1:]
+
+		namespace, ok := p.prefixes[prefix]
+		if !ok {
+			return turtleToken{}, p.errorf("undefined prefix %q", prefix)
+		}
+		return turtleToken{kind: turtleIRI, text: namespace + unescapeLocal(local)}, nil
+	}
+
+	// numbers
+	datatype := xsdInteger
+	if strings.ContainsAny(word, "eE") {
+		datatype = xsdDouble
+	} else if strings.Contains(word, ".") {
+		datatype = xsdDecimal
+	}
+	if _, err := strconv.ParseFloat(word, 64); err != nil || strings.Trim(word, "0123456789+-.eE") != "" {
+		return turtleToken{}, p.errorf("unexpected %q", word)
+	}
+
+	return turtleToken{kind: turtleLiteral, text: word, literal: RDFLiteral{Lexical: word, Datatype: datatype}}, nil
+}
+
+// word reads a prefixed name, blank node label, keyword or number. A final dot is left, since it ends the statement.
+func (p *turtleParser) word() string {
+	start := p.pos
+	for p.pos < len(p.src) && !strings.ContainsRune(" \t\r\n<>\"'();,[]#", rune(p.src[p.pos])) {
+		p.pos++
+	}
+	for p.pos > start && p.src[p.pos-1] == '.' {
+		p.pos--
+	}
+	return p.src[start:p.pos]
+}
+
+// unescapeLocal removes the backslashes escaping characters in the local part of a prefixed name.
+func unescapeLocal(local string) string {
+	if strings.IndexByte(local, '\\') < 0 {
+		return local
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(local); i++ {
+		if local[i] == '\\' && i+1 < len(local) {
+			i++
+		}
+		sb.WriteByte(local[i])
+	}
+	return sb.String()
+}
+
+// iriRef reads an IRI reference in angle brackets and resolves it against the base IRI.
+func (p *turtleParser) iriRef() (string, error) {
+	end := strings.IndexByte(p.src[p.pos:], '>')
+	if end < 0 {
+		return "", p.errorf("unterminated IRI")
+	}
+
+	raw := p.src[p.pos+1 : p.pos+end]
+	p.pos += end + 1
+
+	if strings.ContainsAny(raw, " \n") {
+		return "", p.errorf("invalid IRI %q", raw)
+	}
+
+	iri, err := unescapeUnicode(raw)
+	if err != nil {
+		return "", p.errorf("%v", err)
+	}
+
+	if p.base != "" && !isAbsoluteIRI(iri) {
+		base, err := url.Parse(p.base)
+		if err == nil {
+			if ref, err := url.Parse(iri); err == nil {
+				iri = base.ResolveReference(ref).String()
+			}
+		}
+	}
+
+	return iri, nil
+}
+
+// literal reads a quoted literal with an optional language tag or datatype.
+func (p *turtleParser) literal() (turtleToken, error) {
+	quote := p.src[p.pos : p.pos+1]
+	if strings.HasPrefix(p.src[p.pos:], quote+quote+quote) {
+		quote += quote + quote
+	}
+	p.pos += len(quote)
+
+	var sb strings.Builder
+	for {
+		if p.pos >= len(p.src) {
+			return turtleToken{}, p.errorf("unterminated literal")
+		}
+		if strings.HasPrefix(p.src[p.pos:], quote) {
+			p.pos += len(quote)
+			break
+		}
+
+		c := p.src[p.pos]
+		switch {
+		case c == '\n' && len(quote) == 1:
+			return turtleToken{}, p.errorf("line break in literal")
+
+		case c == '\\' && p.pos+1 < len(p.src):
+			e := p.src[p.pos+1]
+			p.pos += 2
+
+			switch e {
+			case 't':
+				sb.WriteByte('\t')
+			case 'b':
+				sb.WriteByte('\b')
+			case 'n':
+				sb.WriteByte('\n')
+			case 'r':
+				sb.WriteByte('\r')
+			case 'f':
+				sb.WriteByte('\f')
+			case '"', '\'', '\\':
+				sb.WriteByte(e)
+			case 'u', 'U':
+				n := 4
+				if e == 'U' {
+					n = 8
+				}
+				if p.pos+n > len(p.src) {
+					return turtleToken{}, p.errorf("invalid escape")
+				}
+				r, err := strconv.ParseUint(p.src[p.pos:p.pos+n], 16, 32)
+				if err != nil || !utf8.ValidRune(rune(r)) {
+					return turtleToken{}, p.errorf("invalid escape")
+				}
+				sb.WriteRune(rune(r))
+				p.pos += n
+			default:
+				return turtleToken{}, p.errorf("invalid escape \\%c", e)
+			}
+			continue
+
+		case c == '\n':
+			p.line++
+		}
+
+		sb.WriteByte(c)
+		p.pos++
+	}
+
+	t := turtleToken{kind: turtleLiteral, literal: RDFLiteral{Lexical: sb.String()}}
+	t.text = t.literal.Lexical
+
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "@"):
+		p.pos++
+		start := p.pos
+		for p.pos < len(p.src) && (isGMLLetter(p.src[p.pos]) || p.src[p.pos] == '-' || (p.src[p.pos] >= '0' && p.src[p.pos] <= '9')) {
+			p.pos++
+		}
+		t.literal.Language = p.src[start:p.pos]
+
+	case strings.HasPrefix(p.src[p.pos:], "^^"):
+		p.pos += 2
+		datatype, err := p.next()
+		if err != nil {
+			return turtleToken{}, err
+		}
+		if datatype.kind != turtleIRI {
+			return turtleToken{}, p.errorf("expected datatype, found %q", datatype.text)
+		}
+		t.literal.Datatype = datatype.text
+	}
+
+	return t, nil
+}
+
+// unescapeUnicode replaces the \uXXXX and \UXXXXXXXX escapes in an IRI.
+func unescapeUnicode(s string) (string, error) {
+	if strings.IndexByte(s, '\\') < 0 {
+		return s, nil
+	}
+
+	var sb strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' {
+			sb.WriteByte(s[i])
+			continue
+		}
+
+		n := 0
+		if i+1 < len(s) && s[i+1] == 'u' {
+			n = 4
+		} else if i+1 < len(s) && s[i+1] == 'U' {
+			n = 8
+		}
+		if n == 0 || i+2+n > len(s) {
+			return "", fmt.Errorf("invalid escape in IRI %q", s)
+		}
+
+		r, err := strconv.ParseUint(s[i+2:i+2+n], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(r)) {
+			return "", fmt.Errorf("invalid escape in IRI %q", s)
+		}
+		sb.WriteRune(rune(r))
+		i += 1 + n
+	}
+
+	return sb.String(), nil
+}
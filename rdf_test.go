@@ -0,0 +1,167 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestImportRDF(t *testing.T) {
+	turtle := `@prefix ex: <http://example.org/> .
+PREFIX foaf: <http://xmlns.com/foaf/0.1/>
+@base <http://example.org/people/> .
+
+# a comment
+<alice> a foaf:Person ;
+    foaf:name "Alice"@en, "Alicia" ;
+    foaf:knows <bob>, _:carol ;
+    ex:age 42 ;
+    ex:note """multi
+line""" ;
+.
+<bob> foaf:knows <bob> ; ex:height "1.8"^^<http://www.w3.org/2001/XMLSchema#decimal> .
+_:carol <urn:graph-store:weight:7> ex:team.
+`
+
+	g := New()
+	if err := g.ImportRDF(strings.NewReader(turtle), RDFMapping{Base: "http://example.org/people/"}); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if g.Len() != 5 {
+		t.Errorf("expected 5 vertices, got %d", g.Len())
+	}
+
+	alice, err := g.Get("alice")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	expected := map[string]interface{}{
+		"http://xmlns.com/foaf/0.1/name": "Alicia",
+		"http://example.org/age":         "42",
+		"http://example.org/note":        "multi\nline",
+	}
+	if !reflect.DeepEqual(alice.Value(), expected) {
+		t.Errorf("expected %v, got %v", expected, alice.Value())
+	}
+
+	for _, e := range []Edge{
+		{From: "alice", To: "http://xmlns.com/foaf/0.1/Person", Weight: 1},
+		{From: "alice", To: "bob", Weight: 1},
+		{From: "alice", To: "_:carol", Weight: 1},
+		{From: "_:carol", To: "http://example.org/team", Weight: 7},
+	} {
+		if ok, weight := g.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+
+	// self-loops are skipped
+	if ok, _ := g.IsConnected("bob", "bob"); ok {
+		t.Error("expected no self-loop")
+	}
+
+	// N-Triples and custom mappings
+	ntriples := `<http://a> <http://p/keep> <http://b> .
+<http://a> <http://p/drop> <http://c> .
+<http://a> <http://p/label> "xé\"y" .
+`
+	h := New()
+	err = h.ImportRDF(strings.NewReader(ntriples), RDFMapping{
+		Edge: func(_, predicate, _ string) (int, bool) {
+			return 3, predicate == "http://p/keep"
+		},
+		Literal: func(_ interface{}, _ string, literal RDFLiteral) interface{} {
+			return literal.Lexical
+		},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := h.IsConnected("http://a", "http://b"); !ok || weight != 3 {
+		t.Errorf("expected edge of weight 3, got %v %d", ok, weight)
+	}
+	if ok, _ := h.IsConnected("http://a", "http://c"); ok {
+		t.Error("expected dropped edge")
+	}
+	if v, _ := h.Get("http://a"); v.Value() != "xé\"y" {
+		t.Errorf("expected literal value, got %v", v.Value())
+	}
+}
+
+func TestImportRDFErrors(t *testing.T) {
+	for _, input := range []string{
+		`<a> <b> .`,
+		`<a> <b> <c>`,
+		`<a> <b> [ <c> <d> ] .`,
+		`<a> <b> ( <c> ) .`,
+		`<a> ex:b <c> .`,
+		`<a> <b> "unterminated .`,
+		`<a> <b> "bad \q escape" .`,
+		`<a> <b> nonsense .`,
+		`"literal" <b> <c> .`,
+		`@prefix ex <http://example.org/> .`,
+	} {
+		if err := New().ImportRDF(strings.NewReader(input), RDFMapping{}); err == nil {
+			t.Errorf("%q: expected an error", input)
+		}
+	}
+}
+
+func TestWriteRDF(t *testing.T) {
+	g := New()
+	g.Set("a", map[string]interface{}{"http://example.org/name": "A \"1\""})
+	g.Set("b", 5)
+	g.Set("_:c", nil)
+
+	g.Connect("a", "b", 2)
+	g.Connect("a", "_:c", 1)
+	g.Connect("b", "a", -1)
+
+	m := RDFMapping{Base: "http://example.org/"}
+
+	buf := &bytes.Buffer{}
+	if err := g.WriteNTriples(buf, m); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected := `<http://example.org/a> <http://example.org/name> "A \"1\"" .
+<http://example.org/a> <urn:graph-store:weight:1> _:c .
+<http://example.org/a> <urn:graph-store:weight:2> <http://example.org/b> .
+<http://example.org/b> <urn:graph-store:value> "5"^^<http://www.w3.org/2001/XMLSchema#integer> .
+<http://example.org/b> <urn:graph-store:weight:-1> <http://example.org/a> .
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	buf.Reset()
+	if err := g.WriteTurtle(buf, m); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	expected = `<http://example.org/a> <http://example.org/name> "A \"1\"" ;
+    <urn:graph-store:weight:1> _:c ;
+    <urn:graph-store:weight:2> <http://example.org/b> .
+<http://example.org/b> <urn:graph-store:value> "5"^^<http://www.w3.org/2001/XMLSchema#integer> ;
+    <urn:graph-store:weight:-1> <http://example.org/a> .
+`
+	if buf.String() != expected {
+		t.Errorf("expected\n%s\ngot\n%s", expected, buf.String())
+	}
+
+	// edges and map values survive a round trip
+	read := New()
+	if err := read.ImportRDF(buf, m); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	for _, e := range []Edge{{From: "a", To: "b", Weight: 2}, {From: "a", To: "_:c", Weight: 1}, {From: "b", To: "a", Weight: -1}} {
+		if ok, weight := read.IsConnected(e.From, e.To); !ok || weight != e.Weight {
+			t.Errorf("%s → %s: expected weight %d, got %v %d", e.From, e.To, e.Weight, ok, weight)
+		}
+	}
+	if v, _ := read.Get("a"); !reflect.DeepEqual(v.Value(), g.vertices["a"].Value()) {
+		t.Errorf("expected %v, got %v", g.vertices["a"].Value(), v.Value())
+	}
+}
@@ -0,0 +1,197 @@
+package graph
+
+import (
+	"sort"
+)
+
+// reachable is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the set of vertices reachable from any of roots by following outgoing edges, including the roots themselves.
+func (g *Graph) reachable(roots []*Vertex) map[*Vertex]bool {
+	return g.breadthFirst(roots, false, nil)
+}
+
+// breadthFirst is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It visits the vertices reachable from any of roots in breadth-first order, following incoming instead of outgoing edges if reverse is true, and returns the set of visited vertices, including the roots.
+// If visit is not nil, it is called for every vertex reached, and the search stops when it returns false.
+func (g *Graph) breadthFirst(roots []*Vertex, reverse bool, visit func(v *Vertex) bool) map[*Vertex]bool {
+	visited := make(map[*Vertex]bool, len(roots))
+	queue := make([]*Vertex, 0, len(roots))
+
+	for _, root := range roots {
+		if !visited[root] {
+			visited[root] = true
+			queue = append(queue, root)
+
+			if visit != nil && !visit(root) {
+				return visited
+			}
+		}
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		edges := current.GetOutgoing()
+		if reverse {
+			edges = current.GetIncoming()
+		}
+
+		for neighbor := range edges {
+			if !visited[neighbor] {
+				visited[neighbor] = true
+				queue = append(queue, neighbor)
+
+				if visit != nil && !visit(neighbor) {
+					return visited
+				}
+			}
+		}
+	}
+
+	return visited
+}
+
+// CanReach returns true if there is a path from the vertex with key fromKey to the vertex with key toKey, which is always the case if they are the same. Returns false if one of the keys is invalid.
+// The breadth-first search stops as soon as toKey is reached, under a read lock, so the answer is consistent with concurrent changes.
+func (g *Graph) CanReach(fromKey, toKey string) bool {
+	defer g.track("CanReach")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	from := g.get(fromKey)
+	to := g.get(toKey)
+
+	if from == nil || to == nil {
+		return false
+	}
+
+	found := false
+	g.breadthFirst([]*Vertex{from}, false, func(v *Vertex) bool {
+		found = v == to
+		return !found
+	})
+
+	return found
+}
+
+// ReachableFrom returns the sorted keys of all vertices reachable from the vertex with the specified key by following outgoing edges, including the vertex itself. Returns ErrInvalidKey if the key is invalid.
+func (g *Graph) ReachableFrom(key string) ([]string, error) {
+	defer g.track("ReachableFrom")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	v := g.get(key)
+	if v == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return sortedVertexKeys(g.reachable([]*Vertex{v})), nil
+}
+
+// Ancestors returns the sorted keys of all vertices from which the vertex with the specified key can be reached, e.g. everything it depends on if edges point from dependencies to their dependents. Returns ErrInvalidKey if the key is invalid.
+// The vertex itself is not included, even if it is on a cycle.
+func (g *Graph) Ancestors(key string) ([]string, error) {
+	defer g.track("Ancestors")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.related(key, true)
+}
+
+// Descendants returns the sorted keys of all vertices reachable from the vertex with the specified key, e.g. everything affected by a change to it if edges point from dependencies to their dependents. Returns ErrInvalidKey if the key is invalid.
+// The vertex itself is not included, even if it is on a cycle.
+func (g *Graph) Descendants(key string) ([]string, error) {
+	defer g.track("Descendants")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.related(key, false)
+}
+
+// related is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the sorted keys of the descendants of the vertex with key, or its ancestors if reverse is true.
+func (g *Graph) related(key string, reverse bool) ([]string, error) {
+	v := g.get(key)
+	if v == nil {
+		return nil, ErrInvalidKey
+	}
+
+	visited := g.breadthFirst([]*Vertex{v}, reverse, nil)
+	delete(visited, v)
+
+	return sortedVertexKeys(visited), nil
+}
+
+// sortedVertexKeys returns the sorted keys of the vertices in set.
+func sortedVertexKeys(set map[*Vertex]bool) []string {
+	keys := make([]string, 0, len(set))
+	for v := range set {
+		keys = append(keys, v.key)
+	}
+	sort.Strings(keys)
+
+	return keys
+}
+
+// TransitiveClosure returns a new graph with the same vertices (values are copied shallowly) and an edge of weight 1 from one vertex to another whenever there is a directed path between them in this graph, for fast repeated reachability queries with IsConnected.
+// Vertices on cycles are connected to themselves only if self-loops are enabled, see EnableSelfLoops.
+func (g *Graph) TransitiveClosure() *Graph {
+	defer g.track("TransitiveClosure")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	closure := New()
+	closure.selfLoops = g.selfLoops
+	for key, v := range g.vertices {
+		closure.vertices[key] = newVertex(key, v.Value())
+	}
+
+	for key, v := range g.vertices {
+		from := closure.vertices[key]
+
+		// start at the neighbors, so v is only reached if it is on a cycle
+		var neighbors []*Vertex
+		for neighbor := range v.GetOutgoing() {
+			neighbors = append(neighbors, neighbor)
+		}
+
+		for reached := range g.reachable(neighbors) {
+			if reached == v && !g.selfLoops {
+				continue
+			}
+
+			to := closure.vertices[reached.key]
+			from.outgoingEdges[to] = 1
+			to.incomingEdges[from] = 1
+		}
+	}
+
+	return closure
+}
+
+// ExportReachable returns a copy of the subgraph of all vertices reachable from the vertices with the given keys (by following outgoing edges), so huge graphs can be shared piecemeal: the copy can be written by any of the graph's encoders, e.g. GobEncode, MarshalJSON or WriteDOT, and decoded into a new graph, or into an existing one to merge it.
+// Values are copied shallowly with the tags and labels. Returns ErrInvalidKey if one of the root keys is invalid.
+func (g *Graph) ExportReachable(rootKeys []string) (*Graph, error) {
+	defer g.track("ExportReachable")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	roots := make([]*Vertex, len(rootKeys))
+	for i, key := range rootKeys {
+		roots[i] = g.get(key)
+		if roots[i] == nil {
+			return nil, ErrInvalidKey
+		}
+	}
+
+	reachable := g.reachable(roots)
+
+	return g.subgraph(func(v *Vertex) bool { return reachable[v] }), nil
+}
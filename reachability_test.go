@@ -0,0 +1,119 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTransitiveClosure(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d"} {
+		g.Set(key, key)
+	}
+
+	// a → b → c → b, d isolated
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", 5)
+	g.Connect("c", "b", 5)
+
+	closure := g.TransitiveClosure()
+
+	expected := map[[2]string]bool{
+		{"a", "b"}: true,
+		{"a", "c"}: true,
+		{"b", "c"}: true,
+		{"c", "b"}: true,
+	}
+
+	for _, from := range []string{"a", "b", "c", "d"} {
+		for _, to := range []string{"a", "b", "c", "d"} {
+			ok, weight := closure.IsConnected(from, to)
+			if ok != expected[[2]string{from, to}] || (ok && weight != 1) {
+				t.Errorf("%s → %s: unexpected edge %v", from, to, ok)
+			}
+		}
+	}
+
+	if v, _ := closure.Get("d"); v.Value() != "d" {
+		t.Fail()
+	}
+
+	// the original is unchanged
+	if ok, _ := g.IsConnected("a", "c"); ok {
+		t.Fail()
+	}
+}
+
+func TestCanReach(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	// a → b → c → a, c → d, e isolated
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("c", "d", 1)
+
+	for _, c := range []struct {
+		from, to string
+		expected bool
+	}{
+		{"a", "d", true},
+		{"c", "b", true},
+		{"d", "a", false},
+		{"a", "e", false},
+		{"e", "e", true},
+		{"a", "x", false},
+	} {
+		if g.CanReach(c.from, c.to) != c.expected {
+			t.Errorf("%s → %s: expected %v", c.from, c.to, c.expected)
+		}
+	}
+
+	if keys, err := g.ReachableFrom("b"); err != nil || !reflect.DeepEqual(keys, []string{"a", "b", "c", "d"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if keys, err := g.ReachableFrom("d"); err != nil || !reflect.DeepEqual(keys, []string{"d"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+	if _, err := g.ReachableFrom("x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
+
+func TestAncestorsDescendants(t *testing.T) {
+	g := New()
+	for _, key := range []string{"libc", "openssl", "curl", "git", "vim", "python"} {
+		g.Set(key, nil)
+	}
+
+	// edges point from a dependency to its dependents
+	g.Connect("libc", "openssl", 1)
+	g.Connect("libc", "vim", 1)
+	g.Connect("openssl", "curl", 1)
+	g.Connect("openssl", "python", 1)
+	g.Connect("curl", "git", 1)
+	g.Connect("python", "vim", 1)
+
+	if keys, err := g.Descendants("openssl"); err != nil || !reflect.DeepEqual(keys, []string{"curl", "git", "python", "vim"}) {
+		t.Errorf("unexpected descendants %v", keys)
+	}
+	if keys, err := g.Ancestors("vim"); err != nil || !reflect.DeepEqual(keys, []string{"libc", "openssl", "python"}) {
+		t.Errorf("unexpected ancestors %v", keys)
+	}
+	if keys, err := g.Ancestors("libc"); err != nil || len(keys) != 0 {
+		t.Errorf("unexpected ancestors %v", keys)
+	}
+
+	// a cycle doesn't make a vertex its own ancestor
+	g.Connect("git", "libc", 1)
+	if keys, _ := g.Ancestors("libc"); !reflect.DeepEqual(keys, []string{"curl", "git", "openssl"}) {
+		t.Errorf("unexpected ancestors %v", keys)
+	}
+
+	if _, err := g.Descendants("x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
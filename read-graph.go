@@ -0,0 +1,50 @@
+package graph
+
+// ReadGraph is an immutable view of a graph stored in a file written by WriteFlatBufferFile. The file is mapped into memory read-only, so processes opening the same file share its pages through the page cache, and the working set can exceed the heap: only the pages touched by queries are read from disk.
+// Queries are answered by the embedded FlatGraph. ReadGraph is safe for concurrent use, but must not be used after Close. On systems without mmap support, the file is read into memory instead.
+type ReadGraph struct {
+	*FlatGraph
+	data []byte // the mapped file
+}
+
+// WriteFlatBufferFile writes the graph to the file at path in the format of MarshalFlatBuffer, for OpenReadGraph. The file is written to a temporary file first, which is renamed once it is complete, so processes which mapped an earlier version of the file keep using it undisturbed.
+func (g *Graph) WriteFlatBufferFile(path string) error {
+	defer g.track("WriteFlatBufferFile")()
+
+	b, err := g.MarshalFlatBuffer()
+	if err != nil {
+		return err
+	}
+
+	return writeFileAtomic(path, b)
+}
+
+// OpenReadGraph maps the file at path written by WriteFlatBufferFile into memory and returns a view of the graph in it. Opening takes constant time and memory, regardless of the size of the graph.
+// Returns ErrInvalidFlatBuffer if the file doesn't hold a graph. The file must not be modified while it is open; replace it by renaming instead.
+func OpenReadGraph(path string) (*ReadGraph, error) {
+	data, err := mmapFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := NewFlatGraph(data)
+	if err != nil {
+		munmap(data)
+		return nil, err
+	}
+
+	return &ReadGraph{f, data}, nil
+}
+
+// Close unmaps the file. The view must not be used afterwards, but keys and values returned by it stay valid.
+func (r *ReadGraph) Close() error {
+	if r.data == nil {
+		return nil
+	}
+
+	err := munmap(r.data)
+	r.data = nil
+	r.FlatGraph = nil
+
+	return err
+}
@@ -0,0 +1,94 @@
+package graph
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestReadGraph(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readgraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.fb")
+
+	g := New()
+	g.Set("a", "one")
+	g.Set("b", nil)
+	g.Set("c", int64(3))
+	g.Connect("a", "b", 2)
+	g.Connect("a", "c", -1)
+
+	if err := g.WriteFlatBufferFile(path); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	r, err := OpenReadGraph(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	if r.Len() != 3 || !reflect.DeepEqual(r.Keys(), []string{"a", "b", "c"}) {
+		t.Errorf("unexpected vertices %v", r.Keys())
+	}
+	if value, err := r.Get("a"); value != "one" || err != nil {
+		t.Errorf("unexpected value %v %v", value, err)
+	}
+	if ok, weight := r.IsConnected("a", "c"); !ok || weight != -1 {
+		t.Errorf("expected edge, got %v %d", ok, weight)
+	}
+
+	// replacing the file doesn't affect open views
+	g.Delete("c")
+	if err := g.WriteFlatBufferFile(path); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	outgoing, err := r.GetOutgoing("a")
+	if err != nil || !reflect.DeepEqual(outgoing, map[string]int{"b": 2, "c": -1}) {
+		t.Errorf("unexpected outgoing edges %v %v", outgoing, err)
+	}
+
+	keys := r.Keys()
+	if err := r.Close(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := r.Close(); err != nil {
+		t.Errorf("expected closing twice to succeed, got %v", err)
+	}
+	if keys[2] != "c" {
+		t.Errorf("expected keys to stay valid, got %v", keys)
+	}
+
+	r, err = OpenReadGraph(path)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	defer r.Close()
+	if r.Len() != 2 {
+		t.Errorf("expected 2 vertices after reopening, got %d", r.Len())
+	}
+}
+
+func TestOpenReadGraphErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "readgraph")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	for _, contents := range []string{"", "not a graph"} {
+		path := filepath.Join(dir, "invalid")
+		ioutil.WriteFile(path, []byte(contents), 0600)
+		if _, err := OpenReadGraph(path); err != ErrInvalidFlatBuffer {
+			t.Errorf("%q: expected ErrInvalidFlatBuffer, got %v", contents, err)
+		}
+	}
+
+	if _, err := OpenReadGraph(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+}
@@ -0,0 +1,124 @@
+package graph
+
+import (
+	"sync"
+)
+
+// Replica maintains an eventually consistent, read-only copy of a graph by applying the events emitted by the graph's mutations.
+// Events are queued and applied in the background, so the source graph's writers never wait for the replica.
+type Replica struct {
+	g       *Graph  // the copy
+	queue   []Event // events not yet applied
+	queued  int     // number of events ever queued
+	applied int     // number of events ever applied
+	closed  bool
+	cancel  func() // cancels the subscription to the source graph, nil for remote replicas
+	cond    *sync.Cond
+	sync.Mutex
+}
+
+// NewReplica initializes a replica of src, which follows all further mutations of src until Close is called.
+func NewReplica(src *Graph) *Replica {
+	// copy and subscribe atomically, so no mutation is lost or applied twice
+	src.Lock()
+	r := newReplica(src.clone())
+	r.cancel = src.subscribe(r.Apply)
+	src.Unlock()
+
+	return r
+}
+
+// NewRemoteReplica initializes a replica starting with the contents of initial, e.g. a graph decoded from a snapshot received over the network.
+// The replica is updated exclusively by passing events (e.g. received from the source's event stream) to Apply. initial must not be used by the caller afterwards.
+func NewRemoteReplica(initial *Graph) *Replica {
+	return newReplica(initial)
+}
+
+func newReplica(g *Graph) *Replica {
+	// the source validated the events already, including self-loops
+	g.selfLoops = true
+
+	r := &Replica{g: g}
+	r.cond = sync.NewCond(&r.Mutex)
+
+	go r.run()
+
+	return r
+}
+
+// Graph returns the replicated graph. It must only be read from, not modified.
+func (r *Replica) Graph() *Graph {
+	return r.g
+}
+
+// Apply queues an event to be applied to the replica. Events must be passed in the order they were emitted by the source, which is the order its mutations were applied in, see Subscribe; remote replicas must receive them over an ordered, lossless channel, or they diverge.
+func (r *Replica) Apply(e Event) {
+	r.Lock()
+	if !r.closed {
+		r.queue = append(r.queue, e)
+		r.queued++
+		r.cond.Broadcast()
+	}
+	r.Unlock()
+}
+
+// Lag returns the number of events that were queued, but not applied yet.
+func (r *Replica) Lag() int {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.queued - r.applied
+}
+
+// Sync blocks until all events queued so far have been applied.
+func (r *Replica) Sync() {
+	r.Lock()
+	defer r.Unlock()
+
+	target := r.queued
+	for r.applied < target && !r.closed {
+		r.cond.Wait()
+	}
+}
+
+// Close stops following the source graph and discards events that were not applied yet. The replicated graph stays readable.
+func (r *Replica) Close() {
+	if r.cancel != nil {
+		r.cancel()
+	}
+
+	r.Lock()
+	r.closed = true
+	r.queue = nil
+	r.cond.Broadcast()
+	r.Unlock()
+}
+
+// run applies queued events until the replica is closed.
+func (r *Replica) run() {
+	r.Lock()
+	defer r.Unlock()
+
+	for {
+		for len(r.queue) == 0 && !r.closed {
+			r.cond.Wait()
+		}
+
+		if r.closed {
+			return
+		}
+
+		batch := r.queue
+		r.queue = nil
+
+		// apply without blocking new events from being queued
+		r.Unlock()
+		for _, e := range batch {
+			r.g.apply(e)
+		}
+		r.Lock()
+
+		r.applied += len(batch)
+		r.cond.Broadcast()
+	}
+}
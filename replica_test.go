@@ -0,0 +1,128 @@
+package graph
+
+import (
+	"runtime"
+	"strconv"
+	"sync"
+	"testing"
+)
+
+func TestReplica(t *testing.T) {
+	g := New()
+
+	g.Set("1", 123)
+	g.Set("2", 678)
+	g.Connect("1", "2", 5)
+
+	r := NewReplica(g)
+
+	// initial contents are copied
+	if ok, weight := r.Graph().IsConnected("1", "2"); !ok || weight != 5 {
+		t.Fail()
+	}
+
+	// concurrent writers
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				g.Set(strconv.Itoa(i*100+j), j)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	g.Set("1", 456)
+	g.Connect("2", "1", 3)
+	g.Disconnect("1", "2")
+	g.Delete("100")
+
+	r.Sync()
+
+	if r.Lag() != 0 || r.Graph().Len() != g.Len() {
+		t.Fail()
+	}
+	if v, err := r.Graph().Get("1"); err != nil || v.Value() != 456 {
+		t.Fail()
+	}
+	if ok, _ := r.Graph().IsConnected("1", "2"); ok {
+		t.Fail()
+	}
+	if ok, weight := r.Graph().IsConnected("2", "1"); !ok || weight != 3 {
+		t.Fail()
+	}
+	if _, err := r.Graph().Get("100"); err == nil {
+		t.Fail()
+	}
+
+	// mutations after closing are not replicated
+	r.Close()
+	g.Set("x", nil)
+	r.Sync()
+
+	if _, err := r.Graph().Get("x"); err == nil {
+		t.Fail()
+	}
+}
+
+func TestRemoteReplica(t *testing.T) {
+	g := New()
+
+	// feed the replica by hand, as a network transport would
+	r := NewRemoteReplica(New())
+	cancel := g.Subscribe(r.Apply)
+	defer cancel()
+
+	g.Set("1", 123)
+	g.Set("2", 678)
+	g.Connect("2", "1", 1)
+
+	r.Sync()
+	r.Close()
+
+	if r.Graph().Len() != 2 {
+		t.Fail()
+	}
+	if ok, weight := r.Graph().IsConnected("2", "1"); !ok || weight != 1 {
+		t.Fail()
+	}
+}
+
+func TestReplicaConcurrentEdges(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+
+	r := NewReplica(g)
+	defer r.Close()
+
+	// a slow subscriber widens the window between mutations and their events
+	g.Subscribe(func(Event) {
+		runtime.Gosched()
+	})
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 200; j++ {
+				if (i+j)%2 == 0 {
+					g.Connect("a", "b", i*1000+j)
+				} else {
+					g.Disconnect("a", "b")
+				}
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	r.Sync()
+
+	ok, weight := g.IsConnected("a", "b")
+	if replicaOK, replicaWeight := r.Graph().IsConnected("a", "b"); replicaOK != ok || replicaWeight != weight {
+		t.Errorf("expected the replica to match the source %v %d, got %v %d", ok, weight, replicaOK, replicaWeight)
+	}
+}
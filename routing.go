@@ -0,0 +1,135 @@
+package graph
+
+import (
+	"sync"
+)
+
+// CongestionMode determines how a Router treats edges whose capacity would be exceeded by a new flow.
+type CongestionMode int
+
+const (
+	// Penalize makes over-capacity edges more expensive: every unit of flow above the capacity adds the Router's Penalty to the edge's cost.
+	Penalize CongestionMode = iota
+
+	// Forbid excludes edges from routing if their remaining capacity is smaller than the demand of a new flow.
+	Forbid
+)
+
+// Router routes flows through a graph and keeps track of the flow committed to each edge across successive Route calls, for traffic-engineering simulations.
+// Edge weights are used as base costs; the capacity function supplies the capacity of an edge (a negative capacity means unlimited).
+type Router struct {
+	g        *Graph
+	capacity func(fromKey, toKey string) int
+	mode     CongestionMode
+	flow     map[string]map[string]int // maps from key → to key → committed flow
+
+	// Penalty is the extra cost per unit of flow above an edge's capacity in Penalize mode. NewRouter sets it to 1.
+	Penalty int
+
+	sync.Mutex
+}
+
+// NewRouter initializes a new router over g without any committed flows.
+func NewRouter(g *Graph, capacity func(fromKey, toKey string) int, mode CongestionMode) *Router {
+	return &Router{
+		g:        g,
+		capacity: capacity,
+		mode:     mode,
+		flow:     map[string]map[string]int{},
+		Penalty:  1,
+	}
+}
+
+// Route finds the cheapest path from startKey to endKey for a flow of the given demand under the current congestion, and commits the flow to every edge along it.
+// The path is returned in start → end order together with its congested cost. ok is false if no usable path exists, in which case nothing is committed.
+func (r *Router) Route(startKey, endKey string, demand int) (path []string, cost int, ok bool) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.g.RLock()
+	defer r.g.RUnlock()
+
+	start := r.g.get(startKey)
+	end := r.g.get(endKey)
+
+	if start == nil || end == nil {
+		return
+	}
+
+	dist, prev := r.g.dijkstra(start, end, func(from, to *Vertex, weight int) (int, bool) {
+		return r.edgeCost(from.key, to.key, weight, demand)
+	})
+
+	d, ok := dist[end]
+	if !ok || !fitsInt(d) {
+		return nil, 0, false
+	}
+	cost = int(d)
+
+	path = pathTo(prev, end)
+
+	// commit the flow along the path
+	for i := 1; i < len(path); i++ {
+		if r.flow[path[i-1]] == nil {
+			r.flow[path[i-1]] = map[string]int{}
+		}
+		r.flow[path[i-1]][path[i]] += demand
+	}
+
+	return
+}
+
+// edgeCost returns the cost of adding demand to the edge fromKey → toKey, and false if the edge may not be used.
+func (r *Router) edgeCost(fromKey, toKey string, weight, demand int) (int, bool) {
+	capacity := r.capacity(fromKey, toKey)
+	if capacity < 0 {
+		return weight, true
+	}
+
+	excess := r.flow[fromKey][toKey] + demand - capacity
+	if excess <= 0 {
+		return weight, true
+	}
+
+	if r.mode == Forbid {
+		return 0, false
+	}
+
+	return weight + excess*r.Penalty, true
+}
+
+// Release removes a flow of the given demand previously committed by Route from every edge along path (in start → end order).
+func (r *Router) Release(path []string, demand int) {
+	r.Lock()
+	defer r.Unlock()
+
+	for i := 1; i < len(path); i++ {
+		neighbors, ok := r.flow[path[i-1]]
+		if !ok {
+			continue
+		}
+
+		neighbors[path[i]] -= demand
+		if neighbors[path[i]] <= 0 {
+			delete(neighbors, path[i])
+		}
+		if len(neighbors) == 0 {
+			delete(r.flow, path[i-1])
+		}
+	}
+}
+
+// Flow returns the flow currently committed to the edge from fromKey to toKey.
+func (r *Router) Flow(fromKey, toKey string) int {
+	r.Lock()
+	defer r.Unlock()
+
+	return r.flow[fromKey][toKey]
+}
+
+// Reset removes all committed flows.
+func (r *Router) Reset() {
+	r.Lock()
+	r.flow = map[string]map[string]int{}
+	r.Unlock()
+}
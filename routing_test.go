@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"testing"
+)
+
+// two routes from "a" to "d": a cheap one via "b" and an expensive one via "c"
+func newRoutingGraph() *Graph {
+	g := New()
+
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+	g.Set("d", nil)
+
+	g.Connect("a", "b", 1)
+	g.Connect("b", "d", 1)
+	g.Connect("a", "c", 2)
+	g.Connect("c", "d", 2)
+
+	return g
+}
+
+func TestRouterForbid(t *testing.T) {
+	g := newRoutingGraph()
+
+	// every edge can carry 1 unit of flow
+	r := NewRouter(g, func(fromKey, toKey string) int { return 1 }, Forbid)
+
+	path, cost, ok := r.Route("a", "d", 1)
+	if !ok || cost != 2 || len(path) != 3 || path[1] != "b" {
+		t.Fail()
+	}
+
+	// cheap route is saturated now
+	path, cost, ok = r.Route("a", "d", 1)
+	if !ok || cost != 4 || len(path) != 3 || path[1] != "c" {
+		t.Fail()
+	}
+
+	if r.Flow("a", "b") != 1 || r.Flow("c", "d") != 1 {
+		t.Fail()
+	}
+
+	// both routes are saturated
+	_, _, ok = r.Route("a", "d", 1)
+	if ok {
+		t.Fail()
+	}
+
+	// freeing the cheap route makes it usable again
+	r.Release([]string{"a", "b", "d"}, 1)
+	if r.Flow("a", "b") != 0 {
+		t.Fail()
+	}
+
+	path, _, ok = r.Route("a", "d", 1)
+	if !ok || path[1] != "b" {
+		t.Fail()
+	}
+}
+
+func TestRouterPenalize(t *testing.T) {
+	g := newRoutingGraph()
+
+	r := NewRouter(g, func(fromKey, toKey string) int { return 1 }, Penalize)
+	r.Penalty = 5
+
+	r.Route("a", "d", 1)
+	r.Route("a", "d", 1)
+
+	// both routes are congested, the cheap one is still cheapest with penalties: 1+5 + 1+5 < 2+5 + 2+5
+	path, cost, ok := r.Route("a", "d", 1)
+	if !ok || cost != 12 || path[1] != "b" {
+		t.Fail()
+	}
+
+	r.Reset()
+	if r.Flow("a", "b") != 0 {
+		t.Fail()
+	}
+
+	// unlimited capacity never congests
+	r = NewRouter(g, func(fromKey, toKey string) int { return -1 }, Forbid)
+	for i := 0; i < 10; i++ {
+		if _, cost, ok := r.Route("a", "d", 1); !ok || cost != 2 {
+			t.Fail()
+		}
+	}
+}
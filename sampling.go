@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"container/heap"
+	"math"
+	"math/rand"
+)
+
+// SampleVertices returns n vertices chosen uniformly at random without replacement, or all vertices if there are fewer. It uses reservoir sampling in a single pass under the read lock.
+func (g *Graph) SampleVertices(n int) []*Vertex {
+	defer g.track("SampleVertices")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if n <= 0 {
+		return nil
+	}
+
+	// n may be far larger than the graph, e.g. to sample everything
+	size := n
+	if size > len(g.vertices) {
+		size = len(g.vertices)
+	}
+
+	reservoir := make([]*Vertex, 0, size)
+	seen := 0
+
+	for _, v := range g.vertices {
+		seen++
+
+		if len(reservoir) < n {
+			reservoir = append(reservoir, v)
+		} else if i := rand.Intn(seen); i < n {
+			reservoir[i] = v
+		}
+	}
+
+	return reservoir
+}
+
+// SampleEdges returns n edges chosen at random without replacement, or all edges if there are fewer. It uses reservoir sampling in a single pass under the read lock.
+// If weighted is false, all edges are equally likely to be chosen. Otherwise, the probability of an edge to be chosen is proportional to its weight, using the algorithm by Efraimidis and Spirakis; edges without a positive weight are never chosen then.
+func (g *Graph) SampleEdges(n int, weighted bool) []Edge {
+	defer g.track("SampleEdges")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if n <= 0 {
+		return nil
+	}
+
+	if !weighted {
+		// the number of edges isn't known without counting them, so the reservoir grows as needed instead of reserving n entries
+		var reservoir []Edge
+		seen := 0
+
+		for key, v := range g.vertices {
+			for neighbor, weight := range v.GetOutgoing() {
+				seen++
+
+				if len(reservoir) < n {
+					reservoir = append(reservoir, Edge{From: key, To: neighbor.key, Weight: weight})
+				} else if i := rand.Intn(seen); i < n {
+					reservoir[i] = Edge{From: key, To: neighbor.key, Weight: weight}
+				}
+			}
+		}
+
+		return reservoir
+	}
+
+	// keep the n edges with the largest keys u^(1/weight) for uniform u
+	reservoir := &sampleHeap{}
+
+	for key, v := range g.vertices {
+		for neighbor, weight := range v.GetOutgoing() {
+			if weight <= 0 {
+				continue
+			}
+
+			k := math.Pow(rand.Float64(), 1/float64(weight))
+
+			if reservoir.Len() < n {
+				heap.Push(reservoir, weightedSample{Edge{From: key, To: neighbor.key, Weight: weight}, k})
+			} else if k > (*reservoir)[0].key {
+				(*reservoir)[0] = weightedSample{Edge{From: key, To: neighbor.key, Weight: weight}, k}
+				heap.Fix(reservoir, 0)
+			}
+		}
+	}
+
+	edges := make([]Edge, reservoir.Len())
+	for i, s := range *reservoir {
+		edges[i] = s.edge
+	}
+
+	return edges
+}
+
+// weightedSample is an edge in the reservoir of a weighted sample, with its random key.
+type weightedSample struct {
+	edge Edge
+	key  float64
+}
+
+// sampleHeap implements heap.Interface and holds weighted samples, the one with the smallest key first.
+type sampleHeap []weightedSample
+
+func (h sampleHeap) Len() int            { return len(h) }
+func (h sampleHeap) Less(i, j int) bool  { return h[i].key < h[j].key }
+func (h sampleHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *sampleHeap) Push(x interface{}) { *h = append(*h, x.(weightedSample)) }
+
+func (h *sampleHeap) Pop() interface{} {
+	old := *h
+	s := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return s
+}
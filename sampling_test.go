@@ -0,0 +1,86 @@
+package graph
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestSampleVertices(t *testing.T) {
+	g := New()
+	for i := 0; i < 10; i++ {
+		g.Set(strconv.Itoa(i), i)
+	}
+
+	counts := map[string]int{}
+	for run := 0; run < 2000; run++ {
+		sample := g.SampleVertices(3)
+		if len(sample) != 3 {
+			t.Fatalf("expected 3 vertices, got %d", len(sample))
+		}
+
+		seen := map[string]bool{}
+		for _, v := range sample {
+			if seen[v.Key()] {
+				t.Fatal("vertex sampled twice")
+			}
+			seen[v.Key()] = true
+			counts[v.Key()]++
+		}
+	}
+
+	// every vertex is expected 600 times
+	for key, count := range counts {
+		if count < 450 || count > 750 {
+			t.Errorf("vertex %s sampled %d times", key, count)
+		}
+	}
+
+	if len(g.SampleVertices(20)) != 10 || len(g.SampleVertices(0)) != 0 {
+		t.Fail()
+	}
+
+	// huge samples don't reserve memory for more vertices than there are
+	if len(g.SampleVertices(maxInt)) != 10 {
+		t.Fail()
+	}
+}
+
+func TestSampleEdges(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 9)
+	g.Connect("c", "a", 0)
+
+	if edges := g.SampleEdges(5, false); len(edges) != 3 {
+		t.Fail()
+	}
+	if edges := g.SampleEdges(maxInt, false); len(edges) != 3 {
+		t.Fail()
+	}
+
+	// edges without a positive weight are never chosen in weighted samples
+	if edges := g.SampleEdges(5, true); len(edges) != 2 {
+		t.Fail()
+	}
+
+	heavy := 0
+	for run := 0; run < 2000; run++ {
+		edges := g.SampleEdges(1, true)
+		if len(edges) != 1 {
+			t.Fatal("expected one edge")
+		}
+		if reflect.DeepEqual(edges[0], Edge{From: "b", To: "c", Weight: 9}) {
+			heavy++
+		}
+	}
+
+	// the heavy edge is expected 1800 times
+	if heavy < 1700 || heavy > 1900 {
+		t.Errorf("heavy edge sampled %d times", heavy)
+	}
+}
@@ -0,0 +1,28 @@
+package graph
+
+// EnableSelfLoops allows edges from a vertex to itself, e.g. for state machines or Markov chains where a state may stay the same.
+// A self-loop counts as both an incoming and an outgoing edge of its vertex, so it adds 2 to the vertex' degree.
+func (g *Graph) EnableSelfLoops() {
+	defer g.track("EnableSelfLoops")()
+
+	g.Lock()
+	g.selfLoops = true
+	g.Unlock()
+}
+
+// DisableSelfLoops forbids edges from a vertex to itself again, which is the default, see EnableSelfLoops. Existing self-loops are removed.
+func (g *Graph) DisableSelfLoops() {
+	defer g.track("DisableSelfLoops")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	g.selfLoops = false
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+		if _, ok := v.GetOutgoing()[v]; ok {
+			g.disconnect(v, v)
+		}
+	}
+}
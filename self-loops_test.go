@@ -0,0 +1,261 @@
+package graph
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSelfLoops(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+
+	if g.Connect("a", "a", 1) {
+		t.Error("expected self-loops to be rejected by default")
+	}
+
+	g.EnableSelfLoops()
+	if !g.Connect("a", "a", 3) || !g.Connect("a", "b", 1) {
+		t.Fatal("expected self-loops to be allowed")
+	}
+	if ok, weight := g.IsConnected("a", "a"); !ok || weight != 3 {
+		t.Errorf("expected the self-loop, got %v %d", ok, weight)
+	}
+
+	// a self-loop adds one incoming and one outgoing edge
+	s := g.Stats()
+	if s.Edges != 2 || s.MaxInDegree != 1 || s.MaxOutDegree != 2 || s.MaxDegree != 3 || s.Density != 0.5 {
+		t.Errorf("unexpected stats %+v", s)
+	}
+
+	if c, _ := g.ClusteringCoefficient("a"); c != 0 {
+		t.Errorf("expected the vertex not to be its own neighbor, got coefficient %v", c)
+	}
+
+	if path, err := g.ShortestPath("a", "b"); err != nil || !reflect.DeepEqual(path, []string{"a", "b"}) {
+		t.Errorf("expected the self-loop to be skipped, got %v %v", path, err)
+	}
+
+	closure := g.TransitiveClosure()
+	if ok, _ := closure.IsConnected("a", "a"); !ok {
+		t.Error("expected a to reach itself")
+	}
+	if ok, _ := closure.IsConnected("b", "b"); ok {
+		t.Error("expected b not to reach itself")
+	}
+
+	if !g.Disconnect("a", "a") {
+		t.Error("expected the self-loop to be removed")
+	}
+	if ok, _ := g.IsConnected("a", "a"); ok {
+		t.Error("expected no self-loop")
+	}
+
+	g.Connect("b", "b", 1)
+	g.Connect("a", "b", 1)
+	if !g.Delete("b") {
+		t.Fatal("expected a vertex with a self-loop to be deleted")
+	}
+	if v, _ := g.Get("a"); len(v.GetOutgoing()) != 0 {
+		t.Errorf("expected no edges left, got %v", v.GetOutgoing())
+	}
+
+	// disabling removes existing self-loops
+	g.Connect("a", "a", 1)
+	g.DisableSelfLoops()
+	if ok, _ := g.IsConnected("a", "a"); ok || g.Connect("a", "a", 1) {
+		t.Error("expected self-loops to be removed and rejected")
+	}
+}
+
+func TestSelfLoopsMatrix(t *testing.T) {
+	g := New()
+	g.EnableSelfLoops()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Connect("a", "a", 3)
+	g.Connect("a", "b", 1)
+	g.Connect("b", "a", 2)
+
+	// the diagonal entry of a self-loop's vertex is listed once
+	for name, expected := range map[string][][3]float64{
+		"adjacency": {{0, 0, 3}, {0, 1, 1}, {1, 0, 2}},
+		"Laplacian": {{0, 0, 1}, {0, 1, -1}, {1, 0, -2}, {1, 1, 2}},
+	} {
+		m, _ := g.AdjacencyMatrix(Sparse)
+		if name == "Laplacian" {
+			m, _ = g.Laplacian(Sparse)
+		}
+
+		var entries [][3]float64
+		m.(*SparseMatrix).DoNonZero(func(i, j int, v float64) {
+			entries = append(entries, [3]float64{float64(i), float64(j), v})
+		})
+		if !reflect.DeepEqual(entries, expected) {
+			t.Errorf("%s: expected entries %v, got %v", name, expected, entries)
+		}
+	}
+}
+
+func TestSelfLoopsSerialization(t *testing.T) {
+	g := New()
+	g.EnableSelfLoops()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Connect("a", "a", 3)
+	g.Connect("a", "b", 4)
+
+	check := func(name string, h *Graph) {
+		if ok, weight := h.IsConnected("a", "a"); !ok || weight != 3 {
+			t.Errorf("%s: expected the self-loop, got %v %d", name, ok, weight)
+		}
+		if !h.Connect("b", "b", 1) {
+			t.Errorf("%s: expected self-loops to be enabled", name)
+		}
+	}
+
+	b, err := g.GobEncode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := New()
+	if err := h.GobDecode(b); err != nil {
+		t.Fatalf("gob: unexpected error %v", err)
+	}
+	check("gob", h)
+
+	buf := &bytes.Buffer{}
+	g.WriteTo(buf)
+	h = New()
+	if _, err := h.ReadFrom(buf); err != nil {
+		t.Fatalf("stream: unexpected error %v", err)
+	}
+	check("stream", h)
+
+	buf.Reset()
+	g.WriteCompact(buf)
+	if h, err = ReadCompact(buf); err != nil {
+		t.Fatalf("compact: unexpected error %v", err)
+	}
+	check("compact", h)
+
+	buf.Reset()
+	g.WriteDOT(buf)
+	if h, err = ReadDOT(buf); err != nil {
+		t.Fatalf("DOT: unexpected error %v", err)
+	}
+	check("DOT", h)
+
+	if h, err = ReadPajek(strings.NewReader("*Vertices 2\n1 \"a\"\n2 \"b\"\n*Arcs\n1 1 3\n")); err != nil {
+		t.Fatalf("Pajek: unexpected error %v", err)
+	}
+	check("Pajek", h)
+
+	if h, err = ReadGML(strings.NewReader(`graph [ directed 1 node [ id 0 label "a" ] node [ id 1 label "b" ] edge [ source 0 target 0 weight 3 ] ]`)); err != nil {
+		t.Fatalf("GML: unexpected error %v", err)
+	}
+	check("GML", h)
+
+	if b, err = g.MarshalJSON(); err != nil {
+		t.Fatal(err)
+	}
+	h = New()
+	if err := h.UnmarshalJSON(b); err != nil {
+		t.Fatalf("JSON: unexpected error %v", err)
+	}
+	check("JSON", h)
+
+	if b, err = g.MarshalMsgpack(); err != nil {
+		t.Fatal(err)
+	}
+	h = New()
+	if err := h.UnmarshalMsgpack(b); err != nil {
+		t.Fatalf("MessagePack: unexpected error %v", err)
+	}
+	check("MessagePack", h)
+
+	if b, err = g.MarshalProto(); err != nil {
+		t.Fatal(err)
+	}
+	h = New()
+	if err := h.UnmarshalProto(b); err != nil {
+		t.Fatalf("proto: unexpected error %v", err)
+	}
+	check("proto", h)
+
+	buf.Reset()
+	g.StreamJSONL(buf)
+	h = New()
+	if err := h.LoadJSONL(buf); err != nil {
+		t.Fatalf("JSONL: unexpected error %v", err)
+	}
+	check("JSONL", h)
+
+	// importers report self-loops unless told to enable them
+	h = New()
+	h.Set("a", 1)
+	im := h.NewImporter()
+	im.Connect("a", "a", 1)
+	if err := im.Finalize(); err == nil {
+		t.Error("expected the self-loop to be reported")
+	}
+}
+
+func TestSelfLoopsIsomorphism(t *testing.T) {
+	g := New()
+	g.EnableSelfLoops()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Connect("a", "b", 1)
+	g.Connect("b", "b", 1)
+
+	h := New()
+	h.EnableSelfLoops()
+	h.Set("x", nil)
+	h.Set("y", nil)
+	h.Connect("x", "y", 1)
+	h.Connect("x", "x", 1)
+
+	if g.IsIsomorphic(h) {
+		t.Error("expected the self-loops at different ends not to match")
+	}
+
+	h.Disconnect("x", "x")
+	h.Connect("y", "y", 1)
+	if !g.IsIsomorphic(h) {
+		t.Error("expected the graphs to be isomorphic")
+	}
+}
+
+func TestSelfLoopsSplit(t *testing.T) {
+	g := New()
+	g.EnableSelfLoops()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Connect("a", "a", 3)
+	g.Connect("a", "b", 1)
+
+	var edges []Edge
+	created, err := g.SplitVertex("a", func(e Edge) string {
+		edges = append(edges, e)
+		if e.To == "a" {
+			return "a'"
+		}
+		return "a"
+	})
+	if err != nil || !reflect.DeepEqual(created, []string{"a'"}) {
+		t.Fatalf("unexpected result %v %v", created, err)
+	}
+	if len(edges) != 2 {
+		t.Errorf("expected partition to be called once per edge, got %v", edges)
+	}
+
+	if ok, weight := g.IsConnected("a'", "a'"); !ok || weight != 3 {
+		t.Errorf("expected the self-loop to move, got %v %d", ok, weight)
+	}
+	if g.Stats().Edges != 2 {
+		t.Errorf("expected 2 edges, got %d", g.Stats().Edges)
+	}
+}
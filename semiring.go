@@ -0,0 +1,120 @@
+package graph
+
+import (
+	"errors"
+	"math"
+)
+
+// ErrDivergent is returned by PathValues if the values don't converge, e.g. because a cycle keeps shortening paths or adds infinitely many paths to count.
+var ErrDivergent = errors.New("graph: path values diverge")
+
+// Semiring defines the algebra PathValues uses to combine edges into paths and paths into a result, so the same algorithm computes shortest paths, widest paths, path counts and more.
+// Zero must be the identity of Plus and annihilate Times; One must be the identity of Times.
+type Semiring interface {
+	Zero() float64              // value of no path at all
+	One() float64               // value of the empty path from a vertex to itself
+	Plus(a, b float64) float64  // combines the values of two alternative paths
+	Times(a, b float64) float64 // extends a path with value a by an edge with value b
+	Edge(e Edge) float64        // value of a single edge
+}
+
+// semiring implements Semiring with functions.
+type semiring struct {
+	zero, one   float64
+	plus, times func(a, b float64) float64
+	edge        func(e Edge) float64
+}
+
+func (s *semiring) Zero() float64              { return s.zero }
+func (s *semiring) One() float64               { return s.one }
+func (s *semiring) Plus(a, b float64) float64  { return s.plus(a, b) }
+func (s *semiring) Times(a, b float64) float64 { return s.times(a, b) }
+func (s *semiring) Edge(e Edge) float64        { return s.edge(e) }
+
+var (
+	// ShortestPaths computes the smallest sum of edge weights of any path, i.e. the weighted distance.
+	ShortestPaths Semiring = &semiring{math.Inf(1), 0, math.Min, func(a, b float64) float64 { return a + b }, edgeWeight}
+
+	// WidestPaths computes the largest bottleneck of any path, where the bottleneck is the smallest edge weight along it, e.g. the capacity of a network route.
+	WidestPaths Semiring = &semiring{math.Inf(-1), math.Inf(1), math.Max, math.Min, edgeWeight}
+
+	// PathCounts computes the number of distinct paths, regardless of weights. It diverges if a cycle can be reached.
+	PathCounts Semiring = &semiring{0, 1, func(a, b float64) float64 { return a + b }, func(a, b float64) float64 { return a * b }, func(Edge) float64 { return 1 }}
+)
+
+// MostReliablePaths returns a semiring computing the largest probability of any path to succeed, where probability returns the probability of an edge to succeed, between 0 and 1.
+func MostReliablePaths(probability func(e Edge) float64) Semiring {
+	return &semiring{0, 1, math.Max, func(a, b float64) float64 { return a * b }, probability}
+}
+
+// edgeWeight returns the weight of e.
+func edgeWeight(e Edge) float64 {
+	return float64(e.Weight)
+}
+
+// PathValues computes the value of the paths from the vertex with the specified key to every vertex it can reach, as defined by s: the Plus of the values of all paths, where the value of a path is the Times of the values of its edges.
+// The values are computed by repeatedly extending paths by one edge until they don't change anymore, which takes O(|V|·|E|) time at most. Vertices whose value is s.Zero() are omitted.
+// Returns ErrInvalidKey if the key is invalid and ErrDivergent if the values don't converge, e.g. for ShortestPaths with a negative cycle.
+func (g *Graph) PathValues(key string, s Semiring) (map[string]float64, error) {
+	defer g.track("PathValues")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(key)
+	if start == nil {
+		return nil, ErrInvalidKey
+	}
+
+	vertices, index := g.indexVertices()
+
+	edges := make([]map[int]float64, len(vertices)) // values of the incoming edges of each vertex
+	for i, v := range vertices {
+		edges[i] = map[int]float64{}
+		for neighbor, weight := range v.GetIncoming() {
+			edges[i][index[neighbor]] = s.Edge(Edge{From: neighbor.key, To: v.key, Weight: weight})
+		}
+	}
+
+	values := make([]float64, len(vertices))
+	for i := range values {
+		values[i] = s.Zero()
+	}
+	values[index[start]] = s.One()
+
+	// after round k, values covers all paths of up to k edges; paths without cycles have less than |V| edges
+	for round := 0; round <= len(vertices); round++ {
+		next := make([]float64, len(vertices))
+		changed := false
+
+		for i, v := range vertices {
+			next[i] = s.Zero()
+			if v == start {
+				next[i] = s.One()
+			}
+
+			for from, value := range edges[i] {
+				next[i] = s.Plus(next[i], s.Times(values[from], value))
+			}
+
+			if next[i] != values[i] {
+				changed = true
+			}
+		}
+
+		values = next
+
+		if !changed {
+			result := map[string]float64{}
+			for i, v := range vertices {
+				if values[i] != s.Zero() {
+					result[v.key] = values[i]
+				}
+			}
+
+			return result, nil
+		}
+	}
+
+	return nil, ErrDivergent
+}
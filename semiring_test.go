@@ -0,0 +1,65 @@
+package graph
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestPathValues(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	// two routes from a to d, isolated e
+	g.Connect("a", "b", 4)
+	g.Connect("b", "d", 5)
+	g.Connect("a", "c", 2)
+	g.Connect("c", "d", 8)
+	g.Connect("c", "b", 1)
+
+	values, err := g.PathValues("a", ShortestPaths)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := map[string]float64{"a": 0, "b": 3, "c": 2, "d": 8}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("shortest paths: expected %v, got %v", expected, values)
+	}
+
+	values, _ = g.PathValues("a", WidestPaths)
+	if expected := map[string]float64{"a": math.Inf(1), "b": 4, "c": 2, "d": 4}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("widest paths: expected %v, got %v", expected, values)
+	}
+
+	values, _ = g.PathValues("a", PathCounts)
+	if expected := map[string]float64{"a": 1, "b": 2, "c": 1, "d": 3}; !reflect.DeepEqual(values, expected) {
+		t.Errorf("path counts: expected %v, got %v", expected, values)
+	}
+
+	// weights are failure percentages, a → c → b → d is the most reliable route
+	reliable := MostReliablePaths(func(e Edge) float64 { return 1 - float64(e.Weight)/100 })
+	values, _ = g.PathValues("a", reliable)
+	if d := values["d"]; math.Abs(d-0.98*0.99*0.95) > 1e-9 {
+		t.Errorf("most reliable path: expected %v, got %v", 0.98*0.99*0.95, d)
+	}
+
+	// a reachable cycle makes the number of paths infinite, but doesn't change shortest paths
+	g.Connect("d", "a", 1)
+	if _, err = g.PathValues("a", PathCounts); err != ErrDivergent {
+		t.Errorf("expected ErrDivergent, got %v", err)
+	}
+	if values, err = g.PathValues("a", ShortestPaths); err != nil || values["d"] != 8 {
+		t.Fail()
+	}
+
+	// a negative cycle
+	g.Connect("d", "a", -9)
+	if _, err = g.PathValues("a", ShortestPaths); err != ErrDivergent {
+		t.Errorf("expected ErrDivergent, got %v", err)
+	}
+
+	if _, err = g.PathValues("x", ShortestPaths); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
@@ -0,0 +1,165 @@
+package graph
+
+import (
+	"sort"
+	"sync"
+)
+
+// expand returns the outgoing edges (as neighbor key → weight) of every key in the frontier that exists in the cluster.
+// The frontier is split by shard and each part is expanded by its shard concurrently; edges spanning shards are merged in afterwards.
+func (c *Cluster) expand(frontier []string) map[string]map[string]int {
+	byShard := map[*Graph][]string{}
+	for _, key := range frontier {
+		if shard := c.Shard(key); shard != nil {
+			byShard[shard] = append(byShard[shard], key)
+		}
+	}
+
+	edges := map[string]map[string]int{}
+	mutex := sync.Mutex{}
+	wg := sync.WaitGroup{}
+
+	for shard, keys := range byShard {
+		wg.Add(1)
+
+		go func(shard *Graph, keys []string) {
+			defer wg.Done()
+
+			local := map[string]map[string]int{}
+
+			shard.RLock()
+			for _, key := range keys {
+				if v := shard.get(key); v != nil {
+					local[key] = keyWeights(v.GetOutgoing())
+				}
+			}
+			shard.RUnlock()
+
+			mutex.Lock()
+			for key, neighbors := range local {
+				edges[key] = neighbors
+			}
+			mutex.Unlock()
+		}(shard, keys)
+	}
+
+	wg.Wait()
+
+	c.RLock()
+	for key, neighbors := range edges {
+		for otherKey, weight := range c.outgoing[key] {
+			neighbors[otherKey] = weight
+		}
+	}
+	c.RUnlock()
+
+	return edges
+}
+
+// BFS visits the vertices reachable from the vertex with key startKey in breadth-first order, level by level, passing each vertex' key and its depth (number of edges from the start vertex) to visit.
+// Each level's frontier is expanded by all shards in parallel. Vertices of the same level are visited in key order. The traversal stops when visit returns false. Returns false if startKey is invalid.
+func (c *Cluster) BFS(startKey string, visit func(key string, depth int) bool) bool {
+	if _, err := c.Get(startKey); err != nil {
+		return false
+	}
+
+	visited := map[string]bool{startKey: true}
+	frontier := []string{startKey}
+
+	for depth := 0; len(frontier) > 0; depth++ {
+		sort.Strings(frontier)
+
+		for _, key := range frontier {
+			if !visit(key, depth) {
+				return true
+			}
+		}
+
+		var next []string
+		for _, neighbors := range c.expand(frontier) {
+			for neighbor := range neighbors {
+				if !visited[neighbor] {
+					visited[neighbor] = true
+					next = append(next, neighbor)
+				}
+			}
+		}
+
+		frontier = next
+	}
+
+	return true
+}
+
+// ShortestPath returns the shortest path from the vertex with key startKey to the vertex with key endKey in start → end order, and its cost.
+// The search proceeds in rounds: in each round, all vertices whose distance improved are expanded by their shards in parallel and the results are merged by the cluster. Edge weights must not be negative, or distances might improve forever around a negative cycle.
+// Returns ErrInvalidKey if one of the keys is invalid, ErrNoPath if there is no path, and a *WeightError wrapping ErrNegativeWeight if the search encounters a negative edge weight, or ErrCostOverflow if the cost doesn't fit into an int.
+func (c *Cluster) ShortestPath(startKey, endKey string) (path []string, cost int, err error) {
+	if _, err := c.Get(startKey); err != nil {
+		return nil, 0, ErrInvalidKey
+	}
+	if _, err := c.Get(endKey); err != nil {
+		return nil, 0, ErrInvalidKey
+	}
+
+	dist := map[string]int64{startKey: 0}
+	prev := map[string]string{}
+	frontier := []string{startKey}
+
+	for len(frontier) > 0 {
+		improved := map[string]bool{}
+
+		for key, neighbors := range c.expand(frontier) {
+			for neighbor, weight := range neighbors {
+				if weight < 0 {
+					return nil, 0, &WeightError{key, neighbor, weight, ErrNegativeWeight}
+				}
+
+				// paths whose cost overflows are ignored
+				if addOverflows(dist[key], int64(weight)) {
+					continue
+				}
+				d := dist[key] + int64(weight)
+
+				// no need to go on where the path is longer than the best one found
+				if best, ok := dist[endKey]; ok && d >= best {
+					continue
+				}
+
+				if known, ok := dist[neighbor]; ok && known <= d {
+					continue
+				}
+
+				dist[neighbor] = d
+				prev[neighbor] = key
+				improved[neighbor] = true
+			}
+		}
+
+		frontier = frontier[:0]
+		for key := range improved {
+			frontier = append(frontier, key)
+		}
+	}
+
+	d, ok := dist[endKey]
+	if !ok {
+		return nil, 0, ErrNoPath
+	}
+	if !fitsInt(d) {
+		return nil, 0, &WeightError{prev[endKey], endKey, int(d - dist[prev[endKey]]), ErrCostOverflow}
+	}
+	cost = int(d)
+
+	for key := endKey; key != startKey; key = prev[key] {
+		path = append(path, key)
+	}
+	path = append(path, startKey)
+
+	// reverse into start → end order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return
+}
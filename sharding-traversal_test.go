@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"math/rand"
+	"strconv"
+	"testing"
+)
+
+func TestClusterTraversal(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	// the same graph, once in a single process and once distributed over shards
+	g := New()
+	c := NewCluster(NewPartitioner(10, "a", "b", "c"), map[string]*Graph{"a": New(), "b": New(), "c": New()})
+
+	for i := 0; i < 40; i++ {
+		g.Set(strconv.Itoa(i), i)
+		c.Set(strconv.Itoa(i), i)
+	}
+	for i := 0; i < 100; i++ {
+		from, to, weight := strconv.Itoa(r.Intn(40)), strconv.Itoa(r.Intn(40)), 1+r.Intn(10)
+		g.Connect(from, to, weight)
+		c.Connect(from, to, weight)
+	}
+
+	for i := 0; i < 40; i++ {
+		startKey, endKey := "0", strconv.Itoa(i)
+
+		expected, expectedOk := g.ShortestPathBidirectional(startKey, endKey, noHeuristic)
+		path, cost, err := c.ShortestPath(startKey, endKey)
+
+		if (err == nil) != expectedOk || err != nil && err != ErrNoPath {
+			t.Fatalf("%s → %s: expected path to exist: %v, got %v", startKey, endKey, expectedOk, err)
+		}
+		if err != nil {
+			continue
+		}
+
+		if pathCost(g, path) != pathCost(g, expected) || cost != pathCost(g, expected) {
+			t.Fatalf("%s → %s: expected cost %d, got %d", startKey, endKey, pathCost(g, expected), cost)
+		}
+	}
+
+	// BFS reaches the same vertices at the same depths as on a single graph
+	depths := map[string]int{}
+	c.BFS("0", func(key string, depth int) bool {
+		depths[key] = depth
+		return true
+	})
+
+	// unweighted copy of g for counting hops
+	hops := New()
+	for _, v := range g.GetAll() {
+		hops.Set(v.Key(), nil)
+	}
+	for _, v := range g.GetAll() {
+		for neighbor := range v.GetOutgoing() {
+			hops.Connect(v.Key(), neighbor.Key(), 1)
+		}
+	}
+
+	for key, depth := range depths {
+		path, ok := hops.ShortestPathBidirectional("0", key, noHeuristic)
+		if !ok || len(path)-1 != depth {
+			t.Fatalf("%s: expected depth %d, got %d", key, len(path)-1, depth)
+		}
+	}
+
+	for i := 0; i < 40; i++ {
+		_, reachable := g.ShortestPathBidirectional("0", strconv.Itoa(i), noHeuristic)
+		if _, visited := depths[strconv.Itoa(i)]; visited != reachable {
+			t.Fail()
+		}
+	}
+
+	if c.BFS("x", func(key string, depth int) bool { return true }) {
+		t.Fail()
+	}
+}
+
+func TestClusterShortestPathErrors(t *testing.T) {
+	c := NewCluster(NewPartitioner(10, "a", "b"), map[string]*Graph{"a": New(), "b": New()})
+	for _, key := range []string{"0", "1", "2", "3"} {
+		c.Set(key, nil)
+	}
+	c.Connect("0", "1", 1)
+
+	if _, _, err := c.ShortestPath("0", "x"); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+	if _, _, err := c.ShortestPath("0", "3"); err != ErrNoPath {
+		t.Errorf("expected ErrNoPath, got %v", err)
+	}
+
+	// a negative cycle would improve the distances forever
+	c.Connect("1", "2", -5)
+	c.Connect("2", "1", 1)
+	_, _, err := c.ShortestPath("0", "3")
+	if weightErr, ok := err.(*WeightError); !ok || weightErr.Err != ErrNegativeWeight || weightErr.Weight != -5 {
+		t.Errorf("expected a WeightError for the negative weight, got %v", err)
+	}
+}
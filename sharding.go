@@ -0,0 +1,315 @@
+package graph
+
+import (
+	"hash/crc32"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Partitioner assigns vertex keys to shards by consistent hashing: every shard is placed on a hash ring several times (as virtual nodes), and a key belongs to the first shard following the key's hash on the ring.
+// Adding or removing a shard therefore only moves the keys next to that shard's virtual nodes.
+type Partitioner struct {
+	replicas int               // number of virtual nodes per shard
+	ring     []uint32          // sorted hashes of all virtual nodes
+	owners   map[uint32]string // maps virtual node hashes to shard names
+	sync.RWMutex
+}
+
+// NewPartitioner initializes a new partitioner placing each of the given shards on the ring replicas times.
+func NewPartitioner(replicas int, shards ...string) *Partitioner {
+	if replicas < 1 {
+		replicas = 1
+	}
+
+	p := &Partitioner{replicas: replicas, owners: map[uint32]string{}}
+	p.Add(shards...)
+
+	return p
+}
+
+// Add places the given shards on the ring.
+func (p *Partitioner) Add(shards ...string) {
+	p.Lock()
+	defer p.Unlock()
+
+	for _, shard := range shards {
+		for i := 0; i < p.replicas; i++ {
+			h := crc32.ChecksumIEEE([]byte(shard + "#" + strconv.Itoa(i)))
+			if _, ok := p.owners[h]; ok {
+				// hash collision; the first shard keeps the virtual node
+				continue
+			}
+
+			p.owners[h] = shard
+			p.ring = append(p.ring, h)
+		}
+	}
+
+	sort.Slice(p.ring, func(i, j int) bool { return p.ring[i] < p.ring[j] })
+}
+
+// Remove takes the given shard off the ring.
+func (p *Partitioner) Remove(shard string) {
+	p.Lock()
+	defer p.Unlock()
+
+	ring := p.ring[:0]
+	for _, h := range p.ring {
+		if p.owners[h] == shard {
+			delete(p.owners, h)
+			continue
+		}
+		ring = append(ring, h)
+	}
+	p.ring = ring
+}
+
+// Shard returns the name of the shard the key belongs to, or "" if there are no shards.
+func (p *Partitioner) Shard(key string) string {
+	p.RLock()
+	defer p.RUnlock()
+
+	if len(p.ring) == 0 {
+		return ""
+	}
+
+	h := crc32.ChecksumIEEE([]byte(key))
+
+	// first virtual node at or after the key's hash, wrapping around
+	i := sort.Search(len(p.ring), func(i int) bool { return p.ring[i] >= h })
+	if i == len(p.ring) {
+		i = 0
+	}
+
+	return p.owners[p.ring[i]]
+}
+
+// Shards returns the sorted names of all shards on the ring.
+func (p *Partitioner) Shards() (shards []string) {
+	p.RLock()
+	defer p.RUnlock()
+
+	seen := map[string]bool{}
+	for _, shard := range p.owners {
+		if !seen[shard] {
+			seen[shard] = true
+			shards = append(shards, shard)
+		}
+	}
+	sort.Strings(shards)
+
+	return
+}
+
+// Cluster coordinates a logical graph whose vertices are distributed over several shard graphs by a Partitioner.
+// Every vertex operation is routed to the shard owning the key; edges between vertices on the same shard are stored in that shard, edges spanning two shards are stored by the cluster.
+// The partitioner must not be changed while the cluster holds vertices, since vertices are not moved between shards.
+type Cluster struct {
+	partitioner *Partitioner
+	shards      map[string]*Graph         // maps shard names to shard graphs
+	outgoing    map[string]map[string]int // maps from key → to key → weight of edges spanning shards
+	incoming    map[string]map[string]int // maps to key → from key → weight of edges spanning shards
+	sync.RWMutex
+}
+
+// NewCluster initializes a new cluster distributing vertices over the given shards, which must contain a graph for every shard of the partitioner.
+func NewCluster(p *Partitioner, shards map[string]*Graph) *Cluster {
+	return &Cluster{
+		partitioner: p,
+		shards:      shards,
+		outgoing:    map[string]map[string]int{},
+		incoming:    map[string]map[string]int{},
+	}
+}
+
+// Shard returns the shard graph owning the key, or nil if the partitioner assigns it to an unknown shard.
+func (c *Cluster) Shard(key string) *Graph {
+	return c.shards[c.partitioner.Shard(key)]
+}
+
+// Len returns the number of vertices contained in all shards.
+func (c *Cluster) Len() (n int) {
+	for _, shard := range c.shards {
+		n += shard.Len()
+	}
+	return
+}
+
+// Set creates or updates the vertex with the specified key on the shard owning it. Returns false if there is no such shard, or if the shard rejected the value.
+func (c *Cluster) Set(key string, value interface{}) bool {
+	shard := c.Shard(key)
+	if shard == nil {
+		return false
+	}
+
+	_, err := shard.TrySet(key, value)
+
+	return err == nil
+}
+
+// Get returns the vertex with this key from the shard owning it, or nil and an error if there is no vertex with this key.
+// The vertex' edges only include edges within its shard; use Outgoing and Incoming for all edges.
+func (c *Cluster) Get(key string) (*Vertex, error) {
+	shard := c.Shard(key)
+	if shard == nil {
+		return nil, ErrInvalidKey
+	}
+
+	return shard.Get(key)
+}
+
+// Delete deletes the vertex with the specified key and all edges connected to it. Returns false if key is invalid.
+func (c *Cluster) Delete(key string) bool {
+	shard := c.Shard(key)
+	if shard == nil {
+		return false
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if !shard.Delete(key) {
+		return false
+	}
+
+	// remove edges spanning shards
+	for toKey := range c.outgoing[key] {
+		c.removeCross(key, toKey)
+	}
+	for fromKey := range c.incoming[key] {
+		c.removeCross(fromKey, key)
+	}
+
+	return true
+}
+
+// removeCross removes an edge spanning shards. Does NOT lock the cluster.
+func (c *Cluster) removeCross(fromKey, toKey string) {
+	delete(c.outgoing[fromKey], toKey)
+	if len(c.outgoing[fromKey]) == 0 {
+		delete(c.outgoing, fromKey)
+	}
+
+	delete(c.incoming[toKey], fromKey)
+	if len(c.incoming[toKey]) == 0 {
+		delete(c.incoming, toKey)
+	}
+}
+
+// Connect creates a directed edge between the vertices specified by fromKey and toKey, routing it to their shard or storing it in the cluster if they are on different shards.
+// Returns false if one or both of the keys are invalid or if they are the same, unless their shard allows self-loops, see EnableSelfLoops.
+func (c *Cluster) Connect(fromKey, toKey string, weight int) bool {
+	fromShard, toShard := c.Shard(fromKey), c.Shard(toKey)
+	if fromShard == nil || toShard == nil {
+		return false
+	}
+
+	if fromShard == toShard {
+		return fromShard.Connect(fromKey, toKey, weight)
+	}
+
+	// hold the cluster lock while checking the endpoints, so neither can be deleted in between
+	c.Lock()
+	defer c.Unlock()
+
+	if _, err := fromShard.Get(fromKey); err != nil {
+		return false
+	}
+	if _, err := toShard.Get(toKey); err != nil {
+		return false
+	}
+
+	if c.outgoing[fromKey] == nil {
+		c.outgoing[fromKey] = map[string]int{}
+	}
+	c.outgoing[fromKey][toKey] = weight
+
+	if c.incoming[toKey] == nil {
+		c.incoming[toKey] = map[string]int{}
+	}
+	c.incoming[toKey][fromKey] = weight
+
+	return true
+}
+
+// Disconnect removes the edge from fromKey to toKey. Returns false if one or both of the keys are invalid or if they are the same, unless their shard allows self-loops.
+func (c *Cluster) Disconnect(fromKey, toKey string) bool {
+	fromShard, toShard := c.Shard(fromKey), c.Shard(toKey)
+	if fromShard == nil || toShard == nil {
+		return false
+	}
+
+	if fromShard == toShard {
+		return fromShard.Disconnect(fromKey, toKey)
+	}
+
+	c.Lock()
+	defer c.Unlock()
+
+	if _, err := fromShard.Get(fromKey); err != nil {
+		return false
+	}
+	if _, err := toShard.Get(toKey); err != nil {
+		return false
+	}
+
+	c.removeCross(fromKey, toKey)
+
+	return true
+}
+
+// IsConnected returns true and the edge weight if there is an edge from fromKey to toKey, on a shard or spanning shards.
+func (c *Cluster) IsConnected(fromKey, toKey string) (exists bool, weight int) {
+	fromShard, toShard := c.Shard(fromKey), c.Shard(toKey)
+	if fromShard == nil || toShard == nil {
+		return
+	}
+
+	if fromShard == toShard {
+		return fromShard.IsConnected(fromKey, toKey)
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	weight, exists = c.outgoing[fromKey][toKey]
+
+	return
+}
+
+// Outgoing returns the keys of all vertices the vertex with the specified key has an edge to, mapped to the edge weights. Returns nil if the key is invalid.
+func (c *Cluster) Outgoing(key string) map[string]int {
+	return c.edges(key, (*Vertex).GetOutgoing, c.outgoing)
+}
+
+// Incoming returns the keys of all vertices with an edge to the vertex with the specified key, mapped to the edge weights. Returns nil if the key is invalid.
+func (c *Cluster) Incoming(key string) map[string]int {
+	return c.edges(key, (*Vertex).GetIncoming, c.incoming)
+}
+
+// edges merges the edges of a vertex within its shard with the edges spanning shards.
+func (c *Cluster) edges(key string, local func(*Vertex) map[*Vertex]int, cross map[string]map[string]int) map[string]int {
+	shard := c.Shard(key)
+	if shard == nil {
+		return nil
+	}
+
+	c.RLock()
+	defer c.RUnlock()
+
+	shard.RLock()
+	v := shard.get(key)
+	if v == nil {
+		shard.RUnlock()
+		return nil
+	}
+	edges := keyWeights(local(v))
+	shard.RUnlock()
+
+	for otherKey, weight := range cross[key] {
+		edges[otherKey] = weight
+	}
+
+	return edges
+}
@@ -0,0 +1,108 @@
+package graph
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestPartitioner(t *testing.T) {
+	p := NewPartitioner(50, "a", "b", "c")
+
+	shards := p.Shards()
+	if len(shards) != 3 || shards[0] != "a" || shards[2] != "c" {
+		t.Fail()
+	}
+
+	before := map[string]string{}
+	counts := map[string]int{}
+	for i := 0; i < 3000; i++ {
+		key := strconv.Itoa(i)
+		before[key] = p.Shard(key)
+		counts[before[key]]++
+	}
+
+	// all shards get a share of the keys
+	for _, shard := range shards {
+		if counts[shard] < 300 {
+			t.Errorf("shard %s only owns %d keys", shard, counts[shard])
+		}
+	}
+
+	// removing a shard only moves that shard's keys
+	p.Remove("b")
+	for key, shard := range before {
+		if shard != "b" && p.Shard(key) != shard {
+			t.Fatalf("key %s moved from %s to %s", key, shard, p.Shard(key))
+		}
+		if p.Shard(key) == "b" {
+			t.Fatal("key assigned to removed shard")
+		}
+	}
+
+	p.Remove("a")
+	p.Remove("c")
+	if p.Shard("1") != "" {
+		t.Fail()
+	}
+}
+
+func TestCluster(t *testing.T) {
+	p := NewPartitioner(10, "a", "b")
+	c := NewCluster(p, map[string]*Graph{"a": New(), "b": New()})
+
+	for i := 0; i < 20; i++ {
+		c.Set(strconv.Itoa(i), i)
+	}
+
+	if c.Len() != 20 || c.shards["a"].Len() == 0 || c.shards["b"].Len() == 0 {
+		t.Fatal("vertices not distributed over both shards")
+	}
+
+	// find two keys on different shards and two on the same one
+	var crossFrom, crossTo, localFrom, localTo string
+	for i := 1; i < 20; i++ {
+		key := strconv.Itoa(i)
+		if p.Shard(key) != p.Shard("0") && crossTo == "" {
+			crossFrom, crossTo = "0", key
+		}
+		if p.Shard(key) == p.Shard("0") && localTo == "" {
+			localFrom, localTo = "0", key
+		}
+	}
+
+	if !c.Connect(crossFrom, crossTo, 4) || !c.Connect(localFrom, localTo, 2) {
+		t.Fatal("connecting failed")
+	}
+
+	if ok, weight := c.IsConnected(crossFrom, crossTo); !ok || weight != 4 {
+		t.Fail()
+	}
+	if ok, weight := c.IsConnected(localFrom, localTo); !ok || weight != 2 {
+		t.Fail()
+	}
+	if ok, _ := c.IsConnected(crossTo, crossFrom); ok {
+		t.Fail()
+	}
+
+	out := c.Outgoing("0")
+	if len(out) != 2 || out[crossTo] != 4 || out[localTo] != 2 {
+		t.Fail()
+	}
+	if in := c.Incoming(crossTo); len(in) != 1 || in["0"] != 4 {
+		t.Fail()
+	}
+
+	// invalid endpoints
+	if c.Connect("0", "x", 1) || c.Connect("0", "0", 1) {
+		t.Fail()
+	}
+
+	// deleting removes edges spanning shards
+	c.Delete("0")
+	if len(c.Incoming(crossTo)) != 0 || len(c.outgoing) != 0 || len(c.incoming) != 0 {
+		t.Fail()
+	}
+	if _, err := c.Get("0"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
@@ -0,0 +1,36 @@
+package graph
+
+// ShortestPathMulti returns the cheapest path from any of the vertices with keys in sources to any of the vertices with keys in targets, in start → end order.
+// Instead of a search for every pair, a single run of Dijkstra's algorithm starts from all sources at once, as if from a virtual super-source connected to them, and stops at the first target reached. Weights must not be negative.
+// Returns ErrInvalidKey if one of the keys is invalid and ErrNoPath if no target can be reached from any source.
+func (g *Graph) ShortestPathMulti(sources, targets []string) ([]string, error) {
+	defer g.track("ShortestPathMulti")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	starts := make([]*Vertex, 0, len(sources))
+	for _, key := range sources {
+		v := g.get(key)
+		if v == nil {
+			return nil, ErrInvalidKey
+		}
+		starts = append(starts, v)
+	}
+
+	ends := make(map[*Vertex]bool, len(targets))
+	for _, key := range targets {
+		v := g.get(key)
+		if v == nil {
+			return nil, ErrInvalidKey
+		}
+		ends[v] = true
+	}
+
+	_, prev, end := g.multiDijkstra(starts, ends, nil, false)
+	if end == nil {
+		return nil, ErrNoPath
+	}
+
+	return pathTo(prev, end), nil
+}
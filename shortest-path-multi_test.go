@@ -0,0 +1,41 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathMulti(t *testing.T) {
+	g := New()
+	for _, key := range []string{"depot1", "depot2", "a", "b", "c", "store1", "store2"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("depot1", "a", 5)
+	g.Connect("a", "store1", 5)
+	g.Connect("depot2", "b", 2)
+	g.Connect("b", "c", 2)
+	g.Connect("c", "store1", 2)
+	g.Connect("b", "store2", 7)
+	g.Connect("a", "store2", 1)
+
+	path, err := g.ShortestPathMulti([]string{"depot1", "depot2"}, []string{"store1", "store2"})
+	if err != nil || !reflect.DeepEqual(path, []string{"depot2", "b", "c", "store1"}) {
+		t.Errorf("unexpected path %v (%v)", path, err)
+	}
+
+	// a source which is also a target
+	if path, err = g.ShortestPathMulti([]string{"depot1", "a"}, []string{"a"}); err != nil || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Fail()
+	}
+
+	if _, err = g.ShortestPathMulti([]string{"store1"}, []string{"depot1", "depot2"}); err != ErrNoPath {
+		t.Fail()
+	}
+	if _, err = g.ShortestPathMulti(nil, []string{"store1"}); err != ErrNoPath {
+		t.Fail()
+	}
+	if _, err = g.ShortestPathMulti([]string{"depot1"}, []string{"x"}); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
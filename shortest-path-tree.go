@@ -0,0 +1,63 @@
+package graph
+
+// PathTree holds the shortest paths from a source vertex to every vertex it can reach, as computed by ShortestPathTree.
+type PathTree struct {
+	Source      string
+	Distance    map[string]int    // weighted distance from the source to each reachable vertex, including the source itself
+	Predecessor map[string]string // previous vertex on the shortest path to each reachable vertex except the source
+}
+
+// PathTo returns the shortest path from the source to the vertex with key in source → key order, and false if key can't be reached.
+func (t *PathTree) PathTo(key string) ([]string, bool) {
+	if _, ok := t.Distance[key]; !ok {
+		return nil, false
+	}
+
+	var path []string
+	for {
+		path = append(path, key)
+
+		prev, ok := t.Predecessor[key]
+		if !ok {
+			break
+		}
+		key = prev
+	}
+
+	// reverse into source → key order
+	for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+		path[i], path[j] = path[j], path[i]
+	}
+
+	return path, true
+}
+
+// ShortestPathTree computes the shortest paths from the vertex with key sourceKey to all vertices it can reach in a single run of Dijkstra's algorithm, so many queries from the same source don't need a search each.
+// Weights must not be negative. Returns ErrInvalidKey if the key is invalid and ErrCostOverflow if a distance doesn't fit into an int.
+func (g *Graph) ShortestPathTree(sourceKey string) (*PathTree, error) {
+	defer g.track("ShortestPathTree")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	source := g.get(sourceKey)
+	if source == nil {
+		return nil, ErrInvalidKey
+	}
+
+	dist, prev := g.dijkstra(source, nil, nil)
+
+	t := &PathTree{sourceKey, make(map[string]int, len(dist)), make(map[string]string, len(prev))}
+	for v, d := range dist {
+		if !fitsInt(d) {
+			return nil, ErrCostOverflow
+		}
+		t.Distance[v.key] = int(d)
+
+		if p := prev[v]; p != nil {
+			t.Predecessor[v.key] = p.key
+		}
+	}
+
+	return t, nil
+}
@@ -0,0 +1,45 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestShortestPathTree(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	g.Connect("a", "b", 4)
+	g.Connect("a", "c", 1)
+	g.Connect("c", "b", 2)
+	g.Connect("b", "d", 5)
+	g.Connect("e", "a", 1)
+
+	tree, err := g.ShortestPathTree("a")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := map[string]int{"a": 0, "b": 3, "c": 1, "d": 8}; !reflect.DeepEqual(tree.Distance, expected) {
+		t.Errorf("expected distances %v, got %v", expected, tree.Distance)
+	}
+	if expected := map[string]string{"b": "c", "c": "a", "d": "b"}; !reflect.DeepEqual(tree.Predecessor, expected) {
+		t.Errorf("expected predecessors %v, got %v", expected, tree.Predecessor)
+	}
+
+	if path, ok := tree.PathTo("d"); !ok || !reflect.DeepEqual(path, []string{"a", "c", "b", "d"}) {
+		t.Errorf("unexpected path %v", path)
+	}
+	if path, ok := tree.PathTo("a"); !ok || !reflect.DeepEqual(path, []string{"a"}) {
+		t.Fail()
+	}
+	if _, ok := tree.PathTo("e"); ok {
+		t.Fail()
+	}
+
+	if _, err = g.ShortestPathTree("x"); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
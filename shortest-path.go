@@ -0,0 +1,125 @@
+package graph
+
+import (
+	"errors"
+	"fmt"
+)
+
+var (
+	// ErrNoPath is returned when there is no path between two vertices.
+	ErrNoPath = errors.New("graph: no path")
+
+	// ErrOpenListLimit is returned when a path search had to keep track of more open vertices than allowed by MaxOpenList.
+	ErrOpenListLimit = errors.New("graph: open list limit exceeded")
+
+	// ErrNegativeWeight is the cause of a WeightError returned when a search configured with NonNegativeWeights encounters a negative edge weight.
+	ErrNegativeWeight = errors.New("graph: negative edge weight")
+
+	// ErrCostOverflow is the cause of a WeightError returned when a search computes a path cost that doesn't fit into an int64, or into an int if configured with CheckOverflow.
+	ErrCostOverflow = errors.New("graph: path cost overflows int")
+)
+
+// WeightError is returned by path searches when the weight of an edge makes the result invalid, see NonNegativeWeights and CheckOverflow.
+type WeightError struct {
+	FromKey, ToKey string // endpoints of the offending edge
+	Weight         int
+	Err            error // ErrNegativeWeight or ErrCostOverflow
+}
+
+func (err *WeightError) Error() string {
+	return fmt.Sprintf("%v: %q → %q with weight %d", err.Err, err.FromKey, err.ToKey, err.Weight)
+}
+
+// Unwrap returns the cause of the error.
+func (err *WeightError) Unwrap() error {
+	return err.Err
+}
+
+const (
+	maxInt   = int(^uint(0) >> 1)
+	minInt   = -maxInt - 1
+	maxInt64 = int64(^uint64(0) >> 1)
+	minInt64 = -maxInt64 - 1
+)
+
+// addOverflows returns true if a + b doesn't fit into an int64. Path costs are accumulated as int64 internally.
+func addOverflows(a, b int64) bool {
+	return (b > 0 && a > maxInt64-b) || (b < 0 && a < minInt64-b)
+}
+
+// fitsInt returns true if x can be converted into an int without loss, which is not the case for large values on 32-bit platforms.
+func fitsInt(x int64) bool {
+	return x >= int64(minInt) && x <= int64(maxInt)
+}
+
+// PathOption configures a search started with ShortestPath.
+type PathOption func(*pathConfig)
+
+// pathConfig holds the settings of a path search.
+type pathConfig struct {
+	heuristic func(key, endKey string) int
+	maxOpen   int  // maximum number of open vertices, 0 means unlimited
+	recover   bool // convert panics into errors
+
+	tieBreak TieBreaker // orders open vertices with the same priority, nil means unspecified
+
+	nonNegative   bool // fail on negative weights
+	checkOverflow bool // fail on path costs not fitting into an int
+}
+
+// validates returns true if the search has to look at every edge it uses itself, so it must not be answered from the path cache.
+func (cfg *pathConfig) validates() bool {
+	return cfg.nonNegative || cfg.checkOverflow
+}
+
+// WithHeuristic makes the search use the A* heuristic h to estimate the distance from a vertex to the end vertex. It is passed the keys of a vertex and the end vertex.
+func WithHeuristic(h func(key, endKey string) int) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.heuristic = h
+	}
+}
+
+// MaxOpenList bounds the memory used by the search: if more than n vertices are waiting to be visited at once, the search is aborted with ErrOpenListLimit.
+func MaxOpenList(n int) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.maxOpen = n
+	}
+}
+
+// RecoverPanics makes the search recover from panics in user-supplied callbacks like the heuristic and return them as *PanicError, so a faulty callback can't crash the program.
+func RecoverPanics() PathOption {
+	return func(cfg *pathConfig) {
+		cfg.recover = true
+	}
+}
+
+// NonNegativeWeights makes the search fail with a *WeightError wrapping ErrNegativeWeight when it encounters an edge with a negative weight, since A* and Dijkstra's algorithm may return paths which aren't the shortest ones then.
+func NonNegativeWeights() PathOption {
+	return func(cfg *pathConfig) {
+		cfg.nonNegative = true
+	}
+}
+
+// CheckOverflow makes the search fail with a *WeightError wrapping ErrCostOverflow when the cost of a path, or its cost plus the heuristic's estimate, doesn't fit into an int.
+// Costs are accumulated as int64, whose overflow is always detected; this option matters on 32-bit platforms, where int is smaller.
+func CheckOverflow() PathOption {
+	return func(cfg *pathConfig) {
+		cfg.checkOverflow = true
+	}
+}
+
+// ShortestPath returns the shortest path from the vertex with key startKey to the vertex with key endKey in start → end order, using the A* search algorithm as configured by opts.
+// Without a heuristic, the search degrades to Dijkstra's algorithm. Returns ErrInvalidKey if one of the keys is invalid and ErrNoPath if there is no path.
+func (g *Graph) ShortestPath(startKey, endKey string, opts ...PathOption) (path []string, err error) {
+	defer g.track("ShortestPath")()
+
+	cfg := &pathConfig{heuristic: func(key, endKey string) int { return 0 }}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.shortestPath(startKey, endKey, cfg)
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"strconv"
+	"testing"
+)
+
+func TestShortestPath(t *testing.T) {
+	g := New()
+
+	for i := 1; i <= 5; i++ {
+		g.Set(strconv.Itoa(i), i)
+	}
+
+	g.Connect("1", "2", 1)
+	g.Connect("2", "3", 1)
+	g.Connect("1", "3", 3)
+	g.Connect("3", "4", 1)
+	g.Connect("1", "5", 1)
+
+	path, err := g.ShortestPath("1", "4")
+	if err != nil || len(path) != 4 || path[0] != "1" || path[1] != "2" || path[3] != "4" {
+		t.Fail()
+	}
+
+	path, err = g.ShortestPath("1", "4", WithHeuristic(func(key, endKey string) int { return 0 }))
+	if err != nil || len(path) != 4 {
+		t.Fail()
+	}
+
+	if _, err = g.ShortestPath("4", "1"); err != ErrNoPath {
+		t.Fail()
+	}
+	if _, err = g.ShortestPath("1", "6"); err != ErrInvalidKey {
+		t.Fail()
+	}
+
+	// vertex 1 has three neighbors
+	if _, err = g.ShortestPath("1", "4", MaxOpenList(2)); err != ErrOpenListLimit {
+		t.Fail()
+	}
+	if _, err = g.ShortestPath("1", "4", MaxOpenList(3)); err != nil {
+		t.Fail()
+	}
+}
+
+func TestShortestPathRecoverPanics(t *testing.T) {
+	g := New()
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Connect("1", "2", 1)
+
+	faulty := WithHeuristic(func(key, endKey string) int {
+		panic("faulty heuristic")
+	})
+
+	_, err := g.ShortestPath("1", "2", faulty, RecoverPanics())
+
+	pe, ok := err.(*PanicError)
+	if !ok || pe.Value != "faulty heuristic" || len(pe.Stack) == 0 {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+
+	// the graph is still usable, i.e. not locked anymore
+	g.Set("3", nil)
+
+	// without recovering, the panic propagates
+	err = Guard(func() {
+		g.ShortestPath("1", "2", faulty)
+	})
+	if _, ok = err.(*PanicError); !ok {
+		t.Fail()
+	}
+
+	if err = Guard(func() {}); err != nil {
+		t.Fail()
+	}
+}
+
+func TestGuardSimulation(t *testing.T) {
+	g := New()
+	for i := 0; i < 10; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+
+	s := NewSimulation(g, func(key string, value interface{}) interface{} {
+		return 0
+	}, func(n *Neighborhood, state interface{}) interface{} {
+		if n.Key == "7" {
+			panic("faulty update")
+		}
+		return state.(int) + 1
+	})
+	s.Workers = 4
+
+	err := Guard(func() {
+		s.Step()
+	})
+
+	if pe, ok := err.(*PanicError); !ok || pe.Value != "faulty update" {
+		t.Fatalf("expected panic error, got %v", err)
+	}
+
+	// states are unchanged
+	if snapshot := s.State(); snapshot.Step != 0 || snapshot.States["1"] != 0 {
+		t.Fail()
+	}
+}
+
+func TestShortestPathWeightValidation(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+
+	g.Connect("a", "b", 5)
+	g.Connect("b", "c", -3)
+
+	// silently accepted by default
+	if _, err := g.ShortestPath("a", "c"); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := g.ShortestPath("a", "c", NonNegativeWeights())
+	weightErr, ok := err.(*WeightError)
+	if !ok || weightErr.Err != ErrNegativeWeight || weightErr.FromKey != "b" || weightErr.ToKey != "c" || weightErr.Weight != -3 {
+		t.Fatalf("expected negative weight error, got %v", err)
+	}
+
+	g.Connect("b", "c", maxInt)
+
+	_, err = g.ShortestPath("a", "c", CheckOverflow())
+	if weightErr, ok = err.(*WeightError); !ok || weightErr.Err != ErrCostOverflow {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+
+	// a huge heuristic estimate overflows, too
+	g.Connect("b", "c", 1)
+	_, err = g.ShortestPath("a", "c", CheckOverflow(), WithHeuristic(func(key, endKey string) int { return maxInt - 1 }))
+	if weightErr, ok = err.(*WeightError); !ok || weightErr.Err != ErrCostOverflow {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+
+	if path, err := g.ShortestPath("a", "c", CheckOverflow(), NonNegativeWeights()); err != nil || len(path) != 3 {
+		t.Fail()
+	}
+}
+
+func TestShortestPathCostOverflow(t *testing.T) {
+	g := New()
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+
+	g.Connect("a", "b", maxInt)
+	g.Connect("b", "c", maxInt)
+
+	// the sum exceeds an int64 at least on 64-bit platforms, and is too large for an int on all of them
+	_, err := g.ShortestPath("a", "c", CheckOverflow())
+	if weightErr, ok := err.(*WeightError); !ok || weightErr.Err != ErrCostOverflow {
+		t.Fatalf("expected overflow error, got %v", err)
+	}
+
+	// searches without error results ignore paths whose cost overflows
+	if maxInt64 == int64(maxInt) {
+		if _, err = g.ShortestPath("a", "c"); err == nil {
+			t.Fail()
+		}
+		if _, ok := g.ShortestPathBidirectional("a", "c", noHeuristic); ok {
+			t.Fail()
+		}
+		if _, _, ok := NewRouter(g, func(from, to string) int { return -1 }, Penalize).Route("a", "c", 1); ok {
+			t.Fail()
+		}
+	}
+}
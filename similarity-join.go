@@ -0,0 +1,179 @@
+package graph
+
+import (
+	"hash/fnv"
+	"math"
+	"math/rand"
+	"sort"
+	"strconv"
+)
+
+// SimilarityJoin describes how ConnectSimilar compares the values of vertices.
+type SimilarityJoin struct {
+	// Similarity compares two vertex values. If it is nil, Vector must be set.
+	Similarity func(a, b interface{}) float64
+
+	// Vector converts a vertex value into a vector; vectors are compared by cosine similarity. Only used if Similarity is nil.
+	Vector func(value interface{}) []float64
+
+	// Threshold is the minimum similarity for two vertices to be connected.
+	Threshold float64
+
+	// Block assigns vertices to blocks; only vertices sharing at least one block are compared. If it is nil, all pairs of vertices are compared, which takes quadratic time.
+	// See MinHashBlocks for a locality-sensitive hashing scheme.
+	Block func(key string, value interface{}) []string
+
+	// Weight converts a similarity into the weight of the edges created. If it is nil, all edges have a weight of 1.
+	Weight func(similarity float64) int
+}
+
+// ConnectSimilar connects all pairs of vertices whose values are at least as similar as specified by join. Both directions are connected, with the same weight.
+// Returns the number of pairs connected.
+func (g *Graph) ConnectSimilar(join SimilarityJoin) int {
+	defer g.track("ConnectSimilar")()
+
+	similarity := join.Similarity
+	if similarity == nil {
+		similarity = func(a, b interface{}) float64 {
+			return CosineSimilarity(join.Vector(a), join.Vector(b))
+		}
+	}
+
+	// collect the values in a stable order, so the result doesn't depend on map iteration
+	g.RLock()
+	keys := make([]string, 0, len(g.vertices))
+	values := make(map[string]interface{}, len(g.vertices))
+	for key, v := range g.vertices {
+		keys = append(keys, key)
+		values[key] = v.Value()
+	}
+	g.RUnlock()
+	sort.Strings(keys)
+
+	var pairs [][2]string
+	if join.Block == nil {
+		for i := range keys {
+			for j := i + 1; j < len(keys); j++ {
+				pairs = append(pairs, [2]string{keys[i], keys[j]})
+			}
+		}
+	} else {
+		blocks := map[string][]string{}
+		for _, key := range keys {
+			for _, block := range join.Block(key, values[key]) {
+				blocks[block] = append(blocks[block], key)
+			}
+		}
+
+		// vertices sharing several blocks are only compared once
+		compared := map[[2]string]bool{}
+		for _, block := range blocks {
+			for i := range block {
+				for j := i + 1; j < len(block); j++ {
+					pair := [2]string{block[i], block[j]}
+					if !compared[pair] {
+						compared[pair] = true
+						pairs = append(pairs, pair)
+					}
+				}
+			}
+		}
+	}
+
+	connected := 0
+	for _, pair := range pairs {
+		s := similarity(values[pair[0]], values[pair[1]])
+		if s < join.Threshold {
+			continue
+		}
+
+		weight := 1
+		if join.Weight != nil {
+			weight = join.Weight(s)
+		}
+
+		// vertices might have been deleted in the meantime
+		if g.Connect(pair[0], pair[1], weight) && g.Connect(pair[1], pair[0], weight) {
+			connected++
+		}
+	}
+
+	return connected
+}
+
+// CosineSimilarity returns the cosine of the angle between a and b, or 0 if either of them is a zero vector. Missing components of the shorter vector are treated as 0.
+func CosineSimilarity(a, b []float64) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) || i < len(b); i++ {
+		var x, y float64
+		if i < len(a) {
+			x = a[i]
+		}
+		if i < len(b) {
+			y = b[i]
+		}
+
+		dot += x * y
+		normA += x * x
+		normB += y * y
+	}
+
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// MinHashBlocks returns a blocking function for SimilarityJoin that implements locality-sensitive hashing of the sets of features returned by features.
+// Each vertex is assigned to one block per band, determined by the MinHash signature of rows hash functions; vertices with a high Jaccard similarity of their features are likely to share a block.
+// More rows per band make blocks more selective, more bands make it less likely to miss similar pairs. The hash functions are derived from seed.
+func MinHashBlocks(bands, rows int, seed int64, features func(value interface{}) []string) func(key string, value interface{}) []string {
+	r := rand.New(rand.NewSource(seed))
+
+	salts := make([]uint64, bands*rows)
+	for i := range salts {
+		salts[i] = r.Uint64() | 1
+	}
+
+	return func(key string, value interface{}) []string {
+		set := features(value)
+
+		// vertices without features have no meaningful signature
+		if len(set) == 0 {
+			return nil
+		}
+
+		signature := make([]uint64, len(salts))
+		for i := range signature {
+			signature[i] = math.MaxUint64
+		}
+
+		for _, feature := range set {
+			h := fnv.New64a()
+			h.Write([]byte(feature))
+			x := h.Sum64()
+
+			for i, salt := range salts {
+				// multiply-xorshift mixing with a different salt for every hash function
+				y := (x ^ salt) * 0x9e3779b97f4a7c15
+				y ^= y >> 32
+				if y < signature[i] {
+					signature[i] = y
+				}
+			}
+		}
+
+		blocks := make([]string, bands)
+		for b := range blocks {
+			h := fnv.New64a()
+			for _, x := range signature[b*rows : (b+1)*rows] {
+				h.Write([]byte(strconv.FormatUint(x, 16)))
+				h.Write([]byte{0})
+			}
+			blocks[b] = strconv.Itoa(b) + ":" + strconv.FormatUint(h.Sum64(), 16)
+		}
+
+		return blocks
+	}
+}
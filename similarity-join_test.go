@@ -0,0 +1,89 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestConnectSimilar(t *testing.T) {
+	g := New()
+	g.Set("a", []float64{1, 0})
+	g.Set("b", []float64{0.9, 0.1})
+	g.Set("c", []float64{0, 1})
+
+	n := g.ConnectSimilar(SimilarityJoin{
+		Vector:    func(value interface{}) []float64 { return value.([]float64) },
+		Threshold: 0.9,
+		Weight:    func(s float64) int { return int(s * 100) },
+	})
+
+	if n != 1 {
+		t.Fatalf("expected 1 pair, got %d", n)
+	}
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 99 {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("b", "a"); !ok {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("a", "c"); ok {
+		t.Fail()
+	}
+}
+
+func TestConnectSimilarMinHash(t *testing.T) {
+	words := func(value interface{}) []string { return strings.Fields(value.(string)) }
+	jaccard := func(a, b interface{}) float64 {
+		set := map[string]bool{}
+		for _, w := range words(a) {
+			set[w] = true
+		}
+		shared := 0
+		for _, w := range words(b) {
+			if set[w] {
+				shared++
+			}
+		}
+		return float64(shared) / float64(len(set)+len(words(b))-shared)
+	}
+
+	g := New()
+	g.Set("a", "the quick brown fox jumps over the lazy dog")
+	g.Set("b", "the quick brown fox jumps over the lazy cat")
+	g.Set("c", "lorem ipsum dolor sit amet consectetur adipiscing elit")
+	g.Set("d", "")
+
+	compared := 0
+	n := g.ConnectSimilar(SimilarityJoin{
+		Similarity: func(a, b interface{}) float64 {
+			compared++
+			return jaccard(a, b)
+		},
+		Threshold: 0.5,
+		Block:     MinHashBlocks(20, 2, 1, words),
+	})
+
+	if n != 1 {
+		t.Fatalf("expected 1 pair, got %d", n)
+	}
+	if ok, _ := g.IsConnected("a", "b"); !ok {
+		t.Fail()
+	}
+
+	// the dissimilar and empty values should not have been compared at all
+	if compared != 1 {
+		t.Errorf("expected 1 comparison, got %d", compared)
+	}
+}
+
+func TestCosineSimilarity(t *testing.T) {
+	if CosineSimilarity([]float64{1, 2}, []float64{2, 4}) < 0.9999 {
+		t.Fail()
+	}
+	if CosineSimilarity([]float64{1, 0}, []float64{0, 1, 5}) != 0 {
+		t.Fail()
+	}
+	if CosineSimilarity(nil, []float64{1}) != 0 {
+		t.Fail()
+	}
+}
@@ -0,0 +1,177 @@
+package graph
+
+import (
+	"math/rand"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+)
+
+// Neighborhood is passed to an UpdateFunc and describes the vertex being updated and its surroundings at the current step.
+type Neighborhood struct {
+	Key      string         // key of the vertex being updated
+	Value    interface{}    // value stored in the vertex
+	Step     int            // number of the step being computed, starting at 1
+	Incoming map[string]int // maps the keys of vertices with an edge to this one to the edge weights
+	Outgoing map[string]int // maps the keys of vertices this one has an edge to to the edge weights
+	Rand     *rand.Rand     // random source for stochastic processes; only valid during the update call
+
+	states map[string]interface{}
+}
+
+// State returns the state of the vertex with the given key at the beginning of the current step.
+func (n *Neighborhood) State(key string) interface{} {
+	return n.states[key]
+}
+
+// UpdateFunc computes the next state of a vertex from its current state and its neighborhood.
+// It is called concurrently for different vertices and must not modify any of the Neighborhood's maps.
+type UpdateFunc func(n *Neighborhood, state interface{}) interface{}
+
+// SimulationState holds the states of all vertices after a simulation step. States must not be modified.
+type SimulationState struct {
+	Step   int
+	States map[string]interface{}
+}
+
+// Simulation steps a user-defined update function over the vertices of a graph in discrete time, e.g. for SIR-style spreading, gossip or random surfer processes.
+// In each step, the next states of all vertices are computed in parallel from the states at the end of the previous step.
+type Simulation struct {
+	g       *Graph
+	initial func(key string, value interface{}) interface{}
+	update  UpdateFunc
+	states  map[string]interface{}
+	step    int
+
+	// Workers is the number of goroutines updating vertices in parallel. NewSimulation sets it to runtime.GOMAXPROCS(0).
+	Workers int
+
+	// Seed seeds the random sources passed to the update function. With the same seed and number of workers, a simulation is reproducible.
+	Seed int64
+}
+
+// NewSimulation initializes a new simulation over g. The initial function is called to compute the state of every vertex before the first step, and for vertices added to the graph while the simulation is running.
+func NewSimulation(g *Graph, initial func(key string, value interface{}) interface{}, update UpdateFunc) *Simulation {
+	s := &Simulation{
+		g:       g,
+		initial: initial,
+		update:  update,
+		states:  map[string]interface{}{},
+		Workers: runtime.GOMAXPROCS(0),
+	}
+
+	g.RLock()
+	for key, v := range g.vertices {
+		s.states[key] = initial(key, v.Value())
+	}
+	g.RUnlock()
+
+	return s
+}
+
+// State returns the current states of all vertices.
+func (s *Simulation) State() SimulationState {
+	return SimulationState{s.step, s.states}
+}
+
+// Step advances the simulation by one step and returns the new states.
+// If the update function panics, Step panics with a *PanicError in the calling goroutine and leaves the states unchanged; use Guard to recover.
+func (s *Simulation) Step() SimulationState {
+	s.g.RLock()
+	defer s.g.RUnlock()
+
+	// iterate over the vertices in a stable order, so results are reproducible
+	keys := make([]string, 0, len(s.g.vertices))
+	for key, v := range s.g.vertices {
+		keys = append(keys, key)
+
+		// vertex was added since the last step
+		if _, ok := s.states[key]; !ok {
+			s.states[key] = s.initial(key, v.Value())
+		}
+	}
+	sort.Strings(keys)
+
+	s.step++
+
+	workers := s.Workers
+	if workers < 1 {
+		workers = 1
+	}
+
+	next := make([]interface{}, len(keys))
+	chunk := (len(keys) + workers - 1) / workers
+
+	// a panicking update function must not crash the whole program from within a worker, so panics are passed on to the calling goroutine
+	var failure *PanicError
+	failureMutex := sync.Mutex{}
+
+	wg := sync.WaitGroup{}
+	for w := 0; w*chunk < len(keys); w++ {
+		wg.Add(1)
+
+		go func(w int) {
+			defer wg.Done()
+			defer func() {
+				if r := recover(); r != nil {
+					failureMutex.Lock()
+					if failure == nil {
+						failure = &PanicError{r, debug.Stack()}
+					}
+					failureMutex.Unlock()
+				}
+			}()
+
+			r := rand.New(rand.NewSource(s.Seed + int64(s.step)*int64(workers) + int64(w)))
+
+			for i := w * chunk; i < (w+1)*chunk && i < len(keys); i++ {
+				v := s.g.get(keys[i])
+
+				n := &Neighborhood{
+					Key:      v.key,
+					Value:    v.Value(),
+					Step:     s.step,
+					Incoming: keyWeights(v.GetIncoming()),
+					Outgoing: keyWeights(v.GetOutgoing()),
+					Rand:     r,
+					states:   s.states,
+				}
+
+				next[i] = s.update(n, s.states[v.key])
+			}
+		}(w)
+	}
+	wg.Wait()
+
+	if failure != nil {
+		s.step--
+		panic(failure)
+	}
+
+	// states of deleted vertices are dropped
+	s.states = make(map[string]interface{}, len(keys))
+	for i, key := range keys {
+		s.states[key] = next[i]
+	}
+
+	return SimulationState{s.step, s.states}
+}
+
+// Run advances the simulation by the given number of steps, passing the states after each step to emit. It stops early if emit returns false.
+func (s *Simulation) Run(steps int, emit func(SimulationState) bool) {
+	for i := 0; i < steps; i++ {
+		if !emit(s.Step()) {
+			return
+		}
+	}
+}
+
+// keyWeights converts a map of edges into a map of neighbor keys to edge weights.
+func keyWeights(edges map[*Vertex]int) map[string]int {
+	m := make(map[string]int, len(edges))
+	for v, weight := range edges {
+		m[v.key] = weight
+	}
+	return m
+}
@@ -0,0 +1,138 @@
+package graph
+
+import (
+	"fmt"
+	"strconv"
+	"testing"
+)
+
+func TestSimulationGossip(t *testing.T) {
+	g := New()
+
+	// chain 0 → 1 → … → 9
+	for i := 0; i < 10; i++ {
+		g.Set(strconv.Itoa(i), i)
+		if i > 0 {
+			g.Connect(strconv.Itoa(i-1), strconv.Itoa(i), 1)
+		}
+	}
+
+	// only vertex "0" knows the rumor in the beginning
+	s := NewSimulation(g, func(key string, value interface{}) interface{} {
+		return key == "0"
+	}, func(n *Neighborhood, state interface{}) interface{} {
+		if state.(bool) {
+			return true
+		}
+		for key := range n.Incoming {
+			if n.State(key).(bool) {
+				return true
+			}
+		}
+		return false
+	})
+	s.Workers = 3
+
+	steps := 0
+	s.Run(100, func(state SimulationState) bool {
+		steps++
+
+		if state.Step != steps {
+			t.Fail()
+		}
+
+		// the rumor travels one hop per step
+		for i := 0; i < 10; i++ {
+			if state.States[strconv.Itoa(i)].(bool) != (i <= steps) {
+				t.Fatalf("step %d: wrong state of vertex %d", steps, i)
+			}
+		}
+
+		return !state.States["9"].(bool)
+	})
+
+	if steps != 9 {
+		t.Fail()
+	}
+
+	// deleted vertices are dropped, new ones initialized
+	g.Delete("9")
+	g.Set("10", 10)
+
+	state := s.Step()
+	if _, ok := state.States["9"]; ok {
+		t.Fail()
+	}
+	if known, ok := state.States["10"]; !ok || known.(bool) {
+		t.Fail()
+	}
+}
+
+func TestSimulationReproducible(t *testing.T) {
+	g := New()
+	for i := 0; i < 50; i++ {
+		g.Set(strconv.Itoa(i), nil)
+	}
+
+	run := func() map[string]interface{} {
+		s := NewSimulation(g, func(key string, value interface{}) interface{} {
+			return 0
+		}, func(n *Neighborhood, state interface{}) interface{} {
+			return state.(int) + n.Rand.Intn(100)
+		})
+		s.Workers = 4
+		s.Seed = 42
+
+		s.Run(5, func(SimulationState) bool { return true })
+
+		return s.State().States
+	}
+
+	a, b := run(), run()
+	for key := range a {
+		if a[key] != b[key] {
+			t.Fail()
+		}
+	}
+}
+
+func ExampleSimulation() {
+	g := New()
+
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+
+	// SIR model: infected vertices infect their neighbors and recover after one step
+	s := NewSimulation(g, func(key string, value interface{}) interface{} {
+		if key == "a" {
+			return "I"
+		}
+		return "S"
+	}, func(n *Neighborhood, state interface{}) interface{} {
+		switch state {
+		case "I":
+			return "R"
+		case "S":
+			for key := range n.Incoming {
+				if n.State(key) == "I" {
+					return "I"
+				}
+			}
+		}
+		return state
+	})
+
+	s.Run(3, func(state SimulationState) bool {
+		fmt.Println(state.Step, state.States["a"], state.States["b"], state.States["c"])
+		return true
+	})
+
+	// Output:
+	// 1 R I S
+	// 2 R R I
+	// 3 R R R
+}
@@ -0,0 +1,209 @@
+package graph
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// snapshotMagic starts snapshot files written by Snapshot, followed by a version byte, the compression and the compressed GobEncode data. A CRC-32C checksum of everything before it ends the file.
+const snapshotMagic = "\x80gss"
+
+// snapshotVersion is the version of the snapshot file format.
+const snapshotVersion = 1
+
+// snapshotCRCTable is the table of the CRC-32C checksums of snapshot files.
+var snapshotCRCTable = crc32.MakeTable(crc32.Castagnoli)
+
+// ErrSnapshotChecksum is returned by LoadSnapshot if the checksum of a snapshot file doesn't match its contents, e.g. because it was truncated or corrupted on disk.
+var ErrSnapshotChecksum = errors.New("graph: snapshot checksum mismatch")
+
+// SnapshotCompression identifies the compression of a snapshot file.
+type SnapshotCompression byte
+
+// Compressions of snapshot files. Gzip is supported out of the box; others must be registered with RegisterSnapshotCompression before use, so this package doesn't depend on their implementations.
+const (
+	SnapshotUncompressed SnapshotCompression = iota
+	SnapshotGzip
+	SnapshotZstd
+)
+
+// snapshotCodec compresses and decompresses snapshot files.
+type snapshotCodec struct {
+	compress   func(w io.Writer) (io.WriteCloser, error)
+	decompress func(r io.Reader) (io.Reader, error)
+}
+
+// snapshotCodecs holds the registered compressions, see RegisterSnapshotCompression.
+var (
+	snapshotCodecs = map[SnapshotCompression]snapshotCodec{
+		SnapshotGzip: {
+			compress:   func(w io.Writer) (io.WriteCloser, error) { return gzip.NewWriter(w), nil },
+			decompress: func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		},
+	}
+	snapshotCodecsMutex sync.RWMutex
+)
+
+// RegisterSnapshotCompression makes Snapshot and LoadSnapshot support the compression c, using the given functions to wrap writers and readers. E.g. for zstd using github.com/klauspost/compress/zstd:
+//
+//	graph.RegisterSnapshotCompression(graph.SnapshotZstd,
+//		func(w io.Writer) (io.WriteCloser, error) { return zstd.NewWriter(w) },
+//		func(r io.Reader) (io.Reader, error) { return zstd.NewReader(r) })
+func RegisterSnapshotCompression(c SnapshotCompression, compress func(w io.Writer) (io.WriteCloser, error), decompress func(r io.Reader) (io.Reader, error)) {
+	snapshotCodecsMutex.Lock()
+	snapshotCodecs[c] = snapshotCodec{compress, decompress}
+	snapshotCodecsMutex.Unlock()
+}
+
+// snapshotCodecFor returns the codec registered for c.
+func snapshotCodecFor(c SnapshotCompression) (snapshotCodec, error) {
+	snapshotCodecsMutex.RLock()
+	codec, ok := snapshotCodecs[c]
+	snapshotCodecsMutex.RUnlock()
+
+	if !ok {
+		return codec, fmt.Errorf("graph: snapshot compression %d is not registered", c)
+	}
+	return codec, nil
+}
+
+// SnapshotOption configures Snapshot.
+type SnapshotOption func(*snapshotConfig)
+
+// snapshotConfig holds the settings of a snapshot file.
+type snapshotConfig struct {
+	compression SnapshotCompression
+}
+
+// SnapshotCompress makes Snapshot compress the file with c, see RegisterSnapshotCompression.
+func SnapshotCompress(c SnapshotCompression) SnapshotOption {
+	return func(cfg *snapshotConfig) {
+		cfg.compression = c
+	}
+}
+
+// Snapshot writes the vertices and edges of the graph to the file at path in the GobEncode format, optionally compressed, with a checksum. The file is written to a temporary file first, which is renamed once it is complete, so path always holds a complete snapshot, even if the process crashes while writing.
+// The graph is only locked while it is copied, so writers aren't blocked while encoding and writing the file. Read the snapshot with LoadSnapshot.
+func (g *Graph) Snapshot(path string, opts ...SnapshotOption) error {
+	defer g.track("Snapshot")()
+
+	cfg := &snapshotConfig{}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	g.RLock()
+	c := g.clone()
+	g.RUnlock()
+
+	data, err := c.GobEncode()
+	if err != nil {
+		return err
+	}
+
+	buf := bytes.NewBufferString(snapshotMagic)
+	buf.WriteByte(snapshotVersion)
+	buf.WriteByte(byte(cfg.compression))
+
+	if cfg.compression == SnapshotUncompressed {
+		buf.Write(data)
+	} else {
+		codec, err := snapshotCodecFor(cfg.compression)
+		if err != nil {
+			return err
+		}
+
+		w, err := codec.compress(buf)
+		if err != nil {
+			return err
+		}
+		if _, err = w.Write(data); err != nil {
+			return err
+		}
+		if err = w.Close(); err != nil {
+			return err
+		}
+	}
+
+	checksum := make([]byte, 4)
+	binary.BigEndian.PutUint32(checksum, crc32.Checksum(buf.Bytes(), snapshotCRCTable))
+	buf.Write(checksum)
+
+	return writeFileAtomic(path, buf.Bytes())
+}
+
+// LoadSnapshot reads a new graph from the snapshot file at path written by Snapshot.
+// Returns ErrSnapshotChecksum if the file is corrupted, and an error wrapping ErrUnsupportedVersion if it was written by a newer version of this package.
+func LoadSnapshot(path string) (*Graph, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	header := len(snapshotMagic) + 2
+	if len(b) < header+4 || string(b[:len(snapshotMagic)]) != snapshotMagic {
+		return nil, fmt.Errorf("graph: reading snapshot: %s is not a snapshot file", path)
+	}
+
+	content, checksum := b[:len(b)-4], binary.BigEndian.Uint32(b[len(b)-4:])
+	if crc32.Checksum(content, snapshotCRCTable) != checksum {
+		return nil, ErrSnapshotChecksum
+	}
+
+	if version := b[len(snapshotMagic)]; version > snapshotVersion {
+		return nil, fmt.Errorf("graph: reading snapshot: %w %d", ErrUnsupportedVersion, version)
+	}
+
+	data := content[header:]
+	if compression := SnapshotCompression(b[len(snapshotMagic)+1]); compression != SnapshotUncompressed {
+		codec, err := snapshotCodecFor(compression)
+		if err != nil {
+			return nil, err
+		}
+
+		r, err := codec.decompress(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("graph: reading snapshot: %v", err)
+		}
+		if data, err = ioutil.ReadAll(r); err != nil {
+			return nil, fmt.Errorf("graph: reading snapshot: %v", err)
+		}
+	}
+
+	g := New()
+	if err := g.GobDecode(data); err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// writeFileAtomic writes b to a temporary file in the directory of path and renames it to path once it is synced to disk, so there are no incomplete files at path.
+func writeFileAtomic(path string, b []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(path), ".tmp-")
+	if err != nil {
+		return err
+	}
+
+	if _, err = tmp.Write(b); err == nil {
+		err = tmp.Sync()
+	}
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,123 @@
+package graph
+
+import (
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// nopWriteCloser adds a Close method doing nothing to a writer.
+type nopWriteCloser struct {
+	io.Writer
+}
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestSnapshotFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", "two")
+	g.Connect("a", "b", 3)
+
+	// a custom compression, e.g. zstd
+	RegisterSnapshotCompression(SnapshotZstd,
+		func(w io.Writer) (io.WriteCloser, error) { return nopWriteCloser{w}, nil },
+		func(r io.Reader) (io.Reader, error) { return r, nil })
+	defer func() {
+		snapshotCodecsMutex.Lock()
+		delete(snapshotCodecs, SnapshotZstd)
+		snapshotCodecsMutex.Unlock()
+	}()
+
+	for _, compression := range []SnapshotCompression{SnapshotUncompressed, SnapshotGzip, SnapshotZstd} {
+		path := filepath.Join(dir, "graph.snapshot")
+		if err := g.Snapshot(path, SnapshotCompress(compression)); err != nil {
+			t.Fatalf("compression %d: unexpected error %v", compression, err)
+		}
+
+		h, err := LoadSnapshot(path)
+		if err != nil {
+			t.Fatalf("compression %d: unexpected error %v", compression, err)
+		}
+		if h.Len() != 2 {
+			t.Errorf("compression %d: expected 2 vertices, got %d", compression, h.Len())
+		}
+		if ok, weight := h.IsConnected("a", "b"); !ok || weight != 3 {
+			t.Errorf("compression %d: expected edge, got %v %d", compression, ok, weight)
+		}
+	}
+
+	// no temporary files are left behind
+	if files, _ := ioutil.ReadDir(dir); len(files) != 1 {
+		t.Errorf("expected only the snapshot file, got %d files", len(files))
+	}
+}
+
+func TestLoadSnapshotErrors(t *testing.T) {
+	dir, err := ioutil.TempDir("", "snapshot")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "graph.snapshot")
+
+	g := New()
+	g.Set("a", 1)
+	if err := g.Snapshot(path, SnapshotCompress(SnapshotGzip)); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	b, _ := ioutil.ReadFile(path)
+
+	// corrupted contents
+	corrupted := append([]byte{}, b...)
+	corrupted[len(corrupted)/2] ^= 1
+	ioutil.WriteFile(path, corrupted, 0600)
+	if _, err := LoadSnapshot(path); err != ErrSnapshotChecksum {
+		t.Errorf("expected ErrSnapshotChecksum, got %v", err)
+	}
+
+	// truncated file
+	ioutil.WriteFile(path, b[:len(b)-1], 0600)
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("expected an error for a truncated file")
+	}
+
+	// unknown compression
+	if err := g.Snapshot(path, SnapshotCompress(SnapshotZstd)); err == nil {
+		t.Error("expected an error for an unregistered compression")
+	}
+
+	// other files
+	ioutil.WriteFile(path, []byte("not a snapshot"), 0600)
+	if _, err := LoadSnapshot(path); err == nil {
+		t.Error("expected an error for other files")
+	}
+
+	if _, err := LoadSnapshot(filepath.Join(dir, "missing")); !os.IsNotExist(err) {
+		t.Errorf("expected a not exist error, got %v", err)
+	}
+
+	// newer versions
+	newer := append([]byte{}, b...)
+	newer[len(snapshotMagic)]++
+	ioutil.WriteFile(path, newer, 0600)
+	if _, err := LoadSnapshot(path); err != ErrSnapshotChecksum {
+		t.Errorf("expected the checksum to cover the header, got %v", err)
+	}
+	binary.BigEndian.PutUint32(newer[len(newer)-4:], crc32.Checksum(newer[:len(newer)-4], snapshotCRCTable))
+	ioutil.WriteFile(path, newer, 0600)
+	if _, err := LoadSnapshot(path); !errors.Is(err, ErrUnsupportedVersion) {
+		t.Errorf("expected ErrUnsupportedVersion, got %v", err)
+	}
+}
@@ -0,0 +1,105 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+)
+
+// ErrKeyExists is returned when an operation would create a vertex with a key that is taken already.
+var ErrKeyExists = errors.New("graph: key exists")
+
+// SplitVertex divides the vertex with the specified key into several vertices, e.g. to refactor an over-aggregated vertex: partition is called for each incoming and outgoing edge of the vertex and returns the key of the vertex the edge should be moved to.
+// Edges for which partition returns key stay where they are. For every other key returned, a new vertex with the same value, tags and labels as the split vertex is created. Moved edges keep their attributes; a self-loop is passed to partition once and moved as a self-loop of the new vertex. The split vertex itself is kept, even if no edges remain.
+// The split is atomic; it fails without changing the graph with ErrInvalidKey if the key is invalid, ErrKeyExists if partition returns the key of another existing vertex, and ErrDuplicateValue if a unique index rejects the copies of the value.
+// Returns the sorted keys of the vertices created.
+func (g *Graph) SplitVertex(key string, partition func(e Edge) string) (created []string, err error) {
+	defer g.track("SplitVertex")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return nil, ErrInvalidKey
+	}
+
+	type move struct {
+		neighbor *Vertex
+		outgoing bool
+		weight   int
+		to       string
+	}
+
+	var moves []move
+	targets := map[string]bool{}
+
+	for neighbor, weight := range v.GetOutgoing() {
+		moves = append(moves, move{neighbor, true, weight, partition(Edge{From: key, To: neighbor.key, Weight: weight})})
+	}
+	for neighbor, weight := range v.GetIncoming() {
+		// a self-loop is moved once, as an outgoing edge
+		if neighbor == v {
+			continue
+		}
+		moves = append(moves, move{neighbor, false, weight, partition(Edge{From: neighbor.key, To: key, Weight: weight})})
+	}
+
+	// validate everything before changing anything
+	unique := g.unique.copy()
+	for _, m := range moves {
+		if m.to == key || targets[m.to] {
+			continue
+		}
+		if g.get(m.to) != nil {
+			return nil, ErrKeyExists
+		}
+		if !unique.set(m.to, v.Value()) {
+			return nil, ErrDuplicateValue
+		}
+
+		targets[m.to] = true
+		created = append(created, m.to)
+	}
+	sort.Strings(created)
+
+	for _, newKey := range created {
+		w := newVertex(newKey, v.Value())
+		g.vertices[newKey] = w
+		g.keys.insert(newKey)
+		g.unique.set(newKey, w.value)
+
+		g.tags.addAll(w, v)
+		g.labels.addAll(w, v)
+
+		g.emit(Event{Type: EventSet, Key: newKey, Value: w.value})
+	}
+
+	for _, m := range moves {
+		if m.to == key {
+			continue
+		}
+
+		w := g.vertices[m.to]
+		if m.neighbor == v {
+			g.moveEdge(v, v, w, w, m.weight)
+		} else if m.outgoing {
+			g.moveEdge(v, m.neighbor, w, m.neighbor, m.weight)
+		} else {
+			g.moveEdge(m.neighbor, v, m.neighbor, w, m.weight)
+		}
+	}
+
+	return created, nil
+}
+
+// moveEdge is an internal function, does NOT lock the graph, should only be used in between Lock() and Unlock().
+// It replaces the edge from fromV to toV by an edge from newFromV to newToV with the same attributes and the specified weight.
+func (g *Graph) moveEdge(fromV, toV, newFromV, newToV *Vertex, weight int) {
+	fromV.RLock()
+	attrs := fromV.getEdgeAttrs(toV)
+	fromV.RUnlock()
+
+	g.disconnect(fromV, toV)
+	g.connect(newFromV, newToV, weight)
+	g.setEdgeAttrs(newFromV, newToV, attrs)
+}
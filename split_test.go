@@ -0,0 +1,93 @@
+package graph
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestSplitVertex(t *testing.T) {
+	g := New()
+	for _, key := range []string{"java", "coffee:beans", "coffee:shop", "code:jvm", "code:spring"} {
+		g.Set(key, "java")
+	}
+
+	g.Connect("java", "coffee:beans", 1)
+	g.Connect("coffee:shop", "java", 2)
+	g.Connect("java", "code:jvm", 3)
+	g.Connect("code:spring", "java", 4)
+	g.Tag("java", "ambiguous")
+
+	// move the coffee edges to a new vertex, keep the code edges
+	created, err := g.SplitVertex("java", func(e Edge) string {
+		if strings.HasPrefix(e.From, "coffee:") || strings.HasPrefix(e.To, "coffee:") {
+			return "java (island)"
+		}
+		return "java"
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(created, []string{"java (island)"}) {
+		t.Fatalf("unexpected vertices %v", created)
+	}
+
+	expected := map[[2]string]int{
+		{"java (island)", "coffee:beans"}: 1,
+		{"coffee:shop", "java (island)"}:  2,
+		{"java", "code:jvm"}:              3,
+		{"code:spring", "java"}:           4,
+	}
+	if g.Stats().Edges != len(expected) {
+		t.Errorf("expected %d edges, got %d", len(expected), g.Stats().Edges)
+	}
+	for edge, weight := range expected {
+		if ok, w := g.IsConnected(edge[0], edge[1]); !ok || w != weight {
+			t.Errorf("expected edge %v with weight %d", edge, weight)
+		}
+	}
+
+	if v, _ := g.Get("java (island)"); v.Value() != "java" || !g.HasTag("java (island)", "ambiguous") {
+		t.Fail()
+	}
+
+	// splitting onto an existing vertex fails without changes
+	if _, err = g.SplitVertex("java", func(e Edge) string { return "code:jvm" }); err != ErrKeyExists {
+		t.Fail()
+	}
+	if ok, _ := g.IsConnected("java", "code:jvm"); !ok {
+		t.Fail()
+	}
+
+	if _, err = g.SplitVertex("missing", func(e Edge) string { return "" }); err != ErrInvalidKey {
+		t.Fail()
+	}
+}
+
+func TestSplitVertexEdgeAttrs(t *testing.T) {
+	g := New()
+	for _, key := range []string{"java", "coffee:beans", "coffee:shop"} {
+		g.Set(key, key)
+	}
+	g.Connect("java", "coffee:beans", 1)
+	g.Connect("coffee:shop", "java", 2)
+	g.SetEdgeAttr("java", "coffee:beans", "label", "grown")
+	g.SetEdgeAttr("coffee:shop", "java", "label", "sold")
+
+	r := NewReplica(g)
+	defer r.Close()
+
+	if _, err := g.SplitVertex("java", func(e Edge) string { return "java (island)" }); err != nil {
+		t.Fatal(err)
+	}
+	r.Sync()
+
+	for _, h := range []*Graph{g, r.Graph()} {
+		if e, err := h.GetEdge("java (island)", "coffee:beans"); err != nil || e.Attrs["label"] != "grown" {
+			t.Errorf("expected the outgoing edge's attributes to move, got %v, %v", e, err)
+		}
+		if e, err := h.GetEdge("coffee:shop", "java (island)"); err != nil || e.Attrs["label"] != "sold" {
+			t.Errorf("expected the incoming edge's attributes to move, got %v, %v", e, err)
+		}
+	}
+}
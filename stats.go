@@ -0,0 +1,59 @@
+package graph
+
+// Statistics summarizes the size and degree distribution of a graph.
+type Statistics struct {
+	Vertices         int         // number of vertices
+	Edges            int         // number of directed edges
+	Density          float64     // Edges divided by the maximum possible number of edges, which includes self-loops only if they are enabled; 0 if no edges are possible
+	AverageInDegree  float64     // average number of incoming edges per vertex
+	AverageOutDegree float64     // average number of outgoing edges per vertex
+	MaxInDegree      int         // largest number of incoming edges of a vertex
+	MaxOutDegree     int         // largest number of outgoing edges of a vertex
+	MaxDegree        int         // largest number of incoming plus outgoing edges of a vertex
+	DegreeHistogram  map[int]int // maps degrees (incoming plus outgoing edges) to the number of vertices with that degree
+}
+
+// Stats computes the number of vertices and edges, the density and the degree distribution of the graph.
+func (g *Graph) Stats() Statistics {
+	defer g.track("Stats")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	s := Statistics{
+		Vertices:        len(g.vertices),
+		DegreeHistogram: map[int]int{},
+	}
+
+	for _, v := range g.vertices {
+		v.RLock()
+		in, out := len(v.incomingEdges), len(v.outgoingEdges)
+		v.RUnlock()
+
+		s.Edges += out
+		s.DegreeHistogram[in+out]++
+
+		if in > s.MaxInDegree {
+			s.MaxInDegree = in
+		}
+		if out > s.MaxOutDegree {
+			s.MaxOutDegree = out
+		}
+		if in+out > s.MaxDegree {
+			s.MaxDegree = in + out
+		}
+	}
+
+	if s.Vertices > 0 {
+		// every edge is incoming at one vertex and outgoing at another
+		s.AverageInDegree = float64(s.Edges) / float64(s.Vertices)
+		s.AverageOutDegree = s.AverageInDegree
+	}
+	if g.selfLoops && s.Vertices > 0 {
+		s.Density = float64(s.Edges) / float64(s.Vertices*s.Vertices)
+	} else if s.Vertices > 1 {
+		s.Density = float64(s.Edges) / float64(s.Vertices*(s.Vertices-1))
+	}
+
+	return s
+}
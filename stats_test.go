@@ -0,0 +1,43 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestStats(t *testing.T) {
+	g := New()
+
+	if s := g.Stats(); s.Vertices != 0 || s.Edges != 0 || s.Density != 0 || s.AverageInDegree != 0 {
+		t.Fail()
+	}
+
+	g.Set("a", nil)
+	g.Set("b", nil)
+	g.Set("c", nil)
+	g.Set("d", nil)
+
+	g.Connect("a", "b", 1)
+	g.Connect("a", "c", 1)
+	g.Connect("a", "d", 1)
+	g.Connect("b", "a", 1)
+
+	s := g.Stats()
+
+	if s.Vertices != 4 || s.Edges != 4 {
+		t.Fatalf("expected 4 vertices and 4 edges, got %d and %d", s.Vertices, s.Edges)
+	}
+	if s.Density != 4.0/12 {
+		t.Fail()
+	}
+	if s.AverageInDegree != 1 || s.AverageOutDegree != 1 {
+		t.Fail()
+	}
+	if s.MaxInDegree != 1 || s.MaxOutDegree != 3 || s.MaxDegree != 4 {
+		t.Fail()
+	}
+
+	// a: 4, b: 2, c: 1, d: 1
+	if len(s.DegreeHistogram) != 3 || s.DegreeHistogram[4] != 1 || s.DegreeHistogram[2] != 1 || s.DegreeHistogram[1] != 2 {
+		t.Fail()
+	}
+}
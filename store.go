@@ -0,0 +1,544 @@
+package graph
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// ErrEdgeAttrsUnsupported is reported by StoreErr when an edge attribute is set on a graph whose store doesn't implement EdgeAttrStore.
+var ErrEdgeAttrsUnsupported = errors.New("graph: store doesn't support edge attributes")
+
+// Store is a storage backend for the vertices and edges of a graph, e.g. a database on disk or a remote service. Graphs created by NewWithStore load their contents from a store and write every mutation through to it, so the store mirrors the graph.
+// The graph still keeps all vertices and edges in memory and algorithms never read from the store, so a store persists a graph, but doesn't reduce its memory use.
+// Implementations must be safe for concurrent use, since mutations may be written through concurrently.
+type Store interface {
+	// Get returns the value of the vertex with the specified key, and false if there is no such vertex.
+	Get(key string) (value interface{}, ok bool, err error)
+
+	// Set creates or updates the vertex with the specified key.
+	Set(key string, value interface{}) error
+
+	// Delete deletes the vertex with the specified key and all its edges. Deleting a vertex which doesn't exist is not an error.
+	Delete(key string) error
+
+	// Connect creates or updates the edge between two existing vertices.
+	Connect(fromKey, toKey string, weight int) error
+
+	// Disconnect deletes the edge between two vertices. Deleting an edge which doesn't exist is not an error.
+	Disconnect(fromKey, toKey string) error
+
+	// IterVertices calls fn for every vertex, stopping at the first error, which it returns.
+	IterVertices(fn func(key string, value interface{}) error) error
+
+	// IterEdges calls fn for every edge, stopping at the first error, which it returns.
+	IterEdges(fn func(fromKey, toKey string, weight int) error) error
+}
+
+// EdgeAttrStore is implemented by stores which can persist the attributes of edges, see SetEdgeAttr. Deleting or disconnecting an edge must delete its attributes.
+type EdgeAttrStore interface {
+	Store
+
+	// SetEdgeAttr creates or updates an attribute of an existing edge.
+	SetEdgeAttr(fromKey, toKey, name string, value interface{}) error
+
+	// DeleteEdgeAttr deletes an attribute of an edge. Deleting an attribute which doesn't exist is not an error.
+	DeleteEdgeAttr(fromKey, toKey, name string) error
+
+	// IterEdgeAttrs calls fn for every attribute of every edge, stopping at the first error, which it returns.
+	IterEdgeAttrs(fn func(fromKey, toKey, name string, value interface{}) error) error
+}
+
+// storeBinding connects a graph to the store its mutations are written through to.
+type storeBinding struct {
+	store         Store
+	err           error                  // first error returned by the store
+	deferred      bool                   // true if mutations are only tracked, see StoreDeferred
+	dirtyVertices map[string]bool        // keys of the vertices changed since the last flush, true if they were deleted meanwhile
+	dirtyEdges    map[[2]string]struct{} // start and end keys of the edges changed since the last flush
+	dirtyAttrs    map[[3]string]struct{} // start and end keys of the edges and names of the attributes changed since the last flush
+	sync.Mutex
+}
+
+// StoreOption configures NewWithStore.
+type StoreOption func(*storeBinding)
+
+// StoreDeferred makes the graph track which vertices and edges changed instead of writing every mutation through to the store, so FlushDirty can write only the latest state of each of them, e.g. periodically. Mutations since the last flush are lost if the process stops without flushing.
+func StoreDeferred() StoreOption {
+	return func(b *storeBinding) {
+		b.deferred = true
+	}
+}
+
+// NewWithStore initializes a graph with the vertices and edges in s, and writes all further mutations through to s, so the graph can be persisted by any backend while algorithms work on the in-memory graph as usual. If s contains self-loops, they are enabled on the graph, see EnableSelfLoops.
+// Edge attributes are loaded and written through if s implements EdgeAttrStore; otherwise setting one is reported as ErrEdgeAttrsUnsupported by StoreErr.
+// The graph is a write-through mirror, not a view of s: all vertices and edges are loaded into memory, see Store. Returns an *ImportError listing the edges of s which couldn't be connected, e.g. because an endpoint is missing, and the error of s if it fails to iterate.
+// Mutations are applied to the graph before they are written to the store, and are kept even if the store fails; the first error is reported by StoreErr, and the store should be considered out of sync from then on.
+func NewWithStore(s Store, opts ...StoreOption) (*Graph, error) {
+	g := New()
+
+	err := s.IterVertices(func(key string, value interface{}) error {
+		g.Set(key, value)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var dangling []DanglingReference
+
+	err = s.IterEdges(func(fromKey, toKey string, weight int) error {
+		if fromKey == toKey {
+			g.selfLoops = true
+		}
+
+		if !g.Connect(fromKey, toKey, weight) {
+			var missing []string
+			for _, key := range []string{fromKey, toKey} {
+				if _, err := g.Get(key); err != nil {
+					missing = append(missing, key)
+				}
+			}
+			dangling = append(dangling, DanglingReference{"store", fromKey, toKey, missing})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if as, ok := s.(EdgeAttrStore); ok {
+		err = as.IterEdgeAttrs(func(fromKey, toKey, name string, value interface{}) error {
+			if err := g.SetEdgeAttr(fromKey, toKey, name, value); err != nil {
+				dangling = append(dangling, DanglingReference{"store", fromKey, toKey, nil})
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if len(dangling) > 0 {
+		return nil, &ImportError{dangling}
+	}
+
+	b := &storeBinding{store: s, dirtyVertices: map[string]bool{}, dirtyEdges: map[[2]string]struct{}{}, dirtyAttrs: map[[3]string]struct{}{}}
+	for _, opt := range opts {
+		opt(b)
+	}
+	g.store = b
+	g.subscribe(b.apply)
+
+	return g, nil
+}
+
+// StoreErr returns the first error returned by the store when writing a mutation through to it, see NewWithStore. Returns nil for graphs without a store.
+func (g *Graph) StoreErr() error {
+	defer g.track("StoreErr")()
+
+	if g.store == nil {
+		return nil
+	}
+
+	g.store.Lock()
+	defer g.store.Unlock()
+
+	return g.store.err
+}
+
+// apply writes the mutation described by e through to the store, keeping the first error, or marks the vertex or edge as dirty if writes are deferred.
+func (b *storeBinding) apply(e Event) {
+	if b.deferred {
+		b.Lock()
+		b.markDirty(e)
+		b.Unlock()
+		return
+	}
+
+	var err error
+
+	switch e.Type {
+	case EventSet:
+		err = b.store.Set(e.Key, e.Value)
+	case EventDelete:
+		err = b.store.Delete(e.Key)
+	case EventConnect:
+		err = b.store.Connect(e.Key, e.ToKey, e.Weight)
+	case EventDisconnect:
+		err = b.store.Disconnect(e.Key, e.ToKey)
+	case EventSetEdgeAttr, EventDeleteEdgeAttr:
+		as, ok := b.store.(EdgeAttrStore)
+		switch {
+		case !ok:
+			err = ErrEdgeAttrsUnsupported
+		case e.Type == EventSetEdgeAttr:
+			err = as.SetEdgeAttr(e.Key, e.ToKey, e.Attr, e.Value)
+		default:
+			err = as.DeleteEdgeAttr(e.Key, e.ToKey, e.Attr)
+		}
+	}
+
+	if err != nil {
+		b.Lock()
+		if b.err == nil {
+			b.err = err
+		}
+		b.Unlock()
+	}
+}
+
+// markDirty marks the vertex or edge changed by e. Does NOT lock the binding, should only be used in between Lock() and Unlock().
+func (b *storeBinding) markDirty(e Event) {
+	switch e.Type {
+	case EventSet:
+		if _, ok := b.dirtyVertices[e.Key]; !ok {
+			b.dirtyVertices[e.Key] = false
+		}
+	case EventDelete:
+		b.dirtyVertices[e.Key] = true
+	case EventConnect, EventDisconnect:
+		b.dirtyEdges[[2]string{e.Key, e.ToKey}] = struct{}{}
+	case EventSetEdgeAttr, EventDeleteEdgeAttr:
+		b.dirtyAttrs[[3]string{e.Key, e.ToKey, e.Attr}] = struct{}{}
+	}
+}
+
+// DirtyLen returns the number of vertices and edges changed since the last flush, see StoreDeferred. Returns 0 for graphs without a store.
+func (g *Graph) DirtyLen() int {
+	defer g.track("DirtyLen")()
+
+	if g.store == nil {
+		return 0
+	}
+
+	g.store.Lock()
+	defer g.store.Unlock()
+
+	return len(g.store.dirtyVertices) + len(g.store.dirtyEdges) + len(g.store.dirtyAttrs)
+}
+
+// FlushDirty writes the current state of the vertices and edges changed since the last flush to the store, see StoreDeferred: deleted vertices are deleted with all their edges, then existing vertices are set, edges connected or disconnected and edge attributes set or deleted, so a vertex changed many times is written once.
+// If the store fails, the changes stay dirty, so they are written by the next flush. Returns nil for graphs without a store.
+func (g *Graph) FlushDirty() error {
+	defer g.track("FlushDirty")()
+
+	if g.store == nil {
+		return nil
+	}
+
+	b := g.store
+
+	// take the dirty sets, so mutations while flushing are tracked for the next flush
+	b.Lock()
+	vertices, edges, attrs := b.dirtyVertices, b.dirtyEdges, b.dirtyAttrs
+	b.dirtyVertices, b.dirtyEdges, b.dirtyAttrs = map[string]bool{}, map[[2]string]struct{}{}, map[[3]string]struct{}{}
+	b.Unlock()
+
+	g.RLock()
+	err := g.flushDirty(vertices, edges, attrs)
+	g.RUnlock()
+
+	if err != nil {
+		// keep everything dirty, writing the latest state again is harmless
+		b.Lock()
+		for key, deleted := range vertices {
+			b.dirtyVertices[key] = b.dirtyVertices[key] || deleted
+		}
+		for edge := range edges {
+			b.dirtyEdges[edge] = struct{}{}
+		}
+		for attr := range attrs {
+			b.dirtyAttrs[attr] = struct{}{}
+		}
+		b.Unlock()
+	}
+
+	return err
+}
+
+// flushDirty is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It writes the current state of the given vertices, edges and edge attributes to the store.
+func (g *Graph) flushDirty(vertices map[string]bool, edges map[[2]string]struct{}, attrs map[[3]string]struct{}) error {
+	s := g.store.store
+
+	keys := make([]string, 0, len(vertices))
+	for key := range vertices {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		v := g.get(key)
+
+		if vertices[key] || v == nil {
+			if err := s.Delete(key); err != nil {
+				return err
+			}
+		}
+		if v != nil {
+			if err := s.Set(key, v.Value()); err != nil {
+				return err
+			}
+		}
+	}
+
+	pairs := make([][2]string, 0, len(edges))
+	for edge := range edges {
+		pairs = append(pairs, edge)
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1]
+	})
+
+	for _, edge := range pairs {
+		var weight int
+		ok := false
+		if from, to := g.get(edge[0]), g.get(edge[1]); from != nil && to != nil {
+			from.RLock()
+			weight, ok = from.outgoingEdges[to]
+			from.RUnlock()
+		}
+
+		var err error
+		if ok {
+			err = s.Connect(edge[0], edge[1], weight)
+		} else {
+			err = s.Disconnect(edge[0], edge[1])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(attrs) == 0 {
+		return nil
+	}
+	as, ok := s.(EdgeAttrStore)
+	if !ok {
+		return ErrEdgeAttrsUnsupported
+	}
+
+	names := make([][3]string, 0, len(attrs))
+	for attr := range attrs {
+		names = append(names, attr)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		for k := range names[i] {
+			if names[i][k] != names[j][k] {
+				return names[i][k] < names[j][k]
+			}
+		}
+		return false
+	})
+
+	for _, attr := range names {
+		var value interface{}
+		ok := false
+		if from, to := g.get(attr[0]), g.get(attr[1]); from != nil && to != nil {
+			from.RLock()
+			value, ok = from.edgeAttrs[to][attr[2]]
+			from.RUnlock()
+		}
+
+		// attributes of removed edges were deleted with them
+		var err error
+		if ok {
+			err = as.SetEdgeAttr(attr[0], attr[1], attr[2], value)
+		} else {
+			err = as.DeleteEdgeAttr(attr[0], attr[1], attr[2])
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// MemoryStore is an EdgeAttrStore keeping vertices, edges and edge attributes in maps, e.g. for tests, or as a template for other backends.
+type MemoryStore struct {
+	values   map[string]interface{}
+	outgoing map[string]map[string]int            // maps keys to the weights of the outgoing edges by neighbor key
+	attrs    map[[2]string]map[string]interface{} // maps the keys of edges to their attributes by name
+	sync.RWMutex
+}
+
+// NewMemoryStore initializes an empty MemoryStore.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{values: map[string]interface{}{}, outgoing: map[string]map[string]int{}, attrs: map[[2]string]map[string]interface{}{}}
+}
+
+// Get returns the value of the vertex with the specified key, and false if there is no such vertex.
+func (s *MemoryStore) Get(key string) (interface{}, bool, error) {
+	s.RLock()
+	defer s.RUnlock()
+
+	value, ok := s.values[key]
+	return value, ok, nil
+}
+
+// Set creates or updates the vertex with the specified key.
+func (s *MemoryStore) Set(key string, value interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+
+	s.values[key] = value
+	return nil
+}
+
+// Delete deletes the vertex with the specified key and all its edges.
+func (s *MemoryStore) Delete(key string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.values, key)
+	delete(s.outgoing, key)
+	for _, neighbors := range s.outgoing {
+		delete(neighbors, key)
+	}
+	for edge := range s.attrs {
+		if edge[0] == key || edge[1] == key {
+			delete(s.attrs, edge)
+		}
+	}
+	return nil
+}
+
+// Connect creates or updates an edge. Returns ErrInvalidKey if one of the vertices doesn't exist.
+func (s *MemoryStore) Connect(fromKey, toKey string, weight int) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.values[fromKey]; !ok {
+		return ErrInvalidKey
+	}
+	if _, ok := s.values[toKey]; !ok {
+		return ErrInvalidKey
+	}
+
+	if s.outgoing[fromKey] == nil {
+		s.outgoing[fromKey] = map[string]int{}
+	}
+	s.outgoing[fromKey][toKey] = weight
+	return nil
+}
+
+// Disconnect deletes an edge and its attributes.
+func (s *MemoryStore) Disconnect(fromKey, toKey string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	delete(s.outgoing[fromKey], toKey)
+	delete(s.attrs, [2]string{fromKey, toKey})
+	return nil
+}
+
+// SetEdgeAttr creates or updates an attribute of an edge. Returns ErrNoEdge if the edge doesn't exist.
+func (s *MemoryStore) SetEdgeAttr(fromKey, toKey, name string, value interface{}) error {
+	s.Lock()
+	defer s.Unlock()
+
+	if _, ok := s.outgoing[fromKey][toKey]; !ok {
+		return ErrNoEdge
+	}
+
+	edge := [2]string{fromKey, toKey}
+	if s.attrs[edge] == nil {
+		s.attrs[edge] = map[string]interface{}{}
+	}
+	s.attrs[edge][name] = value
+	return nil
+}
+
+// DeleteEdgeAttr deletes an attribute of an edge.
+func (s *MemoryStore) DeleteEdgeAttr(fromKey, toKey, name string) error {
+	s.Lock()
+	defer s.Unlock()
+
+	edge := [2]string{fromKey, toKey}
+	delete(s.attrs[edge], name)
+	if len(s.attrs[edge]) == 0 {
+		delete(s.attrs, edge)
+	}
+	return nil
+}
+
+// IterVertices calls fn for every vertex in key order, stopping at the first error, which it returns. fn must not modify the store.
+func (s *MemoryStore) IterVertices(fn func(key string, value interface{}) error) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	keys := make([]string, 0, len(s.values))
+	for key := range s.values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		if err := fn(key, s.values[key]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// IterEdges calls fn for every edge sorted by keys, stopping at the first error, which it returns. fn must not modify the store.
+func (s *MemoryStore) IterEdges(fn func(fromKey, toKey string, weight int) error) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	froms := make([]string, 0, len(s.outgoing))
+	for key := range s.outgoing {
+		froms = append(froms, key)
+	}
+	sort.Strings(froms)
+
+	for _, from := range froms {
+		neighbors := s.outgoing[from]
+
+		tos := make([]string, 0, len(neighbors))
+		for key := range neighbors {
+			tos = append(tos, key)
+		}
+		sort.Strings(tos)
+
+		for _, to := range tos {
+			if err := fn(from, to, neighbors[to]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// IterEdgeAttrs calls fn for every attribute of every edge sorted by keys and names, stopping at the first error, which it returns. fn must not modify the store.
+func (s *MemoryStore) IterEdgeAttrs(fn func(fromKey, toKey, name string, value interface{}) error) error {
+	s.RLock()
+	defer s.RUnlock()
+
+	edges := make([][2]string, 0, len(s.attrs))
+	for edge := range s.attrs {
+		edges = append(edges, edge)
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		return edges[i][0] < edges[j][0] || edges[i][0] == edges[j][0] && edges[i][1] < edges[j][1]
+	})
+
+	for _, edge := range edges {
+		attrs := s.attrs[edge]
+
+		names := make([]string, 0, len(attrs))
+		for name := range attrs {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			if err := fn(edge[0], edge[1], name, attrs[name]); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,245 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+// storeEdges returns all edges of s.
+func storeEdges(t *testing.T, s Store) []Edge {
+	var edges []Edge
+	err := s.IterEdges(func(fromKey, toKey string, weight int) error {
+		edges = append(edges, Edge{From: fromKey, To: toKey, Weight: weight})
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	return edges
+}
+
+func TestMemoryStore(t *testing.T) {
+	s := NewMemoryStore()
+	s.Set("b", 2)
+	s.Set("a", 1)
+	s.Set("c", nil)
+
+	if err := s.Connect("a", "b", 5); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	s.Connect("c", "a", 1)
+	s.Connect("b", "c", 2)
+	if err := s.Connect("a", "x", 1); err != ErrInvalidKey {
+		t.Errorf("expected ErrInvalidKey, got %v", err)
+	}
+
+	if value, ok, err := s.Get("b"); value != 2 || !ok || err != nil {
+		t.Errorf("unexpected result %v %v %v", value, ok, err)
+	}
+	if _, ok, _ := s.Get("x"); ok {
+		t.Error("expected no vertex")
+	}
+
+	var keys []string
+	s.IterVertices(func(key string, value interface{}) error {
+		keys = append(keys, key)
+		return nil
+	})
+	if !reflect.DeepEqual(keys, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected keys %v", keys)
+	}
+
+	s.Disconnect("b", "c")
+	s.Delete("a")
+	if edges := storeEdges(t, s); len(edges) != 0 {
+		t.Errorf("expected no edges, got %v", edges)
+	}
+
+	stop := errors.New("stop")
+	if err := s.IterVertices(func(string, interface{}) error { return stop }); err != stop {
+		t.Errorf("expected error to be passed through, got %v", err)
+	}
+}
+
+func TestNewWithStore(t *testing.T) {
+	s := NewMemoryStore()
+	s.Set("a", 1)
+	s.Set("b", 2)
+	s.Connect("a", "b", 5)
+
+	g, err := NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := g.IsConnected("a", "b"); !ok || weight != 5 {
+		t.Errorf("expected loaded edge, got %v %d", ok, weight)
+	}
+
+	// mutations are written through
+	g.Set("c", 3)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 2)
+	g.Disconnect("a", "b")
+	g.Set("a", 10)
+	g.Delete("b")
+
+	if value, ok, _ := s.Get("a"); !ok || value != 10 {
+		t.Errorf("expected updated value, got %v", value)
+	}
+	if _, ok, _ := s.Get("b"); ok {
+		t.Error("expected deleted vertex")
+	}
+	if edges := storeEdges(t, s); !reflect.DeepEqual(edges, []Edge{{From: "c", To: "a", Weight: 2}}) {
+		t.Errorf("unexpected edges %v", edges)
+	}
+
+	// a new graph sees the same contents
+	h, err := NewWithStore(s)
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if h.Len() != 2 {
+		t.Errorf("expected 2 vertices, got %d", h.Len())
+	}
+	if g.StoreErr() != nil || New().StoreErr() != nil {
+		t.Error("expected no store errors")
+	}
+}
+
+// plainStore hides the optional interfaces of a MemoryStore, e.g. EdgeAttrStore.
+type plainStore struct {
+	Store
+}
+
+// failingStore fails all writes.
+type failingStore struct {
+	*MemoryStore
+}
+
+func (failingStore) Set(string, interface{}) error {
+	return errors.New("disk full")
+}
+
+func TestNewWithStoreErrors(t *testing.T) {
+	g, err := NewWithStore(failingStore{NewMemoryStore()})
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	// the graph is still updated
+	if g.Set("a", 1); g.Len() != 1 {
+		t.Error("expected the vertex to be set")
+	}
+	g.Set("b", 1)
+	if err := g.StoreErr(); err == nil || err.Error() != "disk full" {
+		t.Errorf("expected store error, got %v", err)
+	}
+
+	broken := NewMemoryStore()
+	broken.Set("a", nil)
+	if _, err := NewWithStore(iterFailingStore{broken}); err == nil {
+		t.Error("expected an error when loading fails")
+	}
+
+	_, err = NewWithStore(danglingStore{broken})
+	if importErr, ok := err.(*ImportError); !ok || len(importErr.References) != 1 || importErr.References[0].Missing[0] != "missing" {
+		t.Errorf("expected the dangling edge to be reported, got %v", err)
+	}
+}
+
+// danglingStore has an edge to a vertex which doesn't exist.
+type danglingStore struct {
+	*MemoryStore
+}
+
+func (danglingStore) IterEdges(fn func(string, string, int) error) error {
+	return fn("a", "missing", 1)
+}
+
+// iterFailingStore fails iterating edges.
+type iterFailingStore struct {
+	*MemoryStore
+}
+
+func (iterFailingStore) IterEdges(func(string, string, int) error) error {
+	return errors.New("corrupt")
+}
+
+// countingStore counts the writes to a MemoryStore.
+type countingStore struct {
+	*MemoryStore
+	writes int
+}
+
+func (s *countingStore) Set(key string, value interface{}) error {
+	s.writes++
+	return s.MemoryStore.Set(key, value)
+}
+
+func (s *countingStore) Connect(fromKey, toKey string, weight int) error {
+	s.writes++
+	return s.MemoryStore.Connect(fromKey, toKey, weight)
+}
+
+func TestFlushDirty(t *testing.T) {
+	s := &countingStore{MemoryStore: NewMemoryStore()}
+	s.MemoryStore.Set("x", 0)
+	s.MemoryStore.Set("y", 0)
+	s.MemoryStore.Connect("x", "y", 1)
+
+	g, err := NewWithStore(s, StoreDeferred())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+
+	for i := 0; i < 10; i++ {
+		g.Set("a", i)
+	}
+	g.Set("b", nil)
+	g.Connect("a", "b", 1)
+	g.Connect("a", "b", 2)
+	g.Connect("x", "a", 3)
+	g.Disconnect("x", "a")
+
+	// x is deleted and recreated, so its old edge must be removed from the store
+	g.Delete("x")
+	g.Set("x", 1)
+
+	if s.writes != 0 {
+		t.Errorf("expected no writes before flushing, got %d", s.writes)
+	}
+	if n := g.DirtyLen(); n != 5 {
+		t.Errorf("expected 3 dirty vertices and 2 dirty edges, got %d", n)
+	}
+
+	if err := g.FlushDirty(); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if s.writes != 4 {
+		t.Errorf("expected 3 vertices and 1 edge to be written, got %d writes", s.writes)
+	}
+	if g.DirtyLen() != 0 {
+		t.Errorf("expected nothing to be dirty after flushing")
+	}
+
+	if value, _, _ := s.Get("a"); value != 9 {
+		t.Errorf("expected the last value, got %v", value)
+	}
+	if edges := storeEdges(t, s); !reflect.DeepEqual(edges, []Edge{{From: "a", To: "b", Weight: 2}}) {
+		t.Errorf("unexpected edges %v", edges)
+	}
+
+	// failed flushes keep the changes dirty
+	f, err := NewWithStore(failingStore{NewMemoryStore()}, StoreDeferred())
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	f.Set("c", 1)
+	if err := f.FlushDirty(); err == nil || f.DirtyLen() != 1 {
+		t.Errorf("expected an error and a dirty vertex, got %v %d", err, f.DirtyLen())
+	}
+	if New().FlushDirty() != nil || New().DirtyLen() != 0 {
+		t.Error("expected graphs without a store to have nothing to flush")
+	}
+}
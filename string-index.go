@@ -0,0 +1,81 @@
+package graph
+
+import (
+	"sort"
+)
+
+// stringIndex maps vertices to a set of strings and back, e.g. their tags or labels, so vertices can be looked up by string in time proportional to their number. The zero value is an empty index. Like the graph's other indexes, it has no lock of its own; it must only be modified while the graph is locked for writing.
+type stringIndex struct {
+	vertices map[string]map[*Vertex]struct{} // maps strings to the vertices having them
+	strings  map[*Vertex]map[string]struct{} // maps vertices to their strings
+}
+
+// add adds s to the strings of v.
+func (x *stringIndex) add(v *Vertex, s string) {
+	if x.vertices == nil {
+		x.vertices = map[string]map[*Vertex]struct{}{}
+		x.strings = map[*Vertex]map[string]struct{}{}
+	}
+
+	if x.vertices[s] == nil {
+		x.vertices[s] = map[*Vertex]struct{}{}
+	}
+	x.vertices[s][v] = struct{}{}
+
+	if x.strings[v] == nil {
+		x.strings[v] = map[string]struct{}{}
+	}
+	x.strings[v][s] = struct{}{}
+}
+
+// remove removes s from the strings of v.
+func (x *stringIndex) remove(v *Vertex, s string) {
+	delete(x.vertices[s], v)
+	if len(x.vertices[s]) == 0 {
+		delete(x.vertices, s)
+	}
+
+	delete(x.strings[v], s)
+	if len(x.strings[v]) == 0 {
+		delete(x.strings, v)
+	}
+}
+
+// removeAll removes all strings of v, e.g. when v is deleted.
+func (x *stringIndex) removeAll(v *Vertex) {
+	for s := range x.strings[v] {
+		x.remove(v, s)
+	}
+}
+
+// addAll adds the strings of from to the strings of to, e.g. when from is merged into to.
+func (x *stringIndex) addAll(to, from *Vertex) {
+	for s := range x.strings[from] {
+		x.add(to, s)
+	}
+}
+
+// has returns true if v has the string s.
+func (x *stringIndex) has(v *Vertex, s string) bool {
+	_, ok := x.strings[v][s]
+	return ok
+}
+
+// sorted returns the sorted strings of v, nil if it has none.
+func (x *stringIndex) sorted(v *Vertex) (strings []string) {
+	for s := range x.strings[v] {
+		strings = append(strings, s)
+	}
+	sort.Strings(strings)
+
+	return
+}
+
+// lookup returns the vertices having the string s, nil if there are none.
+func (x *stringIndex) lookup(s string) (vertices []*Vertex) {
+	for v := range x.vertices[s] {
+		vertices = append(vertices, v)
+	}
+
+	return
+}
@@ -0,0 +1,294 @@
+package graph
+
+import (
+	"sort"
+)
+
+// FindSubgraphs finds all occurrences of pattern in the graph using the VF2 algorithm for subgraph isomorphism, e.g. to detect small structural motifs.
+// An occurrence is an induced subgraph: a set of vertices with exactly the edges between them that the pattern has, in the same directions. Values and weights are ignored.
+// Each occurrence is passed to fn as a mapping from pattern keys to keys of the graph as soon as it is found. Symmetric patterns match the same vertices several times, once per automorphism. The enumeration stops when fn returns false.
+func (g *Graph) FindSubgraphs(pattern *Graph, fn func(mapping map[string]string) bool) {
+	defer g.track("FindSubgraphs")()
+
+	// copy the pattern first, so the two graphs are never locked at the same time
+	pattern.RLock()
+	copied := pattern.clone()
+	pattern.RUnlock()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	m := newSubgraphMatcher(copied, g)
+	m.induced = true
+	m.fn = func(core map[*Vertex]*Vertex) bool {
+		mapping := make(map[string]string, len(core))
+		for p, h := range core {
+			mapping[p.key] = h.key
+		}
+		return fn(mapping)
+	}
+
+	m.match(0)
+}
+
+// subgraphMatcher holds the state of the VF2 search for occurrences of a pattern graph in a host graph.
+type subgraphMatcher struct {
+	pattern []*Vertex           // pattern vertices in the order they are matched
+	hosts   []*Vertex           // host vertices sorted by key
+	core    map[*Vertex]*Vertex // maps matched pattern vertices to host vertices
+	reverse map[*Vertex]*Vertex // maps matched host vertices to pattern vertices
+
+	induced     bool                    // whether host vertices must not have edges the pattern lacks
+	vertexMatch func(p, h *Vertex) bool // whether pattern vertex p may be mapped to host vertex h; nil if any may
+	edgeMatch   func(p, h int) bool     // whether a pattern edge with weight p may be mapped to a host edge with weight h; nil if any may
+
+	fn func(core map[*Vertex]*Vertex) bool // called with every complete mapping, stops the search when returning false
+}
+
+// newSubgraphMatcher is an internal function, does NOT lock the graphs, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()) of both.
+// It orders the pattern vertices so that each one is connected to as many of its predecessors as possible, which lets infeasible mappings fail early.
+func newSubgraphMatcher(pattern, host *Graph) *subgraphMatcher {
+	m := &subgraphMatcher{
+		core:    make(map[*Vertex]*Vertex, len(pattern.vertices)),
+		reverse: make(map[*Vertex]*Vertex, len(pattern.vertices)),
+	}
+
+	for _, key := range host.sortedKeys() {
+		m.hosts = append(m.hosts, host.vertices[key])
+	}
+
+	neighbors := make(map[*Vertex]map[*Vertex]struct{}, len(pattern.vertices))
+	for _, v := range pattern.vertices {
+		neighbors[v] = undirectedNeighbors(v)
+	}
+
+	ordered := make(map[*Vertex]bool, len(pattern.vertices))
+	links := make(map[*Vertex]int, len(pattern.vertices))       // number of ordered neighbors of each vertex
+	remaining := append([]string(nil), pattern.sortedKeys()...) // copy, as the sorted keys are shared
+
+	for len(remaining) > 0 {
+		best := 0
+		for i, key := range remaining[1:] {
+			v, b := pattern.vertices[key], pattern.vertices[remaining[best]]
+			if links[v] > links[b] || (links[v] == links[b] && len(neighbors[v]) > len(neighbors[b])) {
+				best = i + 1
+			}
+		}
+
+		v := pattern.vertices[remaining[best]]
+		remaining = append(remaining[:best], remaining[best+1:]...)
+		m.pattern = append(m.pattern, v)
+
+		ordered[v] = true
+		for neighbor := range neighbors[v] {
+			if !ordered[neighbor] {
+				links[neighbor]++
+			}
+		}
+	}
+
+	return m
+}
+
+// match extends the current mapping by the pattern vertex at position depth. Returns false if the search was stopped.
+func (m *subgraphMatcher) match(depth int) bool {
+	if depth == len(m.pattern) {
+		return m.fn(m.core)
+	}
+
+	p := m.pattern[depth]
+
+	for _, h := range m.candidates(p) {
+		if !m.feasible(p, h) {
+			continue
+		}
+
+		m.core[p] = h
+		m.reverse[h] = p
+
+		ok := m.match(depth + 1)
+
+		delete(m.core, p)
+		delete(m.reverse, h)
+
+		if !ok {
+			return false
+		}
+	}
+
+	return true
+}
+
+// candidates returns the host vertices p might be mapped to: the unmatched neighbors of the image of an already matched neighbor of p, or all unmatched host vertices if p has none.
+func (m *subgraphMatcher) candidates(p *Vertex) []*Vertex {
+	var adjacent map[*Vertex]int
+	for q := range p.GetOutgoing() {
+		if h, ok := m.core[q]; ok {
+			adjacent = h.GetIncoming()
+			break
+		}
+	}
+	if adjacent == nil {
+		for q := range p.GetIncoming() {
+			if h, ok := m.core[q]; ok {
+				adjacent = h.GetOutgoing()
+				break
+			}
+		}
+	}
+
+	var candidates []*Vertex
+	if adjacent == nil {
+		for _, h := range m.hosts {
+			if _, ok := m.reverse[h]; !ok {
+				candidates = append(candidates, h)
+			}
+		}
+		return candidates
+	}
+
+	for h := range adjacent {
+		if _, ok := m.reverse[h]; !ok {
+			candidates = append(candidates, h)
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].key < candidates[j].key })
+
+	return candidates
+}
+
+// feasible returns whether mapping p to h keeps the mapping consistent: every edge between p and matched pattern vertices must exist between h and their images, and, if the match is induced, vice versa.
+func (m *subgraphMatcher) feasible(p, h *Vertex) bool {
+	if m.vertexMatch != nil && !m.vertexMatch(p, h) {
+		return false
+	}
+
+	pOut, pIn := p.GetOutgoing(), p.GetIncoming()
+	hOut, hIn := h.GetOutgoing(), h.GetIncoming()
+
+	if len(hOut) < len(pOut) || len(hIn) < len(pIn) {
+		return false
+	}
+
+	// self-loops aren't covered below, as p and h aren't matched yet
+	pWeight, pLoop := pOut[p]
+	hWeight, hLoop := hOut[h]
+	if pLoop && (!hLoop || (m.edgeMatch != nil && !m.edgeMatch(pWeight, hWeight))) || hLoop && !pLoop && m.induced {
+		return false
+	}
+
+	for _, edges := range [][2]map[*Vertex]int{{pOut, hOut}, {pIn, hIn}} {
+		patternEdges, hostEdges := edges[0], edges[1]
+
+		// edges of the pattern must be present in the host
+		unmatched := 0
+		for q, weight := range patternEdges {
+			image, ok := m.core[q]
+			if !ok {
+				unmatched++
+				continue
+			}
+
+			hostWeight, ok := hostEdges[image]
+			if !ok || (m.edgeMatch != nil && !m.edgeMatch(weight, hostWeight)) {
+				return false
+			}
+		}
+
+		// edges of the host between matched vertices must be present in the pattern
+		hostUnmatched := 0
+		for image := range hostEdges {
+			q, ok := m.reverse[image]
+			if !ok {
+				hostUnmatched++
+				continue
+			}
+
+			if _, ok := patternEdges[q]; !ok && m.induced {
+				return false
+			}
+		}
+
+		// look ahead: the remaining edges of p need distinct unmatched host neighbors
+		if hostUnmatched < unmatched {
+			return false
+		}
+	}
+
+	return true
+}
+
+// IsomorphismOption configures the comparison made by IsIsomorphic.
+type IsomorphismOption func(*subgraphMatcher)
+
+// CompareValues makes IsIsomorphic only map vertices onto each other whose values are equal according to equal.
+func CompareValues(equal func(a, b interface{}) bool) IsomorphismOption {
+	return func(m *subgraphMatcher) {
+		m.vertexMatch = func(p, h *Vertex) bool {
+			return equal(p.Value(), h.Value())
+		}
+	}
+}
+
+// CompareWeights makes IsIsomorphic only map edges onto each other whose weights are equal.
+func CompareWeights() IsomorphismOption {
+	return func(m *subgraphMatcher) {
+		m.edgeMatch = func(p, h int) bool {
+			return p == h
+		}
+	}
+}
+
+// IsIsomorphic returns whether the graph and other have the same structure regardless of their keys: whether there is a one-to-one mapping between their vertices which maps every edge of one graph onto an edge of the other, in the same direction.
+// Values and weights are ignored, unless CompareValues or CompareWeights is passed.
+func (g *Graph) IsIsomorphic(other *Graph, opts ...IsomorphismOption) bool {
+	defer g.track("IsIsomorphic")()
+
+	// copy other first, so the two graphs are never locked at the same time
+	other.RLock()
+	o := other.clone()
+	other.RUnlock()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if len(g.vertices) != len(o.vertices) || !sameDegrees(g, o) {
+		return false
+	}
+
+	m := newSubgraphMatcher(g, o)
+	m.induced = true
+	for _, opt := range opts {
+		opt(m)
+	}
+
+	found := false
+	m.fn = func(map[*Vertex]*Vertex) bool {
+		found = true
+		return false
+	}
+
+	m.match(0)
+
+	return found
+}
+
+// sameDegrees is an internal function, does NOT lock the graphs, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()) of both.
+// It returns whether both graphs have the same number of vertices with each combination of in- and out-degree, which isomorphic graphs must have.
+func sameDegrees(a, b *Graph) bool {
+	degrees := map[[2]int]int{}
+	for _, v := range a.vertices {
+		degrees[[2]int{len(v.GetIncoming()), len(v.GetOutgoing())}]++
+	}
+	for _, v := range b.vertices {
+		degrees[[2]int{len(v.GetIncoming()), len(v.GetOutgoing())}]--
+	}
+
+	for _, n := range degrees {
+		if n != 0 {
+			return false
+		}
+	}
+
+	return true
+}
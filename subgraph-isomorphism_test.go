@@ -0,0 +1,191 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+	"time"
+)
+
+func TestFindSubgraphs(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e"} {
+		g.Set(key, nil)
+	}
+
+	// cycle a → b → c → a, chain c → d → e, chord b → d
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "a", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 1)
+	g.Connect("b", "d", 1)
+
+	find := func(pattern *Graph) []string {
+		var found []string
+		g.FindSubgraphs(pattern, func(mapping map[string]string) bool {
+			keys := make([]string, 0, len(mapping))
+			for key := range mapping {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+
+			match := ""
+			for _, key := range keys {
+				match += mapping[key]
+			}
+			found = append(found, match)
+			return true
+		})
+		sort.Strings(found)
+		return found
+	}
+
+	// directed triangle, found once per rotation
+	cycle := New()
+	cycle.Set("x", nil)
+	cycle.Set("y", nil)
+	cycle.Set("z", nil)
+	cycle.Connect("x", "y", 1)
+	cycle.Connect("y", "z", 1)
+	cycle.Connect("z", "x", 1)
+
+	if expected := []string{"abc", "bca", "cab"}; !reflect.DeepEqual(find(cycle), expected) {
+		t.Errorf("expected %v, got %v", expected, find(cycle))
+	}
+
+	// induced path x → y → z: b → c → d has the chord b → d, c → d → e and a → b → d don't
+	path := New()
+	path.Set("x", nil)
+	path.Set("y", nil)
+	path.Set("z", nil)
+	path.Connect("x", "y", 1)
+	path.Connect("y", "z", 1)
+
+	if expected := []string{"abd", "bde", "cde"}; !reflect.DeepEqual(find(path), expected) {
+		t.Errorf("expected %v, got %v", expected, find(path))
+	}
+
+	// two unconnected vertices: a, d; a, e; b, e; c, e, in both orders
+	pair := New()
+	pair.Set("x", nil)
+	pair.Set("y", nil)
+
+	if found := find(pair); len(found) != 2*4 || found[0] != "ad" {
+		t.Errorf("unexpected matches %v", found)
+	}
+
+	// stopping early
+	n := 0
+	g.FindSubgraphs(cycle, func(map[string]string) bool {
+		n++
+		return false
+	})
+	if n != 1 {
+		t.Errorf("expected the enumeration to stop after 1 match, got %d", n)
+	}
+
+	// the graph contains itself exactly once, as it has no automorphisms
+	if found := find(g); !reflect.DeepEqual(found, []string{"abcde"}) {
+		t.Errorf("unexpected matches %v", found)
+	}
+
+	// a pattern larger than the graph
+	pair.Set("z", nil)
+	pair.Set("w", nil)
+	pair.Set("v", nil)
+	pair.Set("u", nil)
+	if found := find(pair); len(found) != 0 {
+		t.Errorf("unexpected matches %v", found)
+	}
+}
+
+func TestIsIsomorphic(t *testing.T) {
+	build := func(prefix string, values []string, edges [][3]int) *Graph {
+		g := New()
+		for i, value := range values {
+			g.Set(prefix+string(rune('a'+i)), value)
+		}
+		for _, e := range edges {
+			g.Connect(prefix+string(rune('a'+e[0])), prefix+string(rune('a'+e[1])), e[2])
+		}
+		return g
+	}
+
+	// a square with a diagonal, renamed and renumbered
+	g := build("", []string{"x", "y", "x", "y"}, [][3]int{{0, 1, 1}, {1, 2, 2}, {2, 3, 1}, {3, 0, 2}, {0, 2, 5}})
+	h := build("v", []string{"x", "y", "x", "y"}, [][3]int{{2, 3, 1}, {3, 0, 2}, {0, 1, 1}, {1, 2, 2}, {2, 0, 5}})
+
+	if !g.IsIsomorphic(h) || !h.IsIsomorphic(g) || !g.IsIsomorphic(g) {
+		t.Fail()
+	}
+
+	equal := func(a, b interface{}) bool { return a == b }
+	if !g.IsIsomorphic(h, CompareValues(equal), CompareWeights()) {
+		t.Error("expected isomorphism respecting values and weights")
+	}
+
+	// swapping values breaks the value-preserving isomorphism only
+	h.Set("va", "y")
+	h.Set("vb", "x")
+	if !g.IsIsomorphic(h, CompareWeights()) || g.IsIsomorphic(h, CompareValues(equal)) {
+		t.Error("expected values to be compared")
+	}
+
+	// changing a weight breaks the weight-preserving isomorphism only
+	h.Connect("vc", "va", 4)
+	if !g.IsIsomorphic(h) || g.IsIsomorphic(h, CompareWeights()) {
+		t.Error("expected weights to be compared")
+	}
+
+	// reversing an edge keeps the degree sequence of a cycle, but not the structure
+	cycle := build("", []string{"", "", "", ""}, [][3]int{{0, 1, 1}, {1, 2, 1}, {2, 3, 1}, {3, 0, 1}})
+	twoCycles := build("", []string{"", "", "", ""}, [][3]int{{0, 1, 1}, {1, 0, 1}, {2, 3, 1}, {3, 2, 1}})
+	if cycle.IsIsomorphic(twoCycles) {
+		t.Error("expected a 4-cycle and two 2-cycles to differ")
+	}
+
+	// different sizes
+	if g.IsIsomorphic(New()) || !New().IsIsomorphic(New()) {
+		t.Fail()
+	}
+}
+
+func TestSubgraphLockOrder(t *testing.T) {
+	compare := map[string]func(g, h *Graph){
+		"IsIsomorphic": func(g, h *Graph) { g.IsIsomorphic(h) },
+		"FindSubgraphs": func(g, h *Graph) {
+			g.FindSubgraphs(h, func(map[string]string) bool { return true })
+		},
+	}
+
+	for name, fn := range compare {
+		t.Run(name, func(t *testing.T) {
+			g, h := New(), New()
+			g.Set("a", nil)
+			h.Set("a", nil)
+
+			// a writer waiting for h blocks all further readers of h
+			h.RLock()
+			go h.Set("b", nil)
+			time.Sleep(10 * time.Millisecond)
+
+			// the comparison waits for h, which must not keep g locked meanwhile, or writers of g would wait for h as well
+			go fn(g, h)
+			time.Sleep(10 * time.Millisecond)
+
+			set := make(chan struct{})
+			go func() {
+				g.Set("b", nil)
+				close(set)
+			}()
+
+			select {
+			case <-set:
+			case <-time.After(5 * time.Second):
+				t.Error("g is locked while waiting for h")
+			}
+			h.RUnlock()
+		})
+	}
+}
@@ -0,0 +1,188 @@
+package graph
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrSnapshotExists is returned by TagSnapshot if there already is a snapshot with the requested name.
+	ErrSnapshotExists = errors.New("graph: snapshot exists")
+
+	// ErrNoSnapshot is returned when there is no snapshot with the requested name.
+	ErrNoSnapshot = errors.New("graph: no such snapshot")
+)
+
+// snapshotExt is the extension of snapshot files written to the snapshot directory.
+const snapshotExt = ".snapshot"
+
+// SetSnapshotDir makes TagSnapshot store snapshots as gob-encoded files in dir instead of in memory, so they survive restarts and can be shared between deployments. An empty dir stores them in memory again.
+// Snapshots already taken stay where they are.
+func (g *Graph) SetSnapshotDir(dir string) {
+	defer g.track("SetSnapshotDir")()
+
+	g.Lock()
+	g.snapshotDir = dir
+	g.Unlock()
+}
+
+// TagSnapshot stores a copy of the current vertices and edges of the graph under name, e.g. to compare the topologies before and after a migration. Values are copied shallowly with the tags and labels; other settings are not part of the snapshot.
+// The graph is copied while it is locked for reading, like AutoSave does, and the copy is encoded and written to the snapshot directory afterwards, so readers aren't blocked and writers only while the graph is copied.
+// Snapshots are immutable: returns ErrSnapshotExists if name is taken already, see DeleteSnapshot. Writing a snapshot file may also fail with the error returned by the file system or GobEncode.
+func (g *Graph) TagSnapshot(name string) error {
+	defer g.track("TagSnapshot")()
+
+	// the directory is read with the copy, so a concurrent SetSnapshotDir doesn't split them
+	var dir string
+	taken := func() bool {
+		dir = g.snapshotDir
+		if _, ok := g.snapshots[name]; ok {
+			return true
+		}
+		if dir == "" {
+			return false
+		}
+		_, err := os.Stat(snapshotPath(dir, name))
+		return err == nil
+	}
+
+	return g.snapshot(taken, func(c *Graph) error {
+		if dir == "" {
+			g.Lock()
+			if g.snapshots == nil {
+				g.snapshots = map[string]*Graph{}
+			}
+			g.snapshots[name] = c
+			g.Unlock()
+			return nil
+		}
+
+		b, err := c.GobEncode()
+		if err != nil {
+			return err
+		}
+
+		return writeFileAtomic(snapshotPath(dir, name), b)
+	})
+}
+
+// snapshot copies the graph for TagSnapshot and Commit. It returns ErrSnapshotExists if taken returns true, otherwise it passes the copy to store and returns its error.
+// taken is called while the graph is locked for reading, so the graph is only blocked for writers while it is copied. store is called without locking the graph, and must lock it to save the copy; calls of snapshot are serialized, so the copies are stored in the order they were taken and no other snapshot can take the name meanwhile.
+func (g *Graph) snapshot(taken func() bool, store func(c *Graph) error) error {
+	g.snapshotting.Lock()
+	defer g.snapshotting.Unlock()
+
+	g.RLock()
+	if taken() {
+		g.RUnlock()
+		return ErrSnapshotExists
+	}
+	c := g.clone()
+	g.RUnlock()
+
+	return store(c)
+}
+
+// TaggedSnapshot returns a new graph with the vertices and edges of the snapshot stored under name, from memory or the snapshot directory. Changing the returned graph doesn't change the snapshot.
+// Returns ErrNoSnapshot if there is no such snapshot.
+func (g *Graph) TaggedSnapshot(name string) (*Graph, error) {
+	defer g.track("TaggedSnapshot")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if s, ok := g.snapshots[name]; ok {
+		s.RLock()
+		defer s.RUnlock()
+
+		return s.clone(), nil
+	}
+
+	if g.snapshotDir == "" {
+		return nil, ErrNoSnapshot
+	}
+
+	b, err := ioutil.ReadFile(snapshotPath(g.snapshotDir, name))
+	if os.IsNotExist(err) {
+		return nil, ErrNoSnapshot
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	s := New()
+	if err = s.GobDecode(b); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// SnapshotNames returns the sorted names of all snapshots, in memory and in the snapshot directory.
+func (g *Graph) SnapshotNames() ([]string, error) {
+	defer g.track("SnapshotNames")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	names := make([]string, 0, len(g.snapshots))
+	for name := range g.snapshots {
+		names = append(names, name)
+	}
+
+	if g.snapshotDir != "" {
+		files, err := ioutil.ReadDir(g.snapshotDir)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, f := range files {
+			if f.IsDir() || !strings.HasSuffix(f.Name(), snapshotExt) {
+				continue
+			}
+
+			name, err := url.PathUnescape(strings.TrimSuffix(f.Name(), snapshotExt))
+			if err == nil {
+				names = append(names, name)
+			}
+		}
+	}
+
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// DeleteSnapshot deletes the snapshot stored under name. Returns ErrNoSnapshot if there is no such snapshot.
+func (g *Graph) DeleteSnapshot(name string) error {
+	defer g.track("DeleteSnapshot")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	if _, ok := g.snapshots[name]; ok {
+		delete(g.snapshots, name)
+		return nil
+	}
+
+	if g.snapshotDir == "" {
+		return ErrNoSnapshot
+	}
+
+	err := os.Remove(snapshotPath(g.snapshotDir, name))
+	if os.IsNotExist(err) {
+		return ErrNoSnapshot
+	}
+
+	return err
+}
+
+// snapshotPath returns the path of the file storing the snapshot name in dir. Names are escaped, so they can contain any character.
+func snapshotPath(dir, name string) string {
+	return filepath.Join(dir, url.PathEscape(name)+snapshotExt)
+}
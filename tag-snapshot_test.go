@@ -0,0 +1,182 @@
+package graph
+
+import (
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestTagSnapshot(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Connect("a", "b", 3)
+
+	if err := g.TagSnapshot("before migration"); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.TagSnapshot("before migration"); err != ErrSnapshotExists {
+		t.Fail()
+	}
+
+	dir, err := ioutil.TempDir("", "snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g.SetSnapshotDir(dir)
+
+	g.Disconnect("a", "b")
+	g.Set("c", 4)
+	g.Connect("b", "c", 5)
+
+	if err = g.TagSnapshot("after migration/v2"); err != nil {
+		t.Fatal(err)
+	}
+	if err = g.TagSnapshot("after migration/v2"); err != ErrSnapshotExists {
+		t.Fail()
+	}
+
+	names, err := g.SnapshotNames()
+	if err != nil || !reflect.DeepEqual(names, []string{"after migration/v2", "before migration"}) {
+		t.Fatalf("unexpected names %v (%v)", names, err)
+	}
+
+	before, err := g.TaggedSnapshot("before migration")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if before.Len() != 2 {
+		t.Fail()
+	}
+	if ok, w := before.IsConnected("a", "b"); !ok || w != 3 {
+		t.Fail()
+	}
+
+	// snapshots are immutable
+	before.Connect("b", "a", 1)
+	if before, _ = g.TaggedSnapshot("before migration"); before.Len() != 2 {
+		t.Fail()
+	}
+	if ok, _ := before.IsConnected("b", "a"); ok {
+		t.Error("expected the snapshot not to change")
+	}
+
+	after, err := g.TaggedSnapshot("after migration/v2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if v, _ := after.Get("c"); after.Len() != 3 || v.Value() != 4 {
+		t.Fail()
+	}
+	if ok, _ := after.IsConnected("a", "b"); ok {
+		t.Fail()
+	}
+
+	// snapshot files can be read by other graphs using the same directory
+	other := New()
+	other.SetSnapshotDir(dir)
+	if s, err := other.TaggedSnapshot("after migration/v2"); err != nil || !s.IsIsomorphic(after, CompareWeights()) {
+		t.Fail()
+	}
+
+	for _, name := range names {
+		if err = g.DeleteSnapshot(name); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = g.TaggedSnapshot(name); err != ErrNoSnapshot {
+			t.Fail()
+		}
+	}
+	if g.DeleteSnapshot("before migration") != ErrNoSnapshot {
+		t.Fail()
+	}
+
+	if files, _ := ioutil.ReadDir(dir); len(files) != 0 {
+		t.Errorf("expected no files left, got %d", len(files))
+	}
+}
+
+// blockingValue blocks encoding until released, to observe the graph while a snapshot is written.
+type blockingValue struct {
+	encoding, release chan struct{}
+}
+
+func (b blockingValue) GobEncode() ([]byte, error) {
+	close(b.encoding)
+	<-b.release
+	return nil, nil
+}
+
+func (b *blockingValue) GobDecode([]byte) error {
+	return nil
+}
+
+func TestTagSnapshotConcurrent(t *testing.T) {
+	gob.Register(blockingValue{})
+
+	dir, err := ioutil.TempDir("", "snapshots")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+
+	g := New()
+	g.SetSnapshotDir(dir)
+	b := blockingValue{make(chan struct{}), make(chan struct{})}
+	g.Set("a", b)
+
+	done := make(chan error)
+	go func() {
+		done <- g.TagSnapshot("slow")
+	}()
+
+	// the graph can be read and written while the snapshot is encoded
+	<-b.encoding
+	read := make(chan struct{})
+	go func() {
+		g.Get("a")
+		g.Set("b", nil)
+		close(read)
+	}()
+	select {
+	case <-read:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected the graph not to be locked while the snapshot is written")
+	}
+	close(b.release)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	g.Delete("a")
+
+	// concurrent snapshots with the same name are stored once, versions get distinct numbers
+	wg := sync.WaitGroup{}
+	var m sync.Mutex
+	stored, numbers := 0, map[int]bool{}
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := g.TagSnapshot("race")
+			n, _ := g.Commit("")
+
+			m.Lock()
+			defer m.Unlock()
+			if err == nil {
+				stored++
+			}
+			numbers[n] = true
+		}()
+	}
+	wg.Wait()
+
+	if stored != 1 || len(numbers) != 8 {
+		t.Errorf("expected one snapshot and 8 versions, got %d and %v", stored, numbers)
+	}
+}
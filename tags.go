@@ -0,0 +1,107 @@
+package graph
+
+// Tag adds the given tags to the vertex with the specified key. Returns false if the key is invalid.
+// Like labels, tags are copied with the graph and encoded by GobEncode, but not reported to subscribers, see SetLabels.
+func (g *Graph) Tag(key string, tags ...string) bool {
+	defer g.track("Tag")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	for _, tag := range tags {
+		g.tags.add(v, tag)
+	}
+
+	return true
+}
+
+// Untag removes the given tags from the vertex with the specified key. Returns false if the key is invalid.
+func (g *Graph) Untag(key string, tags ...string) bool {
+	defer g.track("Untag")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	v := g.get(key)
+	if v == nil {
+		return false
+	}
+
+	for _, tag := range tags {
+		g.tags.remove(v, tag)
+	}
+
+	return true
+}
+
+// Tags returns the sorted tags of the vertex with the specified key. The slice is empty if the key is invalid or the vertex has no tags.
+func (g *Graph) Tags(key string) []string {
+	defer g.track("Tags")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.tags.sorted(g.get(key))
+}
+
+// HasTag returns true if the vertex with the specified key has the given tag.
+func (g *Graph) HasTag(key, tag string) bool {
+	defer g.track("HasTag")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.tags.has(g.get(key), tag)
+}
+
+// Tagged returns a slice containing all vertices with the given tag. The slice is empty if there are no such vertices.
+func (g *Graph) Tagged(tag string) []*Vertex {
+	defer g.track("Tagged")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	return g.tags.lookup(tag)
+}
+
+// TraverseTagged visits the vertices reachable from the vertex with key startKey in breadth-first order, following outgoing edges only to vertices with the given tag. The start vertex is visited even if it doesn't have the tag.
+// The traversal stops when visit returns false. Returns false if startKey is invalid.
+func (g *Graph) TraverseTagged(startKey, tag string, visit func(v *Vertex) bool) bool {
+	defer g.track("TraverseTagged")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	start := g.get(startKey)
+	if start == nil {
+		return false
+	}
+
+	visited := map[*Vertex]bool{start: true}
+	queue := []*Vertex{start}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		if !visit(current) {
+			return true
+		}
+
+		for neighbor := range current.GetOutgoing() {
+			if !g.tags.has(neighbor, tag) || visited[neighbor] {
+				continue
+			}
+
+			visited[neighbor] = true
+			queue = append(queue, neighbor)
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,66 @@
+package graph
+
+import (
+	"testing"
+)
+
+func TestTags(t *testing.T) {
+	g := New()
+
+	g.Set("1", nil)
+	g.Set("2", nil)
+	g.Set("3", nil)
+	g.Set("4", nil)
+
+	g.Connect("1", "2", 1)
+	g.Connect("2", "3", 1)
+	g.Connect("3", "4", 1)
+	g.Connect("1", "4", 1)
+
+	if !g.Tag("1", "critical", "db") || !g.Tag("2", "critical") || !g.Tag("4", "critical") {
+		t.Fail()
+	}
+
+	// invalid key
+	if g.Tag("5", "critical") {
+		t.Fail()
+	}
+
+	tags := g.Tags("1")
+	if len(tags) != 2 || tags[0] != "critical" || tags[1] != "db" {
+		t.Fail()
+	}
+
+	if !g.HasTag("2", "critical") || g.HasTag("2", "db") || g.HasTag("5", "critical") {
+		t.Fail()
+	}
+
+	if len(g.Tagged("critical")) != 3 || len(g.Tagged("db")) != 1 || len(g.Tagged("other")) != 0 {
+		t.Fail()
+	}
+
+	// traversal skips "3", which isn't critical, so "4" is only reached via the edge 1 → 4
+	var visited []string
+	g.TraverseTagged("1", "critical", func(v *Vertex) bool {
+		visited = append(visited, v.Key())
+		return true
+	})
+	if len(visited) != 3 || visited[0] != "1" {
+		t.Fail()
+	}
+
+	g.Untag("1", "db")
+	if g.HasTag("1", "db") || len(g.Tagged("db")) != 0 {
+		t.Fail()
+	}
+
+	// deleted vertices are removed from the index
+	g.Delete("2")
+	if len(g.Tagged("critical")) != 2 {
+		t.Fail()
+	}
+
+	if g.TraverseTagged("5", "critical", func(v *Vertex) bool { return true }) {
+		t.Fail()
+	}
+}
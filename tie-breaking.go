@@ -0,0 +1,68 @@
+package graph
+
+// OpenVertex describes a vertex waiting to be expanded by an A* search, as passed to a TieBreaker.
+type OpenVertex struct {
+	Key   string
+	Cost  int64 // cost of the best known path from the start vertex
+	Order int   // number of vertices opened before this one, counting reopened vertices again
+}
+
+// TieBreaker decides which of two open vertices with the same priority (cost plus estimated remaining distance) an A* search expands first. It returns true if a should come before b.
+type TieBreaker func(a, b OpenVertex) bool
+
+var (
+	// HigherCostFirst prefers the vertex with the more expensive path from the start, i.e. the one whose priority relies less on the heuristic. On grid-like graphs with many equally short paths, it usually expands far fewer vertices.
+	HigherCostFirst TieBreaker = func(a, b OpenVertex) bool { return a.Cost > b.Cost }
+
+	// FIFO prefers the vertex which was opened first.
+	FIFO TieBreaker = func(a, b OpenVertex) bool { return a.Order < b.Order }
+
+	// LIFO prefers the vertex which was opened last.
+	LIFO TieBreaker = func(a, b OpenVertex) bool { return a.Order > b.Order }
+)
+
+// TieBreaking makes the search decide between open vertices with the same priority using tb. Without it, the order is unspecified.
+func TieBreaking(tb TieBreaker) PathOption {
+	return func(cfg *pathConfig) {
+		cfg.tieBreak = tb
+	}
+}
+
+// tieBreakQueue is a priorityQueue which orders items with the same priority using a TieBreaker.
+type tieBreakQueue struct {
+	priorityQueue
+	tieBreak TieBreaker
+	order    map[*Item]int // maps items to the number of items pushed before them
+	pushed   int
+}
+
+func newTieBreakQueue(tb TieBreaker) *tieBreakQueue {
+	return &tieBreakQueue{tieBreak: tb, order: map[*Item]int{}}
+}
+
+func (q *tieBreakQueue) Less(i, j int) bool {
+	a, b := q.priorityQueue[i], q.priorityQueue[j]
+	if a.priority != b.priority {
+		return a.priority < b.priority
+	}
+
+	return q.tieBreak(q.openVertex(a), q.openVertex(b))
+}
+
+func (q *tieBreakQueue) Push(x interface{}) {
+	q.order[x.(*Item)] = q.pushed
+	q.pushed++
+
+	q.priorityQueue.Push(x)
+}
+
+func (q *tieBreakQueue) Pop() interface{} {
+	item := q.priorityQueue.Pop()
+	delete(q.order, item.(*Item))
+
+	return item
+}
+
+func (q *tieBreakQueue) openVertex(item *Item) OpenVertex {
+	return OpenVertex{item.v.key, item.distanceFromStart, q.order[item]}
+}
@@ -0,0 +1,90 @@
+package graph
+
+import (
+	"fmt"
+	"testing"
+)
+
+// gridGraph returns a size × size grid with edges of weight 1 between horizontally and vertically adjacent cells, and the Manhattan distance as heuristic.
+func gridGraph(size int) (*Graph, func(key, endKey string) int) {
+	g := New()
+	key := func(x, y int) string { return fmt.Sprintf("%d,%d", x, y) }
+
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			g.Set(key(x, y), [2]int{x, y})
+		}
+	}
+
+	for x := 0; x < size; x++ {
+		for y := 0; y < size; y++ {
+			if x+1 < size {
+				g.Connect(key(x, y), key(x+1, y), 1)
+				g.Connect(key(x+1, y), key(x, y), 1)
+			}
+			if y+1 < size {
+				g.Connect(key(x, y), key(x, y+1), 1)
+				g.Connect(key(x, y+1), key(x, y), 1)
+			}
+		}
+	}
+
+	heuristic := func(key, endKey string) int {
+		var x1, y1, x2, y2 int
+		fmt.Sscanf(key, "%d,%d", &x1, &y1)
+		fmt.Sscanf(endKey, "%d,%d", &x2, &y2)
+
+		d := 0
+		if x1 > x2 {
+			d += x1 - x2
+		} else {
+			d += x2 - x1
+		}
+		if y1 > y2 {
+			d += y1 - y2
+		} else {
+			d += y2 - y1
+		}
+		return d
+	}
+
+	return g, heuristic
+}
+
+func TestTieBreaking(t *testing.T) {
+	g, heuristic := gridGraph(15)
+
+	// counts the neighbors examined by each search
+	calls := 0
+	counting := func(key, endKey string) int {
+		calls++
+		return heuristic(key, endKey)
+	}
+
+	examined := map[string]int{}
+	for name, tb := range map[string]TieBreaker{"higher cost": HigherCostFirst, "FIFO": FIFO, "LIFO": LIFO} {
+		calls = 0
+
+		path, err := g.ShortestPath("0,0", "14,14", WithHeuristic(counting), TieBreaking(tb))
+		if err != nil || len(path) != 29 {
+			t.Fatalf("%s: unexpected path %v, %v", name, path, err)
+		}
+
+		examined[name] = calls
+	}
+
+	if examined["higher cost"] >= examined["FIFO"] {
+		t.Errorf("expected preferring higher costs to examine fewer vertices: %v", examined)
+	}
+
+	// a custom comparator determines the path shape: preferring lower keys moves along the first row first
+	path, err := g.ShortestPath("0,0", "2,2", WithHeuristic(heuristic), TieBreaking(func(a, b OpenVertex) bool {
+		if a.Cost != b.Cost {
+			return a.Cost > b.Cost
+		}
+		return a.Key < b.Key
+	}))
+	if err != nil || fmt.Sprint(path) != "[0,0 0,1 0,2 1,2 2,2]" {
+		t.Fatalf("unexpected path %v, %v", path, err)
+	}
+}
@@ -0,0 +1,134 @@
+package graph
+
+import (
+	"errors"
+	"reflect"
+)
+
+// ErrDuplicateValue is returned when a value is rejected because another vertex holds it already and a unique index is enabled.
+var ErrDuplicateValue = errors.New("graph: duplicate value")
+
+// uniqueIndex maps the index keys derived from vertex values back to vertex keys.
+type uniqueIndex struct {
+	derive func(value interface{}) interface{}
+	owners map[interface{}]string // maps index keys to the keys of the vertices holding them
+	keys   map[string]interface{} // maps vertex keys to their index keys
+}
+
+// EnableUniqueIndex enforces that no two vertices hold the same value, and makes FindByValue look up vertices by value in constant time.
+// derive computes the index key of a value, e.g. a user's e-mail address; if it is nil, the value itself is used. Values whose index key is nil or not comparable, e.g. a slice or a map, are not indexed and never rejected.
+// Once enabled, Set ignores and TrySet rejects values whose index key belongs to another vertex already. Returns ErrDuplicateValue, leaving the index disabled, if the current values aren't unique.
+func (g *Graph) EnableUniqueIndex(derive func(value interface{}) interface{}) error {
+	defer g.track("EnableUniqueIndex")()
+
+	if derive == nil {
+		derive = func(value interface{}) interface{} { return value }
+	}
+
+	u := &uniqueIndex{derive, map[interface{}]string{}, map[string]interface{}{}}
+
+	g.Lock()
+	defer g.Unlock()
+
+	for key, v := range g.vertices {
+		if !u.set(key, v.Value()) {
+			return ErrDuplicateValue
+		}
+	}
+
+	g.unique = u
+
+	return nil
+}
+
+// DisableUniqueIndex stops enforcing unique values and drops the index.
+func (g *Graph) DisableUniqueIndex() {
+	defer g.track("DisableUniqueIndex")()
+
+	g.Lock()
+	g.unique = nil
+	g.Unlock()
+}
+
+// FindByValue returns the key of the vertex holding a value with the same index key as value, and false if there is none. It requires a unique index, see EnableUniqueIndex; without one, it always returns false.
+func (g *Graph) FindByValue(value interface{}) (key string, ok bool) {
+	defer g.track("FindByValue")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	if g.unique == nil {
+		return "", false
+	}
+
+	indexKey := g.unique.indexKey(value)
+	if indexKey == nil {
+		return "", false
+	}
+
+	key, ok = g.unique.owners[indexKey]
+
+	return
+}
+
+// indexKey returns the index key of value, nil if it isn't indexed because its index key is nil or can't be used as a map key.
+func (u *uniqueIndex) indexKey(value interface{}) interface{} {
+	indexKey := u.derive(value)
+	if indexKey == nil || !reflect.TypeOf(indexKey).Comparable() {
+		return nil
+	}
+
+	return indexKey
+}
+
+// set indexes value as the value of the vertex with the given key. Returns false if another vertex holds a value with the same index key. Safe to call on a nil index.
+func (u *uniqueIndex) set(key string, value interface{}) bool {
+	if u == nil {
+		return true
+	}
+
+	indexKey := u.indexKey(value)
+	if indexKey != nil {
+		if owner, ok := u.owners[indexKey]; ok && owner != key {
+			return false
+		}
+	}
+
+	u.remove(key)
+
+	if indexKey != nil {
+		u.owners[indexKey] = key
+		u.keys[key] = indexKey
+	}
+
+	return true
+}
+
+// remove drops the vertex with the given key from the index. Safe to call on a nil index.
+func (u *uniqueIndex) remove(key string) {
+	if u == nil {
+		return
+	}
+
+	if indexKey, ok := u.keys[key]; ok {
+		delete(u.owners, indexKey)
+		delete(u.keys, key)
+	}
+}
+
+// copy returns a copy of the index, e.g. to simulate changes. Safe to call on a nil index.
+func (u *uniqueIndex) copy() *uniqueIndex {
+	if u == nil {
+		return nil
+	}
+
+	c := &uniqueIndex{u.derive, make(map[interface{}]string, len(u.owners)), make(map[string]interface{}, len(u.keys))}
+	for indexKey, key := range u.owners {
+		c.owners[indexKey] = key
+	}
+	for key, indexKey := range u.keys {
+		c.keys[key] = indexKey
+	}
+
+	return c
+}
@@ -0,0 +1,126 @@
+package graph
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUniqueIndex(t *testing.T) {
+	g := New()
+	g.Set("1", "alice@example.com")
+	g.Set("2", "bob@example.com")
+	g.Set("3", nil)
+	g.Set("4", nil)
+
+	// e-mail addresses are case-insensitive; nil values aren't indexed
+	lower := func(value interface{}) interface{} {
+		if s, ok := value.(string); ok {
+			return strings.ToLower(s)
+		}
+		return nil
+	}
+
+	if err := g.EnableUniqueIndex(lower); err != nil {
+		t.Fatal(err)
+	}
+
+	if key, ok := g.FindByValue("Bob@Example.com"); !ok || key != "2" {
+		t.Fail()
+	}
+	if _, ok := g.FindByValue("carol@example.com"); ok {
+		t.Fail()
+	}
+
+	// duplicates are rejected, updating a vertex with its own value is fine
+	if _, err := g.TrySet("5", "ALICE@example.com"); err != ErrDuplicateValue {
+		t.Errorf("expected ErrDuplicateValue, got %v", err)
+	}
+	if _, err := g.Get("5"); err == nil {
+		t.Fail()
+	}
+	if created, err := g.TrySet("1", "Alice@example.com"); created || err != nil {
+		t.Errorf("expected the vertex to be updated, got %v %v", created, err)
+	}
+
+	// Set ignores rejected values
+	g.Set("2", "alice@example.com")
+	if v, _ := g.Get("2"); v.Value() != "bob@example.com" {
+		t.Errorf("expected the value to be unchanged, got %v", v.Value())
+	}
+
+	// changing and deleting values frees them
+	if _, err := g.TrySet("2", "robert@example.com"); err != nil {
+		t.Fail()
+	}
+	if created, err := g.TrySet("5", "bob@example.com"); !created || err != nil {
+		t.Errorf("expected the vertex to be created, got %v %v", created, err)
+	}
+	g.Delete("1")
+	if _, err := g.TrySet("6", "alice@example.com"); err != nil {
+		t.Fail()
+	}
+	if key, _ := g.FindByValue("alice@example.com"); key != "6" {
+		t.Fail()
+	}
+
+	g.DisableUniqueIndex()
+	if _, err := g.TrySet("7", "alice@example.com"); err != nil {
+		t.Fail()
+	}
+	if _, ok := g.FindByValue("alice@example.com"); ok {
+		t.Fail()
+	}
+
+	// existing duplicates
+	if err := g.EnableUniqueIndex(nil); err != ErrDuplicateValue {
+		t.Fail()
+	}
+}
+
+func TestUniqueIndexBatch(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.EnableUniqueIndex(nil)
+
+	// the value is freed earlier in the same batch
+	batch := Batch{}
+	batch.Set("a", 2)
+	batch.Set("b", 1)
+	if errs := g.Apply(batch); len(errs) != 0 {
+		t.Fatal(errs)
+	}
+
+	batch = Batch{}
+	batch.Delete("b")
+	batch.Set("c", 2)
+	batch.Set("d", 1)
+
+	errs := g.Validate(batch)
+	if len(errs) != 1 || errs[0].(*BatchError).Index != 1 || errs[0].(*BatchError).Err != ErrDuplicateValue {
+		t.Fatalf("unexpected errors %v", errs)
+	}
+}
+
+func TestUniqueIndexUncomparable(t *testing.T) {
+	g := New()
+	g.Set("1", []string{"a"})
+	g.Set("2", []string{"a"})
+	g.Set("3", map[string]int{"a": 1})
+
+	// slices and maps can't be index keys, so they aren't indexed
+	if err := g.EnableUniqueIndex(nil); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if _, err := g.TrySet("4", []string{"a"}); err != nil {
+		t.Errorf("unexpected error %v", err)
+	}
+	if _, ok := g.FindByValue([]string{"a"}); ok {
+		t.Error("expected slices not to be found")
+	}
+
+	// comparable values are still indexed
+	g.Set("5", "a")
+	if _, err := g.TrySet("6", "a"); err != ErrDuplicateValue {
+		t.Errorf("expected ErrDuplicateValue, got %v", err)
+	}
+}
@@ -0,0 +1,215 @@
+package graph
+
+import (
+	"reflect"
+	"sort"
+	"time"
+)
+
+// Version describes a version of a graph stored by Commit.
+type Version struct {
+	Number int       // consecutive number of the version, starting at 1
+	Tag    string    // name of the version, may be empty
+	Time   time.Time // time of the commit
+}
+
+// committedVersion is a version with the copy of the graph it refers to.
+type committedVersion struct {
+	Version
+	graph *Graph
+}
+
+// Commit stores a copy of the current vertices and edges of the graph as a new version, like TagSnapshot, and returns its number. The tag names the version, so it can be retrieved by TaggedVersion; it may be empty, otherwise it must be unique, or ErrSnapshotExists is returned.
+// Values are copied shallowly with the tags and labels; other settings are not part of the version. All versions are kept in memory until they are deleted by DeleteVersion or PruneVersions, see TagSnapshot to store copies on disk.
+func (g *Graph) Commit(tag string) (int, error) {
+	defer g.track("Commit")()
+
+	taken := func() bool {
+		return tag != "" && g.versionIndex(func(v Version) bool { return v.Tag == tag }) >= 0
+	}
+
+	var number int
+	err := g.snapshot(taken, func(c *Graph) error {
+		g.Lock()
+		g.lastVersion++
+		number = g.lastVersion
+		g.versions = append(g.versions, committedVersion{Version{number, tag, time.Now()}, c})
+		g.Unlock()
+		return nil
+	})
+
+	return number, err
+}
+
+// versionIndex is an internal function, does NOT lock the graph, should only be used in between RLock() and RUnlock() (or Lock() and Unlock()).
+// It returns the index of the first version matching, or -1 if there is none.
+func (g *Graph) versionIndex(match func(v Version) bool) int {
+	for i, v := range g.versions {
+		if match(v.Version) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+// DeleteVersion deletes the version with the given number, so its copy of the graph can be garbage collected. The numbers of the other versions don't change. Returns ErrNoSnapshot if there is no such version.
+func (g *Graph) DeleteVersion(number int) error {
+	defer g.track("DeleteVersion")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	i := g.versionIndex(func(v Version) bool { return v.Number == number })
+	if i < 0 {
+		return ErrNoSnapshot
+	}
+
+	g.versions = append(g.versions[:i:i], g.versions[i+1:]...)
+
+	return nil
+}
+
+// PruneVersions deletes the oldest versions, so at most keep versions are left, and returns the number of versions deleted. Tagged versions are deleted like untagged ones.
+func (g *Graph) PruneVersions(keep int) int {
+	defer g.track("PruneVersions")()
+
+	g.Lock()
+	defer g.Unlock()
+
+	if keep < 0 {
+		keep = 0
+	}
+	if len(g.versions) <= keep {
+		return 0
+	}
+
+	pruned := len(g.versions) - keep
+	g.versions = append([]committedVersion(nil), g.versions[pruned:]...)
+
+	return pruned
+}
+
+// Versions returns all versions stored by Commit, oldest first.
+func (g *Graph) Versions() []Version {
+	defer g.track("Versions")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	versions := make([]Version, len(g.versions))
+	for i, v := range g.versions {
+		versions[i] = v.Version
+	}
+
+	return versions
+}
+
+// AtVersion returns a new graph with the vertices and edges of the version with the given number, to inspect how the graph looked in the past. Changing the returned graph doesn't change the version.
+// Returns ErrNoSnapshot if there is no such version.
+func (g *Graph) AtVersion(number int) (*Graph, error) {
+	defer g.track("AtVersion")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	i := g.versionIndex(func(v Version) bool { return v.Number == number })
+	if i < 0 {
+		return nil, ErrNoSnapshot
+	}
+
+	return g.versions[i].graph.clone(), nil
+}
+
+// TaggedVersion returns a new graph with the vertices and edges of the version with the given tag, like AtVersion. Returns ErrNoSnapshot if there is no such version.
+func (g *Graph) TaggedVersion(tag string) (*Graph, error) {
+	defer g.track("TaggedVersion")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	i := g.versionIndex(func(v Version) bool { return v.Tag == tag })
+	if tag == "" || i < 0 {
+		return nil, ErrNoSnapshot
+	}
+
+	return g.versions[i].graph.clone(), nil
+}
+
+// GraphDiff describes the differences between two graphs, see Diff. All slices are sorted by keys.
+type GraphDiff struct {
+	AddedVertices   []string // keys of the vertices only in the second graph
+	RemovedVertices []string // keys of the vertices only in the first graph
+	ChangedVertices []string // keys of the vertices in both graphs with values which aren't deeply equal
+	AddedEdges      []Edge   // edges only in the second graph
+	RemovedEdges    []Edge   // edges only in the first graph, with their old weights
+	ChangedEdges    []Edge   // edges in both graphs with different weights, with their new weights
+}
+
+// Empty returns true if there are no differences.
+func (d *GraphDiff) Empty() bool {
+	return len(d.AddedVertices) == 0 && len(d.RemovedVertices) == 0 && len(d.ChangedVertices) == 0 &&
+		len(d.AddedEdges) == 0 && len(d.RemovedEdges) == 0 && len(d.ChangedEdges) == 0
+}
+
+// Diff returns the changes turning the graph into other, e.g. to compare two versions returned by AtVersion. Values are compared with reflect.DeepEqual.
+func (g *Graph) Diff(other *Graph) *GraphDiff {
+	defer g.track("Diff")()
+
+	// copy other first, so the two graphs are never locked at the same time
+	other.RLock()
+	o := other.clone()
+	other.RUnlock()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	d := &GraphDiff{}
+
+	// weight returns the weight of the edge between the vertices with the given keys in h, and false if there is none
+	weight := func(h *Graph, from, to string) (int, bool) {
+		fromV, toV := h.vertices[from], h.vertices[to]
+		if fromV == nil || toV == nil {
+			return 0, false
+		}
+		w, ok := fromV.GetOutgoing()[toV]
+		return w, ok
+	}
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+		if ov := o.vertices[key]; ov == nil {
+			d.RemovedVertices = append(d.RemovedVertices, key)
+		} else if !reflect.DeepEqual(v.Value(), ov.value) {
+			d.ChangedVertices = append(d.ChangedVertices, key)
+		}
+
+		for neighbor, w := range v.GetOutgoing() {
+			if otherW, ok := weight(o, key, neighbor.key); !ok {
+				d.RemovedEdges = append(d.RemovedEdges, Edge{From: key, To: neighbor.key, Weight: w})
+			} else if otherW != w {
+				d.ChangedEdges = append(d.ChangedEdges, Edge{From: key, To: neighbor.key, Weight: otherW})
+			}
+		}
+	}
+
+	for _, key := range o.sortedKeys() {
+		if g.vertices[key] == nil {
+			d.AddedVertices = append(d.AddedVertices, key)
+		}
+
+		for neighbor, w := range o.vertices[key].outgoingEdges {
+			if _, ok := weight(g, key, neighbor.key); !ok {
+				d.AddedEdges = append(d.AddedEdges, Edge{From: key, To: neighbor.key, Weight: w})
+			}
+		}
+	}
+
+	for _, edges := range [][]Edge{d.AddedEdges, d.RemovedEdges, d.ChangedEdges} {
+		sort.Slice(edges, func(i, j int) bool {
+			return edges[i].From < edges[j].From || edges[i].From == edges[j].From && edges[i].To < edges[j].To
+		})
+	}
+
+	return d
+}
@@ -0,0 +1,141 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommit(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", 2)
+	g.Connect("a", "b", 3)
+
+	if n, err := g.Commit("initial"); n != 1 || err != nil {
+		t.Fatalf("expected version 1, got %d %v", n, err)
+	}
+
+	g.Set("a", 10)
+	g.Delete("b")
+	g.Set("c", 4)
+	g.Connect("a", "c", 5)
+
+	if n, err := g.Commit(""); n != 2 || err != nil {
+		t.Fatalf("expected version 2, got %d %v", n, err)
+	}
+	if _, err := g.Commit("initial"); err != ErrSnapshotExists {
+		t.Errorf("expected ErrSnapshotExists, got %v", err)
+	}
+
+	versions := g.Versions()
+	if len(versions) != 2 || versions[0].Tag != "initial" || versions[1].Number != 2 || versions[1].Time.Before(versions[0].Time) {
+		t.Errorf("unexpected versions %v", versions)
+	}
+
+	initial, err := g.TaggedVersion("initial")
+	if err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if ok, weight := initial.IsConnected("a", "b"); !ok || weight != 3 {
+		t.Errorf("expected the old edge, got %v %d", ok, weight)
+	}
+
+	// changing a returned graph doesn't change the version
+	initial.Delete("a")
+	if v1, _ := g.AtVersion(1); v1.Len() != 2 {
+		t.Errorf("expected version 1 to be unchanged, got %d vertices", v1.Len())
+	}
+
+	for _, n := range []int{0, 3} {
+		if _, err := g.AtVersion(n); err != ErrNoSnapshot {
+			t.Errorf("version %d: expected ErrNoSnapshot, got %v", n, err)
+		}
+	}
+	if _, err := g.TaggedVersion(""); err != ErrNoSnapshot {
+		t.Errorf("expected ErrNoSnapshot for the empty tag, got %v", err)
+	}
+}
+
+func TestDeleteVersions(t *testing.T) {
+	g := New()
+	for i, tag := range []string{"first", "", "third", ""} {
+		g.Set("a", i)
+		g.Commit(tag)
+	}
+
+	if err := g.DeleteVersion(2); err != nil {
+		t.Fatalf("unexpected error %v", err)
+	}
+	if err := g.DeleteVersion(2); err != ErrNoSnapshot {
+		t.Errorf("expected ErrNoSnapshot, got %v", err)
+	}
+
+	// numbers stay the same and aren't reused
+	if v3, err := g.AtVersion(3); err != nil || v3.vertices["a"].Value() != 2 {
+		t.Errorf("expected version 3, got %v", err)
+	}
+	if n, _ := g.Commit(""); n != 5 {
+		t.Errorf("expected version 5, got %d", n)
+	}
+
+	if pruned := g.PruneVersions(2); pruned != 2 {
+		t.Errorf("expected 2 versions to be pruned, got %d", pruned)
+	}
+	var numbers []int
+	for _, v := range g.Versions() {
+		numbers = append(numbers, v.Number)
+	}
+	if !reflect.DeepEqual(numbers, []int{4, 5}) {
+		t.Errorf("expected the newest versions to be kept, got %v", numbers)
+	}
+	if _, err := g.TaggedVersion("first"); err != ErrNoSnapshot {
+		t.Errorf("expected the pruned tag to be gone, got %v", err)
+	}
+	if _, err := g.Commit("first"); err != nil {
+		t.Errorf("expected the tag of a deleted version to be free, got %v", err)
+	}
+
+	if pruned := g.PruneVersions(5); pruned != 0 {
+		t.Errorf("expected nothing to be pruned, got %d", pruned)
+	}
+	if pruned := g.PruneVersions(0); pruned != 3 || len(g.Versions()) != 0 {
+		t.Errorf("expected all versions to be pruned, got %d", pruned)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	g := New()
+	g.Set("a", 1)
+	g.Set("b", []int{2})
+	g.Set("c", 3)
+	g.Connect("a", "b", 1)
+	g.Connect("a", "c", 2)
+	g.Connect("c", "a", 3)
+	g.Commit("before")
+
+	g.Set("b", []int{2})
+	g.Set("a", 5)
+	g.Delete("c")
+	g.Set("d", nil)
+	g.Connect("a", "b", 4)
+	g.Connect("d", "a", 1)
+
+	before, _ := g.TaggedVersion("before")
+	d := before.Diff(g)
+
+	expected := &GraphDiff{
+		AddedVertices:   []string{"d"},
+		RemovedVertices: []string{"c"},
+		ChangedVertices: []string{"a"},
+		AddedEdges:      []Edge{{From: "d", To: "a", Weight: 1}},
+		RemovedEdges:    []Edge{{From: "a", To: "c", Weight: 2}, {From: "c", To: "a", Weight: 3}},
+		ChangedEdges:    []Edge{{From: "a", To: "b", Weight: 4}},
+	}
+	if !reflect.DeepEqual(d, expected) {
+		t.Errorf("expected %+v, got %+v", expected, d)
+	}
+
+	if d.Empty() || !g.Diff(g).Empty() {
+		t.Error("expected only the diff of different graphs to be non-empty")
+	}
+}
@@ -0,0 +1,47 @@
+package graph
+
+import (
+	"sort"
+)
+
+// VertexCoverApprox returns the sorted keys of a vertex cover: every edge starts or ends at a vertex in the set.
+// It takes both vertices of every edge of a maximal matching, so the cover is at most twice as large as a minimum one. Edges are considered in key order, so the result is deterministic.
+func (g *Graph) VertexCoverApprox() (keys []string) {
+	defer g.track("VertexCoverApprox")()
+
+	g.RLock()
+	defer g.RUnlock()
+
+	covered := map[*Vertex]bool{}
+
+	for _, key := range g.sortedKeys() {
+		v := g.vertices[key]
+		if covered[v] {
+			continue
+		}
+
+		neighbors := make([]*Vertex, 0, len(v.GetOutgoing()))
+		for neighbor := range v.GetOutgoing() {
+			neighbors = append(neighbors, neighbor)
+		}
+		sort.Slice(neighbors, func(i, j int) bool {
+			return neighbors[i].key < neighbors[j].key
+		})
+
+		// match v with its first uncovered neighbor; a self-loop is covered by v alone
+		for _, neighbor := range neighbors {
+			if !covered[neighbor] {
+				covered[v], covered[neighbor] = true, true
+				keys = append(keys, v.key)
+				if neighbor != v {
+					keys = append(keys, neighbor.key)
+				}
+				break
+			}
+		}
+	}
+
+	sort.Strings(keys)
+
+	return
+}
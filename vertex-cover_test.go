@@ -0,0 +1,57 @@
+package graph
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestVertexCoverApprox(t *testing.T) {
+	g := New()
+	for _, key := range []string{"a", "b", "c", "d", "e", "f"} {
+		g.Set(key, nil)
+	}
+
+	// path a → b → c → d → e, f isolated
+	g.Connect("a", "b", 1)
+	g.Connect("b", "c", 1)
+	g.Connect("c", "d", 1)
+	g.Connect("d", "e", 1)
+
+	cover := g.VertexCoverApprox()
+	if !reflect.DeepEqual(cover, []string{"a", "b", "c", "d"}) {
+		t.Fatalf("unexpected cover %v", cover)
+	}
+
+	// every edge is covered
+	in := map[string]bool{}
+	for _, key := range cover {
+		in[key] = true
+	}
+	for _, v := range g.GetAll() {
+		for neighbor := range v.GetOutgoing() {
+			if !in[v.Key()] && !in[neighbor.Key()] {
+				t.Errorf("edge %s → %s is not covered", v.Key(), neighbor.Key())
+			}
+		}
+	}
+
+	if len(New().VertexCoverApprox()) != 0 {
+		t.Fail()
+	}
+}
+
+func TestVertexCoverApproxSelfLoops(t *testing.T) {
+	g := New()
+	g.EnableSelfLoops()
+	for _, key := range []string{"a", "b", "c"} {
+		g.Set(key, nil)
+	}
+
+	// a self-loop is covered by its vertex, which is only listed once
+	g.Connect("a", "a", 1)
+	g.Connect("b", "c", 1)
+
+	if cover := g.VertexCoverApprox(); !reflect.DeepEqual(cover, []string{"a", "b", "c"}) {
+		t.Errorf("unexpected cover %v", cover)
+	}
+}